@@ -1,48 +1,301 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io"
+	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/export"
+	"github.com/csheth/browse/internal/httpheaders"
+	"github.com/csheth/browse/internal/httpx"
 	"github.com/csheth/browse/internal/llm"
+	"github.com/csheth/browse/internal/notes"
 	"github.com/csheth/browse/internal/tui"
+	"github.com/csheth/browse/internal/version"
 )
 
 func main() {
-	defaultPath := filepath.Join(".", "zettelkasten.json")
-	zettelPath := flag.String("zettel", defaultPath, "path to the knowledge base JSON file")
+	zettelPath := flag.String("zettel", notes.DefaultPath(), "path to the knowledge base JSON file (default from $"+notes.DefaultPathEnvVar+" when set)")
+	kbFormat := flag.String("kb-format", string(notes.FormatFile), "knowledge base layout: file (one JSON file), jsonl (one JSON entry per line, so saves append; a -zettel path ending in .jsonl picks it too), or dir (one JSON file per note inside the -zettel directory)")
 	noAltScreen := flag.Bool("no-alt-screen", true, "disable the alternate screen buffer (set to false to keep it)")
-	llmModel := flag.String("llm-model", "", "override the default Ollama model (ministral-3:latest)")
-	llmEndpoint := flag.String("llm-endpoint", "", "custom Ollama host (eg. http://localhost:11434)")
+	llmProvider := flag.String("llm-provider", "auto", "LLM service: auto (anthropic when ANTHROPIC_API_KEY is set, else gemini when GEMINI_API_KEY is set, else ollama), ollama, anthropic, or gemini")
+	llmModel := flag.String("llm-model", "", "override the default model (ministral-3:latest for Ollama, claude-3-5-sonnet-latest for Anthropic, gemini-1.5-flash for Gemini)")
+	llmEndpoint := flag.String("llm-endpoint", "", "custom Ollama host (eg. http://localhost:11434) or Anthropic/Gemini base URL")
+	ollamaKeepAlive := flag.String("ollama-keep-alive", "", "how long Ollama keeps the model loaded between calls (eg. 30m, -1 for forever)")
+	autosave := flag.Bool("autosave", false, "save each manual note to the knowledge base as soon as it is captured instead of waiting for s")
+	noAutoBrief := flag.Bool("no-auto-brief", false, "wait for /brief instead of generating the reading brief when a paper loads")
+	preview := flag.Bool("preview", false, "open papers from their metadata alone (title, authors, abstract) and wait for /full before downloading the PDF")
+	cacheBriefs := flag.Bool("cache-briefs", false, "store generated briefs next to the cached PDF and reuse them on reload")
+	llmCache := flag.Bool("llm-cache", false, "reuse stored LLM responses for identical requests (kept in the llm folder of the PDF cache directory)")
+	llmCacheTTL := flag.Duration("llm-cache-ttl", llm.DefaultCacheTTL, "how long -llm-cache reuses a stored response")
+	llmCacheBypass := flag.Bool("llm-cache-bypass", false, "with -llm-cache, ignore stored responses but store fresh ones")
+	userAgent := flag.String("user-agent", "", "User-Agent for arXiv, PDF, and LLM requests (default paperscout/<version>)")
+	var extraHeaders headerFlags
+	flag.Var(&extraHeaders, "header", "extra HTTP header as \"Key: Value\" sent with every request (repeatable)")
+	composerLimit := flag.Int("composer-limit", 2000, "maximum characters accepted by the composer")
+	maxTranscriptEntries := flag.Int("max-transcript-entries", 0, "render only this many of the newest conversation entries, collapsing older ones into one line (0 renders all; the knowledge base keeps everything)")
+	snapshotFlushInterval := flag.Duration("snapshot-flush-interval", 0, "batch conversation history writes and flush them this often, eg. 10s (0 writes every message)")
+	summaryBullets := flag.Int("summary-bullets", 0, "how many bullets summaries and the summary brief section ask for (0 = 5 for summaries, 3-5 for the brief)")
+	technicalBullets := flag.Int("technical-bullets", 0, "how many bullets the technical brief section asks for (0 = 3-7)")
+	deepDiveBullets := flag.Int("deep-dive-bullets", 0, "how many bullets the deep dive brief section asks for (0 = 3)")
+	bulletWords := flag.Int("bullet-words", 0, "cap on words per top-level summary and brief bullet (0 = 20 for summaries, no cap for the brief)")
+	llmTemperature := flag.Float64("llm-temperature", llm.DefaultTemperature, "sampling temperature for every LLM request, from 0 (most deterministic) to 2 (Anthropic caps it at 1)")
+	systemPrompt := flag.String("system-prompt", "", "persona every LLM request is framed with, eg. \"Focus on reproducibility.\" (empty sends none)")
+	minTechnicalBullets := flag.Int("min-technical-bullets", 3, "ask the LLM to continue the technical section when it returns fewer top-level bullets than this (0 disables)")
+	fetchTimeout := flag.Duration("fetch-timeout", 3*time.Minute, "how long loading one paper (metadata, PDF download, and parsing) may take")
+	questionTimeout := flag.Duration("question-timeout", 2*time.Minute, "how long one streamed answer may take")
+	briefTimeout := flag.Duration("brief-timeout", 2*time.Minute, "how long the summary brief section may take; larger sections get proportionally longer")
+	briefConcurrency := flag.Int("brief-concurrency", 0, "how many brief sections may generate at once (0 = 1 for ollama, 3 for cloud providers)")
+	briefStreamRate := flag.Int("brief-stream-rate", 8, "maximum redraws per second while a brief section streams (negative redraws every token)")
+	prefetchPath := flag.String("prefetch", "", "fetch and brief every arXiv id listed in this file (one per line), then exit without the TUI")
+	showVersion := flag.Bool("version", false, "print the version, git commit, and build date, then exit")
+	clearCache := flag.Bool("clear-cache", false, "delete cached PDFs and ar5iv pages, print the space freed, then exit")
+	migrateJSONL := flag.String("migrate-jsonl", "", "copy the -zettel JSON knowledge base into a new .jsonl file at this path, then exit without the TUI")
+	exportMarkdown := flag.String("export-markdown", "", "write every knowledge base note to its own Markdown file in this directory (for Obsidian), then exit without the TUI")
+	prefetchConcurrency := flag.Int("prefetch-concurrency", 2, "how many papers -prefetch fetches and briefs at once (at most 8)")
+	fromClipboard := flag.Bool("from-clipboard", false, "load the arXiv URL or identifier currently on the clipboard at startup")
+	offline := flag.Bool("offline", false, "never touch the network: open papers only from the PDF cache and turn LLM features off")
+	enrich := flag.Bool("enrich", false, "look up citation counts and related arXiv papers on Semantic Scholar (one extra request per paper)")
+	resume := flag.Bool("resume", false, "reopen the paper from the most recent saved conversation at startup")
+	transcriptName := flag.String("transcript-name", export.DefaultTranscriptTemplate, "file name template for /export-transcript, with {id}, {title}, and {date} placeholders")
+	exportName := flag.String("export-name", export.DefaultFileTemplate, "file name template for /export, with {id}, {title}, and {date} placeholders")
+	fullTextSource := flag.String("fulltext-source", string(arxiv.FullTextPDF), "where paper text comes from: pdf, or ar5iv (HTML with section headings, falling back to the PDF)")
+	logFile := flag.String("log-file", "", "append one key=value line per background job (kind, status, duration, error) to this file; unset keeps jobs silent")
+	debug := flag.Bool("debug", false, "log raw LLM responses that fail to parse to paperscout-debug.log in the temp directory")
+	a11y := flag.Bool("a11y", false, "append plain-text state announcements (\"Answer ready\") to paperscout-a11y.log in the temp directory for screen readers")
+	keymapPath := flag.String("keymap", tui.DefaultKeyMapPath(), "JSON file remapping display keys by action name, eg. {\"top\": \"h\"} (missing file keeps the defaults)")
+	tokenEstimator := flag.String("token-estimator", llm.EstimatorHeuristic, "how prompt context budgets count tokens: heuristic (~4 characters per token) or bpe (tighter for code- and math-heavy papers)")
+	themeName := flag.String("theme", "dark", "color theme: dark, light, or high-contrast (NO_COLOR turns colors off)")
+	exportIncludeGuide := flag.Bool("export-include-guide", false, "append the reading guide steps to exported briefs")
 	flag.Parse()
 
+	if *showVersion {
+		fmt.Println("paperscout", version.String())
+		return
+	}
+
+	if *clearCache {
+		freed, err := arxiv.ClearCache()
+		if err != nil {
+			fmt.Println("failed to clear cache:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("cleared %s: freed %.1f MiB\n", arxiv.CacheDir(), float64(freed)/(1<<20))
+		return
+	}
+
 	absPath, err := filepath.Abs(*zettelPath)
 	if err != nil {
 		fmt.Println("failed to resolve knowledge base path:", err)
 		os.Exit(1)
 	}
+	format, err := notes.ParseFormat(*kbFormat)
+	if err != nil {
+		fmt.Println("invalid -kb-format:", err)
+		os.Exit(1)
+	}
+	kb := notes.Store{Path: absPath, Format: format}
+	if err := kb.Validate(); err != nil {
+		fmt.Println("knowledge base unavailable:", err)
+		os.Exit(1)
+	}
+	if *migrateJSONL != "" {
+		count, err := notes.MigrateToJSONL(absPath, *migrateJSONL)
+		if err != nil {
+			fmt.Println("jsonl migration failed:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("migrated %d knowledge base entries to %s; pass -zettel %s to use it\n", count, *migrateJSONL, *migrateJSONL)
+		return
+	}
+	if *exportMarkdown != "" {
+		if err := kb.ExportMarkdown(*exportMarkdown); err != nil {
+			fmt.Println("markdown export failed:", err)
+			os.Exit(1)
+		}
+		fmt.Println("exported knowledge base notes to", *exportMarkdown)
+		return
+	}
+
+	keyBindings, err := tui.LoadKeyBindings(*keymapPath)
+	if err != nil {
+		fmt.Println("invalid -keymap:", err)
+		os.Exit(1)
+	}
+
+	theme, err := tui.ParseTheme(*themeName)
+	if err != nil {
+		fmt.Println("invalid -theme:", err)
+		os.Exit(1)
+	}
+
+	if err := httpx.Check(); err != nil {
+		fmt.Println("invalid CA bundle:", err)
+		os.Exit(1)
+	}
+
+	headers, err := httpheaders.Parse(extraHeaders)
+	if err != nil {
+		fmt.Println("invalid -header:", err)
+		os.Exit(1)
+	}
+	source, err := arxiv.ParseFullTextSource(*fullTextSource)
+	if err != nil {
+		fmt.Println("invalid -fulltext-source:", err)
+		os.Exit(1)
+	}
+	arxiv.SetFullTextSource(source)
+	arxiv.SetOffline(*offline)
+	arxiv.SetEnrichment(*enrich)
 
+	requestHeaders := httpheaders.Config{UserAgent: *userAgent, Extra: headers}
+	arxiv.SetRequestHeaders(requestHeaders)
+
+	if *logFile != "" {
+		jobLog, err := os.OpenFile(*logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			fmt.Println("failed to open -log-file:", err)
+			os.Exit(1)
+		}
+		defer jobLog.Close()
+		tui.SetJobLog(jobLog)
+	}
+
+	var debugLog *log.Logger
+	if *debug {
+		logPath := filepath.Join(os.TempDir(), "paperscout-debug.log")
+		logFile, err := tea.LogToFile(logPath, "paperscout")
+		if err != nil {
+			fmt.Println("failed to open debug log:", err)
+			os.Exit(1)
+		}
+		defer logFile.Close()
+		debugLog = log.Default()
+		fmt.Println("debug log:", logPath)
+	}
+
+	var announcements io.Writer
+	if *a11y {
+		logPath := filepath.Join(os.TempDir(), "paperscout-a11y.log")
+		logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			fmt.Println("failed to open announcement log:", err)
+			os.Exit(1)
+		}
+		defer logFile.Close()
+		announcements = logFile
+		fmt.Println("announcements:", logPath)
+	}
+
+	// program is assigned before Run, which is the only time LLM calls (and their retries) happen.
+	var program *tea.Program
 	var llmClient llm.Client
+	provider, err := llm.ParseProvider(*llmProvider)
+	if err != nil {
+		fmt.Println("invalid -llm-provider:", err)
+		os.Exit(1)
+	}
+	estimator, err := llm.ParseTokenEstimator(*tokenEstimator)
+	if err != nil {
+		fmt.Println("invalid -token-estimator:", err)
+		os.Exit(1)
+	}
+	if err := llm.ValidateTemperature(*llmTemperature); err != nil {
+		fmt.Println("invalid -llm-temperature:", err)
+		os.Exit(1)
+	}
+	for _, count := range []struct {
+		name  string
+		value int
+	}{
+		{"summary-bullets", *summaryBullets},
+		{"technical-bullets", *technicalBullets},
+		{"deep-dive-bullets", *deepDiveBullets},
+		{"bullet-words", *bulletWords},
+	} {
+		if count.value < 0 {
+			fmt.Printf("invalid -%s: %d is negative\n", count.name, count.value)
+			os.Exit(1)
+		}
+	}
 	llmClient, err = llm.NewFromEnv(llm.Config{
-		Model:    *llmModel,
-		Endpoint: *llmEndpoint,
+		Provider:         provider,
+		Model:            *llmModel,
+		Endpoint:         *llmEndpoint,
+		Headers:          requestHeaders,
+		KeepAlive:        *ollamaKeepAlive,
+		DebugLog:         debugLog,
+		TokenEstimator:   estimator,
+		SummaryBullets:   *summaryBullets,
+		TechnicalBullets: *technicalBullets,
+		DeepDiveBullets:  *deepDiveBullets,
+		BulletMaxWords:   *bulletWords,
+		SystemPrompt:     *systemPrompt,
+		Temperature:      *llmTemperature,
+		Offline:          *offline,
+		OnRetry: func(message string) {
+			if program != nil {
+				program.Send(tui.NoticeMsg(message))
+			}
+		},
 	})
 	if err != nil {
 		fmt.Println("LLM disabled:", err)
+	} else if *llmCache {
+		llmClient = llm.NewCachingClient(llmClient, llm.CacheOptions{
+			Dir:    filepath.Join(arxiv.CacheDir(), "llm"),
+			TTL:    *llmCacheTTL,
+			Bypass: *llmCacheBypass,
+		})
+	}
+
+	if *prefetchPath != "" {
+		os.Exit(runPrefetch(*prefetchPath, *prefetchConcurrency, tui.Config{KnowledgeBasePath: absPath, KnowledgeBaseFormat: format, LLM: llmClient, FetchTimeout: *fetchTimeout, BriefSectionTimeout: *briefTimeout, TokenEstimator: estimator}))
 	}
 
 	opts := []tea.ProgramOption{}
 	if !*noAltScreen {
 		opts = append(opts, tea.WithAltScreen())
 	}
-	program := tea.NewProgram(
+	program = tea.NewProgram(
 		tui.New(tui.Config{
-			KnowledgeBasePath: absPath,
-			LLM:               llmClient,
+			KnowledgeBasePath:      absPath,
+			KnowledgeBaseFormat:    format,
+			LLM:                    llmClient,
+			ExportIncludeGuide:     *exportIncludeGuide,
+			ExportFileTemplate:     *exportName,
+			TranscriptFileTemplate: *transcriptName,
+			CacheBriefs:            *cacheBriefs,
+			AutoBrief:              !*noAutoBrief,
+			Preview:                *preview,
+			Autosave:               *autosave,
+			ComposerCharLimit:      *composerLimit,
+			BriefStreamRate:        *briefStreamRate,
+			FetchTimeout:           *fetchTimeout,
+			QuestionTimeout:        *questionTimeout,
+			BriefSectionTimeout:    *briefTimeout,
+			BriefConcurrency:       *briefConcurrency,
+			MinTechnicalBullets:    *minTechnicalBullets,
+			Announcements:          announcements,
+			SnapshotFlushInterval:  *snapshotFlushInterval,
+			MaxTranscriptEntries:   *maxTranscriptEntries,
+			KeyBindings:            keyBindings,
+			Theme:                  theme,
+			TokenEstimator:         estimator,
+			InitialPaper:           flag.Arg(0),
+			Resume:                 *resume,
+			Offline:                *offline,
+			FromClipboard:          *fromClipboard,
+			ClipboardUnavailable:   tui.SystemClipboardUnavailable(),
 		}),
 		opts...,
 	)
@@ -52,3 +305,53 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// runPrefetch briefs every identifier in path headlessly and returns the process exit code.
+func runPrefetch(path string, concurrency int, cfg tui.Config) int {
+	if cfg.LLM == nil {
+		fmt.Println("prefetch requires an LLM")
+		return 1
+	}
+	ids, err := tui.LoadPrefetchIDs(path)
+	if err != nil {
+		fmt.Println("failed to read prefetch list:", err)
+		return 1
+	}
+	done, succeeded, skipped, failed := 0, 0, 0, 0
+	tui.Prefetch(context.Background(), tui.PrefetchConfig{
+		Config:      cfg,
+		Concurrency: concurrency,
+		Progress: func(result tui.PrefetchResult) {
+			done++
+			progress := fmt.Sprintf("[%d/%d]", done, len(ids))
+			switch {
+			case result.Err != nil:
+				failed++
+				fmt.Printf("%s failed  %s: %v\n", progress, result.ID, result.Err)
+			case result.Skipped:
+				skipped++
+				fmt.Printf("%s skipped %s (brief already stored)\n", progress, result.ID)
+			default:
+				succeeded++
+				fmt.Printf("%s briefed %s – %s\n", progress, result.ID, result.Title)
+			}
+		},
+	}, ids)
+	fmt.Printf("prefetch finished: %d succeeded, %d skipped, %d failed\n", succeeded, skipped, failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// headerFlags collects repeated -header values.
+type headerFlags []string
+
+func (h *headerFlags) String() string {
+	return strings.Join(*h, ", ")
+}
+
+func (h *headerFlags) Set(value string) error {
+	*h = append(*h, value)
+	return nil
+}
@@ -0,0 +1,199 @@
+package arxiv
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// FullTextSource selects where FetchPaper reads the paper body from.
+type FullTextSource string
+
+const (
+	// FullTextPDF extracts text from the arXiv PDF (the default).
+	FullTextPDF FullTextSource = "pdf"
+	// FullTextAr5iv reads the ar5iv HTML rendering, which keeps section headings and LaTeX, and falls
+	// back to the PDF when ar5iv has no usable page.
+	FullTextAr5iv FullTextSource = "ar5iv"
+)
+
+var (
+	fullTextSource = FullTextPDF
+	// ar5ivBaseURL is swapped out in tests.
+	ar5ivBaseURL = "https://ar5iv.org"
+)
+
+// ParseFullTextSource validates a -fulltext-source flag value.
+func ParseFullTextSource(value string) (FullTextSource, error) {
+	switch source := FullTextSource(strings.ToLower(strings.TrimSpace(value))); source {
+	case "", FullTextPDF:
+		return FullTextPDF, nil
+	case FullTextAr5iv:
+		return FullTextAr5iv, nil
+	default:
+		return "", fmt.Errorf("unknown full-text source %q (want pdf or ar5iv)", value)
+	}
+}
+
+// SetFullTextSource configures where FetchPaper reads full text from; main sets it once at startup.
+func SetFullTextSource(source FullTextSource) {
+	fullTextSource = source
+}
+
+// fetchFullText returns the paper body, its PDF sections, and its PDF page count. ar5iv text
+// reports zero pages since the scanned-PDF heuristic does not apply to HTML, and no sections since
+// its headings are already inline.
+func fetchFullText(ctx context.Context, id, pdfURL string) (string, []Section, int, error) {
+	if fullTextSource == FullTextAr5iv {
+		if text, err := fetchAr5ivText(ctx, id); err == nil && text != "" {
+			return text, nil, 0, nil
+		}
+	}
+	return fetchPDFText(ctx, pdfURL)
+}
+
+func fetchAr5ivText(ctx context.Context, id string) (string, error) {
+	cache, err := newDocumentCache(nil, ".html")
+	if err != nil {
+		return "", err
+	}
+	path, err := cache.Fetch(ctx, fmt.Sprintf("%s/abs/%s", ar5ivBaseURL, id))
+	if err != nil {
+		return "", err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return extractAr5ivText(file)
+}
+
+var (
+	// scriptOrStyle is stripped up front because script bodies routinely contain "<" that would
+	// otherwise be read as markup.
+	scriptOrStyle = regexp.MustCompile(`(?is)<(script|style)\b.*?</(script|style)\s*>`)
+	ar5ivSkipped  = map[string]bool{"head": true, "nav": true, "header": true, "footer": true, "button": true}
+	ar5ivBlocks   = map[string]bool{
+		"p": true, "div": true, "li": true, "figcaption": true, "caption": true, "tr": true,
+		"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "blockquote": true, "pre": true,
+	}
+)
+
+// extractAr5ivText flattens an ar5iv page into markdown-ish text: headings become "#"-prefixed lines
+// so section structure survives, math is replaced by its LaTeX alttext, and each paragraph or list
+// item becomes its own block.
+func extractAr5ivText(r io.Reader) (string, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	decoder := xml.NewDecoder(strings.NewReader(scriptOrStyle.ReplaceAllString(string(raw), "")))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var (
+		blocks    []string
+		current   strings.Builder
+		heading   string
+		skipDepth int
+		mathDepth int
+		parseErr  error
+	)
+	flush := func() {
+		text := strings.TrimSpace(extraneousWhitespace.ReplaceAllString(strings.ReplaceAll(current.String(), "\u00a0", " "), " "))
+		current.Reset()
+		if text == "" {
+			heading = ""
+			return
+		}
+		if heading != "" {
+			text = heading + " " + text
+			heading = ""
+		}
+		blocks = append(blocks, text)
+	}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				parseErr = err
+			}
+			break
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			switch {
+			case skipDepth > 0 || ar5ivSkipped[name] || hasClass(t, "ltx_page_header", "ltx_page_footer"):
+				skipDepth++
+			case mathDepth > 0 || name == "math":
+				if mathDepth == 0 {
+					if alt := attr(t, "alttext"); alt != "" {
+						current.WriteString(" $" + alt + "$ ")
+					}
+				}
+				mathDepth++
+			case ar5ivBlocks[name]:
+				flush()
+				if len(name) == 2 && name[0] == 'h' {
+					level := int(name[1] - '0')
+					if level > 3 {
+						level = 3
+					}
+					heading = strings.Repeat("#", level)
+				}
+			case name == "br":
+				current.WriteRune(' ')
+			}
+		case xml.EndElement:
+			name := strings.ToLower(t.Name.Local)
+			switch {
+			case skipDepth > 0:
+				skipDepth--
+			case mathDepth > 0:
+				mathDepth--
+			case ar5ivBlocks[name]:
+				flush()
+			}
+		case xml.CharData:
+			if skipDepth == 0 && mathDepth == 0 {
+				current.Write(t)
+			}
+		}
+	}
+	flush()
+	if len(blocks) == 0 {
+		if parseErr != nil {
+			return "", fmt.Errorf("failed to parse ar5iv html: %w", parseErr)
+		}
+		return "", errors.New("ar5iv page has no text")
+	}
+	return strings.Join(blocks, "\n\n"), nil
+}
+
+func attr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if strings.EqualFold(a.Name.Local, name) {
+			return strings.TrimSpace(a.Value)
+		}
+	}
+	return ""
+}
+
+func hasClass(el xml.StartElement, classes ...string) bool {
+	for _, class := range strings.Fields(attr(el, "class")) {
+		for _, want := range classes {
+			if class == want {
+				return true
+			}
+		}
+	}
+	return false
+}
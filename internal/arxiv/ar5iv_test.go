@@ -0,0 +1,89 @@
+package arxiv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestExtractAr5ivTextKeepsSectionStructure(t *testing.T) {
+	file, err := os.Open("testdata/ar5iv.html")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer file.Close()
+
+	got, err := extractAr5ivText(file)
+	if err != nil {
+		t.Fatalf("extractAr5ivText() error = %v", err)
+	}
+	for _, want := range []string{
+		"# Sparse Attention Revisited",
+		"### Abstract\n\nWe revisit sparse attention",
+		"## 1 Introduction\n\nAttention costs $O(n^{2})$ in sequence length [1]. We reduce it.",
+		"### 1.1 Contributions\n\nA block-sparse kernel.\n\nAn analysis of when sparsity hurts.",
+		"## References\n\n[1]A. Vaswani et al. Attention is all you need. 2017.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected extracted text to contain %q, got:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"Feedback", "LaTeXML", "About", "innerWidth", "semantics", "<mi>"} {
+		if strings.Contains(got, unwanted) {
+			t.Fatalf("expected %q to be dropped, got:\n%s", unwanted, got)
+		}
+	}
+}
+
+func TestFetchFullTextPrefersAr5ivAndFallsBack(t *testing.T) {
+	t.Setenv(cacheEnvVar, t.TempDir())
+	fixture, err := os.ReadFile("testdata/ar5iv.html")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/abs/2101.00001" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(fixture)
+	}))
+	defer server.Close()
+
+	originalBase, originalSource := ar5ivBaseURL, fullTextSource
+	ar5ivBaseURL = server.URL
+	SetFullTextSource(FullTextAr5iv)
+	t.Cleanup(func() {
+		ar5ivBaseURL = originalBase
+		SetFullTextSource(originalSource)
+	})
+
+	text, _, pages, err := fetchFullText(context.Background(), "2101.00001", server.URL+"/pdf/2101.00001.pdf")
+	if err != nil {
+		t.Fatalf("fetchFullText() error = %v", err)
+	}
+	if pages != 0 || !strings.Contains(text, "## 1 Introduction") {
+		t.Fatalf("expected ar5iv text, got %d pages and:\n%s", pages, text)
+	}
+
+	if _, _, _, err := fetchFullText(context.Background(), "2101.99999", server.URL+"/pdf/2101.99999.pdf"); err == nil || !strings.Contains(err.Error(), "pdf download failed") {
+		t.Fatalf("expected fallback to the PDF download, got %v", err)
+	}
+}
+
+func TestParseFullTextSource(t *testing.T) {
+	t.Parallel()
+
+	if source, err := ParseFullTextSource("AR5IV"); err != nil || source != FullTextAr5iv {
+		t.Fatalf("expected ar5iv, got %q (%v)", source, err)
+	}
+	if source, err := ParseFullTextSource(""); err != nil || source != FullTextPDF {
+		t.Fatalf("expected pdf default, got %q (%v)", source, err)
+	}
+	if _, err := ParseFullTextSource("latex"); err == nil {
+		t.Fatal("expected error for unknown source")
+	}
+}
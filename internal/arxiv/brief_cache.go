@@ -0,0 +1,72 @@
+package arxiv
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	briefSidecarSuffix = ".brief.json"
+	briefSidecarTTL    = 7 * 24 * time.Hour
+)
+
+// BriefSidecar stores generated reading-brief sections next to the cached PDF so reopening a paper
+// can skip the LLM entirely.
+type BriefSidecar struct {
+	PaperID   string    `json:"paperId"`
+	Summary   []string  `json:"summary,omitempty"`
+	Technical []string  `json:"technical,omitempty"`
+	DeepDive  []string  `json:"deepDive,omitempty"`
+	CachedAt  time.Time `json:"cachedAt"`
+}
+
+// Complete reports whether every brief section has content.
+func (s BriefSidecar) Complete() bool {
+	return len(s.Summary) > 0 && len(s.Technical) > 0 && len(s.DeepDive) > 0
+}
+
+// SaveBriefSidecar writes the brief sidecar for the paper into the PDF cache directory.
+func SaveBriefSidecar(sidecar BriefSidecar) error {
+	if sidecar.PaperID == "" {
+		return errors.New("brief sidecar requires a paper ID")
+	}
+	dir := cacheDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if sidecar.CachedAt.IsZero() {
+		sidecar.CachedAt = time.Now().UTC()
+	}
+	data, err := json.MarshalIndent(sidecar, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(briefSidecarPath(dir, sidecar.PaperID), data, 0o644)
+}
+
+// LoadBriefSidecar returns the cached brief for the paper when one exists, is complete, and is
+// younger than the sidecar TTL. Missing, stale, or corrupt sidecars report ok=false.
+func LoadBriefSidecar(paperID string) (BriefSidecar, bool) {
+	if paperID == "" {
+		return BriefSidecar{}, false
+	}
+	data, err := os.ReadFile(briefSidecarPath(cacheDir(), paperID))
+	if err != nil {
+		return BriefSidecar{}, false
+	}
+	var sidecar BriefSidecar
+	if err := json.Unmarshal(data, &sidecar); err != nil {
+		return BriefSidecar{}, false
+	}
+	if sidecar.PaperID != paperID || !sidecar.Complete() || time.Since(sidecar.CachedAt) > briefSidecarTTL {
+		return BriefSidecar{}, false
+	}
+	return sidecar, true
+}
+
+func briefSidecarPath(dir, paperID string) string {
+	return filepath.Join(dir, sanitizeKey(paperID)+briefSidecarSuffix)
+}
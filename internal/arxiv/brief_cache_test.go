@@ -0,0 +1,52 @@
+package arxiv
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBriefSidecarRoundTrip(t *testing.T) {
+	t.Setenv(cacheEnvVar, t.TempDir())
+
+	sidecar := BriefSidecar{
+		PaperID:   "2101.00001",
+		Summary:   []string{"summary"},
+		Technical: []string{"technical"},
+		DeepDive:  []string{"deep"},
+	}
+	if err := SaveBriefSidecar(sidecar); err != nil {
+		t.Fatalf("SaveBriefSidecar() error = %v", err)
+	}
+	got, ok := LoadBriefSidecar("2101.00001")
+	if !ok {
+		t.Fatal("expected fresh sidecar to load")
+	}
+	if got.Summary[0] != "summary" || got.DeepDive[0] != "deep" {
+		t.Fatalf("unexpected sidecar payload: %#v", got)
+	}
+}
+
+func TestBriefSidecarIgnoresStaleAndIncompleteEntries(t *testing.T) {
+	t.Setenv(cacheEnvVar, t.TempDir())
+
+	stale := BriefSidecar{
+		PaperID:   "stale",
+		Summary:   []string{"s"},
+		Technical: []string{"t"},
+		DeepDive:  []string{"d"},
+		CachedAt:  time.Now().Add(-briefSidecarTTL - time.Hour),
+	}
+	if err := SaveBriefSidecar(stale); err != nil {
+		t.Fatalf("SaveBriefSidecar() error = %v", err)
+	}
+	if _, ok := LoadBriefSidecar("stale"); ok {
+		t.Fatal("expected stale sidecar to be ignored")
+	}
+
+	if err := SaveBriefSidecar(BriefSidecar{PaperID: "partial", Summary: []string{"s"}}); err != nil {
+		t.Fatalf("SaveBriefSidecar() error = %v", err)
+	}
+	if _, ok := LoadBriefSidecar("partial"); ok {
+		t.Fatal("expected incomplete sidecar to be ignored")
+	}
+}
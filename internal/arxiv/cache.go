@@ -12,6 +12,9 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/csheth/browse/internal/httpheaders"
+	"github.com/csheth/browse/internal/httpx"
 )
 
 const (
@@ -23,9 +26,12 @@ const (
 	defaultHTTPTimeout = 90 * time.Second
 )
 
+// pdfCache keeps downloaded paper documents on disk. Despite the name it also stores ar5iv HTML;
+// ext selects the file extension so both formats share one directory and revalidation logic.
 type pdfCache struct {
 	dir    string
 	client *http.Client
+	ext    string
 }
 
 type pdfCacheMeta struct {
@@ -34,24 +40,136 @@ type pdfCacheMeta struct {
 	LastModified string    `json:"lastModified"`
 	CachedAt     time.Time `json:"cachedAt"`
 	Size         int64     `json:"size"`
+	// Paper is the arXiv metadata recorded after a successful fetch, which offline mode reads back.
+	Paper *cachedPaperMeta `json:"paper,omitempty"`
 }
 
 func newPDFCache(client *http.Client) (*pdfCache, error) {
-	dir := os.Getenv(cacheEnvVar)
-	if dir == "" {
-		base, err := os.UserCacheDir()
-		if err != nil {
-			base = filepath.Join(os.TempDir(), "paperscout-cache")
-		}
-		dir = filepath.Join(base, cacheSubdir)
-	}
+	return newDocumentCache(client, ".pdf")
+}
+
+func newDocumentCache(client *http.Client, ext string) (*pdfCache, error) {
+	dir := cacheDir()
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return nil, err
 	}
 	if client == nil {
-		client = &http.Client{Timeout: defaultHTTPTimeout}
+		client = httpx.NewClient(defaultHTTPTimeout)
+	}
+	return &pdfCache{dir: dir, client: client, ext: ext}, nil
+}
+
+// CacheDir returns the PDF cache directory the fetcher writes to.
+func CacheDir() string {
+	return cacheDir()
+}
+
+// CacheUsage reports how many PDFs are cached and their combined size in bytes. A cache directory
+// that does not exist yet counts as empty.
+func CacheUsage() (int, int64, error) {
+	entries, err := os.ReadDir(cacheDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+	count := 0
+	var size int64
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".pdf" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return 0, 0, err
+		}
+		count++
+		size += info.Size()
+	}
+	return count, size, nil
+}
+
+// clearableSuffixes are the file endings the document cache writes. ClearCache removes only these
+// so a PAPERSCOUT_CACHE_DIR shared with other files is left alone.
+var clearableSuffixes = []string{".pdf", ".html", metaSuffix, partialSuffix}
+
+// ClearCache deletes cached PDFs and ar5iv pages with their metadata and partial downloads, and
+// reports how many bytes were reclaimed. Other files in the directory, including stored brief
+// sidecars, are kept. A cache directory that does not exist yet frees nothing.
+func ClearCache() (int64, error) {
+	dir := cacheDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	var freed int64
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !hasClearableSuffix(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return freed, err
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return freed, err
+		}
+		freed += info.Size()
+	}
+	return freed, nil
+}
+
+func hasClearableSuffix(name string) bool {
+	for _, suffix := range clearableSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheDir resolves the PDF cache directory, honoring PAPERSCOUT_CACHE_DIR when set.
+func cacheDir() string {
+	if dir := os.Getenv(cacheEnvVar); dir != "" {
+		return dir
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = filepath.Join(os.TempDir(), "paperscout-cache")
+	}
+	return filepath.Join(base, cacheSubdir)
+}
+
+// CachedPDFPath returns where the PDF at pdfURL is cached and whether a non-empty copy is there. A
+// copy past the revalidation TTL still counts, since it is only read, not refreshed.
+func CachedPDFPath(pdfURL string) (string, bool) {
+	if strings.TrimSpace(pdfURL) == "" {
+		return "", false
+	}
+	cache := &pdfCache{dir: cacheDir(), ext: ".pdf"}
+	path, _, _ := cache.pathsFor(cacheKey(pdfURL))
+	info, err := os.Stat(path)
+	return path, err == nil && info.Size() > 0
+}
+
+// FetchPDF downloads the PDF at pdfURL into the cache (revalidating a stale copy) and returns its
+// path. Offline mode only hands back a copy that is already cached.
+func FetchPDF(ctx context.Context, pdfURL string) (string, error) {
+	if offline {
+		if path, ok := CachedPDFPath(pdfURL); ok {
+			return path, nil
+		}
+		return "", fmt.Errorf("offline mode: %s is not in the PDF cache; open it once while online", strings.TrimSpace(pdfURL))
+	}
+	cache, err := newPDFCache(nil)
+	if err != nil {
+		return "", err
 	}
-	return &pdfCache{dir: dir, client: client}, nil
+	return cache.Fetch(ctx, pdfURL)
 }
 
 func (c *pdfCache) Fetch(ctx context.Context, pdfURL string) (string, error) {
@@ -79,6 +197,7 @@ func (c *pdfCache) download(ctx context.Context, pdfURL, pdfPath, metaPath, part
 	if err != nil {
 		return "", err
 	}
+	httpheaders.Apply(req, requestHeaders)
 	if current != nil && current.Size() > 0 {
 		if meta.ETag != "" {
 			req.Header.Set("If-None-Match", meta.ETag)
@@ -99,7 +218,7 @@ func (c *pdfCache) download(ctx context.Context, pdfURL, pdfPath, metaPath, part
 		}
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := doWithRetry(ctx, c.client, req)
 	if err != nil {
 		return "", err
 	}
@@ -162,7 +281,13 @@ func (c *pdfCache) saveBody(resp *http.Response, pdfPath, metaPath, partialPath
 }
 
 func (c *pdfCache) pathsFor(key string) (string, string, string) {
-	return filepath.Join(c.dir, key+".pdf"), filepath.Join(c.dir, key+metaSuffix), filepath.Join(c.dir, key+partialSuffix)
+	// PDFs keep their original <key>.meta/<key>.part names so existing caches stay valid; other
+	// formats fold the extension in so they never share metadata with the PDF of the same paper.
+	base := key
+	if c.ext != ".pdf" {
+		base += c.ext
+	}
+	return filepath.Join(c.dir, key+c.ext), filepath.Join(c.dir, base+metaSuffix), filepath.Join(c.dir, base+partialSuffix)
 }
 
 func cacheKey(pdfURL string) string {
@@ -6,9 +6,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/csheth/browse/internal/httpheaders"
 )
 
 func TestPDFCacheReusesFreshFile(t *testing.T) {
@@ -140,6 +143,32 @@ func TestPDFCacheResumesPartialDownload(t *testing.T) {
 	}
 }
 
+func TestCachedPDFPathFindsFetchedPDF(t *testing.T) {
+	t.Setenv(cacheEnvVar, t.TempDir())
+	client, baseURL := newMockClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("%PDF-1.4\nHello"))
+	}))
+	pdfURL := baseURL + "/pdf/2101.00001.pdf"
+	if path, ok := CachedPDFPath(pdfURL); ok {
+		t.Fatalf("expected nothing cached yet, got %s", path)
+	}
+
+	cache, err := newPDFCache(client)
+	if err != nil {
+		t.Fatalf("newPDFCache: %v", err)
+	}
+	fetched, err := cache.Fetch(context.Background(), pdfURL)
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if path, ok := CachedPDFPath(pdfURL); !ok || path != fetched {
+		t.Fatalf("CachedPDFPath() = %q, %v; want %q", path, ok, fetched)
+	}
+	if _, ok := CachedPDFPath(""); ok {
+		t.Fatal("expected no cached PDF for an empty URL")
+	}
+}
+
 func TestCacheKeyFallsBackToHash(t *testing.T) {
 	t.Parallel()
 	key := cacheKey("https://example.com/foo.pdf")
@@ -151,6 +180,22 @@ func TestCacheKeyFallsBackToHash(t *testing.T) {
 	}
 }
 
+func TestCacheKeySeparatesVersions(t *testing.T) {
+	t.Parallel()
+	keys := map[string]string{}
+	for _, pdfURL := range []string{
+		"https://arxiv.org/pdf/2308.01234.pdf",
+		"https://arxiv.org/pdf/2308.01234v1.pdf",
+		"https://arxiv.org/pdf/2308.01234v2.pdf",
+	} {
+		key := cacheKey(pdfURL)
+		if other, ok := keys[key]; ok {
+			t.Fatalf("%s and %s share cache key %q", other, pdfURL, key)
+		}
+		keys[key] = pdfURL
+	}
+}
+
 type roundTripperFunc func(*http.Request) (*http.Response, error)
 
 func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
@@ -166,3 +211,94 @@ func newMockClient(handler http.HandlerFunc) (*http.Client, string) {
 		}),
 	}, "http://example.com"
 }
+
+func TestPDFCacheDownloadSendsUserAgent(t *testing.T) {
+	t.Setenv(cacheEnvVar, t.TempDir())
+	SetRequestHeaders(httpheaders.Config{UserAgent: "paperscout-test/1.0"})
+	t.Cleanup(func() { SetRequestHeaders(httpheaders.Config{}) })
+
+	var userAgent string
+	client, baseURL := newMockClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		_, _ = w.Write([]byte("%PDF-1.4\nHello"))
+	}))
+	cache, err := newPDFCache(client)
+	if err != nil {
+		t.Fatalf("newPDFCache: %v", err)
+	}
+	if _, err := cache.Fetch(context.Background(), baseURL+"/pdf/2101.00002.pdf"); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if userAgent != "paperscout-test/1.0" {
+		t.Fatalf("expected user agent on PDF download, got %q", userAgent)
+	}
+}
+
+func TestCacheUsageCountsPDFs(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(cacheEnvVar, dir)
+	for name, body := range map[string]string{
+		"2101.00001.pdf":  "12345",
+		"2101.00002.pdf":  "123",
+		"2101.00002.meta": "{}",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+	count, size, err := CacheUsage()
+	if err != nil {
+		t.Fatalf("CacheUsage() error = %v", err)
+	}
+	if count != 2 || size != 8 {
+		t.Fatalf("expected 2 PDFs totalling 8 bytes, got %d (%d bytes)", count, size)
+	}
+
+	t.Setenv(cacheEnvVar, filepath.Join(dir, "missing"))
+	if count, size, err := CacheUsage(); err != nil || count != 0 || size != 0 {
+		t.Fatalf("expected empty usage for missing dir, got %d %d %v", count, size, err)
+	}
+}
+
+func TestClearCacheRemovesOnlyCacheFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(cacheEnvVar, dir)
+	files := map[string]string{
+		"2101.00001.pdf":           "12345",
+		"2101.00001.meta":          "{}",
+		"2101.00002.part":          "123",
+		"2101.00003.html":          "<p>",
+		"2101.00001.brief.json":    "{}",
+		"notes.txt":                "keep me",
+		"pdf-not-a-suffix.pdf.bak": "keep me too",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	freed, err := ClearCache()
+	if err != nil {
+		t.Fatalf("ClearCache() error = %v", err)
+	}
+	if freed != 13 {
+		t.Fatalf("expected 13 bytes freed, got %d", freed)
+	}
+	remaining, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range remaining {
+		names = append(names, entry.Name())
+	}
+	if len(names) != 3 {
+		t.Fatalf("expected the brief sidecar and unrelated files kept, got %v", names)
+	}
+
+	t.Setenv(cacheEnvVar, filepath.Join(dir, "missing"))
+	if freed, err := ClearCache(); err != nil || freed != 0 {
+		t.Fatalf("expected nothing freed for a missing dir, got %d %v", freed, err)
+	}
+}
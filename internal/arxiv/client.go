@@ -11,44 +11,200 @@ import (
 	"sort"
 	"strings"
 	"time"
-	"unicode"
-	"unicode/utf8"
 
 	"github.com/ledongthuc/pdf"
+
+	"github.com/csheth/browse/internal/httpheaders"
+	"github.com/csheth/browse/internal/httpx"
+	"github.com/csheth/browse/internal/sentences"
 )
 
-// Paper represents a subset of metadata returned by the arXiv API.
+// Paper represents a subset of metadata returned by the arXiv API, or by the bioRxiv/medRxiv and
+// OpenReview APIs for papers from those sites.
 type Paper struct {
-	ID               string
+	// ID is the arXiv identifier, the 10.1101 DOI for bioRxiv and medRxiv preprints, or the forum id
+	// for OpenReview papers.
+	ID string
+	// Version is the paper version, e.g. "v2": the one requested when the identifier names it (ID
+	// then ends with it), otherwise the latest version the API reported. It is empty when neither
+	// said.
+	Version string
+	// Source is the preprint server; empty means arXiv.
+	Source           Source
 	Title            string
 	Authors          []string
 	Abstract         string
 	Subjects         []string
+	PrimaryCategory  string
 	KeyContributions []string
-	PDFURL           string
-	FullText         string
+	// References holds the numbered bibliography parsed from the PDF; References[i] is entry [i+1].
+	References []string
+	PDFURL     string
+	FullText   string
+	// Sections splits the PDF text at detected headings ("1 Introduction", "References"). It is empty
+	// when no headings were found or the text came from ar5iv; FullText always holds the whole body.
+	Sections []Section
+	// ScannedPDF marks image-only PDFs whose text layer was (nearly) empty; FullText is cleared so
+	// downstream LLM calls skip the junk rather than summarizing watermarks.
+	ScannedPDF bool
+	// Withdrawn marks entries whose metadata says the paper was withdrawn. The PDF is not
+	// downloaded, so FullText stays empty.
+	Withdrawn bool
+	// Restored marks a paper rebuilt from saved conversation history because fetching it failed.
+	// Only ID and Title are set.
+	Restored bool
+	// PDFUnavailable marks a paper whose metadata loaded but whose PDF could not be fetched, so
+	// FullText is empty. FetchPaper reports it with ErrPDFUnavailable alongside the paper.
+	PDFUnavailable bool
+	// Preview marks a paper loaded by FetchMetadata whose full text has not been fetched yet;
+	// FetchFullText clears it.
+	Preview bool
+	// CitationCount and RelatedPaperIDs (arXiv identifiers) come from Semantic Scholar when
+	// enrichment is on; both stay zero-valued when it is off or the lookup fails.
+	CitationCount   int
+	RelatedPaperIDs []string
+}
+
+// ErrPDFUnavailable is returned together with a metadata-only Paper when the PDF download or
+// extraction fails, for example because a withdrawn paper no longer serves one.
+var ErrPDFUnavailable = errors.New("paper PDF unavailable")
+
+// ScannedPDFNotice explains why a scanned PDF has no text to brief.
+const ScannedPDFNotice = "This appears to be a scanned PDF; text extraction failed — OCR not supported."
+
+// WithdrawnNotice explains why a withdrawn paper has no text to brief.
+const WithdrawnNotice = "This paper has been withdrawn on arXiv; there is no PDF text to brief."
+
+// PDFUnavailableNotice explains why a paper opened from its metadata alone has no text to brief.
+const PDFUnavailableNotice = "The PDF could not be downloaded, so only the metadata is shown; the brief falls back to bullets from the abstract."
+
+// RestoredNotice explains why a paper restored from history has no text to brief or question.
+const RestoredNotice = "Restored from saved history because the paper could not be fetched; questions and new brief sections need the paper text, so reload it once it is reachable."
+
+// PreviewNotice explains why a paper opened as an abstract-only preview has no text to brief.
+const PreviewNotice = "Only the abstract is loaded for this preview; load the full text to build the brief or ask questions."
+
+// Notice returns the reason the paper has no usable text, or "" when nothing is wrong with it.
+func (p *Paper) Notice() string {
+	switch {
+	case p.Restored:
+		return RestoredNotice
+	case p.Withdrawn:
+		return WithdrawnNotice
+	case p.PDFUnavailable:
+		return PDFUnavailableNotice
+	case p.ScannedPDF:
+		return ScannedPDFNotice
+	case p.Preview:
+		return PreviewNotice
+	default:
+		return ""
+	}
+}
+
+// minCharsPerPage is the extracted-text density below which a PDF is treated as image-only. Real
+// papers yield thousands of characters per page; scans usually only carry an arXiv stamp.
+const minCharsPerPage = 200
+
+// requestHeaders is applied to every arXiv API and PDF request; main configures it once at startup.
+var requestHeaders httpheaders.Config
+
+// SetRequestHeaders configures the User-Agent and extra headers sent with arXiv and PDF requests.
+func SetRequestHeaders(cfg httpheaders.Config) {
+	requestHeaders = cfg
 }
 
 var (
-	idRegexp             = regexp.MustCompile(`(?i)arxiv\.org/(?:abs|pdf)/([0-9a-z.\-]+)(?:\.pdf)?`)
+	idRegexp             = regexp.MustCompile(`(?i)ar(?:xiv|5iv)(?:\.labs\.arxiv)?\.org/(?:abs|pdf)/([0-9a-z.\-]+)(?:\.pdf)?`)
 	extraneousWhitespace = regexp.MustCompile(`\s+`)
+	modernIDRegexp       = regexp.MustCompile(`^\d{4}\.\d{4,5}(?:v\d+)?$`)
+	withdrawnRegexp      = regexp.MustCompile(`(?i)\b(?:paper|article|submission|manuscript) (?:has been|was|is) withdrawn\b`)
+
+	// pdfBaseURL and apiBaseURL are swapped out in tests.
+	pdfBaseURL = "https://arxiv.org"
+	apiBaseURL = "https://export.arxiv.org"
 )
 
-// FetchPaper fetches metadata for a given arXiv URL or identifier and derives key contributions.
+// FetchPaper fetches metadata for a given arXiv URL or identifier and derives key contributions,
+// then downloads the full text (see FetchMetadata and FetchFullText). An arXiv paper whose PDF
+// cannot be fetched is still returned, together with an error wrapping ErrPDFUnavailable.
 func FetchPaper(ctx context.Context, input string) (*Paper, error) {
-	id := extractIdentifier(input)
-	if id == "" {
+	paper, err := FetchMetadata(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := FetchFullText(ctx, paper); err != nil {
+		if errors.Is(err, ErrPDFUnavailable) {
+			return paper, err
+		}
+		return nil, err
+	}
+	return paper, nil
+}
+
+// FetchMetadata fetches only the metadata (title, authors, abstract, subjects) for input, skipping
+// the slow PDF download; the paper is marked Preview until FetchFullText loads its text. bioRxiv and
+// medRxiv content URLs are fetched from their own API instead, and OpenReview forum or PDF URLs from
+// the OpenReview API once the input is known not to be an arXiv identifier. Offline, the paper is
+// read from the PDF cache in full, since that needs no network.
+func FetchMetadata(ctx context.Context, input string) (*Paper, error) {
+	if offline {
+		return fetchCachedPaper(input)
+	}
+	var (
+		paper *Paper
+		err   error
+	)
+	if source, doi, version := detectSource(input); doi != "" {
+		paper, err = fetchRxivMetadata(ctx, source, doi, version)
+	} else if id := extractIdentifier(input); id != "" {
+		paper, err = fetchArxivMetadata(ctx, id)
+	} else if forumID := detectOpenReview(input); forumID != "" {
+		paper, err = fetchOpenReviewMetadata(ctx, forumID)
+	} else {
 		return nil, fmt.Errorf("unable to extract arXiv identifier from %q", input)
 	}
+	if err != nil {
+		return nil, err
+	}
+	paper.Preview = !paper.Withdrawn
+	// Withdrawn papers never reach FetchFullText, so their enrichment runs here.
+	if paper.Withdrawn && enrich {
+		enrichFromSemanticScholar(ctx, paper)
+	}
+	return paper, nil
+}
+
+// FetchFullText downloads and extracts the text of a paper returned by FetchMetadata, filling
+// FullText, Sections, and References and clearing Preview; papers that are not previews are left
+// alone. For arXiv papers it also runs the Semantic Scholar enrichment when that is on, and a PDF
+// that cannot be fetched marks the paper PDFUnavailable and returns an error wrapping
+// ErrPDFUnavailable. Other failures leave the paper a preview.
+func FetchFullText(ctx context.Context, paper *Paper) error {
+	if paper == nil || !paper.Preview {
+		return nil
+	}
+	switch paper.Source {
+	case "", SourceArxiv:
+		err := fetchArxivFullText(ctx, paper)
+		if enrich && ctx.Err() == nil {
+			enrichFromSemanticScholar(ctx, paper)
+		}
+		return err
+	default:
+		return fetchPDFFullText(ctx, paper)
+	}
+}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	url := fmt.Sprintf("https://export.arxiv.org/api/query?id_list=%s", id)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// fetchArxivMetadata requests the API entry for id and builds its metadata-only Paper.
+func fetchArxivMetadata(ctx context.Context, id string) (*Paper, error) {
+	client := httpx.NewClient(10 * time.Second)
+	req, err := newAPIRequest(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.Do(req)
+	resp, err := doWithRetry(ctx, client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +222,12 @@ func FetchPaper(ctx context.Context, input string) (*Paper, error) {
 	if entry == nil {
 		return nil, errors.New("paper not found")
 	}
+	return paperFromEntry(id, entry), nil
+}
 
+// paperFromEntry builds the metadata-only Paper for a decoded API entry, marking it withdrawn when
+// the entry says so.
+func paperFromEntry(id string, entry *apiEntry) *Paper {
 	authors := make([]string, 0, len(entry.Authors))
 	for _, a := range entry.Authors {
 		authors = append(authors, strings.TrimSpace(a.Name))
@@ -75,27 +236,113 @@ func FetchPaper(ctx context.Context, input string) (*Paper, error) {
 	abstract := normalizeWhitespace(entry.Summary)
 	contributions := extractKeyContributions(abstract)
 
-	subjects := make([]string, 0, len(entry.Categories))
-	for _, cat := range entry.Categories {
-		subjects = append(subjects, strings.TrimSpace(cat.Term))
-	}
+	primary, subjects := entrySubjects(entry)
 
-	pdfURL := fmt.Sprintf("https://arxiv.org/pdf/%s.pdf", id)
-	fullText, err := fetchPDFText(ctx, pdfURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to process paper PDF: %w", err)
-	}
-
-	return &Paper{
+	pdfURL := fmt.Sprintf("%s/pdf/%s.pdf", pdfBaseURL, id)
+	paper := &Paper{
 		ID:               id,
 		Title:            normalizeWhitespace(entry.Title),
 		Authors:          authors,
 		Abstract:         abstract,
 		Subjects:         subjects,
+		PrimaryCategory:  primary,
 		KeyContributions: contributions,
 		PDFURL:           pdfURL,
-		FullText:         fullText,
-	}, nil
+		Withdrawn:        entryWithdrawn(entry),
+		Version:          entryVersion(id, entry),
+	}
+	return paper
+}
+
+// VersionSuffix returns the version an arXiv identifier pins, e.g. "v2" for "2308.01234v2", or ""
+// for an unversioned identifier.
+func VersionSuffix(id string) string {
+	return arxivVersionRegexp.FindString(strings.TrimSpace(id))
+}
+
+// entryVersion is the version id requests, or else the latest one, which the API reports in the
+// entry's abs URL ("http://arxiv.org/abs/2101.00001v3").
+func entryVersion(id string, entry *apiEntry) string {
+	if version := VersionSuffix(id); version != "" {
+		return version
+	}
+	return VersionSuffix(entry.ID)
+}
+
+// fetchArxivFullText downloads the text of an arXiv preview. When the PDF cannot be fetched the
+// paper stays usable from its metadata and the error wraps ErrPDFUnavailable; only cancellation
+// fails outright.
+func fetchArxivFullText(ctx context.Context, paper *Paper) error {
+	fullText, sections, pages, err := fetchFullText(ctx, paper.ID, paper.PDFURL)
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to process paper PDF: %w", err)
+		}
+		paper.Preview = false
+		paper.PDFUnavailable = true
+		return fmt.Errorf("%w: %v", ErrPDFUnavailable, err)
+	}
+	setFullText(paper, fullText, sections, pages)
+	rememberPaperMetadata(paper)
+	return nil
+}
+
+// fetchPDFFullText downloads the text of a bioRxiv, medRxiv, or OpenReview preview, which the PDF
+// cache and extractor handle exactly like arXiv PDFs.
+func fetchPDFFullText(ctx context.Context, paper *Paper) error {
+	fullText, sections, pages, err := fetchPDFText(ctx, paper.PDFURL)
+	if err != nil {
+		return fmt.Errorf("failed to process paper PDF: %w", err)
+	}
+	setFullText(paper, fullText, sections, pages)
+	return nil
+}
+
+// setFullText stores extracted text on paper, clearing it when the PDF looks scanned, and ends
+// the preview.
+func setFullText(paper *Paper, fullText string, sections []Section, pages int) {
+	paper.ScannedPDF = looksScanned(fullText, pages)
+	if paper.ScannedPDF {
+		fullText, sections = "", nil
+	}
+	paper.FullText = fullText
+	paper.Sections = sections
+	paper.References = extractReferences(fullText)
+	paper.Preview = false
+}
+
+// entryWithdrawn reports whether the entry carries arXiv's withdrawal marker. Withdrawn versions
+// replace the abstract (or add a comment) with "This paper has been withdrawn …", and some titles
+// are prefixed with "[Withdrawn]".
+func entryWithdrawn(entry *apiEntry) bool {
+	title := strings.ToLower(strings.TrimLeft(strings.TrimSpace(entry.Title), "[("))
+	if strings.HasPrefix(title, "withdrawn") {
+		return true
+	}
+	return withdrawnRegexp.MatchString(normalizeWhitespace(entry.Summary)) ||
+		withdrawnRegexp.MatchString(normalizeWhitespace(entry.Comment))
+}
+
+func newAPIRequest(ctx context.Context, id string) (*http.Request, error) {
+	url := fmt.Sprintf("%s/api/query?id_list=%s", apiBaseURL, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpheaders.Apply(req, requestHeaders)
+	return req, nil
+}
+
+// Identifier returns the identifier FetchPaper would request for input (an arXiv id, or the DOI of a
+// bioRxiv/medRxiv URL, or the forum id of an OpenReview URL), or "" when input names no paper.
+func Identifier(input string) string {
+	if _, doi, _ := detectSource(input); doi != "" {
+		return doi
+	}
+	if id := extractIdentifier(input); id != "" {
+		return id
+	}
+	return detectOpenReview(input)
 }
 
 func extractIdentifier(input string) string {
@@ -121,16 +368,31 @@ func extractIdentifier(input string) string {
 	return ""
 }
 
+// IdentifierFromText finds an arXiv identifier in free-form text such as clipboard contents. Unlike
+// the fetch path, which accepts any id-shaped token typed on purpose, it only returns well-formed
+// YYMM.NNNNN identifiers so stray words are never mistaken for papers.
+func IdentifierFromText(text string) string {
+	for _, field := range strings.Fields(text) {
+		field = strings.Trim(field, "<>()[]{}\"'`,;")
+		if id := extractIdentifier(field); modernIDRegexp.MatchString(id) {
+			return id
+		}
+	}
+	return ""
+}
+
 type apiFeed struct {
 	Entries []apiEntry `xml:"entry"`
 }
 
 type apiEntry struct {
-	ID         string        `xml:"id"`
-	Title      string        `xml:"title"`
-	Summary    string        `xml:"summary"`
-	Authors    []apiAuthor   `xml:"author"`
-	Categories []apiCategory `xml:"category"`
+	ID              string        `xml:"id"`
+	Title           string        `xml:"title"`
+	Summary         string        `xml:"summary"`
+	Comment         string        `xml:"http://arxiv.org/schemas/atom comment"`
+	Authors         []apiAuthor   `xml:"author"`
+	Categories      []apiCategory `xml:"category"`
+	PrimaryCategory apiCategory   `xml:"http://arxiv.org/schemas/atom primary_category"`
 }
 
 type apiAuthor struct {
@@ -152,6 +414,32 @@ func decodeEntry(reader io.Reader) (*apiEntry, error) {
 	return &feed.Entries[0], nil
 }
 
+// entrySubjects returns the primary category plus the deduplicated category terms. The primary
+// category leads the list so callers that only show the first few subjects always include it; when
+// the feed omits <arxiv:primary_category> the first listed category is treated as primary.
+func entrySubjects(entry *apiEntry) (string, []string) {
+	primary := strings.TrimSpace(entry.PrimaryCategory.Term)
+	seen := map[string]bool{}
+	subjects := make([]string, 0, len(entry.Categories)+1)
+	add := func(term string) {
+		term = strings.TrimSpace(term)
+		key := strings.ToLower(term)
+		if term == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		subjects = append(subjects, term)
+	}
+	add(primary)
+	for _, cat := range entry.Categories {
+		add(cat.Term)
+	}
+	if primary == "" && len(subjects) > 0 {
+		primary = subjects[0]
+	}
+	return primary, subjects
+}
+
 func normalizeWhitespace(s string) string {
 	s = strings.ReplaceAll(s, "\n", " ")
 	s = strings.ReplaceAll(s, "\t", " ")
@@ -256,64 +544,47 @@ func containsString(haystack []string, needle string) bool {
 }
 
 func splitSentences(text string) []string {
-	text = strings.TrimSpace(text)
-	if text == "" {
-		return nil
-	}
-	var sentences []string
-	start := 0
-	for idx, r := range text {
-		if r == '.' || r == '!' || r == '?' {
-			end := idx + utf8.RuneLen(r)
-			segment := strings.TrimSpace(text[start:end])
-			if segment != "" {
-				sentences = append(sentences, segment)
-			}
-			start = end
-			for start < len(text) {
-				nextRune, size := utf8.DecodeRuneInString(text[start:])
-				if unicode.IsSpace(nextRune) {
-					start += size
-					continue
-				}
-				break
-			}
-		}
-	}
-	if start < len(text) {
-		if segment := strings.TrimSpace(text[start:]); segment != "" {
-			sentences = append(sentences, segment)
-		}
-	}
-	return sentences
+	return sentences.Split(text)
 }
 
-func fetchPDFText(ctx context.Context, pdfURL string) (string, error) {
-	cache, err := newPDFCache(nil)
-	if err != nil {
-		return "", err
+// looksScanned reports whether the extracted text is too sparse for the page count to be a real
+// text layer.
+func looksScanned(text string, pages int) bool {
+	if pages <= 0 {
+		return strings.TrimSpace(text) == ""
 	}
-	path, err := cache.Fetch(ctx, pdfURL)
+	return len([]rune(strings.TrimSpace(text))) < pages*minCharsPerPage
+}
+
+// fetchPDFText returns the whitespace-normalized PDF text, its sections (split before the line
+// breaks are collapsed), and the page count.
+func fetchPDFText(ctx context.Context, pdfURL string) (string, []Section, int, error) {
+	path, err := FetchPDF(ctx, pdfURL)
 	if err != nil {
-		return "", err
+		return "", nil, 0, err
 	}
+	return readPDFText(path)
+}
 
+// readPDFText extracts the text, sections, and page count of the PDF at path.
+func readPDFText(path string) (string, []Section, int, error) {
 	file, reader, err := pdf.Open(path)
 	if err != nil {
-		return "", fmt.Errorf("failed to open pdf: %w", err)
+		return "", nil, 0, fmt.Errorf("failed to open pdf: %w", err)
 	}
 	defer file.Close()
 
 	content, err := reader.GetPlainText()
 	if err != nil {
-		return "", fmt.Errorf("failed to extract pdf text: %w", err)
+		return "", nil, 0, fmt.Errorf("failed to extract pdf text: %w", err)
 	}
 
 	var builder strings.Builder
 	if _, err := io.Copy(&builder, content); err != nil {
-		return "", err
+		return "", nil, 0, err
 	}
 
-	fullText := extraneousWhitespace.ReplaceAllString(builder.String(), " ")
-	return strings.TrimSpace(fullText), nil
+	raw := builder.String()
+	fullText := extraneousWhitespace.ReplaceAllString(raw, " ")
+	return strings.TrimSpace(fullText), splitSections(raw), reader.NumPage(), nil
 }
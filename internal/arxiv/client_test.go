@@ -1,8 +1,16 @@
 package arxiv
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"strings"
+	"sync/atomic"
 	"testing"
+
+	"github.com/csheth/browse/internal/httpheaders"
 )
 
 func TestExtractIdentifier(t *testing.T) {
@@ -33,6 +41,54 @@ func TestExtractIdentifier(t *testing.T) {
 	}
 }
 
+func TestPaperVersionFromIdentifierOrLatestEntry(t *testing.T) {
+	latest := &apiEntry{ID: "http://arxiv.org/abs/2308.01234v3"}
+	tests := []struct {
+		name  string
+		input string
+		entry *apiEntry
+		want  string
+	}{
+		{"pinned bare id", "2308.01234v2", latest, "v2"},
+		{"pinned pdf url", "https://arxiv.org/pdf/2308.01234v1.pdf", latest, "v1"},
+		{"unversioned takes latest", "https://arxiv.org/abs/2308.01234", latest, "v3"},
+		{"unversioned without entry version", "2308.01234", &apiEntry{ID: "http://arxiv.org/abs/2308.01234"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := extractIdentifier(tt.input)
+			paper := paperFromEntry(id, tt.entry)
+			if paper.Version != tt.want {
+				t.Fatalf("Version for %q = %q, want %q", tt.input, paper.Version, tt.want)
+			}
+			if paper.ID != id {
+				t.Fatalf("expected ID %q to keep the requested form, got %q", id, paper.ID)
+			}
+		})
+	}
+}
+
+func TestIdentifierFromText(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"https://arxiv.org/abs/2101.00001v3", "2101.00001v3"},
+		{"see <https://arxiv.org/pdf/2205.12345.pdf> for details", "2205.12345"},
+		{"arXiv:2308.01234", "2308.01234"},
+		{"hello", ""},
+		{"https://example.com/2101.00001", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := IdentifierFromText(tt.in); got != tt.want {
+			t.Fatalf("IdentifierFromText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
 func TestExtractKeyContributionsPrefersKeywordSentences(t *testing.T) {
 	t.Parallel()
 
@@ -83,3 +139,392 @@ func contains(slice []string, needle string) bool {
 	}
 	return false
 }
+
+func TestDecodeEntryDedupesCategoriesAndFindsPrimary(t *testing.T) {
+	t.Parallel()
+
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:arxiv="http://arxiv.org/schemas/atom">
+  <entry>
+    <id>http://arxiv.org/abs/2101.00001v1</id>
+    <title>Sample</title>
+    <summary>Abstract.</summary>
+    <arxiv:primary_category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.AI" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.AI" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="stat.ML" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+</feed>`
+
+	entry, err := decodeEntry(strings.NewReader(feed))
+	if err != nil {
+		t.Fatalf("decodeEntry() error = %v", err)
+	}
+	primary, subjects := entrySubjects(entry)
+	if primary != "cs.LG" {
+		t.Fatalf("expected primary cs.LG, got %q", primary)
+	}
+	want := []string{"cs.LG", "cs.AI", "stat.ML"}
+	if strings.Join(subjects, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected subjects %v, got %v", want, subjects)
+	}
+}
+
+func TestEntrySubjectsFallsBackToFirstCategory(t *testing.T) {
+	t.Parallel()
+
+	entry := &apiEntry{Categories: []apiCategory{{Term: " math.OC "}, {Term: "math.OC"}}}
+	primary, subjects := entrySubjects(entry)
+	if primary != "math.OC" || len(subjects) != 1 {
+		t.Fatalf("unexpected primary %q subjects %v", primary, subjects)
+	}
+}
+
+func TestAPIRequestCarriesUserAgent(t *testing.T) {
+	SetRequestHeaders(httpheaders.Config{UserAgent: "paperscout-test/1.0", Extra: http.Header{"X-Trace": {"abc"}}})
+	t.Cleanup(func() { SetRequestHeaders(httpheaders.Config{}) })
+
+	req, err := newAPIRequest(context.Background(), "2101.00001")
+	if err != nil {
+		t.Fatalf("newAPIRequest() error = %v", err)
+	}
+	if got := req.Header.Get("User-Agent"); got != "paperscout-test/1.0" {
+		t.Fatalf("expected configured user agent, got %q", got)
+	}
+	if got := req.Header.Get("X-Trace"); got != "abc" {
+		t.Fatalf("expected extra header, got %q", got)
+	}
+}
+
+func TestLooksScannedFlagsSparseExtraction(t *testing.T) {
+	t.Parallel()
+
+	if !looksScanned("arXiv:0801.0001v1 [math.AG] 1 Jan 2008", 12) {
+		t.Fatal("expected a watermark-only extraction to be treated as scanned")
+	}
+	if !looksScanned("", 0) {
+		t.Fatal("expected empty extraction without pages to be treated as scanned")
+	}
+	if looksScanned(strings.Repeat("word ", 500), 2) {
+		t.Fatal("expected dense text not to be flagged")
+	}
+}
+
+func TestWithdrawnEntrySkipsPDFDownload(t *testing.T) {
+	t.Setenv(cacheEnvVar, t.TempDir())
+	feed, err := os.ReadFile("testdata/withdrawn.xml")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var pdfRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/query" {
+			_, _ = w.Write(feed)
+			return
+		}
+		atomic.AddInt32(&pdfRequests, 1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	originalPDF, originalAPI := pdfBaseURL, apiBaseURL
+	pdfBaseURL, apiBaseURL = server.URL, server.URL
+	t.Cleanup(func() { pdfBaseURL, apiBaseURL = originalPDF, originalAPI })
+
+	paper, err := FetchPaper(context.Background(), "1501.00001")
+	if err != nil {
+		t.Fatalf("FetchPaper() error = %v", err)
+	}
+	if !paper.Withdrawn || paper.Notice() != WithdrawnNotice {
+		t.Fatalf("expected withdrawn notice, got withdrawn=%v notice=%q", paper.Withdrawn, paper.Notice())
+	}
+	if paper.FullText != "" {
+		t.Fatalf("expected no full text, got %q", paper.FullText)
+	}
+	if got := atomic.LoadInt32(&pdfRequests); got != 0 {
+		t.Fatalf("expected no PDF request, got %d", got)
+	}
+	if paper.Title != "A Flawed Bound on Sparse Recovery" || paper.PrimaryCategory != "math.ST" {
+		t.Fatalf("expected metadata to survive, got %+v", paper)
+	}
+}
+
+func TestDetectSourceRecognizesBiorxivAndMedrxiv(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in      string
+		source  Source
+		doi     string
+		version string
+	}{
+		{"https://www.biorxiv.org/content/10.1101/2020.03.01.972133v2.full.pdf", SourceBiorxiv, "10.1101/2020.03.01.972133", "2"},
+		{"https://www.medrxiv.org/content/10.1101/2021.06.10.21258692v1", SourceMedrxiv, "10.1101/2021.06.10.21258692", "1"},
+		{"biorxiv.org/content/10.1101/123456", SourceBiorxiv, "10.1101/123456", ""},
+		{"https://arxiv.org/abs/2101.00001", SourceArxiv, "", ""},
+	}
+	for _, tt := range tests {
+		source, doi, version := detectSource(tt.in)
+		if source != tt.source || doi != tt.doi || version != tt.version {
+			t.Fatalf("detectSource(%q) = %q, %q, %q; want %q, %q, %q", tt.in, source, doi, version, tt.source, tt.doi, tt.version)
+		}
+	}
+	if got := Identifier("https://www.medrxiv.org/content/10.1101/2021.06.10.21258692v1"); got != "10.1101/2021.06.10.21258692" {
+		t.Fatalf("expected Identifier to accept medRxiv URLs, got %q", got)
+	}
+}
+
+func TestFetchRxivMetadataUsesRequestedVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/details/biorxiv/10.1101/2020.03.01.972133":
+			_, _ = w.Write([]byte(`{"collection":[
+				{"doi":"10.1101/2020.03.01.972133","title":"Draft title","authors":"Doe, J.; Roe, R.","abstract":"Old.","category":"genomics","version":"1"},
+				{"doi":"10.1101/2020.03.01.972133","title":"Cell atlas\n of the fly","authors":"Doe, J.; Roe, R.; ","abstract":"We present a single-cell atlas of the fly brain.","category":"neuroscience","version":"2"}]}`))
+		case "/details/medrxiv/10.1101/2021.06.10.21258692":
+			_, _ = w.Write([]byte(`{"collection":[{"doi":"10.1101/2021.06.10.21258692","title":"Vaccine cohort","authors":"Lee, K.","abstract":"We follow a vaccine cohort.","category":"epidemiology","version":"3"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	originalAPI, originalSites := rxivAPIBaseURL, rxivSiteBaseURLs
+	rxivAPIBaseURL = server.URL
+	rxivSiteBaseURLs = map[Source]string{SourceBiorxiv: "https://bio.test", SourceMedrxiv: "https://med.test"}
+	t.Cleanup(func() {
+		rxivAPIBaseURL, rxivSiteBaseURLs = originalAPI, originalSites
+	})
+
+	source, doi, version := detectSource("https://www.biorxiv.org/content/10.1101/2020.03.01.972133v2")
+	paper, err := fetchRxivMetadata(context.Background(), source, doi, version)
+	if err != nil {
+		t.Fatalf("fetchRxivMetadata() error = %v", err)
+	}
+	if paper.Title != "Cell atlas of the fly" || paper.PrimaryCategory != "neuroscience" || len(paper.Authors) != 2 {
+		t.Fatalf("unexpected bioRxiv metadata %+v", paper)
+	}
+	if paper.Version != "v2" || paper.PDFURL != "https://bio.test/content/10.1101/2020.03.01.972133v2.full.pdf" {
+		t.Fatalf("unexpected PDF URL %q", paper.PDFURL)
+	}
+	if paper.SourceName() != "bioRxiv" || paper.AbsURL() != "https://bio.test/content/10.1101/2020.03.01.972133" {
+		t.Fatalf("unexpected source link %q %q", paper.SourceName(), paper.AbsURL())
+	}
+
+	source, doi, version = detectSource("https://www.medrxiv.org/content/10.1101/2021.06.10.21258692")
+	paper, err = fetchRxivMetadata(context.Background(), source, doi, version)
+	if err != nil {
+		t.Fatalf("fetchRxivMetadata() error = %v", err)
+	}
+	if paper.Source != SourceMedrxiv || paper.PDFURL != "https://med.test/content/10.1101/2021.06.10.21258692v3.full.pdf" {
+		t.Fatalf("unexpected medRxiv paper %+v", paper)
+	}
+}
+
+func TestDetectOpenReviewRecognizesForumAndPDFLinks(t *testing.T) {
+	cases := map[string]string{
+		"https://openreview.net/forum?id=YicbFdNTTy":              "YicbFdNTTy",
+		"openreview.net/pdf?id=rJ4km2R5t7":                        "rJ4km2R5t7",
+		"https://openreview.net/forum?noteId=abc&id=H1-nGgWC-":    "H1-nGgWC-",
+		"https://openreview.net/group?id=ICLR.cc/2024/Conference": "",
+		"https://arxiv.org/abs/2101.00001":                        "",
+	}
+	for input, want := range cases {
+		if got := detectOpenReview(input); got != want {
+			t.Errorf("detectOpenReview(%q) = %q, want %q", input, got, want)
+		}
+	}
+	if got := Identifier("https://openreview.net/forum?id=YicbFdNTTy"); got != "YicbFdNTTy" {
+		t.Fatalf("Identifier() = %q", got)
+	}
+	if got := Identifier("https://arxiv.org/abs/2101.00001"); got != "2101.00001" {
+		t.Fatalf("expected the arXiv path first, got %q", got)
+	}
+}
+
+func TestFetchOpenReviewMetadataParsesV2AndFallsBackToV1(t *testing.T) {
+	v2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("id") {
+		case "YicbFdNTTy":
+			_, _ = w.Write([]byte(`{"notes":[{"id":"YicbFdNTTy","forum":"YicbFdNTTy","content":{
+				"title":{"value":"An Image is Worth\n 16x16 Words"},
+				"authors":{"value":["Alexey Dosovitskiy","Lucas Beyer"]},
+				"abstract":{"value":"We show that a pure transformer applied to image patches performs well. Our main contribution is a simple recipe."},
+				"keywords":{"value":["vision","transformers","vision"]},
+				"venue":{"value":"ICLR 2021 Oral"},
+				"pdf":{"value":"/pdf/0123abcd.pdf"}}}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"notes":[]}`))
+		}
+	}))
+	defer v2.Close()
+	v1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("id") != "rJ4km2R5t7" {
+			_, _ = w.Write([]byte(`{"notes":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"notes":[{"id":"rJ4km2R5t7","forum":"rJ4km2R5t7","content":{
+			"title":"GLUE","authors":["Alex Wang"],"abstract":"We introduce a benchmark.","venue":"ICLR 2019"}}]}`))
+	}))
+	defer v1.Close()
+	originalAPIs, originalSite := openReviewAPIBaseURLs, openReviewSiteBaseURL
+	openReviewAPIBaseURLs = []string{v2.URL, v1.URL}
+	openReviewSiteBaseURL = "https://or.test"
+	t.Cleanup(func() {
+		openReviewAPIBaseURLs, openReviewSiteBaseURL = originalAPIs, originalSite
+	})
+
+	paper, err := fetchOpenReviewMetadata(context.Background(), detectOpenReview("https://openreview.net/forum?id=YicbFdNTTy"))
+	if err != nil {
+		t.Fatalf("fetchOpenReviewMetadata() error = %v", err)
+	}
+	if paper.Title != "An Image is Worth 16x16 Words" || len(paper.Authors) != 2 || paper.PrimaryCategory != "ICLR 2021 Oral" {
+		t.Fatalf("unexpected OpenReview metadata %+v", paper)
+	}
+	if len(paper.Subjects) != 2 || len(paper.KeyContributions) == 0 {
+		t.Fatalf("expected deduplicated keywords and key contributions, got %+v", paper)
+	}
+	if paper.PDFURL != "https://or.test/pdf/0123abcd.pdf" || paper.AbsURL() != "https://or.test/forum?id=YicbFdNTTy" || paper.SourceName() != "OpenReview" {
+		t.Fatalf("unexpected links %q %q %q", paper.PDFURL, paper.AbsURL(), paper.SourceName())
+	}
+
+	paper, err = fetchOpenReviewMetadata(context.Background(), "rJ4km2R5t7")
+	if err != nil {
+		t.Fatalf("fetchOpenReviewMetadata() v1 error = %v", err)
+	}
+	if paper.Title != "GLUE" || paper.Abstract != "We introduce a benchmark." || paper.PDFURL != "https://or.test/pdf?id=rJ4km2R5t7" {
+		t.Fatalf("unexpected v1 metadata %+v", paper)
+	}
+
+	if _, err := fetchOpenReviewMetadata(context.Background(), "missing"); err == nil {
+		t.Fatal("expected an error for an unknown forum id")
+	}
+}
+
+func TestFetchPaperKeepsMetadataWhenPDFIsMissing(t *testing.T) {
+	t.Setenv(cacheEnvVar, t.TempDir())
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:arxiv="http://arxiv.org/schemas/atom">
+  <entry>
+    <id>http://arxiv.org/abs/2101.00001v1</id>
+    <title>Sparse Routing at Scale</title>
+    <summary>We route tokens to two experts. Experiments halve the compute.</summary>
+    <author><name>Ada Lovelace</name></author>
+    <arxiv:primary_category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+</feed>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			_, _ = w.Write([]byte(feed))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	originalPDF, originalAPI := pdfBaseURL, apiBaseURL
+	pdfBaseURL, apiBaseURL = server.URL, server.URL
+	t.Cleanup(func() { pdfBaseURL, apiBaseURL = originalPDF, originalAPI })
+
+	paper, err := FetchPaper(context.Background(), "2101.00001")
+	if !errors.Is(err, ErrPDFUnavailable) {
+		t.Fatalf("expected ErrPDFUnavailable, got %v", err)
+	}
+	if paper == nil {
+		t.Fatal("expected the metadata-only paper alongside the error")
+	}
+	if paper.Title != "Sparse Routing at Scale" || paper.Abstract == "" || paper.PrimaryCategory != "cs.LG" {
+		t.Fatalf("expected the metadata to survive, got %+v", paper)
+	}
+	if paper.FullText != "" || !paper.PDFUnavailable || paper.Notice() != PDFUnavailableNotice {
+		t.Fatalf("expected a metadata-only paper with its notice, got text=%q notice=%q", paper.FullText, paper.Notice())
+	}
+}
+
+func TestFetchMetadataSkipsPDFUntilFullTextIsRequested(t *testing.T) {
+	t.Setenv(cacheEnvVar, t.TempDir())
+	feed := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:arxiv="http://arxiv.org/schemas/atom">
+  <entry>
+    <id>http://arxiv.org/abs/2101.00001v1</id>
+    <title>Sparse Routing at Scale</title>
+    <summary>We route tokens to two experts. Experiments halve the compute.</summary>
+    <author><name>Ada Lovelace</name></author>
+    <arxiv:primary_category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+    <category term="cs.LG" scheme="http://arxiv.org/schemas/atom"/>
+  </entry>
+</feed>`
+	body := strings.Repeat("Sparse routing sends each token to two experts and halves the compute per layer. ", 4)
+	var pdfRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/") {
+			_, _ = w.Write([]byte(feed))
+			return
+		}
+		atomic.AddInt32(&pdfRequests, 1)
+		_, _ = w.Write(minimalPDF(body))
+	}))
+	defer server.Close()
+	originalPDF, originalAPI := pdfBaseURL, apiBaseURL
+	pdfBaseURL, apiBaseURL = server.URL, server.URL
+	t.Cleanup(func() { pdfBaseURL, apiBaseURL = originalPDF, originalAPI })
+
+	paper, err := FetchMetadata(context.Background(), "https://arxiv.org/abs/2101.00001")
+	if err != nil {
+		t.Fatalf("FetchMetadata() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&pdfRequests); got != 0 {
+		t.Fatalf("expected no PDF request for a preview, got %d", got)
+	}
+	if paper.Title != "Sparse Routing at Scale" || len(paper.Authors) != 1 || paper.Abstract == "" {
+		t.Fatalf("expected title, authors, and abstract, got %+v", paper)
+	}
+	if !paper.Preview || paper.FullText != "" || paper.Notice() != PreviewNotice {
+		t.Fatalf("expected an abstract-only preview, got preview=%v notice=%q", paper.Preview, paper.Notice())
+	}
+
+	if err := FetchFullText(context.Background(), paper); err != nil {
+		t.Fatalf("FetchFullText() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&pdfRequests); got != 1 {
+		t.Fatalf("expected one PDF request, got %d", got)
+	}
+	if paper.Preview || !strings.Contains(paper.FullText, "halves the compute") || paper.Notice() != "" {
+		t.Fatalf("expected the full text to replace the preview, got preview=%v text=%q", paper.Preview, paper.FullText)
+	}
+}
+
+func TestEnrichmentAddsCitationsAndRelatedPapers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/graph/v1/paper/arXiv:2101.00001":
+			_, _ = w.Write([]byte(`{"paperId":"abc","citationCount":42}`))
+		case "/recommendations/v1/papers/forpaper/arXiv:2101.00001":
+			_, _ = w.Write([]byte(`{"recommendedPapers":[
+				{"paperId":"a","externalIds":{"ArXiv":"2202.00002"}},
+				{"paperId":"b","externalIds":{"DOI":"10.1/xyz"}},
+				{"paperId":"c","externalIds":{"ArXiv":"2202.00002"}},
+				{"paperId":"d","externalIds":{"ArXiv":"2303.00003"}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	originalBase := semanticScholarBaseURL
+	semanticScholarBaseURL = server.URL
+	t.Cleanup(func() { semanticScholarBaseURL = originalBase })
+
+	paper := &Paper{ID: "2101.00001v2"}
+	enrichFromSemanticScholar(context.Background(), paper)
+	if paper.CitationCount != 42 {
+		t.Fatalf("expected 42 citations, got %d", paper.CitationCount)
+	}
+	if strings.Join(paper.RelatedPaperIDs, ",") != "2202.00002,2303.00003" {
+		t.Fatalf("expected deduplicated arXiv recommendations, got %v", paper.RelatedPaperIDs)
+	}
+
+	unknown := &Paper{ID: "1999.99999"}
+	enrichFromSemanticScholar(context.Background(), unknown)
+	if unknown.CitationCount != 0 || unknown.RelatedPaperIDs != nil {
+		t.Fatalf("expected an unavailable lookup to leave the fields empty, got %+v", unknown)
+	}
+}
@@ -0,0 +1,87 @@
+package arxiv
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// offline makes FetchPaper read papers from the PDF cache without touching the network.
+var offline bool
+
+// SetOffline switches FetchPaper to cached papers only; main sets it once at startup.
+func SetOffline(enabled bool) {
+	offline = enabled
+}
+
+// cachedPaperMeta is the arXiv metadata kept in a cached PDF's .meta file.
+type cachedPaperMeta struct {
+	Title           string   `json:"title"`
+	Authors         []string `json:"authors,omitempty"`
+	Abstract        string   `json:"abstract,omitempty"`
+	Subjects        []string `json:"subjects,omitempty"`
+	PrimaryCategory string   `json:"primaryCategory,omitempty"`
+}
+
+// cachedPDFPaths returns the cached PDF and .meta paths for an arXiv id.
+func cachedPDFPaths(id string) (string, string) {
+	cache := &pdfCache{dir: cacheDir(), ext: ".pdf"}
+	pdfPath, metaPath, _ := cache.pathsFor(cacheKey(fmt.Sprintf("%s/pdf/%s.pdf", pdfBaseURL, id)))
+	return pdfPath, metaPath
+}
+
+// rememberPaperMetadata records the paper's metadata next to its cached PDF so offline mode can
+// show the real title and authors. Failures only cost offline mode the metadata.
+func rememberPaperMetadata(paper *Paper) {
+	pdfPath, metaPath := cachedPDFPaths(paper.ID)
+	if _, err := os.Stat(pdfPath); err != nil {
+		return
+	}
+	meta, _ := readMeta(metaPath)
+	meta.Paper = &cachedPaperMeta{
+		Title:           paper.Title,
+		Authors:         paper.Authors,
+		Abstract:        paper.Abstract,
+		Subjects:        paper.Subjects,
+		PrimaryCategory: paper.PrimaryCategory,
+	}
+	_ = writeMeta(metaPath, meta)
+}
+
+// fetchCachedPaper rebuilds an arXiv paper from the PDF cache alone. Metadata comes from the .meta
+// file when an earlier online fetch recorded it; otherwise the id stands in for the title.
+func fetchCachedPaper(input string) (*Paper, error) {
+	id := extractIdentifier(input)
+	if id == "" {
+		return nil, fmt.Errorf("offline mode only opens cached arXiv papers; %q is not an arXiv identifier", strings.TrimSpace(input))
+	}
+	pdfPath, metaPath := cachedPDFPaths(id)
+	if info, err := os.Stat(pdfPath); err != nil || info.Size() == 0 {
+		return nil, fmt.Errorf("offline mode: %s is not in the PDF cache; open it once while online", id)
+	}
+
+	paper := &Paper{ID: id, Version: VersionSuffix(id), Title: id, PDFURL: fmt.Sprintf("%s/pdf/%s.pdf", pdfBaseURL, id)}
+	if meta, err := readMeta(metaPath); err == nil && meta.Paper != nil {
+		if title := strings.TrimSpace(meta.Paper.Title); title != "" {
+			paper.Title = title
+		}
+		paper.Authors = meta.Paper.Authors
+		paper.Abstract = meta.Paper.Abstract
+		paper.Subjects = meta.Paper.Subjects
+		paper.PrimaryCategory = meta.Paper.PrimaryCategory
+		paper.KeyContributions = extractKeyContributions(paper.Abstract)
+	}
+
+	fullText, sections, pages, err := readPDFText(pdfPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process cached PDF: %w", err)
+	}
+	if looksScanned(fullText, pages) {
+		fullText, sections = "", nil
+		paper.ScannedPDF = true
+	}
+	paper.FullText = fullText
+	paper.Sections = sections
+	paper.References = extractReferences(fullText)
+	return paper, nil
+}
@@ -0,0 +1,102 @@
+package arxiv
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// minimalPDF builds a one-page PDF whose only content is text in Helvetica.
+func minimalPDF(text string) []byte {
+	stream := fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+	var b strings.Builder
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return []byte(b.String())
+}
+
+func TestOfflineFetchReadsCachedPaperWithoutNetwork(t *testing.T) {
+	t.Setenv(cacheEnvVar, t.TempDir())
+	body := strings.Repeat("Sparse routing sends each token to two experts and halves the compute per layer. ", 4)
+	var requests int32
+	online := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if !online {
+			t.Errorf("offline fetch reached the network: %s", r.URL)
+			http.Error(w, "offline", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write(minimalPDF(body))
+	}))
+	defer server.Close()
+	originalPDF, originalAPI := pdfBaseURL, apiBaseURL
+	pdfBaseURL, apiBaseURL = server.URL, server.URL
+	t.Cleanup(func() { pdfBaseURL, apiBaseURL = originalPDF, originalAPI })
+	t.Cleanup(func() { SetOffline(false) })
+
+	entry := &apiEntry{
+		Title:           "Sparse Routing",
+		Summary:         "We route tokens sparsely.",
+		Authors:         []apiAuthor{{Name: "Ada Lovelace"}},
+		PrimaryCategory: apiCategory{Term: "cs.LG"},
+		Categories:      []apiCategory{{Term: "cs.LG"}},
+	}
+	cached := paperFromEntry("2101.00001", entry)
+	cached.Preview = true
+	if err := FetchFullText(context.Background(), cached); err != nil {
+		t.Fatalf("FetchFullText() error = %v", err)
+	}
+	seen := atomic.LoadInt32(&requests)
+
+	online = false
+	SetOffline(true)
+	paper, err := FetchPaper(context.Background(), "https://arxiv.org/abs/2101.00001")
+	if err != nil {
+		t.Fatalf("offline FetchPaper() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != seen {
+		t.Fatalf("expected no requests offline, got %d more", got-seen)
+	}
+	if paper.Title != "Sparse Routing" || len(paper.Authors) != 1 || paper.PrimaryCategory != "cs.LG" {
+		t.Fatalf("expected metadata from the cache sidecar, got %+v", paper)
+	}
+	if !strings.Contains(paper.FullText, "two experts") {
+		t.Fatalf("expected the cached PDF text, got %q", paper.FullText)
+	}
+
+	if _, err := FetchPaper(context.Background(), "2101.99999"); err == nil || !strings.Contains(err.Error(), "not in the PDF cache") {
+		t.Fatalf("expected an uncached paper to fail clearly, got %v", err)
+	}
+
+	if path, err := FetchPDF(context.Background(), paper.PDFURL); err != nil || path == "" {
+		t.Fatalf("offline FetchPDF() = %q, %v; want the cached copy", path, err)
+	}
+	if _, err := FetchPDF(context.Background(), server.URL+"/pdf/2101.99999.pdf"); err == nil || !strings.Contains(err.Error(), "not in the PDF cache") {
+		t.Fatalf("expected offline FetchPDF of an uncached paper to fail, got %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != seen {
+		t.Fatalf("expected offline FetchPDF to make no requests, got %d more", got-seen)
+	}
+}
@@ -0,0 +1,149 @@
+package arxiv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/csheth/browse/internal/httpheaders"
+	"github.com/csheth/browse/internal/httpx"
+)
+
+// SourceOpenReview marks conference papers (ICLR, NeurIPS, ...) fetched from OpenReview.
+const SourceOpenReview Source = "openreview"
+
+var (
+	// openReviewURLRegexp matches OpenReview forum and PDF links, capturing the forum id.
+	openReviewURLRegexp = regexp.MustCompile(`(?i)openreview\.net/(?:forum|pdf)/?\?(?:[^#\s]*&)?id=([A-Za-z0-9_\-]+)`)
+
+	// openReviewAPIBaseURLs lists the API versions to query in order: current venues live in API v2,
+	// older ones (ICLR before 2023, NeurIPS before 2023) only in v1. openReviewSiteBaseURL builds
+	// forum and PDF links. Both are swapped out in tests.
+	openReviewAPIBaseURLs = []string{"https://api2.openreview.net", "https://api.openreview.net"}
+	openReviewSiteBaseURL = "https://openreview.net"
+)
+
+// detectOpenReview returns the forum id of an OpenReview forum or PDF URL, or "".
+func detectOpenReview(input string) string {
+	matches := openReviewURLRegexp.FindStringSubmatch(strings.TrimSpace(input))
+	if len(matches) < 2 {
+		return ""
+	}
+	return matches[1]
+}
+
+type openReviewResponse struct {
+	Notes []openReviewNote `json:"notes"`
+}
+
+type openReviewNote struct {
+	ID      string                     `json:"id"`
+	Forum   string                     `json:"forum"`
+	Content map[string]json.RawMessage `json:"content"`
+}
+
+// field decodes a content field into dst. API v2 wraps every field as {"value": ...}; v1 stores
+// the value directly. Missing or mistyped fields leave dst untouched.
+func (n openReviewNote) field(name string, dst any) {
+	raw, ok := n.Content[name]
+	if !ok {
+		return
+	}
+	var wrapped struct {
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &wrapped); err == nil && len(wrapped.Value) > 0 {
+		raw = wrapped.Value
+	}
+	_ = json.Unmarshal(raw, dst)
+}
+
+// fetchOpenReviewMetadata loads a forum note from the first OpenReview API version that has it.
+func fetchOpenReviewMetadata(ctx context.Context, forumID string) (*Paper, error) {
+	client := httpx.NewClient(10 * time.Second)
+	for _, base := range openReviewAPIBaseURLs {
+		note, err := fetchOpenReviewNote(ctx, client, base, forumID)
+		if err != nil {
+			return nil, err
+		}
+		if note != nil {
+			return paperFromOpenReviewNote(forumID, *note), nil
+		}
+	}
+	return nil, errors.New("paper not found")
+}
+
+// fetchOpenReviewNote returns nil without an error when this API version does not know the note.
+func fetchOpenReviewNote(ctx context.Context, client *http.Client, base, forumID string) (*openReviewNote, error) {
+	endpoint := fmt.Sprintf("%s/notes?id=%s", base, url.QueryEscape(forumID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpheaders.Apply(req, requestHeaders)
+	resp, err := doWithRetry(ctx, client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("openreview API error: %s (%s)", resp.Status, string(body))
+	}
+
+	var payload openReviewResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode openreview response: %w", err)
+	}
+	if len(payload.Notes) == 0 {
+		return nil, nil
+	}
+	return &payload.Notes[0], nil
+}
+
+func paperFromOpenReviewNote(forumID string, note openReviewNote) *Paper {
+	var title, abstract, venue, pdfPath string
+	var authors, keywords []string
+	note.field("title", &title)
+	note.field("abstract", &abstract)
+	note.field("venue", &venue)
+	note.field("pdf", &pdfPath)
+	note.field("authors", &authors)
+	note.field("keywords", &keywords)
+
+	pdfURL := fmt.Sprintf("%s/pdf?id=%s", openReviewSiteBaseURL, url.QueryEscape(forumID))
+	if pdfPath = strings.TrimSpace(pdfPath); pdfPath != "" {
+		if strings.HasPrefix(pdfPath, "/") {
+			pdfPath = openReviewSiteBaseURL + pdfPath
+		}
+		pdfURL = pdfPath
+	}
+	abstract = normalizeWhitespace(abstract)
+	var subjects []string
+	for _, keyword := range keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" && !containsString(subjects, keyword) {
+			subjects = append(subjects, keyword)
+		}
+	}
+	return &Paper{
+		ID:               forumID,
+		Source:           SourceOpenReview,
+		Title:            normalizeWhitespace(title),
+		Authors:          authors,
+		Abstract:         abstract,
+		Subjects:         subjects,
+		PrimaryCategory:  strings.TrimSpace(venue),
+		KeyContributions: extractKeyContributions(abstract),
+		PDFURL:           pdfURL,
+	}
+}
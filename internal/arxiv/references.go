@@ -0,0 +1,89 @@
+package arxiv
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	maxReferenceChars = 400
+	// maxHeadingGap is how far after the heading the [1] entry may start; anything further away is
+	// more likely a sentence mentioning "references" than the section itself.
+	maxHeadingGap = 40
+)
+
+var (
+	referencesHeading = regexp.MustCompile(`\b(References|REFERENCES|Bibliography|BIBLIOGRAPHY)\b`)
+	referenceMarker   = regexp.MustCompile(`\[(\d{1,3})\]\s*`)
+	// dottedReferenceMarker matches "1. " style entries; parseNumberedReferences additionally
+	// requires an uppercase letter after it so "Vol. 2. 10" style numbers are not entries.
+	dottedReferenceMarker = regexp.MustCompile(`(?:^|\s)(\d{1,3})\.\s+`)
+	// trailingPageNumber is a bare page number the PDF footer left after an entry's final period.
+	trailingPageNumber = regexp.MustCompile(`\.\s+\d{1,3}$`)
+)
+
+// extractReferences pulls the numbered bibliography ("[1] ..." or "1. ...") out of the flattened
+// PDF text. References[i] holds entry [i+1]. Only markers that continue the 1, 2, 3, … sequence
+// after the last References heading count, so inline citations in the body are not mistaken for
+// entries. Papers without a recognizable section get nil.
+func extractReferences(fullText string) []string {
+	headings := referencesHeading.FindAllStringIndex(fullText, -1)
+	if len(headings) == 0 {
+		return nil
+	}
+	var refs []string
+	// Walk back from the last heading: running headers or a closing "Bibliography" note can appear
+	// after the real section, so use the latest heading that is followed by an [1] entry.
+	for h := len(headings) - 1; h >= 0 && len(refs) == 0; h-- {
+		section := fullText[headings[h][1]:]
+		refs = parseNumberedReferences(section, referenceMarker)
+		if len(refs) == 0 {
+			refs = parseNumberedReferences(section, dottedReferenceMarker)
+		}
+	}
+	return refs
+}
+
+func parseNumberedReferences(section string, pattern *regexp.Regexp) []string {
+	markers := pattern.FindAllStringSubmatchIndex(section, -1)
+	type start struct{ begin, end int }
+	var starts []start
+	next := 1
+	for _, marker := range markers {
+		n, err := strconv.Atoi(section[marker[2]:marker[3]])
+		if err != nil || n != next {
+			continue
+		}
+		if pattern == dottedReferenceMarker && !startsWithUpper(section[marker[1]:]) {
+			continue
+		}
+		if next == 1 && marker[0] > maxHeadingGap {
+			return nil
+		}
+		starts = append(starts, start{begin: marker[0], end: marker[1]})
+		next++
+	}
+	refs := make([]string, 0, len(starts))
+	for idx, s := range starts {
+		stop := len(section)
+		if idx+1 < len(starts) {
+			stop = starts[idx+1].begin
+		}
+		entry := strings.TrimSpace(section[s.end:stop])
+		entry = trailingPageNumber.ReplaceAllString(entry, ".")
+		if runes := []rune(entry); len(runes) > maxReferenceChars {
+			entry = strings.TrimSpace(string(runes[:maxReferenceChars])) + "…"
+		}
+		refs = append(refs, entry)
+	}
+	return refs
+}
+
+func startsWithUpper(text string) bool {
+	for _, r := range text {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package arxiv
+
+import "testing"
+
+func TestExtractReferencesNumberedEntries(t *testing.T) {
+	t.Parallel()
+
+	text := "Prior work [2] and [1] explored this. Conclusion. References [1] A. Author. First paper. NeurIPS, 2020. [2] B. Author. Second paper. ICML, 2021. [3] C. Author. Third paper."
+	refs := extractReferences(text)
+	if len(refs) != 3 {
+		t.Fatalf("expected 3 references, got %d: %q", len(refs), refs)
+	}
+	if refs[1] != "B. Author. Second paper. ICML, 2021." {
+		t.Fatalf("unexpected second reference %q", refs[1])
+	}
+}
+
+func TestExtractReferencesWithoutSection(t *testing.T) {
+	t.Parallel()
+
+	if refs := extractReferences("No bibliography here [1] just citations."); refs != nil {
+		t.Fatalf("expected no references, got %q", refs)
+	}
+}
+
+func TestExtractReferencesIgnoresProseMentions(t *testing.T) {
+	t.Parallel()
+
+	text := "See the References for details, as in [1]. References [1] Only entry."
+	refs := extractReferences(text)
+	if len(refs) != 1 || refs[0] != "Only entry." {
+		t.Fatalf("expected the real section to win, got %q", refs)
+	}
+}
+
+func TestExtractReferencesDottedEntriesTrimPageNumbers(t *testing.T) {
+	t.Parallel()
+
+	text := "Results in Vol. 2. 10 settings. Bibliography 1. Vaswani, A. et al. Attention is all you need. NeurIPS, 2017. 2. Devlin, J. et al. BERT. NAACL, 2019. 14 3. Brown, T. et al. Language models are few-shot learners. 2020."
+	refs := extractReferences(text)
+	want := []string{
+		"Vaswani, A. et al. Attention is all you need. NeurIPS, 2017.",
+		"Devlin, J. et al. BERT. NAACL, 2019.",
+		"Brown, T. et al. Language models are few-shot learners. 2020.",
+	}
+	if len(refs) != len(want) {
+		t.Fatalf("expected %d references, got %d: %q", len(want), len(refs), refs)
+	}
+	for i := range want {
+		if refs[i] != want[i] {
+			t.Fatalf("reference %d = %q, want %q", i+1, refs[i], want[i])
+		}
+	}
+}
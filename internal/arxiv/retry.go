@@ -0,0 +1,97 @@
+package arxiv
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxFetchAttempts = 3
+	baseRetryDelay   = 500 * time.Millisecond
+	maxRetryDelay    = 30 * time.Second
+)
+
+// retryWait pauses between attempts; tests swap it out so backoff does not slow them down.
+var retryWait = sleepContext
+
+// doWithRetry sends req, retrying transient failures (network errors, 429, and 5xx) for up to
+// maxFetchAttempts attempts in total. Waits grow exponentially from baseRetryDelay with random
+// jitter unless the server sends Retry-After. Other responses, including the last failing one, are
+// returned as-is so callers keep their status handling.
+func doWithRetry(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := client.Do(req.Clone(ctx))
+		if attempt >= maxFetchAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+		delay := backoffDelay(attempt)
+		if err == nil {
+			if !retryableStatus(resp.StatusCode) {
+				return resp, nil
+			}
+			if after, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				delay = after
+			}
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+		if err := retryWait(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// backoffDelay doubles baseRetryDelay per attempt and adds up to 50% jitter so concurrent
+// prefetches do not retry in lockstep.
+func backoffDelay(attempt int) time.Duration {
+	delay := baseRetryDelay << (attempt - 1)
+	delay += time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
+
+// parseRetryAfter reads a Retry-After header given as delta-seconds or an HTTP date, capped at
+// maxRetryDelay. ok is false when the header is missing or malformed.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	var delay time.Duration
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		delay = time.Duration(secs) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		delay = at.Sub(now)
+	} else {
+		return 0, false
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay, true
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
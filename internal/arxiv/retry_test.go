@@ -0,0 +1,106 @@
+package arxiv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recordRetryWaits replaces retryWait for the test and returns the delays it was asked for.
+func recordRetryWaits(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var waits []time.Duration
+	original := retryWait
+	retryWait = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	t.Cleanup(func() { retryWait = original })
+	return &waits
+}
+
+func TestFetchPaperRetriesTransientAPIFailures(t *testing.T) {
+	waits := recordRetryWaits(t)
+	fixture, err := os.ReadFile("testdata/withdrawn.xml")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "7")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+		case 2:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		default:
+			w.Write(fixture)
+		}
+	}))
+	defer server.Close()
+	original := apiBaseURL
+	apiBaseURL = server.URL
+	t.Cleanup(func() { apiBaseURL = original })
+
+	paper, err := FetchPaper(context.Background(), "1501.00001")
+	if err != nil {
+		t.Fatalf("FetchPaper() error = %v", err)
+	}
+	if paper.Title != "A Flawed Bound on Sparse Recovery" {
+		t.Fatalf("unexpected paper %+v", paper)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Fatalf("expected three attempts, got %d", got)
+	}
+	if len(*waits) != 2 || (*waits)[0] != 7*time.Second {
+		t.Fatalf("expected Retry-After honored then backoff, got %v", *waits)
+	}
+	if backoff := (*waits)[1]; backoff < 2*baseRetryDelay || backoff > 3*baseRetryDelay {
+		t.Fatalf("expected second wait within jittered backoff, got %v", backoff)
+	}
+}
+
+func TestDoWithRetrySkipsClientErrors(t *testing.T) {
+	waits := recordRetryWaits(t)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := doWithRetry(context.Background(), server.Client(), req)
+	if err != nil {
+		t.Fatalf("doWithRetry() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound || atomic.LoadInt32(&calls) != 1 || len(*waits) != 0 {
+		t.Fatalf("expected one 404 without retries, got status %d after %d calls", resp.StatusCode, calls)
+	}
+}
+
+func TestDoWithRetryStopsWhenContextCanceled(t *testing.T) {
+	recordRetryWaits(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := doWithRetry(ctx, server.Client(), req); err == nil {
+		t.Fatal("expected a canceled context to stop the retries")
+	}
+}
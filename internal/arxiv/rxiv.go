@@ -0,0 +1,160 @@
+package arxiv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/csheth/browse/internal/httpheaders"
+	"github.com/csheth/browse/internal/httpx"
+)
+
+// Source names the preprint server a Paper was fetched from.
+type Source string
+
+const (
+	// SourceArxiv is the default; papers without a Source came from arXiv.
+	SourceArxiv   Source = "arxiv"
+	SourceBiorxiv Source = "biorxiv"
+	SourceMedrxiv Source = "medrxiv"
+)
+
+var (
+	// rxivURLRegexp matches bioRxiv and medRxiv content URLs, capturing the server, the 10.1101 DOI,
+	// and an optional version suffix. Both dated (10.1101/2020.01.01.123456) and legacy numeric
+	// (10.1101/123456) DOIs are accepted.
+	rxivURLRegexp = regexp.MustCompile(`(?i)(bio|med)rxiv\.org/content/(?:early/\d{4}/\d{2}/\d{2}/)?(10\.1101/(?:\d{4}\.\d{2}\.\d{2}\.)?\d+)(?:v(\d+))?`)
+
+	// rxivAPIBaseURL and rxivSiteBaseURLs are swapped out in tests.
+	rxivAPIBaseURL   = "https://api.biorxiv.org"
+	rxivSiteBaseURLs = map[Source]string{
+		SourceBiorxiv: "https://www.biorxiv.org",
+		SourceMedrxiv: "https://www.medrxiv.org",
+	}
+)
+
+// SourceName is the display name of the paper's preprint server.
+func (p *Paper) SourceName() string {
+	switch p.Source {
+	case SourceBiorxiv:
+		return "bioRxiv"
+	case SourceMedrxiv:
+		return "medRxiv"
+	case SourceOpenReview:
+		return "OpenReview"
+	default:
+		return "arXiv"
+	}
+}
+
+// AbsURL links to the paper's landing page on its preprint server.
+func (p *Paper) AbsURL() string {
+	if base, ok := rxivSiteBaseURLs[p.Source]; ok {
+		return fmt.Sprintf("%s/content/%s", base, p.ID)
+	}
+	if p.Source == SourceOpenReview {
+		return fmt.Sprintf("%s/forum?id=%s", openReviewSiteBaseURL, p.ID)
+	}
+	return fmt.Sprintf("https://arxiv.org/abs/%s", p.ID)
+}
+
+// detectSource recognizes bioRxiv and medRxiv content URLs, returning the server, DOI, and version
+// ("" for the latest). Anything else is left to the arXiv identifier parser.
+func detectSource(input string) (Source, string, string) {
+	matches := rxivURLRegexp.FindStringSubmatch(strings.TrimSpace(input))
+	if len(matches) == 0 {
+		return SourceArxiv, "", ""
+	}
+	source := SourceBiorxiv
+	if strings.EqualFold(matches[1], "med") {
+		source = SourceMedrxiv
+	}
+	return source, matches[2], matches[3]
+}
+
+type rxivResponse struct {
+	Collection []rxivRecord `json:"collection"`
+}
+
+type rxivRecord struct {
+	DOI      string `json:"doi"`
+	Title    string `json:"title"`
+	Authors  string `json:"authors"`
+	Abstract string `json:"abstract"`
+	Category string `json:"category"`
+	Version  string `json:"version"`
+}
+
+// fetchRxivMetadata loads a bioRxiv or medRxiv preprint from the shared details API. The API lists
+// every version; the requested one is used when present, otherwise the latest.
+func fetchRxivMetadata(ctx context.Context, source Source, doi, version string) (*Paper, error) {
+	url := fmt.Sprintf("%s/details/%s/%s", rxivAPIBaseURL, source, doi)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpheaders.Apply(req, requestHeaders)
+	client := httpx.NewClient(10 * time.Second)
+	resp, err := doWithRetry(ctx, client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("%s API error: %s (%s)", source, resp.Status, string(body))
+	}
+
+	var payload rxivResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", source, err)
+	}
+	if len(payload.Collection) == 0 {
+		return nil, errors.New("paper not found")
+	}
+	record := payload.Collection[len(payload.Collection)-1]
+	for _, candidate := range payload.Collection {
+		if version != "" && candidate.Version == version {
+			record = candidate
+		}
+	}
+
+	var authors []string
+	for _, name := range strings.Split(record.Authors, ";") {
+		if name = strings.TrimSpace(name); name != "" {
+			authors = append(authors, name)
+		}
+	}
+	abstract := normalizeWhitespace(record.Abstract)
+	var subjects []string
+	category := strings.TrimSpace(record.Category)
+	if category != "" {
+		subjects = []string{category}
+	}
+	return &Paper{
+		ID:               doi,
+		Version:          rxivVersion(record.Version),
+		Source:           source,
+		Title:            normalizeWhitespace(record.Title),
+		Authors:          authors,
+		Abstract:         abstract,
+		Subjects:         subjects,
+		PrimaryCategory:  category,
+		KeyContributions: extractKeyContributions(abstract),
+		PDFURL:           fmt.Sprintf("%s/content/%sv%s.full.pdf", rxivSiteBaseURLs[source], doi, record.Version),
+	}, nil
+}
+
+// rxivVersion renders the API's bare version number ("2") the way arXiv versions read ("v2").
+func rxivVersion(number string) string {
+	if number = strings.TrimSpace(number); number == "" {
+		return ""
+	}
+	return "v" + number
+}
@@ -0,0 +1,75 @@
+package arxiv
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Section is one headed part of a paper's text, such as "2 Method".
+type Section struct {
+	Heading string
+	Text    string
+}
+
+const maxHeadingWords = 8
+
+var (
+	// numberedHeading matches "1 Introduction", "2. Method", "3.1 Training Setup", and "IV. Results".
+	numberedHeading = regexp.MustCompile(`^(?:\d{1,2}(?:\.\d{1,2})*\.?|[IVX]{1,4}\.)\s+[A-Z][A-Za-z][A-Za-z ,&:/'-]*$`)
+	knownHeadings   = map[string]bool{
+		"abstract": true, "introduction": true, "background": true, "related work": true,
+		"preliminaries": true, "method": true, "methods": true, "methodology": true, "approach": true,
+		"experiments": true, "experimental setup": true, "experimental results": true, "evaluation": true,
+		"results": true, "discussion": true, "analysis": true, "limitations": true, "conclusion": true,
+		"conclusions": true, "future work": true, "acknowledgments": true, "acknowledgements": true,
+		"references": true, "bibliography": true, "appendix": true,
+	}
+)
+
+// splitSections groups extracted text, which must still have its line breaks, under heading lines.
+// A heading is a short line that is either numbered ("1 Introduction", "3.1 Training Setup") or one
+// of the usual unnumbered headings ("Related Work", "REFERENCES"). Text before the first heading is
+// kept as a section with an empty heading.
+func splitSections(raw string) []Section {
+	var (
+		sections []Section
+		heading  string
+		body     []string
+		started  bool
+	)
+	flush := func() {
+		text := normalizeWhitespace(strings.Join(body, " "))
+		if started || text != "" {
+			sections = append(sections, Section{Heading: heading, Text: text})
+		}
+		body = body[:0]
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n") {
+		line = normalizeWhitespace(line)
+		if line == "" {
+			continue
+		}
+		if isSectionHeading(line) {
+			flush()
+			heading = strings.TrimSuffix(line, ":")
+			started = true
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+	if !started {
+		return nil
+	}
+	return sections
+}
+
+func isSectionHeading(line string) bool {
+	if len(strings.Fields(line)) > maxHeadingWords || strings.HasSuffix(line, ".") {
+		return false
+	}
+	if knownHeadings[strings.ToLower(strings.TrimSuffix(line, ":"))] {
+		return true
+	}
+	return numberedHeading.MatchString(line)
+}
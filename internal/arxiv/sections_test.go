@@ -0,0 +1,54 @@
+package arxiv
+
+import "testing"
+
+func TestSplitSectionsRecognizesHeadings(t *testing.T) {
+	t.Parallel()
+
+	raw := `Sparse Attention at Scale
+Ada Lovelace
+Abstract
+We study sparse attention.
+1 Introduction
+Transformers are everywhere.
+Attention is costly.
+2. Method
+We prune heads using 3 criteria.
+2.1 Training Setup
+We train for 10 epochs.
+Related Work
+Prior work is dense.
+4 Experiments
+Table 1 Baseline 23.4
+Results improve by 2.1 points.
+REFERENCES
+[1] A. Author. A paper. 2020.`
+
+	sections := splitSections(raw)
+	want := []Section{
+		{Heading: "", Text: "Sparse Attention at Scale Ada Lovelace"},
+		{Heading: "Abstract", Text: "We study sparse attention."},
+		{Heading: "1 Introduction", Text: "Transformers are everywhere. Attention is costly."},
+		{Heading: "2. Method", Text: "We prune heads using 3 criteria."},
+		{Heading: "2.1 Training Setup", Text: "We train for 10 epochs."},
+		{Heading: "Related Work", Text: "Prior work is dense."},
+		{Heading: "4 Experiments", Text: "Table 1 Baseline 23.4 Results improve by 2.1 points."},
+		{Heading: "REFERENCES", Text: "[1] A. Author. A paper. 2020."},
+	}
+	if len(sections) != len(want) {
+		t.Fatalf("expected %d sections, got %d: %+v", len(want), len(sections), sections)
+	}
+	for i := range want {
+		if sections[i] != want[i] {
+			t.Fatalf("section %d: expected %+v, got %+v", i, want[i], sections[i])
+		}
+	}
+}
+
+func TestSplitSectionsWithoutHeadings(t *testing.T) {
+	t.Parallel()
+
+	if sections := splitSections("One line of text.\nAnother line of text."); sections != nil {
+		t.Fatalf("expected no sections without headings, got %+v", sections)
+	}
+}
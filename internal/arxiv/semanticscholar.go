@@ -0,0 +1,100 @@
+package arxiv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/csheth/browse/internal/httpheaders"
+	"github.com/csheth/browse/internal/httpx"
+)
+
+// relatedPaperLimit caps how many Semantic Scholar recommendations are kept.
+const relatedPaperLimit = 5
+
+var (
+	// enrich turns on the Semantic Scholar lookup after arXiv papers load; main sets it from -enrich.
+	enrich bool
+
+	// semanticScholarBaseURL is swapped out in tests.
+	semanticScholarBaseURL = "https://api.semanticscholar.org"
+
+	// arxivVersionRegexp matches the version suffix Semantic Scholar leaves off its arXiv ids.
+	arxivVersionRegexp = regexp.MustCompile(`v\d+$`)
+)
+
+// SetEnrichment turns the Semantic Scholar citation and recommendation lookup on or off.
+func SetEnrichment(enabled bool) {
+	enrich = enabled
+}
+
+type semanticScholarPaper struct {
+	CitationCount int `json:"citationCount"`
+	ExternalIDs   struct {
+		ArXiv string `json:"ArXiv"`
+	} `json:"externalIds"`
+}
+
+type semanticScholarRecommendations struct {
+	RecommendedPapers []semanticScholarPaper `json:"recommendedPapers"`
+}
+
+// enrichFromSemanticScholar fills CitationCount and RelatedPaperIDs from Semantic Scholar. It is
+// best effort: any failure (no network, rate limiting, a paper Semantic Scholar does not know)
+// leaves the fields zero-valued rather than failing the load.
+func enrichFromSemanticScholar(ctx context.Context, paper *Paper) {
+	id := arxivVersionRegexp.ReplaceAllString(paper.ID, "")
+	if id == "" {
+		return
+	}
+	client := httpx.NewClient(5 * time.Second)
+	key := url.PathEscape("arXiv:" + id)
+
+	var details semanticScholarPaper
+	if err := getSemanticScholar(ctx, client, fmt.Sprintf("%s/graph/v1/paper/%s?fields=citationCount", semanticScholarBaseURL, key), &details); err == nil {
+		paper.CitationCount = details.CitationCount
+	}
+
+	var recommendations semanticScholarRecommendations
+	endpoint := fmt.Sprintf("%s/recommendations/v1/papers/forpaper/%s?fields=externalIds&limit=%d", semanticScholarBaseURL, key, relatedPaperLimit)
+	if err := getSemanticScholar(ctx, client, endpoint, &recommendations); err != nil {
+		return
+	}
+	var related []string
+	for _, recommended := range recommendations.RecommendedPapers {
+		relatedID := strings.TrimSpace(recommended.ExternalIDs.ArXiv)
+		// Only arXiv recommendations can be opened in PaperScout, so the rest are skipped.
+		if relatedID == "" || relatedID == id || containsString(related, relatedID) {
+			continue
+		}
+		related = append(related, relatedID)
+		if len(related) == relatedPaperLimit {
+			break
+		}
+	}
+	paper.RelatedPaperIDs = related
+}
+
+// getSemanticScholar decodes the JSON response at endpoint into dst. It does not retry: the lookup
+// is optional, so a rate-limited request is skipped instead of delaying the load.
+func getSemanticScholar(ctx context.Context, client *http.Client, endpoint string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	httpheaders.Apply(req, requestHeaders)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("semantic scholar API error: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"unicode"
 
+	"github.com/csheth/browse/internal/arxiv"
 	"github.com/csheth/browse/internal/llm"
 )
 
@@ -25,9 +26,10 @@ type Chunk struct {
 	End   int
 }
 
-// Builder preprocesses PDF text into trimmed, deduplicated sections within fixed budgets.
+// Builder preprocesses PDF text into trimmed, deduplicated sections within fixed token budgets.
 type Builder struct {
-	budgets map[llm.BriefSectionKind]int
+	budgets   map[llm.BriefSectionKind]int
+	estimator llm.TokenEstimator
 }
 
 var (
@@ -49,6 +51,12 @@ func NewBuilder(budgets map[llm.BriefSectionKind]int) *Builder {
 	return &Builder{budgets: result}
 }
 
+// WithTokenEstimator makes the builder count budgets with est instead of llm.HeuristicEstimator.
+func (b *Builder) WithTokenEstimator(est llm.TokenEstimator) *Builder {
+	b.estimator = est
+	return b
+}
+
 // Build trims the provided content, removes boilerplate/repeated paragraphs, and emits per-section
 // context strings that stay under each budget.
 func (b *Builder) Build(content string) Package {
@@ -87,7 +95,7 @@ func (b *Builder) Build(content string) Package {
 		if kind == llm.BriefTechnical {
 			sectionChunks = rankChunksForTechnical(chunks)
 		}
-		sections[kind] = clipChunks(sectionChunks, budget)
+		sections[kind] = clipChunks(sectionChunks, budget, b.estimator)
 	}
 
 	return Package{
@@ -96,6 +104,59 @@ func (b *Builder) Build(content string) Package {
 	}
 }
 
+// technicalHeadingKeywords mark sections whose text should lead the technical context.
+var technicalHeadingKeywords = []string{
+	"method", "approach", "model", "architecture", "experiment", "evaluation", "setup",
+	"implementation", "training", "results",
+}
+
+// BuildWithSections works like Build, but when sections include method or experiment headings the
+// technical context is built from those sections first, followed by the remaining sections in paper
+// order (references and acknowledgments are dropped). Without such headings it matches Build.
+func (b *Builder) BuildWithSections(content string, sections []arxiv.Section) Package {
+	pkg := b.Build(content)
+	var preferred, rest []Chunk
+	for _, section := range sections {
+		text := strings.TrimSpace(section.Text)
+		if text == "" {
+			continue
+		}
+		heading := strings.ToLower(section.Heading)
+		if heading != "" {
+			text = section.Heading + "\n" + text
+		}
+		chunk := Chunk{ID: hashChunk(text), Text: text}
+		switch {
+		case isTechnicalHeading(heading):
+			preferred = append(preferred, chunk)
+		case isBackMatterHeading(heading):
+		default:
+			rest = append(rest, chunk)
+		}
+	}
+	if len(preferred) == 0 {
+		return pkg
+	}
+	pkg.Sections[llm.BriefTechnical] = clipChunks(append(preferred, rest...), b.budgets[llm.BriefTechnical], b.estimator)
+	return pkg
+}
+
+func isBackMatterHeading(heading string) bool {
+	return strings.Contains(heading, "references") || strings.Contains(heading, "bibliography") || strings.HasPrefix(strings.TrimLeft(heading, "0123456789. "), "acknowledg")
+}
+
+func isTechnicalHeading(heading string) bool {
+	if strings.Contains(heading, "related") {
+		return false
+	}
+	for _, keyword := range technicalHeadingKeywords {
+		if strings.Contains(heading, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
 func sanitizeDocument(content string) string {
 	content = strings.ReplaceAll(content, "\r\n", "\n")
 	content = strings.ReplaceAll(content, "\r", "\n")
@@ -152,10 +213,16 @@ func hashChunk(text string) string {
 	return hex.EncodeToString(sum[:])
 }
 
-func clipChunks(chunks []Chunk, budget int) string {
+// clipChunks joins chunks until est counts budget tokens, clipping the last chunk that only partly
+// fits. A nil est uses llm.HeuristicEstimator.
+func clipChunks(chunks []Chunk, budget int, est llm.TokenEstimator) string {
 	if budget <= 0 {
 		return ""
 	}
+	if est == nil {
+		est = llm.HeuristicEstimator{}
+	}
+	separator := est.EstimateTokens("\n\n")
 	var builder strings.Builder
 	remaining := budget
 	for idx, chunk := range chunks {
@@ -163,20 +230,19 @@ func clipChunks(chunks []Chunk, budget int) string {
 			break
 		}
 		if idx > 0 && builder.Len() > 0 {
-			if remaining <= 2 {
+			if remaining <= separator {
 				break
 			}
 			builder.WriteString("\n\n")
-			remaining -= 2
+			remaining -= separator
 		}
-		runes := []rune(chunk.Text)
-		if len(runes) > remaining {
-			builder.WriteString(string(runes[:remaining]))
-			remaining = 0
+		cost := est.EstimateTokens(chunk.Text)
+		if cost > remaining {
+			builder.WriteString(llm.ClipTokens(chunk.Text, remaining, est))
 			break
 		}
 		builder.WriteString(chunk.Text)
-		remaining -= len(runes)
+		remaining -= cost
 	}
 	return builder.String()
 }
@@ -185,6 +251,24 @@ func runeLen(text string) int {
 	return len([]rune(text))
 }
 
+// TechnicalRemainder returns up to budget tokens (counted by est) of technically ranked chunks that
+// are not part of used (the technical section context), giving a follow-up prompt material the
+// first pass did not see. When everything already fit, it falls back to the lower-ranked half of
+// the chunks.
+func TechnicalRemainder(chunks []Chunk, used string, budget int, est llm.TokenEstimator) string {
+	ranked := rankChunksForTechnical(chunks)
+	var rest []Chunk
+	for _, chunk := range ranked {
+		if !strings.Contains(used, chunk.Text) {
+			rest = append(rest, chunk)
+		}
+	}
+	if len(rest) == 0 {
+		rest = ranked[len(ranked)/2:]
+	}
+	return clipChunks(rest, budget, est)
+}
+
 func rankChunksForTechnical(chunks []Chunk) []Chunk {
 	if len(chunks) == 0 {
 		return chunks
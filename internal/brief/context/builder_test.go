@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/csheth/browse/internal/arxiv"
 	"github.com/csheth/browse/internal/llm"
 )
 
@@ -41,7 +42,7 @@ func TestBuilderRespectsBudgets(t *testing.T) {
 	content := strings.Repeat("This paragraph is somewhat long and detailed.\n\n", 5)
 	pkg := builder.Build(content)
 	for kind, limit := range budgets {
-		got := len([]rune(pkg.Sections[kind]))
+		got := llm.HeuristicEstimator{}.EstimateTokens(pkg.Sections[kind])
 		if got > limit {
 			t.Fatalf("%s exceeded budget: got %d want <= %d", kind, got, limit)
 		}
@@ -63,3 +64,62 @@ func TestRankChunksForTechnicalPrioritizesRelevantParagraphs(t *testing.T) {
 		t.Fatalf("expected first chunk to mention method, got %s", ranked[0].Text)
 	}
 }
+
+func TestBuildWithSectionsLeadsTechnicalWithMethodSections(t *testing.T) {
+	builder := NewBuilder(nil)
+	sections := []arxiv.Section{
+		{Heading: "1 Introduction", Text: "Transformers are everywhere and attention is costly."},
+		{Heading: "2 Related Work", Text: "Prior models used dense attention throughout training."},
+		{Heading: "3 Method", Text: "We prune attention heads with a learned gate."},
+		{Heading: "4 Experiments", Text: "We evaluate on GLUE against dense baselines."},
+		{Heading: "References", Text: "[1] A. Author. A paper."},
+	}
+	content := "Transformers are everywhere and attention is costly. Prior models used dense attention throughout training. We prune attention heads with a learned gate. We evaluate on GLUE against dense baselines."
+
+	pkg := builder.BuildWithSections(content, sections)
+	technical := pkg.Sections[llm.BriefTechnical]
+	if !strings.HasPrefix(technical, "3 Method\nWe prune attention heads") {
+		t.Fatalf("expected the method section first, got %q", technical)
+	}
+	method := strings.Index(technical, "3 Method")
+	experiments := strings.Index(technical, "4 Experiments")
+	intro := strings.Index(technical, "1 Introduction")
+	if experiments < method || intro < experiments {
+		t.Fatalf("expected method, experiments, then the remaining sections, got %q", technical)
+	}
+	if strings.Contains(technical, "A. Author") {
+		t.Fatalf("expected references dropped from technical context, got %q", technical)
+	}
+	if pkg.Sections[llm.BriefSummary] != builder.Build(content).Sections[llm.BriefSummary] {
+		t.Fatal("expected other sections unchanged")
+	}
+}
+
+func TestBuildWithSectionsFallsBackWithoutTechnicalHeadings(t *testing.T) {
+	builder := NewBuilder(nil)
+	content := "The method relies on contrastive pretraining."
+	sections := []arxiv.Section{{Heading: "Introduction", Text: content}}
+
+	if got, want := builder.BuildWithSections(content, sections).Sections[llm.BriefTechnical], builder.Build(content).Sections[llm.BriefTechnical]; got != want {
+		t.Fatalf("expected Build's technical context, got %q want %q", got, want)
+	}
+}
+
+func TestClipChunksCountsCodeTokensWithEstimator(t *testing.T) {
+	code := strings.Repeat("for (i = 0; i < n; i++) { x[i] = f(y[i], 2); }\n", 20)
+	chunks := []Chunk{{Text: code}}
+	budget := 60
+	est := llm.BPEEstimator{}
+
+	clipped := clipChunks(chunks, budget, est)
+	if got := est.EstimateTokens(clipped); got > budget {
+		t.Fatalf("expected at most %d estimated tokens, got %d", budget, got)
+	}
+	heuristic := clipChunks(chunks, budget, nil)
+	if est.EstimateTokens(heuristic) <= budget {
+		t.Fatal("expected the heuristic clip to overrun the BPE budget on code")
+	}
+	if runeLen(clipped) >= runeLen(heuristic) {
+		t.Fatalf("expected the BPE clip to keep fewer runes than the heuristic: %d vs %d", runeLen(clipped), runeLen(heuristic))
+	}
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/csheth/browse/internal/guide"
+)
+
+// Brief bundles the paper metadata and generated reading-brief sections for export.
+type Brief struct {
+	PaperID string
+	// SourceName and URL link the paper's landing page; an empty URL links PaperID on arXiv.
+	SourceName string
+	URL        string
+	Title      string
+	Authors    []string
+	Summary    []string
+	Technical  []string
+	DeepDive   []string
+	Guide      []guide.Step
+}
+
+// Options toggles optional blocks in the exported artifact.
+type Options struct {
+	IncludeGuide bool
+}
+
+// BriefMarkdown renders the reading brief as a standalone Markdown document. When IncludeGuide is
+// set, the personalized guide steps are appended so the export doubles as a reading plan.
+func BriefMarkdown(brief Brief, opts Options) string {
+	var b strings.Builder
+	writeHeader(&b, brief.Title, brief.SourceName, brief.URL, brief.PaperID, brief.Authors)
+
+	writeSection(&b, "Summary", brief.Summary)
+	writeSection(&b, "Technical", brief.Technical)
+	writeSection(&b, "Deep Dive", brief.DeepDive)
+
+	if opts.IncludeGuide && len(brief.Guide) > 0 {
+		b.WriteString("## Reading Guide\n\n")
+		for idx, step := range brief.Guide {
+			b.WriteString(fmt.Sprintf("%d. **%s** – %s\n", idx+1, strings.TrimSpace(step.Title), strings.TrimSpace(step.Description)))
+		}
+		b.WriteRune('\n')
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// writeHeader writes the paper title, its landing page (or arXiv link), and authors.
+func writeHeader(b *strings.Builder, title, sourceName, url, paperID string, authors []string) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = "Untitled paper"
+	}
+	b.WriteString("# " + title + "\n\n")
+	if url = strings.TrimSpace(url); url != "" {
+		b.WriteString(fmt.Sprintf("- %s: %s\n", sourceName, url))
+	} else if id := strings.TrimSpace(paperID); id != "" {
+		b.WriteString(fmt.Sprintf("- arXiv: https://arxiv.org/abs/%s\n", id))
+	}
+	if len(authors) > 0 {
+		b.WriteString("- Authors: " + strings.Join(authors, ", ") + "\n")
+	}
+	b.WriteRune('\n')
+}
+
+func writeSection(b *strings.Builder, heading string, bullets []string) {
+	b.WriteString("## " + heading + "\n\n")
+	lines := sectionLines(heading, bullets)
+	if len(lines) == 0 {
+		b.WriteString("_Not generated yet._\n\n")
+		return
+	}
+	b.WriteString(strings.Join(lines, "\n"))
+	b.WriteString("\n\n")
+}
+
+// sectionLines flattens the section bullets and drops the "### <Heading>" line the LLM prompt asks
+// for, since the export already emits its own heading.
+func sectionLines(heading string, bullets []string) []string {
+	var lines []string
+	for _, bullet := range bullets {
+		for _, line := range strings.Split(bullet, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" {
+				continue
+			}
+			if strings.HasPrefix(trimmed, "#") {
+				if strings.EqualFold(strings.TrimSpace(strings.TrimLeft(trimmed, "#")), heading) {
+					continue
+				}
+			}
+			lines = append(lines, strings.TrimRight(line, " \t"))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,52 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csheth/browse/internal/guide"
+)
+
+func TestBriefMarkdownRendersSections(t *testing.T) {
+	brief := Brief{
+		PaperID:   "2101.00001",
+		Title:     "Great Paper",
+		Authors:   []string{"Ada", "Grace"},
+		Summary:   []string{"### Summary\n- Problem framing\n  - Nested detail"},
+		Technical: []string{"- Dataset details"},
+	}
+
+	got := BriefMarkdown(brief, Options{})
+	for _, want := range []string{
+		"# Great Paper",
+		"https://arxiv.org/abs/2101.00001",
+		"Authors: Ada, Grace",
+		"## Summary\n\n- Problem framing\n  - Nested detail",
+		"## Technical\n\n- Dataset details",
+		"## Deep Dive\n\n_Not generated yet._",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected export to contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Count(got, "Summary") != 1 {
+		t.Fatalf("expected duplicate LLM heading to be dropped, got:\n%s", got)
+	}
+}
+
+func TestBriefMarkdownGuideToggle(t *testing.T) {
+	steps := guide.Build(guide.Metadata{Title: "Great Paper"})
+	brief := Brief{Title: "Great Paper", Guide: steps}
+
+	without := BriefMarkdown(brief, Options{})
+	if strings.Contains(without, "Reading Guide") || strings.Contains(without, steps[0].Title) {
+		t.Fatalf("expected guide to be omitted, got:\n%s", without)
+	}
+
+	with := BriefMarkdown(brief, Options{IncludeGuide: true})
+	for _, step := range steps {
+		if !strings.Contains(with, step.Title) {
+			t.Fatalf("expected guide step %q in export, got:\n%s", step.Title, with)
+		}
+	}
+}
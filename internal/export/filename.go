@@ -0,0 +1,75 @@
+package export
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultFileTemplate names brief exports after the arXiv identifier.
+const DefaultFileTemplate = "{id}-brief.md"
+
+// maxTitleRunes keeps {title} from producing file names longer than most filesystems allow.
+const maxTitleRunes = 80
+
+// FileNameFields supplies the values substituted into an export file name template.
+type FileNameFields struct {
+	ID    string
+	Title string
+	Date  time.Time
+}
+
+// RenderFileName expands {id}, {title}, and {date} (YYYY-MM-DD) in template. Substituted values are
+// sanitized so paper titles cannot introduce path separators or characters Windows rejects; the
+// template itself may still name a subdirectory. A template without an extension gets ".md".
+func RenderFileName(template string, fields FileNameFields) string {
+	if strings.TrimSpace(template) == "" {
+		template = DefaultFileTemplate
+	}
+	title := []rune(SanitizeFileComponent(fields.Title))
+	if len(title) > maxTitleRunes {
+		title = []rune(strings.TrimRight(string(title[:maxTitleRunes]), "_-."))
+	}
+	date := fields.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	template = strings.TrimSpace(template)
+	name := strings.NewReplacer(
+		"{id}", orUntitled(SanitizeFileComponent(fields.ID)),
+		"{title}", orUntitled(string(title)),
+		"{date}", date.Format("2006-01-02"),
+	).Replace(template)
+	name = filepath.Clean(name)
+	// Check the template, not the result: arXiv identifiers contain dots that look like extensions.
+	if filepath.Ext(template) == "" {
+		name += ".md"
+	}
+	return name
+}
+
+// SanitizeFileComponent makes value safe to use as a single path element: separators, reserved
+// punctuation, and control characters become "-", whitespace becomes "_", and leading dots are
+// dropped so the result is never hidden or a parent-directory reference.
+func SanitizeFileComponent(value string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(value) {
+		switch {
+		case strings.ContainsRune(`/\:*?"<>|`, r), unicode.IsControl(r):
+			b.WriteRune('-')
+		case unicode.IsSpace(r):
+			b.WriteRune('_')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.TrimLeft(b.String(), ".")
+}
+
+func orUntitled(value string) string {
+	if value == "" {
+		return "untitled"
+	}
+	return value
+}
@@ -0,0 +1,43 @@
+package export
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderFileNameExpandsPlaceholders(t *testing.T) {
+	fields := FileNameFields{
+		ID:    "2101.00001v2",
+		Title: `Attention: Is "All" You Need?/Revisited`,
+		Date:  time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC),
+	}
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"", "2101.00001v2-brief.md"},
+		{"{date}-{id}", "2025-03-04-2101.00001v2.md"},
+		{"{title}.md", "Attention-_Is_-All-_You_Need--Revisited.md"},
+		{"briefs/{id}.txt", "briefs/2101.00001v2.txt"},
+	}
+	for _, tt := range tests {
+		if got := RenderFileName(tt.template, fields); got != tt.want {
+			t.Fatalf("RenderFileName(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestRenderFileNameSanitizesUnsafeValues(t *testing.T) {
+	got := RenderFileName("{id}-{title}", FileNameFields{ID: "../../etc", Title: strings.Repeat("a", 200)})
+	if filepath.Base(got) != got || strings.HasPrefix(got, ".") {
+		t.Fatalf("expected substituted values to stay in one path element, got %q", got)
+	}
+	if len(got) > 100 {
+		t.Fatalf("expected long titles to be truncated, got %d chars", len(got))
+	}
+	if got := RenderFileName("{id}", FileNameFields{}); got != "untitled.md" {
+		t.Fatalf("expected empty id to render as untitled, got %q", got)
+	}
+}
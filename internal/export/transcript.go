@@ -0,0 +1,48 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultTranscriptTemplate names transcript exports when no template is configured.
+const DefaultTranscriptTemplate = "{id}-transcript.md"
+
+// Transcript bundles the paper metadata and the conversation, oldest entry first.
+type Transcript struct {
+	PaperID    string
+	SourceName string
+	URL        string
+	Title      string
+	Authors    []string
+	Entries    []TranscriptEntry
+}
+
+// TranscriptEntry is one conversation message with the speaker label the TUI shows for it.
+type TranscriptEntry struct {
+	Label     string
+	Timestamp time.Time
+	Content   string
+}
+
+// TranscriptMarkdown renders the conversation as a Markdown document: the paper header used by
+// BriefMarkdown, then one heading per message with its speaker and local time.
+func TranscriptMarkdown(transcript Transcript) string {
+	var b strings.Builder
+	writeHeader(&b, transcript.Title, transcript.SourceName, transcript.URL, transcript.PaperID, transcript.Authors)
+	b.WriteString("## Conversation\n\n")
+	for _, entry := range transcript.Entries {
+		content := strings.TrimSpace(entry.Content)
+		if content == "" {
+			continue
+		}
+		heading := strings.TrimSpace(entry.Label)
+		if !entry.Timestamp.IsZero() {
+			heading = fmt.Sprintf("%s · %s", heading, entry.Timestamp.Local().Format("2006-01-02 15:04:05"))
+		}
+		b.WriteString("### " + heading + "\n\n")
+		b.WriteString(content + "\n\n")
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
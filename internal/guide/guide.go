@@ -15,6 +15,10 @@ type Step struct {
 type Metadata struct {
 	Title   string
 	Authors []string
+	// Subject is the primary subject or reader-chosen focus (eg. an arXiv category such as cs.LG).
+	Subject string
+	// Contributions are the extracted key contributions used to anchor the second pass.
+	Contributions []string
 }
 
 // Build returns a three-pass inspired reading checklist tailored for a single paper.
@@ -28,14 +32,23 @@ func Build(meta Metadata) []Step {
 		authors = fmt.Sprintf(" by %s", strings.Join(meta.Authors, ", "))
 	}
 
+	skim := fmt.Sprintf("Spend five minutes to answer: What domain is %s%s in? Note the venue, section structure, key figures, and unfamiliar terms for later lookup.", displayTitle, authors)
+	if subject := strings.TrimSpace(meta.Subject); subject != "" {
+		skim = fmt.Sprintf("Spend five minutes placing %s%s within %s: which open problem in %s does it target? Note the venue, section structure, key figures, and unfamiliar terms for later lookup.", displayTitle, authors, subject, subject)
+	}
+	grasp := "Dive into the core sections and redraw the main figure or diagram. Summarize the problem statement, method, and evaluation setup in your own words and flag any assumptions."
+	if claims := firstContributions(meta.Contributions, 2); len(claims) > 0 {
+		grasp += fmt.Sprintf(" Check the evidence behind the stated contributions: %s.", strings.Join(claims, "; "))
+	}
+
 	return []Step{
 		{
 			Title:       "Pass 1 – Quick skim",
-			Description: fmt.Sprintf("Spend five minutes to answer: What domain is %s%s in? Note the venue, section structure, key figures, and unfamiliar terms for later lookup.", displayTitle, authors),
+			Description: skim,
 		},
 		{
 			Title:       "Pass 2 – Grasp the content",
-			Description: "Dive into the core sections and redraw the main figure or diagram. Summarize the problem statement, method, and evaluation setup in your own words and flag any assumptions.",
+			Description: grasp,
 		},
 		{
 			Title:       "Pass 3 – Deep audit",
@@ -51,3 +64,33 @@ func Build(meta Metadata) []Step {
 		},
 	}
 }
+
+// Rebuild recomputes the guide from the current metadata and reports whether any step changed, so
+// callers can tell the reader when a refresh was a no-op.
+func Rebuild(previous []Step, meta Metadata) ([]Step, bool) {
+	steps := Build(meta)
+	if len(steps) != len(previous) {
+		return steps, true
+	}
+	for idx := range steps {
+		if steps[idx] != previous[idx] {
+			return steps, true
+		}
+	}
+	return steps, false
+}
+
+func firstContributions(contributions []string, limit int) []string {
+	var result []string
+	for _, contribution := range contributions {
+		contribution = strings.TrimRight(strings.TrimSpace(contribution), ".")
+		if contribution == "" {
+			continue
+		}
+		result = append(result, contribution)
+		if len(result) == limit {
+			break
+		}
+	}
+	return result
+}
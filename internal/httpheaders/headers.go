@@ -0,0 +1,57 @@
+// Package httpheaders centralizes the identification and custom headers PaperScout attaches to
+// every outbound HTTP request (arXiv API, PDF downloads, and LLM providers).
+package httpheaders
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/csheth/browse/internal/version"
+)
+
+// Config describes the User-Agent plus any extra headers (eg. proxy auth) to send.
+type Config struct {
+	UserAgent string
+	Extra     http.Header
+}
+
+// DefaultUserAgent identifies the client politely as paperscout/<version>.
+func DefaultUserAgent() string {
+	return "paperscout/" + version.Version
+}
+
+// Apply stamps the configured headers onto req. Extra headers never override ones the caller set
+// explicitly (Content-Type, Range, conditional headers), so protocol logic stays intact.
+func Apply(req *http.Request, cfg Config) {
+	if req == nil {
+		return
+	}
+	for key, values := range cfg.Extra {
+		if req.Header.Get(key) != "" || len(values) == 0 {
+			continue
+		}
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	userAgent := strings.TrimSpace(cfg.UserAgent)
+	if userAgent == "" {
+		userAgent = DefaultUserAgent()
+	}
+	req.Header.Set("User-Agent", userAgent)
+}
+
+// Parse converts "Key: Value" pairs into an http.Header.
+func Parse(pairs []string) (http.Header, error) {
+	headers := http.Header{}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid header %q; expected \"Key: Value\"", pair)
+		}
+		headers.Add(key, strings.TrimSpace(value))
+	}
+	return headers, nil
+}
@@ -0,0 +1,41 @@
+package httpheaders
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestApplySetsDefaultUserAgentAndExtras(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	extra := http.Header{}
+	extra.Set("X-Proxy-Auth", "token")
+	extra.Set("Content-Type", "text/plain")
+
+	Apply(req, Config{Extra: extra})
+	if got := req.Header.Get("User-Agent"); got != DefaultUserAgent() {
+		t.Fatalf("expected default user agent, got %q", got)
+	}
+	if got := req.Header.Get("X-Proxy-Auth"); got != "token" {
+		t.Fatalf("expected extra header, got %q", got)
+	}
+	if got := req.Header.Get("Content-Type"); got != "application/json" {
+		t.Fatalf("extra headers must not override explicit ones, got %q", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	headers, err := Parse([]string{"Authorization: Bearer abc", "X-Empty:"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if headers.Get("Authorization") != "Bearer abc" {
+		t.Fatalf("unexpected headers: %#v", headers)
+	}
+	if _, err := Parse([]string{"missing-colon"}); err == nil {
+		t.Fatal("expected error for malformed header")
+	}
+}
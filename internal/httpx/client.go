@@ -0,0 +1,68 @@
+// Package httpx builds the HTTP clients PaperScout uses for arXiv, PDF downloads, and the LLM
+// providers, so they all go through HTTPS_PROXY (and the other proxy variables) and trust an
+// optional extra CA bundle for corporate proxies that re-sign TLS traffic.
+package httpx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CABundleEnv names a PEM file of extra root certificates trusted on top of the system pool.
+const CABundleEnv = "PAPERSCOUT_CA_BUNDLE"
+
+var (
+	sharedOnce      sync.Once
+	sharedTransport *http.Transport
+	sharedErr       error
+)
+
+// NewClient returns a client with the given timeout that shares one proxy- and CA-aware transport,
+// so connections are pooled across callers.
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: transport()}
+}
+
+// Check reports whether the CA bundle named by PAPERSCOUT_CA_BUNDLE loaded. main calls it at startup
+// so a bad path fails loudly instead of surfacing later as a TLS error.
+func Check() error {
+	transport()
+	return sharedErr
+}
+
+func transport() *http.Transport {
+	sharedOnce.Do(func() {
+		sharedTransport, sharedErr = newTransport()
+	})
+	return sharedTransport
+}
+
+// newTransport clones the default transport, keeps its timeouts, and adds the CA bundle from the
+// environment. On error it still returns a usable transport with the system roots.
+func newTransport() (*http.Transport, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.Proxy = http.ProxyFromEnvironment
+	path := strings.TrimSpace(os.Getenv(CABundleEnv))
+	if path == "" {
+		return base, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return base, fmt.Errorf("%s: %w", CABundleEnv, err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return base, fmt.Errorf("%s: no PEM certificates in %s", CABundleEnv, path)
+	}
+	base.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	return base, nil
+}
@@ -0,0 +1,240 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/csheth/browse/internal/httpheaders"
+)
+
+const (
+	// defaultAnthropicModel tracks the latest claude-3-5-sonnet release.
+	defaultAnthropicModel   = "claude-3-5-sonnet-latest"
+	defaultAnthropicBaseURL = "https://api.anthropic.com"
+	anthropicVersion        = "2023-06-01"
+	// anthropicMaxTokens bounds each response; briefs and answers stay well under it.
+	anthropicMaxTokens = 4096
+)
+
+type anthropicClient struct {
+	baseURL  string
+	apiKey   string
+	model    string
+	client   *http.Client
+	headers  httpheaders.Config
+	onRetry  RetryNotifier
+	debugLog *log.Logger
+	// wait pauses between rate-limit retries; nil uses a context-aware timer.
+	wait func(ctx context.Context, d time.Duration) error
+	// tokens sizes prompt context; nil uses HeuristicEstimator.
+	tokens TokenEstimator
+	// lengths overrides the bullet counts and word caps prompts ask for.
+	lengths promptLengths
+	// system replaces the default (empty) system prompt; see Config.SystemPrompt.
+	system string
+	// temperature is the sampling temperature sent with every request; see Config.Temperature.
+	temperature float64
+}
+
+func (c *anthropicClient) Name() string {
+	return fmt.Sprintf("Anthropic (%s)", c.model)
+}
+
+func (c *anthropicClient) tokenEstimator() TokenEstimator {
+	return c.tokens
+}
+
+func (c *anthropicClient) promptLengths() promptLengths {
+	return c.lengths
+}
+
+func (c *anthropicClient) systemPrompt() string {
+	return c.system
+}
+
+func (c *anthropicClient) samplingTemperature() float64 {
+	return c.temperature
+}
+
+func (c *anthropicClient) Summarize(ctx context.Context, title, content string) (string, error) {
+	return summarize(ctx, c, title, content)
+}
+
+func (c *anthropicClient) Answer(ctx context.Context, title, question, content string) (string, error) {
+	return answer(ctx, c, title, question, content)
+}
+
+func (c *anthropicClient) StreamAnswer(ctx context.Context, title, question, content string, handler AnswerStreamHandler) error {
+	return streamAnswer(ctx, c, title, question, content, handler)
+}
+
+func (c *anthropicClient) SuggestNotes(ctx context.Context, title, abstract string, contributions []string, content string) ([]SuggestedNote, error) {
+	return suggestNotes(ctx, c, c.debugLog, title, abstract, contributions, content)
+}
+
+func (c *anthropicClient) StreamSuggestNotes(ctx context.Context, title, abstract string, contributions []string, content string, handler SuggestedNoteHandler) ([]SuggestedNote, error) {
+	return streamSuggestNotes(ctx, c, c.debugLog, title, abstract, contributions, content, handler)
+}
+
+func (c *anthropicClient) NoteGaps(ctx context.Context, title string, existing []SuggestedNote, content string) ([]SuggestedNote, error) {
+	return noteGaps(ctx, c, c.debugLog, title, existing, content)
+}
+
+func (c *anthropicClient) ReadingBrief(ctx context.Context, title, content string) (ReadingBrief, error) {
+	return readingBrief(ctx, c, c.debugLog, title, content)
+}
+
+func (c *anthropicClient) BriefSection(ctx context.Context, kind BriefSectionKind, title, content string) ([]string, error) {
+	return briefSection(ctx, c, c.debugLog, kind, title, content)
+}
+
+func (c *anthropicClient) StreamBriefSection(ctx context.Context, kind BriefSectionKind, title, content string, detail DetailLevel, handler BriefSectionStreamHandler) error {
+	return streamBriefSection(ctx, c, kind, title, content, detail, handler)
+}
+
+func (c *anthropicClient) ContinueBriefSection(ctx context.Context, kind BriefSectionKind, title, content string, existing []string, missing int) ([]string, error) {
+	return continueBriefSection(ctx, c, c.debugLog, kind, title, content, existing, missing)
+}
+
+// messagesPayload builds a single-turn /v1/messages body carrying the prompt as the user message
+// and, when configured, the system prompt.
+func (c *anthropicClient) messagesPayload(prompt string, stream bool) map[string]any {
+	payload := map[string]any{
+		"model":      c.model,
+		"max_tokens": anthropicMaxTokens,
+		// The Messages API only accepts temperatures up to 1.
+		"temperature": min(c.temperature, 1),
+		"stream":      stream,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+	if c.system != "" {
+		payload["system"] = c.system
+	}
+	return payload
+}
+
+// post sends the messages payload, retrying 429 and 529 (overloaded) responses through retryPost.
+func (c *anthropicClient) post(ctx context.Context, body []byte) (*http.Response, error) {
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/messages", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("x-api-key", c.apiKey)
+		req.Header.Set("anthropic-version", anthropicVersion)
+		httpheaders.Apply(req, c.headers)
+		return req, nil
+	}
+	retryable := func(status int) bool { return status == http.StatusTooManyRequests || status == 529 }
+	return retryPost(ctx, c.client, newRequest, retryable, c.onRetry, c.wait)
+}
+
+func (c *anthropicClient) generate(ctx context.Context, prompt string) (string, error) {
+	buf, err := json.Marshal(c.messagesPayload(prompt, false))
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.post(ctx, buf)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("anthropic API error: %s (%s)", resp.Status, string(body))
+	}
+
+	var parsed struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", err
+	}
+	var text strings.Builder
+	for _, block := range parsed.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if strings.TrimSpace(text.String()) == "" {
+		return "", fmt.Errorf("anthropic returned an empty response")
+	}
+	return strings.TrimSpace(text.String()), nil
+}
+
+// streamGenerate reads the Messages API server-sent events, forwarding each text_delta and
+// reporting done on message_stop.
+func (c *anthropicClient) streamGenerate(ctx context.Context, prompt string, fn func(chunk string, done bool) error) error {
+	buf, err := json.Marshal(c.messagesPayload(prompt, true))
+	if err != nil {
+		return err
+	}
+	resp, err := c.post(ctx, buf)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("anthropic API error: %s (%s)", resp.Status, string(body))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		var event struct {
+			Type  string `json:"type"`
+			Delta struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			} `json:"delta"`
+			Error struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &event); err != nil {
+			return err
+		}
+		switch event.Type {
+		case "content_block_delta":
+			if event.Delta.Type != "text_delta" {
+				continue
+			}
+			if err := fn(event.Delta.Text, false); err != nil {
+				return err
+			}
+		case "message_stop":
+			return fn("", true)
+		case "error":
+			return fmt.Errorf("anthropic stream error: %s (%s)", event.Error.Message, event.Error.Type)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("anthropic stream ended before message_stop")
+}
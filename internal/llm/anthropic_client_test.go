@@ -0,0 +1,186 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAnthropicClientSummarize(t *testing.T) {
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/v1/messages" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Method != http.MethodPost {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		if got := r.Header.Get("x-api-key"); got != "test-key" {
+			t.Fatalf("expected api key header, got %q", got)
+		}
+		if got := r.Header.Get("anthropic-version"); got != anthropicVersion {
+			t.Fatalf("expected anthropic-version %s, got %q", anthropicVersion, got)
+		}
+		var payload struct {
+			Model     string `json:"model"`
+			MaxTokens int    `json:"max_tokens"`
+			Stream    bool   `json:"stream"`
+			Messages  []struct {
+				Role    string `json:"role"`
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		if payload.Model != defaultAnthropicModel {
+			t.Fatalf("expected model %s, got %s", defaultAnthropicModel, payload.Model)
+		}
+		if payload.MaxTokens <= 0 {
+			t.Fatalf("expected max_tokens to be set, got %d", payload.MaxTokens)
+		}
+		if len(payload.Messages) != 1 || payload.Messages[0].Role != "user" || !strings.Contains(payload.Messages[0].Content, "Paper title: Cool Paper") {
+			t.Fatalf("unexpected messages: %+v", payload.Messages)
+		}
+		if payload.Stream {
+			t.Fatal("expected streaming to be disabled")
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"content":[{"type":"text","text":"Bullet 1"}],"stop_reason":"end_turn"}`)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	client := &anthropicClient{
+		baseURL: "http://example.com",
+		apiKey:  "test-key",
+		model:   defaultAnthropicModel,
+		client:  &http.Client{Transport: rt},
+	}
+
+	result, err := client.Summarize(context.Background(), "Cool Paper", "This is the PDF content.")
+	if err != nil {
+		t.Fatalf("summarize failed: %v", err)
+	}
+	if result != "Bullet 1" {
+		t.Fatalf("unexpected summarize result: %s", result)
+	}
+}
+
+func TestAnthropicClientStreamBriefSection(t *testing.T) {
+	stream := strings.Join([]string{
+		"event: message_start",
+		`data: {"type":"message_start","message":{"id":"msg_1"}}`,
+		"",
+		"event: content_block_delta",
+		`data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"- first bullet\n"}}`,
+		"",
+		"event: ping",
+		`data: {"type":"ping"}`,
+		"",
+		"event: content_block_delta",
+		`data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"- second bullet"}}`,
+		"",
+		"event: message_stop",
+		`data: {"type":"message_stop"}`,
+		"",
+	}, "\n")
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(stream)),
+			Header:     make(http.Header),
+		}, nil
+	})
+	client := &anthropicClient{baseURL: "http://example.com", apiKey: "test-key", model: defaultAnthropicModel, client: &http.Client{Transport: rt}}
+
+	var deltas []BriefSectionDelta
+	err := client.StreamBriefSection(context.Background(), BriefSummary, "Cool Paper", "content", DetailStandard, func(delta BriefSectionDelta) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("stream brief section failed: %v", err)
+	}
+	if len(deltas) != 3 || !deltas[len(deltas)-1].Done {
+		t.Fatalf("expected two text deltas and a final done delta, got %+v", deltas)
+	}
+	if final := deltas[len(deltas)-1].Bullets; len(final) != 1 || !strings.Contains(final[0], "- first bullet\n- second bullet") {
+		t.Fatalf("unexpected final bullets %q", final)
+	}
+}
+
+func TestAnthropicClientSendsSystemPrompt(t *testing.T) {
+	var seen []any
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		seen = append(seen, payload["system"])
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"content":[{"type":"text","text":"Bullet 1"}],"stop_reason":"end_turn"}`)),
+			Header:     make(http.Header),
+		}, nil
+	})
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	client, err := NewFromEnv(Config{
+		Provider:     ProviderAnthropic,
+		Endpoint:     "http://example.com",
+		HTTPClient:   &http.Client{Transport: rt},
+		SystemPrompt: "  Focus on reproducibility.  ",
+	})
+	if err != nil {
+		t.Fatalf("NewFromEnv() error = %v", err)
+	}
+	if _, err := client.Summarize(context.Background(), "Cool Paper", "This is the PDF content."); err != nil {
+		t.Fatalf("summarize failed: %v", err)
+	}
+	client.(*anthropicClient).system = ""
+	if _, err := client.Summarize(context.Background(), "Cool Paper", "This is the PDF content."); err != nil {
+		t.Fatalf("summarize failed: %v", err)
+	}
+
+	if len(seen) != 2 || seen[0] != "Focus on reproducibility." {
+		t.Fatalf("expected the trimmed system prompt in the payload, got %v", seen)
+	}
+	if seen[1] != nil {
+		t.Fatalf("expected no system prompt by default, got %v", seen[1])
+	}
+}
+
+func TestNewFromEnvPicksProvider(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("GEMINI_API_KEY", "")
+	client, err := NewFromEnv(Config{})
+	if err != nil || !strings.HasPrefix(client.Name(), "Ollama") {
+		t.Fatalf("expected Ollama without an Anthropic key, got %v (%v)", client, err)
+	}
+	if _, err := NewFromEnv(Config{Provider: ProviderAnthropic}); err == nil {
+		t.Fatal("expected anthropic provider to require ANTHROPIC_API_KEY")
+	}
+
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	t.Setenv("ANTHROPIC_BASE_URL", "https://proxy.example.com/")
+	client, err = NewFromEnv(Config{})
+	if err != nil {
+		t.Fatalf("NewFromEnv() error = %v", err)
+	}
+	anthropic, ok := client.(*anthropicClient)
+	if !ok {
+		t.Fatalf("expected anthropic client under auto mode, got %T", client)
+	}
+	if anthropic.baseURL != "https://proxy.example.com" || anthropic.model != defaultAnthropicModel {
+		t.Fatalf("unexpected anthropic config: %+v", anthropic)
+	}
+	if client, _ := NewFromEnv(Config{Provider: ProviderOllama}); !strings.HasPrefix(client.Name(), "Ollama") {
+		t.Fatalf("expected explicit ollama provider to win, got %s", client.Name())
+	}
+	if _, err := ParseProvider("openai"); err == nil {
+		t.Fatal("expected unknown provider to be rejected")
+	}
+}
@@ -0,0 +1,240 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheTTL is how long cached responses are reused when CacheOptions.TTL is zero.
+const DefaultCacheTTL = 7 * 24 * time.Hour
+
+// CacheOptions configures NewCachingClient.
+type CacheOptions struct {
+	// Dir holds one JSON file per cached response; it is created on first write.
+	Dir string
+	// TTL bounds how old a cached response may be; zero uses DefaultCacheTTL.
+	TTL time.Duration
+	// Bypass skips cached responses but still stores fresh ones, which refreshes the cache.
+	Bypass bool
+}
+
+// CachingClient wraps a Client and reuses earlier responses for identical requests. Entries are
+// keyed by a hash of the wrapped client's name (which includes the model), the method, and every
+// prompt input, so a different model or paper text never hits a stale answer. Only deterministic
+// request/response calls are cached; StreamBriefSection replays a cached section as a single delta,
+// and StreamSuggestNotes replays cached suggestions one note at a time.
+// Errors reading or writing the cache fall through to the wrapped client.
+type CachingClient struct {
+	inner  Client
+	dir    string
+	ttl    time.Duration
+	bypass bool
+	now    func() time.Time
+}
+
+// NewCachingClient wraps inner with a response cache in opts.Dir.
+func NewCachingClient(inner Client, opts CacheOptions) *CachingClient {
+	ttl := opts.TTL
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &CachingClient{inner: inner, dir: opts.Dir, ttl: ttl, bypass: opts.Bypass, now: time.Now}
+}
+
+// Unwrap returns the client whose responses are cached.
+func (c *CachingClient) Unwrap() Client {
+	return c.inner
+}
+
+type cacheEntry struct {
+	CachedAt time.Time       `json:"cachedAt"`
+	Response json.RawMessage `json:"response"`
+}
+
+func (c *CachingClient) key(method string, inputs ...any) string {
+	parts := []any{c.inner.Name(), method}
+	// Configured bullet counts and word caps change the prompt, so they key separately too.
+	if shaped, ok := c.inner.(textGenerator); ok && shaped.promptLengths() != (promptLengths{}) {
+		lengths := shaped.promptLengths()
+		counts := []int{lengths.summaryBullets, lengths.bulletMaxWords}
+		if lengths.technicalBullets > 0 || lengths.deepDiveBullets > 0 {
+			counts = append(counts, lengths.technicalBullets, lengths.deepDiveBullets)
+		}
+		parts = append(parts, counts)
+	}
+	// So does a custom persona.
+	if shaped, ok := c.inner.(textGenerator); ok && shaped.systemPrompt() != "" {
+		parts = append(parts, shaped.systemPrompt())
+	}
+	// As does a non-default temperature; the default stays out of the key so earlier entries still hit.
+	if shaped, ok := c.inner.(textGenerator); ok && shaped.samplingTemperature() != DefaultTemperature {
+		parts = append(parts, shaped.samplingTemperature())
+	}
+	payload, _ := json.Marshal(append(parts, inputs...))
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *CachingClient) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// load decodes a fresh cached response for key into dst.
+func (c *CachingClient) load(key string, dst any) bool {
+	if c.bypass {
+		return false
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return false
+	}
+	if c.now().Sub(entry.CachedAt) > c.ttl {
+		return false
+	}
+	return json.Unmarshal(entry.Response, dst) == nil
+}
+
+// store records response for key; failures only cost a future cache miss.
+func (c *CachingClient) store(key string, response any) {
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(cacheEntry{CachedAt: c.now().UTC(), Response: raw})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+	tmp := c.path(key) + ".part"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, c.path(key)); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+func (c *CachingClient) Name() string {
+	return c.inner.Name()
+}
+
+func (c *CachingClient) Summarize(ctx context.Context, title, content string) (string, error) {
+	key := c.key("Summarize", title, content)
+	var cached string
+	if c.load(key, &cached) {
+		return cached, nil
+	}
+	summary, err := c.inner.Summarize(ctx, title, content)
+	if err == nil {
+		c.store(key, summary)
+	}
+	return summary, err
+}
+
+func (c *CachingClient) Answer(ctx context.Context, title, question, content string) (string, error) {
+	key := c.key("Answer", title, question, content)
+	var cached string
+	if c.load(key, &cached) {
+		return cached, nil
+	}
+	answer, err := c.inner.Answer(ctx, title, question, content)
+	if err == nil {
+		c.store(key, answer)
+	}
+	return answer, err
+}
+
+func (c *CachingClient) StreamAnswer(ctx context.Context, title, question, content string, handler AnswerStreamHandler) error {
+	return c.inner.StreamAnswer(ctx, title, question, content, handler)
+}
+
+func (c *CachingClient) SuggestNotes(ctx context.Context, title, abstract string, contributions []string, content string) ([]SuggestedNote, error) {
+	key := c.key("SuggestNotes", title, abstract, contributions, content)
+	var cached []SuggestedNote
+	if c.load(key, &cached) {
+		return cached, nil
+	}
+	suggestions, err := c.inner.SuggestNotes(ctx, title, abstract, contributions, content)
+	if err == nil {
+		c.store(key, suggestions)
+	}
+	return suggestions, err
+}
+
+// StreamSuggestNotes shares its cache entries with SuggestNotes, since both ask the same prompt. A
+// hit is handed to handler note by note; a miss streams from the wrapped client and is cached once
+// the stream completes without error.
+func (c *CachingClient) StreamSuggestNotes(ctx context.Context, title, abstract string, contributions []string, content string, handler SuggestedNoteHandler) ([]SuggestedNote, error) {
+	key := c.key("SuggestNotes", title, abstract, contributions, content)
+	var cached []SuggestedNote
+	if c.load(key, &cached) && len(cached) > 0 {
+		for _, note := range cached {
+			if err := handler(note); err != nil {
+				return cached, err
+			}
+		}
+		return cached, nil
+	}
+	suggestions, err := c.inner.StreamSuggestNotes(ctx, title, abstract, contributions, content, handler)
+	if err == nil && len(suggestions) > 0 {
+		c.store(key, suggestions)
+	}
+	return suggestions, err
+}
+
+func (c *CachingClient) NoteGaps(ctx context.Context, title string, existing []SuggestedNote, content string) ([]SuggestedNote, error) {
+	return c.inner.NoteGaps(ctx, title, existing, content)
+}
+
+func (c *CachingClient) ReadingBrief(ctx context.Context, title, content string) (ReadingBrief, error) {
+	return c.inner.ReadingBrief(ctx, title, content)
+}
+
+func (c *CachingClient) BriefSection(ctx context.Context, kind BriefSectionKind, title, content string) ([]string, error) {
+	key := c.key("BriefSection", kind, title, content)
+	var cached []string
+	if c.load(key, &cached) {
+		return cached, nil
+	}
+	bullets, err := c.inner.BriefSection(ctx, kind, title, content)
+	if err == nil && len(bullets) > 0 {
+		c.store(key, bullets)
+	}
+	return bullets, err
+}
+
+// StreamBriefSection replays a cached section as one finished delta. On a miss it streams from the
+// wrapped client and caches the final bullets once the stream completes without error.
+func (c *CachingClient) StreamBriefSection(ctx context.Context, kind BriefSectionKind, title, content string, detail DetailLevel, handler BriefSectionStreamHandler) error {
+	key := c.key("StreamBriefSection", kind, title, content, detail)
+	var cached []string
+	if c.load(key, &cached) && len(cached) > 0 {
+		return handler(BriefSectionDelta{Kind: kind, Bullets: cached, Done: true})
+	}
+	var final []string
+	err := c.inner.StreamBriefSection(ctx, kind, title, content, detail, func(delta BriefSectionDelta) error {
+		if len(delta.Bullets) > 0 {
+			final = append([]string(nil), delta.Bullets...)
+		}
+		return handler(delta)
+	})
+	if err == nil && len(final) > 0 {
+		c.store(key, final)
+	}
+	return err
+}
+
+func (c *CachingClient) ContinueBriefSection(ctx context.Context, kind BriefSectionKind, title, content string, existing []string, missing int) ([]string, error) {
+	return c.inner.ContinueBriefSection(ctx, kind, title, content, existing, missing)
+}
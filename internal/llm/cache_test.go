@@ -0,0 +1,205 @@
+package llm
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// countingClient answers every call with fixed content and counts how often it was asked.
+type countingClient struct {
+	calls map[string]int
+}
+
+func newCountingClient() *countingClient {
+	return &countingClient{calls: map[string]int{}}
+}
+
+func (c *countingClient) Name() string { return "Counting (test-model)" }
+
+func (c *countingClient) Summarize(ctx context.Context, title, content string) (string, error) {
+	c.calls["Summarize"]++
+	return "summary of " + title, nil
+}
+
+func (c *countingClient) Answer(ctx context.Context, title, question, content string) (string, error) {
+	c.calls["Answer"]++
+	return "answer to " + question, nil
+}
+
+func (c *countingClient) StreamAnswer(ctx context.Context, title, question, content string, handler AnswerStreamHandler) error {
+	c.calls["StreamAnswer"]++
+	return handler(AnswerDelta{Text: "answer", Done: true})
+}
+
+func (c *countingClient) SuggestNotes(ctx context.Context, title, abstract string, contributions []string, content string) ([]SuggestedNote, error) {
+	c.calls["SuggestNotes"]++
+	return []SuggestedNote{{Title: "Idea", Body: "Body", Kind: "concept"}}, nil
+}
+
+func (c *countingClient) StreamSuggestNotes(ctx context.Context, title, abstract string, contributions []string, content string, handler SuggestedNoteHandler) ([]SuggestedNote, error) {
+	c.calls["StreamSuggestNotes"]++
+	notes := []SuggestedNote{{Title: "Idea", Body: "Body", Kind: "concept"}, {Title: "Method", Body: "Routing", Kind: "method"}}
+	for _, note := range notes {
+		if err := handler(note); err != nil {
+			return nil, err
+		}
+	}
+	return notes, nil
+}
+
+func (c *countingClient) NoteGaps(ctx context.Context, title string, existing []SuggestedNote, content string) ([]SuggestedNote, error) {
+	c.calls["NoteGaps"]++
+	return nil, nil
+}
+
+func (c *countingClient) ReadingBrief(ctx context.Context, title, content string) (ReadingBrief, error) {
+	c.calls["ReadingBrief"]++
+	return ReadingBrief{}, nil
+}
+
+func (c *countingClient) BriefSection(ctx context.Context, kind BriefSectionKind, title, content string) ([]string, error) {
+	c.calls["BriefSection"]++
+	return []string{"- " + string(kind)}, nil
+}
+
+func (c *countingClient) StreamBriefSection(ctx context.Context, kind BriefSectionKind, title, content string, detail DetailLevel, handler BriefSectionStreamHandler) error {
+	c.calls["StreamBriefSection"]++
+	if err := handler(BriefSectionDelta{Kind: kind, Bullets: []string{"- First"}}); err != nil {
+		return err
+	}
+	return handler(BriefSectionDelta{Kind: kind, Bullets: []string{"- First", "- Second"}, Done: true})
+}
+
+func (c *countingClient) ContinueBriefSection(ctx context.Context, kind BriefSectionKind, title, content string, existing []string, missing int) ([]string, error) {
+	c.calls["ContinueBriefSection"]++
+	return nil, nil
+}
+
+func TestCachingClientServesHitsAndForwardsMisses(t *testing.T) {
+	inner := newCountingClient()
+	client := NewCachingClient(inner, CacheOptions{Dir: t.TempDir()})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if got, err := client.Answer(ctx, "Paper", "What is new?", "text"); err != nil || got != "answer to What is new?" {
+			t.Fatalf("Answer() = %q, %v", got, err)
+		}
+	}
+	if inner.calls["Answer"] != 1 {
+		t.Fatalf("expected the second answer from the cache, got %d calls", inner.calls["Answer"])
+	}
+
+	if _, err := client.Answer(ctx, "Paper", "What is old?", "text"); err != nil {
+		t.Fatalf("Answer() error = %v", err)
+	}
+	if inner.calls["Answer"] != 2 {
+		t.Fatalf("expected a different question to miss, got %d calls", inner.calls["Answer"])
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Summarize(ctx, "Paper", "text"); err != nil {
+			t.Fatalf("Summarize() error = %v", err)
+		}
+		if notes, err := client.SuggestNotes(ctx, "Paper", "abstract", []string{"c"}, "text"); err != nil || len(notes) != 1 || notes[0].Kind != "concept" {
+			t.Fatalf("SuggestNotes() = %+v, %v", notes, err)
+		}
+		if bullets, err := client.BriefSection(ctx, BriefTechnical, "Paper", "text"); err != nil || len(bullets) != 1 {
+			t.Fatalf("BriefSection() = %v, %v", bullets, err)
+		}
+	}
+	for _, method := range []string{"Summarize", "SuggestNotes", "BriefSection"} {
+		if inner.calls[method] != 1 {
+			t.Fatalf("expected %s to be cached, got %d calls", method, inner.calls[method])
+		}
+	}
+}
+
+func TestCachingClientReplaysStreamedBriefSectionAsOneDelta(t *testing.T) {
+	inner := newCountingClient()
+	client := NewCachingClient(inner, CacheOptions{Dir: t.TempDir()})
+	ctx := context.Background()
+
+	var first []BriefSectionDelta
+	if err := client.StreamBriefSection(ctx, BriefSummary, "Paper", "text", DetailStandard, func(delta BriefSectionDelta) error {
+		first = append(first, delta)
+		return nil
+	}); err != nil {
+		t.Fatalf("StreamBriefSection() error = %v", err)
+	}
+	if len(first) != 2 {
+		t.Fatalf("expected the miss to stream through, got %+v", first)
+	}
+
+	var replay []BriefSectionDelta
+	if err := client.StreamBriefSection(ctx, BriefSummary, "Paper", "text", DetailStandard, func(delta BriefSectionDelta) error {
+		replay = append(replay, delta)
+		return nil
+	}); err != nil {
+		t.Fatalf("StreamBriefSection() error = %v", err)
+	}
+	if inner.calls["StreamBriefSection"] != 1 {
+		t.Fatalf("expected the second stream from the cache, got %d calls", inner.calls["StreamBriefSection"])
+	}
+	if len(replay) != 1 || !replay[0].Done || len(replay[0].Bullets) != 2 || replay[0].Kind != BriefSummary {
+		t.Fatalf("expected one finished delta with both bullets, got %+v", replay)
+	}
+
+	if err := client.StreamBriefSection(ctx, BriefSummary, "Paper", "text", DetailExpanded, func(BriefSectionDelta) error { return nil }); err != nil {
+		t.Fatalf("StreamBriefSection() error = %v", err)
+	}
+	if inner.calls["StreamBriefSection"] != 2 {
+		t.Fatalf("expected a different detail level to miss, got %d calls", inner.calls["StreamBriefSection"])
+	}
+}
+
+func TestCachingClientReplaysStreamedSuggestionsNoteByNote(t *testing.T) {
+	inner := newCountingClient()
+	client := NewCachingClient(inner, CacheOptions{Dir: t.TempDir()})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		var streamed []SuggestedNote
+		notes, err := client.StreamSuggestNotes(ctx, "Paper", "abstract", nil, "text", func(note SuggestedNote) error {
+			streamed = append(streamed, note)
+			return nil
+		})
+		if err != nil || len(notes) != 2 {
+			t.Fatalf("StreamSuggestNotes() = %+v, %v", notes, err)
+		}
+		if len(streamed) != 2 || streamed[0].Title != "Idea" || streamed[1].Title != "Method" {
+			t.Fatalf("expected each note handed over in order, got %+v", streamed)
+		}
+	}
+	if inner.calls["StreamSuggestNotes"] != 1 {
+		t.Fatalf("expected the second stream from the cache, got %d calls", inner.calls["StreamSuggestNotes"])
+	}
+}
+
+func TestCachingClientHonorsTTLAndBypass(t *testing.T) {
+	dir := t.TempDir()
+	inner := newCountingClient()
+	client := NewCachingClient(inner, CacheOptions{Dir: dir, TTL: time.Hour})
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	client.Summarize(ctx, "Paper", "text")
+	now = now.Add(30 * time.Minute)
+	client.Summarize(ctx, "Paper", "text")
+	if inner.calls["Summarize"] != 1 {
+		t.Fatalf("expected a fresh entry to hit, got %d calls", inner.calls["Summarize"])
+	}
+	now = now.Add(time.Hour)
+	client.Summarize(ctx, "Paper", "text")
+	if inner.calls["Summarize"] != 2 {
+		t.Fatalf("expected an expired entry to miss, got %d calls", inner.calls["Summarize"])
+	}
+
+	bypass := NewCachingClient(inner, CacheOptions{Dir: dir, TTL: time.Hour, Bypass: true})
+	bypass.now = client.now
+	bypass.Summarize(ctx, "Paper", "text")
+	if inner.calls["Summarize"] != 3 {
+		t.Fatalf("expected bypass to skip the cache, got %d calls", inner.calls["Summarize"])
+	}
+}
@@ -0,0 +1,22 @@
+package llm
+
+import (
+	"fmt"
+	"log"
+)
+
+// maxDebugPayloadChars bounds how much of a failed response is written to the debug log.
+const maxDebugPayloadChars = 2000
+
+// logParseFailure records the raw model output behind a parse error so prompt problems can be
+// diagnosed after the fact. It is a no-op unless Config.DebugLog was set.
+func logParseFailure(logger *log.Logger, operation, raw string, err error) {
+	if logger == nil || err == nil {
+		return
+	}
+	payload := raw
+	if runes := []rune(payload); len(runes) > maxDebugPayloadChars {
+		payload = string(runes[:maxDebugPayloadChars]) + fmt.Sprintf("… (%d more characters)", len(runes)-maxDebugPayloadChars)
+	}
+	logger.Printf("[llm] parse %s failed: %v\nraw response: %q", operation, err, payload)
+}
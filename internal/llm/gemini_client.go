@@ -0,0 +1,241 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/csheth/browse/internal/httpheaders"
+)
+
+const (
+	defaultGeminiModel   = "gemini-1.5-flash"
+	defaultGeminiBaseURL = "https://generativelanguage.googleapis.com"
+)
+
+type geminiClient struct {
+	baseURL  string
+	apiKey   string
+	model    string
+	client   *http.Client
+	headers  httpheaders.Config
+	onRetry  RetryNotifier
+	debugLog *log.Logger
+	// wait pauses between rate-limit retries; nil uses a context-aware timer.
+	wait func(ctx context.Context, d time.Duration) error
+	// tokens sizes prompt context; nil uses HeuristicEstimator.
+	tokens TokenEstimator
+	// lengths overrides the bullet counts and word caps prompts ask for.
+	lengths promptLengths
+	// system replaces the default (empty) system prompt; see Config.SystemPrompt.
+	system string
+	// temperature is the sampling temperature sent with every request; see Config.Temperature.
+	temperature float64
+}
+
+func (c *geminiClient) Name() string {
+	return fmt.Sprintf("Gemini (%s)", c.model)
+}
+
+func (c *geminiClient) tokenEstimator() TokenEstimator {
+	return c.tokens
+}
+
+func (c *geminiClient) promptLengths() promptLengths {
+	return c.lengths
+}
+
+func (c *geminiClient) systemPrompt() string {
+	return c.system
+}
+
+func (c *geminiClient) samplingTemperature() float64 {
+	return c.temperature
+}
+
+func (c *geminiClient) Summarize(ctx context.Context, title, content string) (string, error) {
+	return summarize(ctx, c, title, content)
+}
+
+func (c *geminiClient) Answer(ctx context.Context, title, question, content string) (string, error) {
+	return answer(ctx, c, title, question, content)
+}
+
+func (c *geminiClient) StreamAnswer(ctx context.Context, title, question, content string, handler AnswerStreamHandler) error {
+	return streamAnswer(ctx, c, title, question, content, handler)
+}
+
+func (c *geminiClient) SuggestNotes(ctx context.Context, title, abstract string, contributions []string, content string) ([]SuggestedNote, error) {
+	return suggestNotes(ctx, c, c.debugLog, title, abstract, contributions, content)
+}
+
+func (c *geminiClient) StreamSuggestNotes(ctx context.Context, title, abstract string, contributions []string, content string, handler SuggestedNoteHandler) ([]SuggestedNote, error) {
+	return streamSuggestNotes(ctx, c, c.debugLog, title, abstract, contributions, content, handler)
+}
+
+func (c *geminiClient) NoteGaps(ctx context.Context, title string, existing []SuggestedNote, content string) ([]SuggestedNote, error) {
+	return noteGaps(ctx, c, c.debugLog, title, existing, content)
+}
+
+func (c *geminiClient) ReadingBrief(ctx context.Context, title, content string) (ReadingBrief, error) {
+	return readingBrief(ctx, c, c.debugLog, title, content)
+}
+
+func (c *geminiClient) BriefSection(ctx context.Context, kind BriefSectionKind, title, content string) ([]string, error) {
+	return briefSection(ctx, c, c.debugLog, kind, title, content)
+}
+
+func (c *geminiClient) StreamBriefSection(ctx context.Context, kind BriefSectionKind, title, content string, detail DetailLevel, handler BriefSectionStreamHandler) error {
+	return streamBriefSection(ctx, c, kind, title, content, detail, handler)
+}
+
+func (c *geminiClient) ContinueBriefSection(ctx context.Context, kind BriefSectionKind, title, content string, existing []string, missing int) ([]string, error) {
+	return continueBriefSection(ctx, c, c.debugLog, kind, title, content, existing, missing)
+}
+
+// geminiResponse is one generateContent response, or one event of a streamGenerateContent stream.
+type geminiResponse struct {
+	Candidates []struct {
+		Content struct {
+			Parts []struct {
+				Text string `json:"text"`
+			} `json:"parts"`
+		} `json:"content"`
+	} `json:"candidates"`
+	PromptFeedback struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+	Error struct {
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// text joins the first candidate's parts.
+func (r geminiResponse) text() string {
+	if len(r.Candidates) == 0 {
+		return ""
+	}
+	var text strings.Builder
+	for _, part := range r.Candidates[0].Content.Parts {
+		text.WriteString(part.Text)
+	}
+	return text.String()
+}
+
+// post sends a single-turn request carrying the prompt as the user message (and the configured
+// system prompt as systemInstruction) to method (generateContent or streamGenerateContent),
+// retrying 429 and 503 responses through retryPost.
+func (c *geminiClient) post(ctx context.Context, method, prompt string) (*http.Response, error) {
+	payload := map[string]any{
+		"contents": []map[string]any{
+			{"role": "user", "parts": []map[string]string{{"text": prompt}}},
+		},
+		"generationConfig": map[string]any{"temperature": c.temperature},
+	}
+	if c.system != "" {
+		payload["systemInstruction"] = map[string]any{"parts": []map[string]string{{"text": c.system}}}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("%s/v1beta/models/%s:%s", c.baseURL, url.PathEscape(c.model), method)
+	if method == "streamGenerateContent" {
+		endpoint += "?alt=sse"
+	}
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("x-goog-api-key", c.apiKey)
+		httpheaders.Apply(req, c.headers)
+		return req, nil
+	}
+	retryable := func(status int) bool {
+		return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+	}
+	return retryPost(ctx, c.client, newRequest, retryable, c.onRetry, c.wait)
+}
+
+func (c *geminiClient) generate(ctx context.Context, prompt string) (string, error) {
+	resp, err := c.post(ctx, "generateContent", prompt)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("gemini API error: %s (%s)", resp.Status, string(body))
+	}
+
+	var parsed geminiResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", err
+	}
+	if reason := parsed.PromptFeedback.BlockReason; reason != "" {
+		return "", fmt.Errorf("gemini blocked the prompt (%s)", reason)
+	}
+	text := strings.TrimSpace(parsed.text())
+	if text == "" {
+		return "", fmt.Errorf("gemini returned an empty response")
+	}
+	return text, nil
+}
+
+// streamGenerate reads streamGenerateContent server-sent events, forwarding each candidate's text.
+// The stream has no closing event, so done is reported once the body ends cleanly.
+func (c *geminiClient) streamGenerate(ctx context.Context, prompt string, fn func(chunk string, done bool) error) error {
+	resp, err := c.post(ctx, "streamGenerateContent", prompt)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("gemini API error: %s (%s)", resp.Status, string(body))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 1024), 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		var event geminiResponse
+		if err := json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &event); err != nil {
+			return err
+		}
+		if event.Error.Message != "" {
+			return fmt.Errorf("gemini stream error: %s (%s)", event.Error.Message, event.Error.Status)
+		}
+		if reason := event.PromptFeedback.BlockReason; reason != "" {
+			return fmt.Errorf("gemini blocked the prompt (%s)", reason)
+		}
+		if chunk := event.text(); chunk != "" {
+			if err := fn(chunk, false); err != nil {
+				return err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fn("", true)
+}
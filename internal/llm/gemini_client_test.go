@@ -0,0 +1,123 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGeminiClientSummarize(t *testing.T) {
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/v1beta/models/"+defaultGeminiModel+":generateContent" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		if r.Method != http.MethodPost {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		if got := r.Header.Get("x-goog-api-key"); got != "test-key" {
+			t.Fatalf("expected api key header, got %q", got)
+		}
+		var payload struct {
+			Contents []struct {
+				Role  string `json:"role"`
+				Parts []struct {
+					Text string `json:"text"`
+				} `json:"parts"`
+			} `json:"contents"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		if len(payload.Contents) != 1 || payload.Contents[0].Role != "user" || len(payload.Contents[0].Parts) != 1 || !strings.Contains(payload.Contents[0].Parts[0].Text, "Paper title: Cool Paper") {
+			t.Fatalf("unexpected contents: %+v", payload.Contents)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"candidates":[{"content":{"role":"model","parts":[{"text":"Bullet 1"}]},"finishReason":"STOP"}]}`)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	client := &geminiClient{
+		baseURL: "http://example.com",
+		apiKey:  "test-key",
+		model:   defaultGeminiModel,
+		client:  &http.Client{Transport: rt},
+	}
+
+	result, err := client.Summarize(context.Background(), "Cool Paper", "This is the PDF content.")
+	if err != nil {
+		t.Fatalf("summarize failed: %v", err)
+	}
+	if result != "Bullet 1" {
+		t.Fatalf("unexpected summarize result: %s", result)
+	}
+}
+
+func TestGeminiClientStreamBriefSection(t *testing.T) {
+	stream := strings.Join([]string{
+		`data: {"candidates":[{"content":{"role":"model","parts":[{"text":"- first bullet\n"}]}}]}`,
+		"",
+		`data: {"candidates":[{"content":{"role":"model","parts":[{"text":"- second bullet"}]},"finishReason":"STOP"}]}`,
+		"",
+	}, "\n")
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if !strings.HasSuffix(r.URL.Path, ":streamGenerateContent") || r.URL.Query().Get("alt") != "sse" {
+			t.Fatalf("unexpected stream URL: %s", r.URL)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(stream)),
+			Header:     make(http.Header),
+		}, nil
+	})
+	client := &geminiClient{baseURL: "http://example.com", apiKey: "test-key", model: defaultGeminiModel, client: &http.Client{Transport: rt}}
+
+	var deltas []BriefSectionDelta
+	err := client.StreamBriefSection(context.Background(), BriefSummary, "Cool Paper", "content", DetailStandard, func(delta BriefSectionDelta) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("stream brief section failed: %v", err)
+	}
+	if len(deltas) != 3 || !deltas[len(deltas)-1].Done {
+		t.Fatalf("expected two text deltas and a final done delta, got %+v", deltas)
+	}
+	if final := deltas[len(deltas)-1].Bullets; len(final) != 1 || !strings.Contains(final[0], "- first bullet\n- second bullet") {
+		t.Fatalf("unexpected final bullets %q", final)
+	}
+}
+
+func TestNewFromEnvPicksGemini(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("GEMINI_API_KEY", "")
+	if _, err := NewFromEnv(Config{Provider: ProviderGemini}); err == nil {
+		t.Fatal("expected gemini provider to require GEMINI_API_KEY")
+	}
+
+	t.Setenv("GEMINI_API_KEY", "test-key")
+	t.Setenv("GEMINI_BASE_URL", "https://proxy.example.com/")
+	client, err := NewFromEnv(Config{})
+	if err != nil {
+		t.Fatalf("NewFromEnv() error = %v", err)
+	}
+	gemini, ok := client.(*geminiClient)
+	if !ok {
+		t.Fatalf("expected gemini client under auto mode, got %T", client)
+	}
+	if gemini.baseURL != "https://proxy.example.com" || gemini.model != defaultGeminiModel {
+		t.Fatalf("unexpected gemini config: %+v", gemini)
+	}
+
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	if client, _ := NewFromEnv(Config{}); !strings.HasPrefix(client.Name(), "Anthropic") {
+		t.Fatalf("expected anthropic to win auto mode when both keys are set, got %s", client.Name())
+	}
+	if provider, err := ParseProvider("Gemini"); err != nil || provider != ProviderGemini {
+		t.Fatalf("ParseProvider(Gemini) = %q, %v", provider, err)
+	}
+}
@@ -0,0 +1,229 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// textGenerator is the transport each provider client implements. Prompt building and response
+// parsing are shared, so every provider produces the same briefs, notes, and answers.
+type textGenerator interface {
+	generate(ctx context.Context, prompt string) (string, error)
+	streamGenerate(ctx context.Context, prompt string, fn func(chunk string, done bool) error) error
+	// tokenEstimator sizes prompt context; nil falls back to HeuristicEstimator.
+	tokenEstimator() TokenEstimator
+	promptLengths() promptLengths
+	// systemPrompt is Config.SystemPrompt; empty means none.
+	systemPrompt() string
+	samplingTemperature() float64
+}
+
+func summarize(ctx context.Context, c textGenerator, title, content string) (string, error) {
+	context := clipText(content, maxSummaryTokens, c.tokenEstimator())
+	if context == "" {
+		return "", fmt.Errorf("paper text empty; cannot summarize")
+	}
+	prompt := buildSummaryPrompt(title, context, c.promptLengths())
+	return c.generate(ctx, prompt)
+}
+
+func answer(ctx context.Context, c textGenerator, title, question, content string) (string, error) {
+	prompt, err := answerPrompt(title, question, content, c.tokenEstimator())
+	if err != nil {
+		return "", err
+	}
+	return c.generate(ctx, prompt)
+}
+
+func streamAnswer(ctx context.Context, c textGenerator, title, question, content string, handler AnswerStreamHandler) error {
+	prompt, err := answerPrompt(title, question, content, c.tokenEstimator())
+	if err != nil {
+		return err
+	}
+	return c.streamGenerate(ctx, prompt, func(chunk string, done bool) error {
+		if chunk == "" && !done {
+			return nil
+		}
+		return handler(AnswerDelta{Text: chunk, Done: done})
+	})
+}
+
+func answerPrompt(title, question, content string, est TokenEstimator) (string, error) {
+	if strings.TrimSpace(question) == "" {
+		return "", fmt.Errorf("question cannot be empty")
+	}
+	context := extractQuestionContext(content, question, maxAnswerTokens, est)
+	if context == "" {
+		return "", fmt.Errorf("paper text empty; cannot answer question")
+	}
+	return buildAnswerPrompt(title, context, question), nil
+}
+
+func suggestNotes(ctx context.Context, c textGenerator, debugLog *log.Logger, title, abstract string, contributions []string, content string) ([]SuggestedNote, error) {
+	context := buildSuggestionContext(abstract, contributions, content, maxSuggestionTokens, c.tokenEstimator())
+	if context == "" {
+		return nil, fmt.Errorf("paper text empty; cannot suggest notes")
+	}
+	prompt := buildSuggestionPrompt(title, context)
+	raw, err := c.generate(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := parseSuggestedNotes(raw)
+	logParseFailure(debugLog, "suggested notes", raw, err)
+	return notes, err
+}
+
+func streamSuggestNotes(ctx context.Context, c textGenerator, debugLog *log.Logger, title, abstract string, contributions []string, content string, handler SuggestedNoteHandler) ([]SuggestedNote, error) {
+	context := buildSuggestionContext(abstract, contributions, content, maxSuggestionTokens, c.tokenEstimator())
+	if context == "" {
+		return nil, fmt.Errorf("paper text empty; cannot suggest notes")
+	}
+	prompt := buildSuggestionPrompt(title, context)
+	var streamed []SuggestedNote
+	var raw strings.Builder
+	feed := streamParseSuggestedNotes(func(note SuggestedNote) error {
+		streamed = append(streamed, note)
+		return handler(note)
+	})
+	err := c.streamGenerate(ctx, prompt, func(chunk string, done bool) error {
+		raw.WriteString(chunk)
+		return feed(chunk)
+	})
+	if err != nil {
+		return streamed, err
+	}
+	if len(streamed) > 0 {
+		return streamed, nil
+	}
+	// Some models emit a single object instead of an array; fall back to the blocking parser.
+	notes, err := parseSuggestedNotes(raw.String())
+	if err != nil {
+		logParseFailure(debugLog, "streamed suggested notes", raw.String(), err)
+		return nil, err
+	}
+	for _, note := range notes {
+		if err := handler(note); err != nil {
+			return notes, err
+		}
+	}
+	return notes, nil
+}
+
+func noteGaps(ctx context.Context, c textGenerator, debugLog *log.Logger, title string, existing []SuggestedNote, content string) ([]SuggestedNote, error) {
+	context := clipText(content, maxGapTokens, c.tokenEstimator())
+	if context == "" {
+		return nil, fmt.Errorf("paper text empty; cannot compare against notes")
+	}
+	prompt := buildGapAnalysisPrompt(title, existing, context)
+	raw, err := c.generate(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := parseSuggestedNotes(raw)
+	logParseFailure(debugLog, "suggested notes", raw, err)
+	return notes, err
+}
+
+func readingBrief(ctx context.Context, c textGenerator, debugLog *log.Logger, title, content string) (ReadingBrief, error) {
+	context := clipText(content, maxBriefTokens, c.tokenEstimator())
+	if context == "" {
+		return ReadingBrief{}, fmt.Errorf("paper text empty; cannot build brief")
+	}
+	prompt := buildBriefPrompt(title, context)
+	raw, err := c.generate(ctx, prompt)
+	if err != nil {
+		return ReadingBrief{}, err
+	}
+	brief, err := parseReadingBrief(raw)
+	logParseFailure(debugLog, "reading brief", raw, err)
+	return brief, err
+}
+
+func briefSection(ctx context.Context, c textGenerator, debugLog *log.Logger, kind BriefSectionKind, title, content string) ([]string, error) {
+	context := clipBriefSectionContext(kind, content, c.tokenEstimator())
+	if context == "" {
+		return nil, fmt.Errorf("paper text empty; cannot build %s section", kind)
+	}
+	prompt := buildBriefSectionPrompt(kind, title, context, DetailStandard, c.promptLengths())
+	raw, err := c.generate(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	bullets, err := parseBriefSection(raw)
+	logParseFailure(debugLog, fmt.Sprintf("%s brief section", kind), raw, err)
+	return bullets, err
+}
+
+func streamBriefSection(ctx context.Context, c textGenerator, kind BriefSectionKind, title, content string, detail DetailLevel, handler BriefSectionStreamHandler) error {
+	context := clipBriefSectionContext(kind, content, c.tokenEstimator())
+	if context == "" {
+		return fmt.Errorf("paper text empty; cannot build %s section", kind)
+	}
+	prompt := buildBriefSectionPrompt(kind, title, context, detail, c.promptLengths())
+	var builder strings.Builder
+	return c.streamGenerate(ctx, prompt, func(chunk string, done bool) error {
+		builder.WriteString(chunk)
+		content := strings.TrimSpace(builder.String())
+		if content == "" && !done {
+			return nil
+		}
+		return handler(BriefSectionDelta{
+			Kind:    kind,
+			Bullets: []string{content},
+			Done:    done,
+		})
+	})
+}
+
+func continueBriefSection(ctx context.Context, c textGenerator, debugLog *log.Logger, kind BriefSectionKind, title, content string, existing []string, missing int) ([]string, error) {
+	context := clipBriefSectionContext(kind, content, c.tokenEstimator())
+	if context == "" {
+		return nil, fmt.Errorf("no additional paper text to continue the %s section", kind)
+	}
+	prompt := buildBriefContinuationPrompt(kind, title, existing, context, missing)
+	raw, err := c.generate(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	bullets, err := parseBriefSection(raw)
+	logParseFailure(debugLog, fmt.Sprintf("%s brief continuation", kind), raw, err)
+	return bullets, err
+}
+
+// retryPost sends the request built by newRequest, retrying while retryable reports the status as
+// a temporary overload, after the server's Retry-After delay (bounded by maxRateLimitWait), up to
+// maxRateLimitRetries times. The final response is returned as-is so callers keep their existing
+// status handling. A nil wait uses a context-aware timer.
+func retryPost(ctx context.Context, client *http.Client, newRequest func() (*http.Request, error), retryable func(status int) bool, onRetry RetryNotifier, wait func(ctx context.Context, d time.Duration) error) (*http.Response, error) {
+	if wait == nil {
+		wait = sleepContext
+	}
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if !retryable(resp.StatusCode) || attempt >= maxRateLimitRetries {
+			return resp, nil
+		}
+		delay := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		if onRetry != nil {
+			onRetry(fmt.Sprintf("LLM rate limited, retrying in %s", formatRetryDelay(delay)))
+		}
+		if err := wait(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -2,42 +2,133 @@ package llm
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/csheth/browse/internal/httpheaders"
+	"github.com/csheth/browse/internal/httpx"
 )
 
 const (
 	defaultOllamaModel = "ministral-3:latest"
-	// Context clipping guards assume ministral-3:latest exposes a 262k-token window (~1M characters).
-	// We cap prompts well below that to keep >=20% headroom (roughly 4 chars/token) and avoid OOMs.
-	maxSummaryChars        = 200_000
-	maxAnswerChars         = 120_000
-	maxSuggestionChars     = 150_000
-	maxBriefChars          = 200_000
-	maxBriefSummaryChars   = 60_000
-	maxBriefTechnicalChars = 110_000
-	maxBriefDeepDiveChars  = 40_000
+	// Context clipping guards assume ministral-3:latest exposes a 262k-token window. We cap prompts
+	// well below that to keep >=20% headroom and avoid OOMs. Budgets are in estimated tokens; see
+	// TokenEstimator.
+	maxSummaryTokens        = 50_000
+	maxAnswerTokens         = 30_000
+	maxSuggestionTokens     = 37_500
+	maxGapTokens            = 37_500
+	maxBriefTokens          = 50_000
+	maxBriefSummaryTokens   = 15_000
+	maxBriefTechnicalTokens = 27_500
+	maxBriefDeepDiveTokens  = 10_000
 )
 
 const defaultLLMHTTPTimeout = 3 * time.Minute
 
+const (
+	// DefaultTemperature keeps briefs and answers focused while leaving some variety in wording.
+	DefaultTemperature = 0.2
+	// MaxTemperature is the highest temperature any supported provider accepts.
+	MaxTemperature = 2.0
+)
+
+// ValidateTemperature rejects a -llm-temperature outside [0, MaxTemperature].
+func ValidateTemperature(temperature float64) error {
+	if temperature < 0 || temperature > MaxTemperature || math.IsNaN(temperature) {
+		return fmt.Errorf("temperature %v is outside [0, %v]", temperature, MaxTemperature)
+	}
+	return nil
+}
+
+// Provider selects which LLM service NewFromEnv builds a client for.
+type Provider string
+
+const (
+	// ProviderAuto uses Anthropic when ANTHROPIC_API_KEY is set, then Gemini when GEMINI_API_KEY is
+	// set, and Ollama otherwise.
+	ProviderAuto      Provider = "auto"
+	ProviderOllama    Provider = "ollama"
+	ProviderAnthropic Provider = "anthropic"
+	ProviderGemini    Provider = "gemini"
+)
+
+// ParseProvider validates a -llm-provider value; empty means ProviderAuto.
+func ParseProvider(value string) (Provider, error) {
+	switch provider := Provider(strings.ToLower(strings.TrimSpace(value))); provider {
+	case "", ProviderAuto:
+		return ProviderAuto, nil
+	case ProviderOllama, ProviderAnthropic, ProviderGemini:
+		return provider, nil
+	default:
+		return "", fmt.Errorf("unknown LLM provider %q (want auto, ollama, anthropic, or gemini)", value)
+	}
+}
+
 // Config describes how to build an LLM client.
 type Config struct {
+	// Provider picks the service; empty behaves like ProviderAuto.
+	Provider   Provider
 	Model      string
 	Endpoint   string
 	HTTPClient *http.Client
+	Headers    httpheaders.Config
+	// KeepAlive is forwarded as Ollama's keep_alive (eg. "30m", "-1") so the model stays loaded
+	// between brief and question calls. Empty leaves the server default in place.
+	KeepAlive string
+	// OnRetry receives human-readable notices when a rate-limited request is retried.
+	OnRetry RetryNotifier
+	// DebugLog, when set, receives the raw (truncated) model output whenever a response fails to
+	// parse.
+	DebugLog *log.Logger
+	// TokenEstimator counts context budgets in tokens; nil uses HeuristicEstimator.
+	TokenEstimator TokenEstimator
+	// SummaryBullets is how many bullets Summarize and the summary brief section ask for; zero keeps
+	// the defaults (5 for Summarize, 3-5 for the brief section).
+	SummaryBullets int
+	// TechnicalBullets and DeepDiveBullets are how many bullets the technical and deep dive brief
+	// sections ask for; zero keeps their defaults (3-7 and 3).
+	TechnicalBullets int
+	DeepDiveBullets  int
+	// BulletMaxWords caps the words in each top-level bullet of summaries and brief sections; zero
+	// keeps the default 20-word cap on Summarize and leaves brief sections uncapped.
+	BulletMaxWords int
+	// SystemPrompt sets the persona every request is framed with (eg. "Explain like I'm an
+	// undergrad."). Anthropic and Gemini send it as the system prompt and Ollama prepends it to the
+	// prompt; empty sends none, as before.
+	SystemPrompt string
+	// Temperature is the sampling temperature sent with every request, within [0, MaxTemperature];
+	// main defaults it to DefaultTemperature. Anthropic caps it at 1, the most its API accepts.
+	Temperature float64
+	// Offline makes NewFromEnv return a stand-in client whose every call fails with ErrOffline, so
+	// nothing reaches the network.
+	Offline bool
 }
 
+// RetryNotifier surfaces transient retry notices (eg. "LLM rate limited, retrying in 2s").
+type RetryNotifier func(message string)
+
 // Client exposes summarization and question-answering helpers.
 type Client interface {
 	Summarize(ctx context.Context, title, content string) (string, error)
 	Answer(ctx context.Context, title, question, content string) (string, error)
+	// StreamAnswer answers like Answer but hands each generated chunk to handler as it arrives.
+	StreamAnswer(ctx context.Context, title, question, content string, handler AnswerStreamHandler) error
 	SuggestNotes(ctx context.Context, title, abstract string, contributions []string, content string) ([]SuggestedNote, error)
+	StreamSuggestNotes(ctx context.Context, title, abstract string, contributions []string, content string, handler SuggestedNoteHandler) ([]SuggestedNote, error)
+	NoteGaps(ctx context.Context, title string, existing []SuggestedNote, content string) ([]SuggestedNote, error)
 	ReadingBrief(ctx context.Context, title, content string) (ReadingBrief, error)
 	BriefSection(ctx context.Context, kind BriefSectionKind, title, content string) ([]string, error)
-	StreamBriefSection(ctx context.Context, kind BriefSectionKind, title, content string, handler BriefSectionStreamHandler) error
+	StreamBriefSection(ctx context.Context, kind BriefSectionKind, title, content string, detail DetailLevel, handler BriefSectionStreamHandler) error
+	// ContinueBriefSection asks for at least missing more top-level bullets that extend existing
+	// without repeating it, grounded in content. Only the new bullets are returned.
+	ContinueBriefSection(ctx context.Context, kind BriefSectionKind, title, content string, existing []string, missing int) ([]string, error)
 	Name() string
 }
 
@@ -49,6 +140,9 @@ type SuggestedNote struct {
 	Kind   string `json:"kind"`
 }
 
+// SuggestedNoteHandler receives each suggested note as soon as it has fully streamed in.
+type SuggestedNoteHandler func(note SuggestedNote) error
+
 // ReadingBrief captures the three-pass inspired sections rendered in the UI.
 type ReadingBrief struct {
 	Summary   []string `json:"summary"`
@@ -65,17 +159,27 @@ const (
 	BriefDeepDive  BriefSectionKind = "deepDive"
 )
 
-// BriefSectionLimit reports the max character budget for the given section.
+// DetailLevel controls how much depth a brief section prompt asks for.
+type DetailLevel int
+
+const (
+	// DetailStandard is the default, concise section.
+	DetailStandard DetailLevel = iota
+	// DetailExpanded asks for more bullets and deeper specifics when a section came back too terse.
+	DetailExpanded
+)
+
+// BriefSectionLimit reports the max token budget for the given section's context.
 func BriefSectionLimit(kind BriefSectionKind) int {
 	switch kind {
 	case BriefSummary:
-		return maxBriefSummaryChars
+		return maxBriefSummaryTokens
 	case BriefTechnical:
-		return maxBriefTechnicalChars
+		return maxBriefTechnicalTokens
 	case BriefDeepDive:
-		return maxBriefDeepDiveChars
+		return maxBriefDeepDiveTokens
 	default:
-		return maxBriefChars
+		return maxBriefTokens
 	}
 }
 
@@ -89,8 +193,38 @@ type BriefSectionDelta struct {
 // BriefSectionStreamHandler receives streaming updates as they are generated.
 type BriefSectionStreamHandler func(delta BriefSectionDelta) error
 
+// AnswerDelta is the next piece of a streaming answer. Text holds only the newly generated chunk.
+type AnswerDelta struct {
+	Text string
+	Done bool
+}
+
+// AnswerStreamHandler receives streaming answer chunks as they are generated.
+type AnswerStreamHandler func(delta AnswerDelta) error
+
 // NewFromEnv inspects CLI arguments & environment variables to build a client.
 func NewFromEnv(cfg Config) (Client, error) {
+	if cfg.Offline {
+		return offlineClient{}, nil
+	}
+	if err := ValidateTemperature(cfg.Temperature); err != nil {
+		return nil, err
+	}
+	provider := cfg.Provider
+	if provider == "" || provider == ProviderAuto {
+		provider = ProviderOllama
+		if strings.TrimSpace(os.Getenv("ANTHROPIC_API_KEY")) != "" {
+			provider = ProviderAnthropic
+		} else if strings.TrimSpace(os.Getenv("GEMINI_API_KEY")) != "" {
+			provider = ProviderGemini
+		}
+	}
+	switch provider {
+	case ProviderAnthropic:
+		return newAnthropicFromEnv(cfg)
+	case ProviderGemini:
+		return newGeminiFromEnv(cfg)
+	}
 	host := cfg.Endpoint
 	if host == "" {
 		if env := os.Getenv("OLLAMA_HOST"); env != "" {
@@ -108,9 +242,110 @@ func NewFromEnv(cfg Config) (Client, error) {
 		}
 	}
 	return &ollamaClient{
-		host:   host,
-		model:  model,
-		client: pickHTTPClient(cfg.HTTPClient),
+		host:        host,
+		model:       model,
+		client:      pickHTTPClient(cfg.HTTPClient),
+		headers:     cfg.Headers,
+		keepAlive:   cfg.KeepAlive,
+		onRetry:     cfg.OnRetry,
+		debugLog:    cfg.DebugLog,
+		tokens:      cfg.TokenEstimator,
+		lengths:     promptLengthsFrom(cfg),
+		system:      strings.TrimSpace(cfg.SystemPrompt),
+		temperature: cfg.Temperature,
+	}, nil
+}
+
+// Describe splits a client's Name, "Provider (model)", into a lowercase provider and the model, so
+// snapshots can record which model wrote a brief. A name without a parenthesized model is returned
+// whole as the provider.
+func Describe(client Client) (provider, model string) {
+	if client == nil {
+		return "", ""
+	}
+	name := strings.TrimSpace(client.Name())
+	if open := strings.Index(name, " ("); open > 0 && strings.HasSuffix(name, ")") {
+		return strings.ToLower(name[:open]), strings.TrimSpace(name[open+2 : len(name)-1])
+	}
+	return strings.ToLower(name), ""
+}
+
+// DefaultConcurrency is how many generation requests to run against client at once: one for a
+// local Ollama server, which typically shares a single GPU between requests, and three for the
+// cloud providers.
+func DefaultConcurrency(client Client) int {
+	if cached, ok := client.(*CachingClient); ok {
+		client = cached.Unwrap()
+	}
+	if _, ok := client.(*ollamaClient); ok {
+		return 1
+	}
+	return 3
+}
+
+// newAnthropicFromEnv builds the Anthropic client. The endpoint comes from Config.Endpoint, then
+// ANTHROPIC_BASE_URL, and the key from ANTHROPIC_API_KEY.
+func newAnthropicFromEnv(cfg Config) (Client, error) {
+	apiKey := strings.TrimSpace(os.Getenv("ANTHROPIC_API_KEY"))
+	if apiKey == "" {
+		return nil, errors.New("ANTHROPIC_API_KEY is required for the anthropic provider")
+	}
+	baseURL := cfg.Endpoint
+	if baseURL == "" {
+		baseURL = os.Getenv("ANTHROPIC_BASE_URL")
+	}
+	if baseURL == "" {
+		baseURL = defaultAnthropicBaseURL
+	}
+	model := cfg.Model
+	if model == "" {
+		model = defaultAnthropicModel
+	}
+	return &anthropicClient{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		apiKey:      apiKey,
+		model:       model,
+		client:      pickHTTPClient(cfg.HTTPClient),
+		headers:     cfg.Headers,
+		onRetry:     cfg.OnRetry,
+		debugLog:    cfg.DebugLog,
+		tokens:      cfg.TokenEstimator,
+		lengths:     promptLengthsFrom(cfg),
+		system:      strings.TrimSpace(cfg.SystemPrompt),
+		temperature: cfg.Temperature,
+	}, nil
+}
+
+// newGeminiFromEnv builds the Gemini client. The endpoint comes from Config.Endpoint, then
+// GEMINI_BASE_URL, and the key from GEMINI_API_KEY.
+func newGeminiFromEnv(cfg Config) (Client, error) {
+	apiKey := strings.TrimSpace(os.Getenv("GEMINI_API_KEY"))
+	if apiKey == "" {
+		return nil, errors.New("GEMINI_API_KEY is required for the gemini provider")
+	}
+	baseURL := cfg.Endpoint
+	if baseURL == "" {
+		baseURL = os.Getenv("GEMINI_BASE_URL")
+	}
+	if baseURL == "" {
+		baseURL = defaultGeminiBaseURL
+	}
+	model := cfg.Model
+	if model == "" {
+		model = defaultGeminiModel
+	}
+	return &geminiClient{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		apiKey:      apiKey,
+		model:       model,
+		client:      pickHTTPClient(cfg.HTTPClient),
+		headers:     cfg.Headers,
+		onRetry:     cfg.OnRetry,
+		debugLog:    cfg.DebugLog,
+		tokens:      cfg.TokenEstimator,
+		lengths:     promptLengthsFrom(cfg),
+		system:      strings.TrimSpace(cfg.SystemPrompt),
+		temperature: cfg.Temperature,
 	}, nil
 }
 
@@ -119,5 +354,5 @@ func pickHTTPClient(custom *http.Client) *http.Client {
 		return custom
 	}
 	// Allow longer-running generations (Ollama often needs >60s) and rely on the caller's context for cancellation.
-	return &http.Client{Timeout: defaultLLMHTTPTimeout}
+	return httpx.NewClient(defaultLLMHTTPTimeout)
 }
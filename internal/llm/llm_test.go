@@ -1,7 +1,12 @@
 package llm
 
 import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 )
@@ -19,3 +24,72 @@ func TestPickHTTPClientUsesLongerTimeout(t *testing.T) {
 		t.Fatalf("expected default timeout %s, got %s", defaultLLMHTTPTimeout, client.Timeout)
 	}
 }
+
+func TestDescribeSplitsProviderAndModel(t *testing.T) {
+	ollama, err := NewFromEnv(Config{Provider: ProviderOllama, Model: "qwen3:8b"})
+	if err != nil {
+		t.Fatalf("NewFromEnv() error = %v", err)
+	}
+	cached := NewCachingClient(ollama, CacheOptions{Dir: t.TempDir()})
+	if provider, model := Describe(cached); provider != "ollama" || model != "qwen3:8b" {
+		t.Fatalf("Describe() = %q, %q", provider, model)
+	}
+	if provider, model := Describe(newCountingClient()); provider != "counting" || model != "test-model" {
+		t.Fatalf("Describe() = %q, %q", provider, model)
+	}
+	if provider, model := Describe(nil); provider != "" || model != "" {
+		t.Fatalf("expected nothing for a nil client, got %q, %q", provider, model)
+	}
+}
+
+func TestConfiguredTemperatureReachesEveryProvider(t *testing.T) {
+	var bodies []map[string]any
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		bodies = append(bodies, payload)
+		reply := `{"response":"ok","done":true}`
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/v1/messages"):
+			reply = `{"content":[{"type":"text","text":"ok"}]}`
+		case strings.Contains(r.URL.Path, ":generateContent"):
+			reply = `{"candidates":[{"content":{"parts":[{"text":"ok"}]}}]}`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(reply)), Header: make(http.Header)}, nil
+	})
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+	t.Setenv("GEMINI_API_KEY", "test-key")
+	for _, provider := range []Provider{ProviderOllama, ProviderAnthropic, ProviderGemini} {
+		client, err := NewFromEnv(Config{Provider: provider, Endpoint: "http://example.com", HTTPClient: &http.Client{Transport: rt}, Temperature: 1.5})
+		if err != nil {
+			t.Fatalf("%s: NewFromEnv() error = %v", provider, err)
+		}
+		if _, err := client.Summarize(context.Background(), "Paper", "content"); err != nil {
+			t.Fatalf("%s: summarize failed: %v", provider, err)
+		}
+	}
+
+	if len(bodies) != 3 {
+		t.Fatalf("expected three requests, got %d", len(bodies))
+	}
+	if options, _ := bodies[0]["options"].(map[string]any); options["temperature"] != 1.5 {
+		t.Fatalf("expected Ollama options.temperature 1.5, got %v", bodies[0]["options"])
+	}
+	if got := bodies[1]["temperature"]; got != 1.0 {
+		t.Fatalf("expected Anthropic's temperature capped at 1, got %v", got)
+	}
+	if config, _ := bodies[2]["generationConfig"].(map[string]any); config["temperature"] != 1.5 {
+		t.Fatalf("expected Gemini generationConfig.temperature 1.5, got %v", bodies[2]["generationConfig"])
+	}
+
+	for _, temperature := range []float64{-0.1, 2.1, math.NaN()} {
+		if _, err := NewFromEnv(Config{Provider: ProviderOllama, Temperature: temperature}); err == nil {
+			t.Fatalf("expected temperature %v to be rejected", temperature)
+		}
+	}
+	if err := ValidateTemperature(0); err != nil {
+		t.Fatalf("expected 0 to be a valid temperature, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package llm
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrOffline is what every call on the offline client returns.
+var ErrOffline = errors.New("LLM disabled in offline mode")
+
+// offlineClient stands in for a provider when Config.Offline is set.
+type offlineClient struct{}
+
+// IsOffline reports whether client is the stand-in NewFromEnv returns for Config.Offline.
+func IsOffline(client Client) bool {
+	if cached, ok := client.(*CachingClient); ok {
+		client = cached.Unwrap()
+	}
+	_, ok := client.(offlineClient)
+	return ok
+}
+
+func (offlineClient) Name() string {
+	return "Offline (LLM disabled)"
+}
+
+func (offlineClient) Summarize(context.Context, string, string) (string, error) {
+	return "", ErrOffline
+}
+
+func (offlineClient) Answer(context.Context, string, string, string) (string, error) {
+	return "", ErrOffline
+}
+
+func (offlineClient) StreamAnswer(context.Context, string, string, string, AnswerStreamHandler) error {
+	return ErrOffline
+}
+
+func (offlineClient) SuggestNotes(context.Context, string, string, []string, string) ([]SuggestedNote, error) {
+	return nil, ErrOffline
+}
+
+func (offlineClient) StreamSuggestNotes(context.Context, string, string, []string, string, SuggestedNoteHandler) ([]SuggestedNote, error) {
+	return nil, ErrOffline
+}
+
+func (offlineClient) NoteGaps(context.Context, string, []SuggestedNote, string) ([]SuggestedNote, error) {
+	return nil, ErrOffline
+}
+
+func (offlineClient) ReadingBrief(context.Context, string, string) (ReadingBrief, error) {
+	return ReadingBrief{}, ErrOffline
+}
+
+func (offlineClient) BriefSection(context.Context, BriefSectionKind, string, string) ([]string, error) {
+	return nil, ErrOffline
+}
+
+func (offlineClient) StreamBriefSection(context.Context, BriefSectionKind, string, string, DetailLevel, BriefSectionStreamHandler) error {
+	return ErrOffline
+}
+
+func (offlineClient) ContinueBriefSection(context.Context, BriefSectionKind, string, string, []string, int) ([]string, error) {
+	return nil, ErrOffline
+}
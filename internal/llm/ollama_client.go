@@ -7,119 +7,146 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/csheth/browse/internal/httpheaders"
 )
 
 type ollamaClient struct {
-	host   string
-	model  string
-	client *http.Client
+	host      string
+	model     string
+	client    *http.Client
+	headers   httpheaders.Config
+	keepAlive string
+	onRetry   RetryNotifier
+	debugLog  *log.Logger
+	// wait pauses between rate-limit retries; nil uses a context-aware timer.
+	wait func(ctx context.Context, d time.Duration) error
+	// tokens sizes prompt context; nil uses HeuristicEstimator.
+	tokens TokenEstimator
+	// lengths overrides the bullet counts and word caps prompts ask for.
+	lengths promptLengths
+	// system replaces the default (empty) system prompt; see Config.SystemPrompt.
+	system string
+	// temperature is the sampling temperature sent with every request; see Config.Temperature.
+	temperature float64
 }
 
 func (c *ollamaClient) Name() string {
 	return fmt.Sprintf("Ollama (%s)", c.model)
 }
 
+func (c *ollamaClient) tokenEstimator() TokenEstimator {
+	return c.tokens
+}
+
+func (c *ollamaClient) promptLengths() promptLengths {
+	return c.lengths
+}
+
+func (c *ollamaClient) systemPrompt() string {
+	return c.system
+}
+
+func (c *ollamaClient) samplingTemperature() float64 {
+	return c.temperature
+}
+
 func (c *ollamaClient) Summarize(ctx context.Context, title, content string) (string, error) {
-	context := clipText(content, maxSummaryChars)
-	if context == "" {
-		return "", fmt.Errorf("paper text empty; cannot summarize")
-	}
-	prompt := buildSummaryPrompt(title, context)
-	return c.generate(ctx, prompt)
+	return summarize(ctx, c, title, content)
 }
 
 func (c *ollamaClient) Answer(ctx context.Context, title, question, content string) (string, error) {
-	if strings.TrimSpace(question) == "" {
-		return "", fmt.Errorf("question cannot be empty")
-	}
-	context := extractQuestionContext(content, question, maxAnswerChars)
-	if context == "" {
-		return "", fmt.Errorf("paper text empty; cannot answer question")
-	}
-	prompt := buildAnswerPrompt(title, context, question)
-	return c.generate(ctx, prompt)
+	return answer(ctx, c, title, question, content)
+}
+
+func (c *ollamaClient) StreamAnswer(ctx context.Context, title, question, content string, handler AnswerStreamHandler) error {
+	return streamAnswer(ctx, c, title, question, content, handler)
 }
 
 func (c *ollamaClient) SuggestNotes(ctx context.Context, title, abstract string, contributions []string, content string) ([]SuggestedNote, error) {
-	context := buildSuggestionContext(abstract, contributions, content, maxSuggestionChars)
-	if context == "" {
-		return nil, fmt.Errorf("paper text empty; cannot suggest notes")
-	}
-	prompt := buildSuggestionPrompt(title, context)
-	raw, err := c.generate(ctx, prompt)
-	if err != nil {
-		return nil, err
-	}
-	return parseSuggestedNotes(raw)
+	return suggestNotes(ctx, c, c.debugLog, title, abstract, contributions, content)
+}
+
+func (c *ollamaClient) StreamSuggestNotes(ctx context.Context, title, abstract string, contributions []string, content string, handler SuggestedNoteHandler) ([]SuggestedNote, error) {
+	return streamSuggestNotes(ctx, c, c.debugLog, title, abstract, contributions, content, handler)
+}
+
+func (c *ollamaClient) NoteGaps(ctx context.Context, title string, existing []SuggestedNote, content string) ([]SuggestedNote, error) {
+	return noteGaps(ctx, c, c.debugLog, title, existing, content)
 }
 
 func (c *ollamaClient) ReadingBrief(ctx context.Context, title, content string) (ReadingBrief, error) {
-	context := clipText(content, maxBriefChars)
-	if context == "" {
-		return ReadingBrief{}, fmt.Errorf("paper text empty; cannot build brief")
-	}
-	prompt := buildBriefPrompt(title, context)
-	raw, err := c.generate(ctx, prompt)
-	if err != nil {
-		return ReadingBrief{}, err
-	}
-	return parseReadingBrief(raw)
+	return readingBrief(ctx, c, c.debugLog, title, content)
 }
 
 func (c *ollamaClient) BriefSection(ctx context.Context, kind BriefSectionKind, title, content string) ([]string, error) {
-	context := clipBriefSectionContext(kind, content)
-	if context == "" {
-		return nil, fmt.Errorf("paper text empty; cannot build %s section", kind)
-	}
-	prompt := buildBriefSectionPrompt(kind, title, context)
-	raw, err := c.generate(ctx, prompt)
-	if err != nil {
-		return nil, err
-	}
-	return parseBriefSection(raw)
+	return briefSection(ctx, c, c.debugLog, kind, title, content)
 }
 
-func (c *ollamaClient) StreamBriefSection(ctx context.Context, kind BriefSectionKind, title, content string, handler BriefSectionStreamHandler) error {
-	context := clipBriefSectionContext(kind, content)
-	if context == "" {
-		return fmt.Errorf("paper text empty; cannot build %s section", kind)
-	}
-	prompt := buildBriefSectionPrompt(kind, title, context)
-	var builder strings.Builder
-	return c.streamGenerate(ctx, prompt, func(chunk string, done bool) error {
-		builder.WriteString(chunk)
-		content := strings.TrimSpace(builder.String())
-		if content == "" && !done {
-			return nil
-		}
-		return handler(BriefSectionDelta{
-			Kind:    kind,
-			Bullets: []string{content},
-			Done:    done,
-		})
-	})
+func (c *ollamaClient) StreamBriefSection(ctx context.Context, kind BriefSectionKind, title, content string, detail DetailLevel, handler BriefSectionStreamHandler) error {
+	return streamBriefSection(ctx, c, kind, title, content, detail, handler)
 }
 
-func (c *ollamaClient) generate(ctx context.Context, prompt string) (string, error) {
+func (c *ollamaClient) ContinueBriefSection(ctx context.Context, kind BriefSectionKind, title, content string, existing []string, missing int) ([]string, error) {
+	return continueBriefSection(ctx, c, c.debugLog, kind, title, content, existing, missing)
+}
+
+// generatePayload builds the /api/generate body. keep_alive is only sent when configured so Ollama's
+// server-side default still applies otherwise. A configured system prompt is prepended to the prompt
+// rather than sent as "system", which would replace the model's own Modelfile system prompt.
+func (c *ollamaClient) generatePayload(prompt string, stream bool) map[string]any {
+	if c.system != "" {
+		prompt = c.system + "\n\n" + prompt
+	}
 	payload := map[string]any{
 		"model":  c.model,
 		"prompt": prompt,
-		"stream": false,
+		"stream": stream,
 	}
-	buf, err := json.Marshal(payload)
-	if err != nil {
-		return "", err
+	payload["options"] = map[string]any{"temperature": c.temperature}
+	if keepAlive := strings.TrimSpace(c.keepAlive); keepAlive != "" {
+		payload["keep_alive"] = keepAliveValue(keepAlive)
+	}
+	return payload
+}
+
+// keepAliveValue sends a bare number such as "-1" or "0" as a JSON number, which Ollama reads as
+// seconds (negative keeps the model loaded forever); it rejects those as duration strings. Anything
+// else, such as "30m", goes through as a duration string.
+func keepAliveValue(keepAlive string) any {
+	if seconds, err := strconv.Atoi(keepAlive); err == nil {
+		return seconds
+	}
+	return keepAlive
+}
+
+// post sends the generate payload, retrying 429 responses through retryPost.
+func (c *ollamaClient) post(ctx context.Context, body []byte) (*http.Response, error) {
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.host+"/api/generate", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		httpheaders.Apply(req, c.headers)
+		return req, nil
 	}
+	retryable := func(status int) bool { return status == http.StatusTooManyRequests }
+	return retryPost(ctx, c.client, newRequest, retryable, c.onRetry, c.wait)
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.host+"/api/generate", bytes.NewReader(buf))
+func (c *ollamaClient) generate(ctx context.Context, prompt string) (string, error) {
+	buf, err := json.Marshal(c.generatePayload(prompt, false))
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.client.Do(req)
+	resp, err := c.post(ctx, buf)
 	if err != nil {
 		return "", err
 	}
@@ -147,22 +174,11 @@ func (c *ollamaClient) generate(ctx context.Context, prompt string) (string, err
 }
 
 func (c *ollamaClient) streamGenerate(ctx context.Context, prompt string, fn func(chunk string, done bool) error) error {
-	payload := map[string]any{
-		"model":  c.model,
-		"prompt": prompt,
-		"stream": true,
-	}
-	buf, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.host+"/api/generate", bytes.NewReader(buf))
+	buf, err := json.Marshal(c.generatePayload(prompt, true))
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.client.Do(req)
+	resp, err := c.post(ctx, buf)
 	if err != nil {
 		return err
 	}
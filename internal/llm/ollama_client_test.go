@@ -1,12 +1,17 @@
 package llm
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
+	"log"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/csheth/browse/internal/httpheaders"
 )
 
 type roundTripFunc func(*http.Request) (*http.Response, error)
@@ -218,7 +223,7 @@ func TestOllamaClientStreamBriefSection(t *testing.T) {
 	}
 
 	var deltas []BriefSectionDelta
-	err := client.StreamBriefSection(context.Background(), BriefSummary, "Cool Paper", "content", func(delta BriefSectionDelta) error {
+	err := client.StreamBriefSection(context.Background(), BriefSummary, "Cool Paper", "content", DetailStandard, func(delta BriefSectionDelta) error {
 		deltas = append(deltas, delta)
 		return nil
 	})
@@ -239,3 +244,276 @@ func TestOllamaClientStreamBriefSection(t *testing.T) {
 		t.Fatalf("final string missing second bullet: %q", final[0])
 	}
 }
+
+func TestOllamaClientStreamAnswer(t *testing.T) {
+	stream := strings.Join([]string{
+		`{"response":"The metric ","done":false}`,
+		`{"response":"is BLEU.","done":true}`,
+	}, "\n")
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(stream)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	client := &ollamaClient{
+		host:   "http://example.com",
+		model:  "ministral-3:latest",
+		client: &http.Client{Transport: rt},
+	}
+
+	var deltas []AnswerDelta
+	err := client.StreamAnswer(context.Background(), "Cool Paper", "Which metric?", "We report BLEU.", func(delta AnswerDelta) error {
+		deltas = append(deltas, delta)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("stream answer failed: %v", err)
+	}
+	if len(deltas) != 2 || deltas[0].Text != "The metric " || deltas[1].Text != "is BLEU." {
+		t.Fatalf("expected one delta per chunk, got %#v", deltas)
+	}
+	if deltas[0].Done || !deltas[1].Done {
+		t.Fatalf("only the final delta should be marked done: %#v", deltas)
+	}
+}
+
+func TestOllamaClientSendsConfiguredHeaders(t *testing.T) {
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if got := r.Header.Get("User-Agent"); got != "paperscout-test/1.0" {
+			t.Fatalf("expected configured user agent, got %q", got)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Fatalf("expected auth header, got %q", got)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"response":"ok","done":true}`)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	client := &ollamaClient{
+		host:   "http://example.com",
+		model:  "ministral-3:latest",
+		client: &http.Client{Transport: rt},
+		headers: httpheaders.Config{
+			UserAgent: "paperscout-test/1.0",
+			Extra:     http.Header{"Authorization": {"Bearer secret"}},
+		},
+	}
+	if _, err := client.Summarize(context.Background(), "Paper", "content"); err != nil {
+		t.Fatalf("summarize failed: %v", err)
+	}
+}
+
+func TestOllamaClientSerializesKeepAlive(t *testing.T) {
+	var seen []any
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		seen = append(seen, payload["keep_alive"])
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"response":"ok","done":true}`)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	client := &ollamaClient{
+		host:      "http://example.com",
+		model:     "ministral-3:latest",
+		client:    &http.Client{Transport: rt},
+		keepAlive: "30m",
+	}
+	if _, err := client.Summarize(context.Background(), "Paper", "content"); err != nil {
+		t.Fatalf("summarize failed: %v", err)
+	}
+	err := client.StreamBriefSection(context.Background(), BriefSummary, "Paper", "content", DetailStandard, func(BriefSectionDelta) error { return nil })
+	if err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	client.keepAlive = ""
+	if _, err := client.Summarize(context.Background(), "Paper", "content"); err != nil {
+		t.Fatalf("summarize failed: %v", err)
+	}
+	client.keepAlive = "-1"
+	if _, err := client.Summarize(context.Background(), "Paper", "content"); err != nil {
+		t.Fatalf("summarize failed: %v", err)
+	}
+
+	if len(seen) != 4 || seen[0] != "30m" || seen[1] != "30m" {
+		t.Fatalf("expected keep_alive 30m on generate and stream, got %v", seen)
+	}
+	if seen[2] != nil {
+		t.Fatalf("expected keep_alive omitted when unset, got %v", seen[2])
+	}
+	// JSON numbers decode as float64; a string "-1" would be rejected by Ollama.
+	if seen[3] != float64(-1) {
+		t.Fatalf("expected keep_alive -1 sent as a number, got %#v", seen[3])
+	}
+}
+
+func TestOllamaClientPrependsSystemPrompt(t *testing.T) {
+	var prompt string
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("failed to decode payload: %v", err)
+		}
+		if _, ok := payload["system"]; ok {
+			t.Fatalf("expected the Modelfile system prompt left alone, got %v", payload["system"])
+		}
+		prompt, _ = payload["prompt"].(string)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"response":"ok","done":true}`)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	client := &ollamaClient{
+		host:   "http://example.com",
+		model:  "ministral-3:latest",
+		client: &http.Client{Transport: rt},
+		system: "Explain like I'm an undergrad.",
+	}
+	if _, err := client.Summarize(context.Background(), "Paper", "content"); err != nil {
+		t.Fatalf("summarize failed: %v", err)
+	}
+	if !strings.HasPrefix(prompt, "Explain like I'm an undergrad.\n\n") || !strings.Contains(prompt, "content") {
+		t.Fatalf("expected the persona ahead of the prompt, got %q", prompt)
+	}
+}
+
+func TestOllamaClientRetriesRateLimit(t *testing.T) {
+	calls := 0
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Status:     "429 Too Many Requests",
+				Body:       io.NopCloser(strings.NewReader("slow down")),
+				Header:     http.Header{"Retry-After": {"1"}},
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"response":"after retry","done":true}`)),
+			Header:     make(http.Header),
+		}, nil
+	})
+
+	var waits []time.Duration
+	var notices []string
+	client := &ollamaClient{
+		host:    "http://example.com",
+		model:   "ministral-3:latest",
+		client:  &http.Client{Transport: rt},
+		onRetry: func(msg string) { notices = append(notices, msg) },
+		wait: func(ctx context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		},
+	}
+	result, err := client.Summarize(context.Background(), "Paper", "content")
+	if err != nil {
+		t.Fatalf("summarize failed: %v", err)
+	}
+	if result != "after retry" || calls != 2 {
+		t.Fatalf("expected retry to succeed on second call, got %q after %d calls", result, calls)
+	}
+	if len(waits) != 1 || waits[0] != time.Second {
+		t.Fatalf("expected a single 1s wait, got %v", waits)
+	}
+	if len(notices) != 1 || notices[0] != "LLM rate limited, retrying in 1s" {
+		t.Fatalf("unexpected retry notices %v", notices)
+	}
+}
+
+func TestParseRetryAfterBounds(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := map[string]time.Duration{
+		"":      defaultRateLimitWait,
+		"bogus": defaultRateLimitWait,
+		"5":     5 * time.Second,
+		"3600":  maxRateLimitWait,
+		now.Add(10 * time.Second).Format(http.TimeFormat): 10 * time.Second,
+	}
+	for value, want := range cases {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Fatalf("parseRetryAfter(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestOllamaClientStreamSuggestNotes(t *testing.T) {
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		body := strings.Join([]string{
+			`{"response":"{\"notes\":[{\"title\":\"Problem\",","done":false}`,
+			`{"response":"\"body\":\"Body text\"}","done":false}`,
+			`{"response":",{\"title\":\"Result\",\"body\":\"Wins\"}]}","done":true}`,
+		}, "\n")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+		}, nil
+	})
+	client := &ollamaClient{
+		host:   "http://example.com",
+		model:  "ministral-3:latest",
+		client: &http.Client{Transport: rt},
+	}
+
+	var titles []string
+	notes, err := client.StreamSuggestNotes(context.Background(), "Paper", "abstract", nil, "body", func(note SuggestedNote) error {
+		titles = append(titles, note.Title)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("stream suggest failed: %v", err)
+	}
+	if len(notes) != 2 || strings.Join(titles, ",") != "Problem,Result" {
+		t.Fatalf("unexpected streamed notes %+v (handler saw %v)", notes, titles)
+	}
+}
+
+func TestOllamaClientLogsRawResponseOnParseFailure(t *testing.T) {
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"response":"Sure! Here are some notes: none really.","done":true}`)),
+			Header:     make(http.Header),
+		}, nil
+	})
+	var logged bytes.Buffer
+	client := &ollamaClient{
+		host:     "http://example.com",
+		model:    "ministral-3:latest",
+		client:   &http.Client{Transport: rt},
+		debugLog: log.New(&logged, "", 0),
+	}
+
+	if _, err := client.SuggestNotes(context.Background(), "Cool Paper", "abstract", nil, "body"); err == nil {
+		t.Fatal("expected a parse error for a non-JSON response")
+	}
+	got := logged.String()
+	if !strings.Contains(got, "parse suggested notes failed") || !strings.Contains(got, "Here are some notes: none really.") {
+		t.Fatalf("expected parse failure with raw payload in debug log, got %q", got)
+	}
+
+	logged.Reset()
+	client.debugLog = nil
+	if _, err := client.SuggestNotes(context.Background(), "Cool Paper", "abstract", nil, "body"); err == nil {
+		t.Fatal("expected a parse error for a non-JSON response")
+	}
+	if logged.Len() != 0 {
+		t.Fatalf("expected nothing logged without debug, got %q", logged.String())
+	}
+}
@@ -2,33 +2,63 @@ package llm
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 	"unicode"
+
+	"github.com/csheth/browse/internal/sentences"
+	"github.com/csheth/browse/internal/textsim"
 )
 
 var whitespaceRe = regexp.MustCompile(`\s+`)
 
-func clipText(text string, limit int) string {
-	text = strings.TrimSpace(text)
-	if limit <= 0 || len(text) <= limit {
-		return text
-	}
-	runes := []rune(text)
-	if len(runes) <= limit {
-		return text
+// ErrEmptyBrief reports a well-formed brief payload whose sections are all empty, letting callers
+// fall back to heuristics instead of surfacing a parse failure.
+var ErrEmptyBrief = errors.New("model produced no content for the reading brief")
+
+func clipText(text string, limit int, est TokenEstimator) string {
+	return ClipTokens(strings.TrimSpace(text), limit, est)
+}
+
+const (
+	defaultSummaryBullets = 5
+	defaultBulletMaxWords = 20
+)
+
+// promptLengths carries the Config bullet counts and BulletMaxWords to the prompt builders; zero
+// fields keep each prompt's default.
+type promptLengths struct {
+	summaryBullets   int
+	technicalBullets int
+	deepDiveBullets  int
+	bulletMaxWords   int
+}
+
+func promptLengthsFrom(cfg Config) promptLengths {
+	return promptLengths{
+		summaryBullets:   max(cfg.SummaryBullets, 0),
+		technicalBullets: max(cfg.TechnicalBullets, 0),
+		deepDiveBullets:  max(cfg.DeepDiveBullets, 0),
+		bulletMaxWords:   max(cfg.BulletMaxWords, 0),
 	}
-	return string(runes[:limit])
 }
 
-func buildSummaryPrompt(title, context string) string {
+func buildSummaryPrompt(title, context string, lengths promptLengths) string {
 	if title == "" {
 		title = "the paper"
 	}
+	bullets, words := defaultSummaryBullets, defaultBulletMaxWords
+	if lengths.summaryBullets > 0 {
+		bullets = lengths.summaryBullets
+	}
+	if lengths.bulletMaxWords > 0 {
+		words = lengths.bulletMaxWords
+	}
 	return "You are an expert research assistant. " +
-		"Write a concise 5-bullet summary covering the core problem, method, results, and limitations.\n" +
-		"Each bullet should be <=20 words.\n\n" +
+		fmt.Sprintf("Write a concise %d-bullet summary covering the core problem, method, results, and limitations.\n", bullets) +
+		fmt.Sprintf("Each bullet should be <=%d words.\n\n", words) +
 		"Paper title: " + title + "\n\n" +
 		"Content:\n" + context
 }
@@ -46,7 +76,7 @@ func buildAnswerPrompt(title, context, question string) string {
 	return builder.String()
 }
 
-func buildSuggestionContext(abstract string, contributions []string, content string, limit int) string {
+func buildSuggestionContext(abstract string, contributions []string, content string, limit int, est TokenEstimator) string {
 	var b strings.Builder
 	abstract = strings.TrimSpace(abstract)
 	if abstract != "" {
@@ -63,7 +93,7 @@ func buildSuggestionContext(abstract string, contributions []string, content str
 		}
 		b.WriteRune('\n')
 	}
-	snippet := clipText(content, limit)
+	snippet := clipText(content, limit, est)
 	snippet = strings.TrimSpace(snippet)
 	if snippet != "" {
 		b.WriteString("Paper Excerpt:\n")
@@ -85,6 +115,39 @@ func buildSuggestionPrompt(title, context string) string {
 	)
 }
 
+// buildGapAnalysisPrompt asks for notes covering what the paper says that the reader's saved notes
+// do not, so the response can be parsed with parseSuggestedNotes.
+func buildGapAnalysisPrompt(title string, existing []SuggestedNote, context string) string {
+	if title == "" {
+		title = "the paper"
+	}
+	var saved strings.Builder
+	for idx, note := range existing {
+		noteTitle := strings.TrimSpace(note.Title)
+		if noteTitle == "" {
+			noteTitle = "Untitled"
+		}
+		saved.WriteString(fmt.Sprintf("%d. %s", idx+1, noteTitle))
+		if kind := strings.TrimSpace(note.Kind); kind != "" {
+			saved.WriteString(fmt.Sprintf(" [%s]", kind))
+		}
+		if body := strings.TrimSpace(note.Body); body != "" {
+			saved.WriteString(": " + body)
+		}
+		saved.WriteRune('\n')
+	}
+	if saved.Len() == 0 {
+		saved.WriteString("(none)\n")
+	}
+	return fmt.Sprintf(
+		"You are reviewing a researcher's zettelkasten notes against the paper they were reading.\n"+
+			"Identify 3-5 insights, results, caveats, or open questions the paper covers that the existing notes do NOT capture. Skip anything the notes already state, even if phrased differently.\n"+
+			"Each note must include: title (<=10 words), body (2-3 sentences grounded in the text), reason (which gap in the existing notes it fills), and kind (problem|method|result|risk|open-question|follow-up).\n"+
+			"Return ONLY JSON that matches: {\"notes\":[{\"title\":\"\",\"body\":\"\",\"reason\":\"\",\"kind\":\"\"}]}.\n\n"+
+			"Paper title: %s\n\nExisting notes:\n%s\nContext:\n%s", title, saved.String(), context,
+	)
+}
+
 func parseSuggestedNotes(raw string) ([]SuggestedNote, error) {
 	raw = strings.TrimSpace(raw)
 	if raw == "" {
@@ -113,6 +176,77 @@ func parseSuggestedNotes(raw string) ([]SuggestedNote, error) {
 	return nil, fmt.Errorf("unable to parse suggestion payload")
 }
 
+// streamParseSuggestedNotes returns a feeder for incrementally streamed suggestion JSON. It tracks
+// brace/bracket depth (ignoring braces inside strings) and calls emit with each note object as soon
+// as its closing brace arrives, whether the model wraps the array in {"notes":[...]} or not.
+func streamParseSuggestedNotes(emit func(SuggestedNote) error) func(chunk string) error {
+	var (
+		buf      []byte
+		stack    []byte
+		inString bool
+		escaped  bool
+		start    = -1
+		depth    int
+		emitted  []SuggestedNote
+	)
+	return func(chunk string) error {
+		for i := 0; i < len(chunk); i++ {
+			c := chunk[i]
+			buf = append(buf, c)
+			if inString {
+				switch {
+				case escaped:
+					escaped = false
+				case c == '\\':
+					escaped = true
+				case c == '"':
+					inString = false
+				}
+				continue
+			}
+			switch c {
+			case '"':
+				inString = true
+			case '[':
+				stack = append(stack, c)
+			case '{':
+				if start < 0 && len(stack) > 0 && stack[len(stack)-1] == '[' {
+					start = len(buf) - 1
+					depth = len(stack)
+				}
+				stack = append(stack, c)
+			case ']', '}':
+				if len(stack) == 0 {
+					continue
+				}
+				stack = stack[:len(stack)-1]
+				if c != '}' || start < 0 || len(stack) != depth {
+					continue
+				}
+				var note SuggestedNote
+				err := json.Unmarshal(buf[start:], &note)
+				start = -1
+				if err != nil {
+					continue
+				}
+				for _, cleaned := range sanitizeSuggestedNotes([]SuggestedNote{note}) {
+					if duplicatesSuggestedNote(emitted, cleaned) {
+						continue
+					}
+					emitted = append(emitted, cleaned)
+					if err := emit(cleaned); err != nil {
+						return err
+					}
+				}
+			}
+			if start < 0 && len(stack) == 0 {
+				buf = buf[:0]
+			}
+		}
+		return nil
+	}
+}
+
 func sanitizeSuggestedNotes(notes []SuggestedNote) []SuggestedNote {
 	result := make([]SuggestedNote, 0, len(notes))
 	for _, note := range notes {
@@ -122,7 +256,7 @@ func sanitizeSuggestedNotes(notes []SuggestedNote) []SuggestedNote {
 			Reason: strings.TrimSpace(note.Reason),
 			Kind:   strings.TrimSpace(note.Kind),
 		}
-		if n.Title == "" || n.Body == "" {
+		if n.Title == "" || n.Body == "" || duplicatesSuggestedNote(result, n) {
 			continue
 		}
 		result = append(result, n)
@@ -130,6 +264,17 @@ func sanitizeSuggestedNotes(notes []SuggestedNote) []SuggestedNote {
 	return result
 }
 
+// duplicatesSuggestedNote reports whether note's title or body is a near duplicate of a kept note,
+// which happens when the model restates one idea under two headings.
+func duplicatesSuggestedNote(kept []SuggestedNote, note SuggestedNote) bool {
+	for _, existing := range kept {
+		if textsim.NearDuplicate(existing.Title, note.Title) || textsim.NearDuplicate(existing.Body, note.Body) {
+			return true
+		}
+	}
+	return false
+}
+
 func buildBriefPrompt(title, context string) string {
 	if title == "" {
 		title = "the paper"
@@ -159,9 +304,11 @@ func parseReadingBrief(raw string) (ReadingBrief, error) {
 			candidates = append(candidates, raw[start:end+1])
 		}
 	}
+	parsed := false
 	for _, candidate := range candidates {
 		var brief ReadingBrief
 		if err := json.Unmarshal([]byte(candidate), &brief); err == nil {
+			parsed = true
 			brief.Summary = sanitizeBullets(brief.Summary)
 			brief.Technical = sanitizeBullets(brief.Technical)
 			brief.DeepDive = sanitizeBullets(brief.DeepDive)
@@ -170,29 +317,60 @@ func parseReadingBrief(raw string) (ReadingBrief, error) {
 			}
 		}
 	}
+	if parsed {
+		return ReadingBrief{}, ErrEmptyBrief
+	}
 	return ReadingBrief{}, fmt.Errorf("unable to parse brief payload")
 }
 
-func buildBriefSectionPrompt(kind BriefSectionKind, title, context string) string {
+func buildBriefSectionPrompt(kind BriefSectionKind, title, context string, detail DetailLevel, lengths promptLengths) string {
 	if title == "" {
 		title = "the paper"
 	}
+	expanded := detail == DetailExpanded
 	var directives string
 	var heading string
 	switch kind {
 	case BriefSummary:
 		heading = "### Summary"
-		directives = "Return 3-5 concise top-level bullets covering the problem domain, leading prior work, the proposed approach with key contributions, and evaluation results. Use two-space indents for nested clarifications."
+		standard, more := "3-5", "6-9"
+		if n := lengths.summaryBullets; n > 0 {
+			standard, more = bulletRange(n, n), bulletRange(n+1, 2*n)
+		}
+		directives = "Return " + standard + " concise top-level bullets covering the problem domain, leading prior work, the proposed approach with key contributions, and evaluation results. Use two-space indents for nested clarifications."
+		if expanded {
+			directives = "Return " + more + " top-level bullets covering the problem domain, leading prior work, the proposed approach with each key contribution, and evaluation results. Give every bullet at least one nested sub-bullet (two-space indent) with concrete specifics such as numbers, baselines, or design choices."
+		}
 	case BriefTechnical:
 		heading = "### Technical"
-		directives = "Return 3-7 bullets covering assumptions, dataset details, architecture, training/evaluation protocols, and reproducibility cues. Include nested sub-bullets (two spaces per depth) and feel free to embed inline `code`, $LaTeX$, and markdown tables for clarity."
+		standard, more := "3-7", "8-12"
+		if n := lengths.technicalBullets; n > 0 {
+			standard, more = bulletRange(n, n), bulletRange(n+1, 2*n)
+		}
+		directives = "Return " + standard + " bullets covering assumptions, dataset details, architecture, training/evaluation protocols, and reproducibility cues. Include nested sub-bullets (two spaces per depth) and feel free to embed inline `code`, $LaTeX$, and markdown tables for clarity."
+		if expanded {
+			directives = "Return " + more + " bullets covering assumptions, dataset details, architecture, training/evaluation protocols, hyperparameters, ablations, and reproducibility cues. Go deeper than a summary: spell out equations in $LaTeX$, exact settings, and failure modes, with nested sub-bullets (two spaces per depth), inline `code`, and markdown tables where they help."
+		}
 	case BriefDeepDive:
 		heading = "### Deep Dive"
-		directives = "Return exactly 3 bullets describing influential cited or related works, each noting the insight or why it matters. Use nested sub-bullets to highlight follow-up resources or comparisons."
+		standard, more := "3", "5-6"
+		if n := lengths.deepDiveBullets; n > 0 {
+			standard, more = bulletRange(n, n), bulletRange(n+1, 2*n)
+		}
+		directives = "Return exactly " + standard + " bullets describing influential cited or related works, each noting the insight or why it matters. Use nested sub-bullets to highlight follow-up resources or comparisons."
+		if expanded {
+			directives = "Return " + more + " bullets describing influential cited or related works, each noting the insight, why it matters, and how this paper differs. Use nested sub-bullets to highlight follow-up resources, open questions, or comparisons."
+		}
 	default:
 		heading = "### Summary"
 		directives = "Return 3 concise bullets summarizing the paper."
 	}
+	if lengths.bulletMaxWords > 0 {
+		directives += fmt.Sprintf(" Keep each top-level bullet to at most %d words and put further detail in nested sub-bullets.", lengths.bulletMaxWords)
+	}
+	if expanded {
+		directives += " A previous version of this section was too terse, so favour depth over brevity."
+	}
 	return fmt.Sprintf(`You are guiding a researcher through S. Keshav's three-pass reading method.
 Write the %s section as standalone markdown that begins with "%s" followed by structured bullet lists (top-level bullets prefixed with "- " and nested bullets indented by two additional spaces).
 %s
@@ -204,6 +382,36 @@ Context:
 %s`, sectionLabel(kind), heading, directives, title, context)
 }
 
+// bulletRange renders a bullet count for a prompt: "4" or "5-8".
+func bulletRange(lo, hi int) string {
+	if hi <= lo {
+		return fmt.Sprint(lo)
+	}
+	return fmt.Sprintf("%d-%d", lo, hi)
+}
+
+// buildBriefContinuationPrompt asks for more bullets when a section came back shorter than the
+// configured minimum. The existing bullets are included so the model extends rather than repeats.
+func buildBriefContinuationPrompt(kind BriefSectionKind, title string, existing []string, context string, missing int) string {
+	if title == "" {
+		title = "the paper"
+	}
+	if missing < 1 {
+		missing = 1
+	}
+	return fmt.Sprintf(`You are guiding a researcher through S. Keshav's three-pass reading method.
+The %s section below is too short. Write at least %d additional top-level bullets (prefixed with "- ", nested bullets indented by two additional spaces) that cover details it misses, grounded in the additional context. Do not repeat or rephrase the existing bullets and do not add a heading.
+Avoid wrapping the output in JSON or prose; emit only the new markdown lines.
+
+Paper title: %s
+
+Existing section:
+%s
+
+Additional context:
+%s`, sectionLabel(kind), missing, title, strings.TrimSpace(strings.Join(existing, "\n")), context)
+}
+
 func sectionLabel(kind BriefSectionKind) string {
 	switch kind {
 	case BriefSummary:
@@ -217,8 +425,18 @@ func sectionLabel(kind BriefSectionKind) string {
 	}
 }
 
-func clipBriefSectionContext(kind BriefSectionKind, text string) string {
-	return clipText(text, BriefSectionLimit(kind))
+func clipBriefSectionContext(kind BriefSectionKind, text string, est TokenEstimator) string {
+	return clipText(text, BriefSectionLimit(kind), est)
+}
+
+// EstimateBriefContext is a dry run of the clipping a brief section request applies: used is how
+// many tokens of content would be sent and budget is the section's limit. A nil est uses
+// HeuristicEstimator, like the clients do.
+func EstimateBriefContext(kind BriefSectionKind, content string, est TokenEstimator) (used, budget int) {
+	if est == nil {
+		est = HeuristicEstimator{}
+	}
+	return est.EstimateTokens(clipBriefSectionContext(kind, content, est)), BriefSectionLimit(kind)
 }
 
 func parseBriefSection(raw string) ([]string, error) {
@@ -295,40 +513,43 @@ func parseSimpleBulletList(raw string) []string {
 	return bullets
 }
 
-func extractQuestionContext(content, question string, limit int) string {
+func extractQuestionContext(content, question string, limit int, est TokenEstimator) string {
 	content = strings.TrimSpace(content)
 	if content == "" {
 		return ""
 	}
 	keywords := questionKeywords(question)
 	if len(keywords) == 0 {
-		return clipText(content, limit)
+		return clipText(content, limit, est)
 	}
 
 	sentences := roughSentenceSplit(content)
 	var matches []string
-	totalLen := 0
+	if est == nil {
+		est = HeuristicEstimator{}
+	}
+	totalTokens := 0
 
 	for _, sentence := range sentences {
 		lower := strings.ToLower(sentence)
 		for keyword := range keywords {
 			if strings.Contains(lower, keyword) {
 				matches = append(matches, sentence)
-				totalLen += len(sentence)
+				totalTokens += est.EstimateTokens(sentence)
 				break
 			}
 		}
-		if totalLen >= limit {
+		if totalTokens >= limit {
 			break
 		}
 	}
 
 	if len(matches) == 0 {
-		return clipText(content, limit)
+		return clipText(content, limit, est)
 	}
 
 	snippet := strings.Join(matches, " ")
-	return clipText(snippet, limit)
+	return clipText(snippet, limit, est)
 }
 
 func questionKeywords(question string) map[string]struct{} {
@@ -357,24 +578,5 @@ func questionKeywords(question string) map[string]struct{} {
 }
 
 func roughSentenceSplit(text string) []string {
-	text = strings.TrimSpace(text)
-	if text == "" {
-		return nil
-	}
-	var sentences []string
-	var current strings.Builder
-	for _, r := range text {
-		current.WriteRune(r)
-		if r == '.' || r == '!' || r == '?' {
-			sentence := strings.TrimSpace(current.String())
-			if sentence != "" {
-				sentences = append(sentences, sentence)
-			}
-			current.Reset()
-		}
-	}
-	if tail := strings.TrimSpace(current.String()); tail != "" {
-		sentences = append(sentences, tail)
-	}
-	return sentences
+	return sentences.Split(text)
 }
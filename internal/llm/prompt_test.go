@@ -0,0 +1,196 @@
+package llm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseReadingBriefEmptyButValid(t *testing.T) {
+	_, err := parseReadingBrief(`{"summary":[],"technical":[" "],"deepDive":[]}`)
+	if !errors.Is(err, ErrEmptyBrief) {
+		t.Fatalf("expected ErrEmptyBrief, got %v", err)
+	}
+	if err.Error() != "model produced no content for the reading brief" {
+		t.Fatalf("unexpected error message: %q", err.Error())
+	}
+}
+
+func TestParseReadingBriefUnparseable(t *testing.T) {
+	_, err := parseReadingBrief("Sorry, I cannot help with that.")
+	if err == nil || errors.Is(err, ErrEmptyBrief) {
+		t.Fatalf("expected parse failure distinct from ErrEmptyBrief, got %v", err)
+	}
+	if err.Error() != "unable to parse brief payload" {
+		t.Fatalf("unexpected error message: %q", err.Error())
+	}
+}
+
+func TestBuildGapAnalysisPromptListsExistingNotes(t *testing.T) {
+	prompt := buildGapAnalysisPrompt("Sparse Attention", []SuggestedNote{
+		{Title: "Core idea", Body: "Attention restricted to local windows.", Kind: "method"},
+		{Title: "  ", Body: ""},
+	}, "Full paper text")
+
+	for _, want := range []string{
+		"Paper title: Sparse Attention",
+		"1. Core idea [method]: Attention restricted to local windows.",
+		"2. Untitled\n",
+		"do NOT capture",
+		"Context:\nFull paper text",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Fatalf("expected prompt to contain %q, got:\n%s", want, prompt)
+		}
+	}
+
+	empty := buildGapAnalysisPrompt("", nil, "text")
+	if !strings.Contains(empty, "Existing notes:\n(none)") || !strings.Contains(empty, "Paper title: the paper") {
+		t.Fatalf("unexpected prompt without notes:\n%s", empty)
+	}
+}
+
+func TestStreamParseSuggestedNotesEmitsCompleteObjects(t *testing.T) {
+	var got []SuggestedNote
+	feed := streamParseSuggestedNotes(func(note SuggestedNote) error {
+		got = append(got, note)
+		return nil
+	})
+
+	fragments := []string{
+		`{"notes":[{"title":"Problem","bo`,
+		`dy":"Uses {braces} and \"quotes\"","kind":"problem","tags":[{"x":1}]}`,
+		`,{"title":"Method",`,
+		`"body":"Sparse routing"}`,
+		`,{"title":"","body":"dropped"}]}`,
+	}
+	wantAfter := []int{0, 1, 1, 2, 2}
+	for idx, fragment := range fragments {
+		if err := feed(fragment); err != nil {
+			t.Fatalf("feed(%q) error = %v", fragment, err)
+		}
+		if len(got) != wantAfter[idx] {
+			t.Fatalf("after fragment %d expected %d notes, got %d (%+v)", idx, wantAfter[idx], len(got), got)
+		}
+	}
+	if got[0].Body != `Uses {braces} and "quotes"` || got[1].Title != "Method" {
+		t.Fatalf("unexpected parsed notes %+v", got)
+	}
+
+	var bare []SuggestedNote
+	feedBare := streamParseSuggestedNotes(func(note SuggestedNote) error {
+		bare = append(bare, note)
+		return nil
+	})
+	if err := feedBare(`Sure! [{"title":"A","body":"B"}]`); err != nil || len(bare) != 1 {
+		t.Fatalf("expected bare array note, got %+v (err %v)", bare, err)
+	}
+}
+
+func TestSanitizeSuggestedNotesDropsNearDuplicates(t *testing.T) {
+	got := sanitizeSuggestedNotes([]SuggestedNote{
+		{Title: "Sparse routing", Body: "Tokens are routed to a few experts, which cuts compute per layer."},
+		{Title: "Compute savings", Body: "Tokens are routed to a few experts which cuts compute per layer!"},
+		{Title: "sparse  Routing.", Body: "Routing is learned jointly with the experts."},
+		{Title: "Evaluation", Body: "Beats dense baselines on three benchmarks."},
+	})
+	if len(got) != 2 || got[0].Title != "Sparse routing" || got[1].Title != "Evaluation" {
+		t.Fatalf("expected only the first of each near-duplicate pair, got %+v", got)
+	}
+
+	var streamed []SuggestedNote
+	feed := streamParseSuggestedNotes(func(note SuggestedNote) error {
+		streamed = append(streamed, note)
+		return nil
+	})
+	if err := feed(`[{"title":"Core idea","body":"Sparse routing cuts compute."},{"title":"Main idea","body":"Sparse routing cuts compute"}]`); err != nil {
+		t.Fatalf("feed error = %v", err)
+	}
+	if len(streamed) != 1 {
+		t.Fatalf("expected the streamed duplicate to be dropped, got %+v", streamed)
+	}
+}
+
+func TestPromptsHonorConfiguredLengths(t *testing.T) {
+	defaults := buildSummaryPrompt("Paper", "context", promptLengths{})
+	if !strings.Contains(defaults, "5-bullet summary") || !strings.Contains(defaults, "<=20 words") {
+		t.Fatalf("expected default summary lengths, got:\n%s", defaults)
+	}
+	lengths := promptLengthsFrom(Config{SummaryBullets: 8, BulletMaxWords: 12})
+	summary := buildSummaryPrompt("Paper", "context", lengths)
+	if !strings.Contains(summary, "8-bullet summary") || !strings.Contains(summary, "<=12 words") {
+		t.Fatalf("expected configured summary lengths, got:\n%s", summary)
+	}
+
+	standard := buildBriefSectionPrompt(BriefSummary, "Paper", "context", DetailStandard, lengths)
+	for _, want := range []string{"Return 8 concise top-level bullets", "at most 12 words"} {
+		if !strings.Contains(standard, want) {
+			t.Fatalf("expected summary section prompt to contain %q, got:\n%s", want, standard)
+		}
+	}
+	if expanded := buildBriefSectionPrompt(BriefSummary, "Paper", "context", DetailExpanded, lengths); !strings.Contains(expanded, "Return 9-16 top-level bullets") {
+		t.Fatalf("expected the expanded range to grow from the configured count, got:\n%s", expanded)
+	}
+	if technical := buildBriefSectionPrompt(BriefTechnical, "Paper", "context", DetailStandard, lengths); !strings.Contains(technical, "Return 3-7 bullets") || !strings.Contains(technical, "at most 12 words") {
+		t.Fatalf("expected the technical range kept with the word cap, got:\n%s", technical)
+	}
+	if plain := buildBriefSectionPrompt(BriefSummary, "Paper", "context", DetailStandard, promptLengths{}); !strings.Contains(plain, "Return 3-5 concise") || strings.Contains(plain, "at most") {
+		t.Fatalf("expected default summary section prompt, got:\n%s", plain)
+	}
+
+	sections := promptLengthsFrom(Config{TechnicalBullets: 5, DeepDiveBullets: 4, SummaryBullets: -2})
+	for _, tc := range []struct {
+		kind   BriefSectionKind
+		detail DetailLevel
+		want   string
+	}{
+		{BriefTechnical, DetailStandard, "Return 5 bullets"},
+		{BriefTechnical, DetailExpanded, "Return 6-10 bullets"},
+		{BriefDeepDive, DetailStandard, "Return exactly 4 bullets"},
+		{BriefDeepDive, DetailExpanded, "Return 5-8 bullets"},
+		{BriefSummary, DetailStandard, "Return 3-5 concise"},
+	} {
+		if prompt := buildBriefSectionPrompt(tc.kind, "Paper", "context", tc.detail, sections); !strings.Contains(prompt, tc.want) {
+			t.Fatalf("expected %s prompt to contain %q, got:\n%s", tc.kind, tc.want, prompt)
+		}
+	}
+}
+
+func TestBuildBriefSectionPromptExpandedRequestsMoreDetail(t *testing.T) {
+	standard := buildBriefSectionPrompt(BriefTechnical, "Paper", "context", DetailStandard, promptLengths{})
+	expanded := buildBriefSectionPrompt(BriefTechnical, "Paper", "context", DetailExpanded, promptLengths{})
+	if !strings.Contains(standard, "Return 3-7 bullets") {
+		t.Fatalf("expected standard bullet range, got:\n%s", standard)
+	}
+	for _, want := range []string{"Return 8-12 bullets", "too terse", "Context:\ncontext"} {
+		if !strings.Contains(expanded, want) {
+			t.Fatalf("expected expanded prompt to contain %q, got:\n%s", want, expanded)
+		}
+	}
+	for _, kind := range []BriefSectionKind{BriefSummary, BriefDeepDive} {
+		if buildBriefSectionPrompt(kind, "Paper", "context", DetailExpanded, promptLengths{}) == buildBriefSectionPrompt(kind, "Paper", "context", DetailStandard, promptLengths{}) {
+			t.Fatalf("expected expanded %s prompt to differ from the standard one", kind)
+		}
+	}
+}
+
+func TestEstimateBriefContextNeverExceedsBudget(t *testing.T) {
+	prose := strings.Repeat("The model attends over long documents with sparse windows. ", 5000)
+	code := strings.Repeat("if (a[i] > b[j]) { c += a[i] * b[j]; }\n", 5000)
+	for _, kind := range []BriefSectionKind{BriefSummary, BriefTechnical, BriefDeepDive} {
+		for _, est := range []TokenEstimator{nil, HeuristicEstimator{}, BPEEstimator{}} {
+			for _, content := range []string{"", "short", prose, code} {
+				used, budget := EstimateBriefContext(kind, content, est)
+				if budget != BriefSectionLimit(kind) {
+					t.Fatalf("%s: expected budget %d, got %d", kind, BriefSectionLimit(kind), budget)
+				}
+				if used > budget {
+					t.Fatalf("%s with %T: used %d exceeds budget %d", kind, est, used, budget)
+				}
+			}
+		}
+	}
+	if used, _ := EstimateBriefContext(BriefSummary, "  short  ", nil); used != 2 {
+		t.Fatalf("expected the trimmed content to cost 2 tokens, got %d", used)
+	}
+}
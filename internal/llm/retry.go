@@ -0,0 +1,52 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxRateLimitRetries  = 3
+	defaultRateLimitWait = 2 * time.Second
+	maxRateLimitWait     = 30 * time.Second
+)
+
+// parseRetryAfter reads a Retry-After header given either as delta-seconds or an HTTP date. Missing
+// or malformed values fall back to defaultRateLimitWait and everything is capped at maxRateLimitWait
+// so a misbehaving proxy cannot stall a reading session.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	delay := defaultRateLimitWait
+	if value != "" {
+		if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+			delay = time.Duration(secs) * time.Second
+		} else if at, err := http.ParseTime(value); err == nil {
+			delay = at.Sub(now)
+		}
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	if delay > maxRateLimitWait {
+		delay = maxRateLimitWait
+	}
+	return delay
+}
+
+func formatRetryDelay(d time.Duration) string {
+	return strconv.Itoa(int(d.Round(time.Second)/time.Second)) + "s"
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,109 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenEstimator approximates how many model tokens a piece of text costs, so context budgets hold
+// for code- and math-heavy papers as well as prose.
+type TokenEstimator interface {
+	EstimateTokens(text string) int
+}
+
+// Token estimator names accepted by ParseTokenEstimator.
+const (
+	EstimatorHeuristic = "heuristic"
+	EstimatorBPE       = "bpe"
+)
+
+// HeuristicEstimator assumes about four characters per token, which holds for English prose. It is
+// the default when no estimator is configured.
+type HeuristicEstimator struct{}
+
+// EstimateTokens rounds the rune count up to whole four-character tokens.
+func (HeuristicEstimator) EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// BPEEstimator mimics how tiktoken-style byte-pair encoders split text: common words cost about a
+// token per six letters, digits group in threes, and every symbol, bracket, or non-Latin
+// character is usually a token of its own. Equations and source code therefore cost noticeably
+// more than their length suggests.
+type BPEEstimator struct{}
+
+// EstimateTokens walks the text in runs of letters, digits, and whitespace.
+func (BPEEstimator) EstimateTokens(text string) int {
+	tokens := 0
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		j := i + 1
+		switch {
+		case r < utf8.RuneSelf && unicode.IsLetter(r):
+			for j < len(runes) && runes[j] < utf8.RuneSelf && unicode.IsLetter(runes[j]) {
+				j++
+			}
+			tokens += (j - i + 5) / 6
+		case unicode.IsDigit(r):
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			tokens += (j - i + 2) / 3
+		case r == ' ':
+			// A single space is merged into the following word.
+			for j < len(runes) && runes[j] == ' ' {
+				j++
+			}
+			if j-i > 1 {
+				tokens++
+			}
+		case unicode.IsSpace(r):
+			for j < len(runes) && unicode.IsSpace(runes[j]) {
+				j++
+			}
+			tokens++
+		default:
+			tokens++
+		}
+		i = j
+	}
+	return tokens
+}
+
+// ParseTokenEstimator validates a -token-estimator value; empty means the heuristic.
+func ParseTokenEstimator(value string) (TokenEstimator, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", EstimatorHeuristic:
+		return HeuristicEstimator{}, nil
+	case EstimatorBPE:
+		return BPEEstimator{}, nil
+	default:
+		return nil, fmt.Errorf("unknown token estimator %q (want heuristic or bpe)", value)
+	}
+}
+
+// ClipTokens returns the longest prefix of text that est counts at no more than limit tokens. A nil
+// est uses HeuristicEstimator and a non-positive limit leaves text untouched.
+func ClipTokens(text string, limit int, est TokenEstimator) string {
+	if est == nil {
+		est = HeuristicEstimator{}
+	}
+	if limit <= 0 || est.EstimateTokens(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	// Estimates grow with the prefix, so binary search for the longest prefix that still fits.
+	lo, hi := 0, len(runes)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if est.EstimateTokens(string(runes[:mid])) <= limit {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return string(runes[:lo])
+}
@@ -0,0 +1,50 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBPEEstimatorChargesCodeMoreThanProse(t *testing.T) {
+	prose := "The model attends over every token in the sequence."
+	code := "x_{i}=\\frac{a_i}{b_i}+\\sum_{j}(w_j*x_j);"
+	est := BPEEstimator{}
+
+	if got, heuristic := est.EstimateTokens(prose), (HeuristicEstimator{}).EstimateTokens(prose); got > heuristic {
+		t.Fatalf("expected prose to cost no more than the heuristic, got %d vs %d", got, heuristic)
+	}
+	if got, heuristic := est.EstimateTokens(code), (HeuristicEstimator{}).EstimateTokens(code); got <= heuristic {
+		t.Fatalf("expected code to cost more than the heuristic, got %d vs %d", got, heuristic)
+	}
+}
+
+func TestClipTokensRespectsBudget(t *testing.T) {
+	code := strings.Repeat("if (a[i] > b[j]) { c += a[i] * b[j]; }\n", 50)
+
+	heuristic := ClipTokens(code, 100, nil)
+	if got := len([]rune(heuristic)); got != 400 {
+		t.Fatalf("expected the heuristic to keep 400 runes, got %d", got)
+	}
+	bpe := ClipTokens(code, 100, BPEEstimator{})
+	if got := (BPEEstimator{}).EstimateTokens(bpe); got > 100 {
+		t.Fatalf("expected at most 100 tokens, got %d", got)
+	}
+	if len(bpe) >= len(heuristic) {
+		t.Fatalf("expected the BPE clip to be tighter: %d vs %d runes", len(bpe), len(heuristic))
+	}
+	if got := ClipTokens("short", 100, BPEEstimator{}); got != "short" {
+		t.Fatalf("expected text under budget untouched, got %q", got)
+	}
+}
+
+func TestParseTokenEstimator(t *testing.T) {
+	for value, want := range map[string]TokenEstimator{"": HeuristicEstimator{}, "heuristic": HeuristicEstimator{}, " BPE ": BPEEstimator{}} {
+		got, err := ParseTokenEstimator(value)
+		if err != nil || got != want {
+			t.Fatalf("ParseTokenEstimator(%q) = %v, %v", value, got, err)
+		}
+	}
+	if _, err := ParseTokenEstimator("tiktoken"); err == nil {
+		t.Fatal("expected an error for an unknown estimator")
+	}
+}
@@ -13,14 +13,19 @@ type ConversationSnapshot struct {
 	Brief           *BriefSnapshot         `json:"brief,omitempty"`
 	SectionMetadata []BriefSectionMetadata `json:"sectionMetadata,omitempty"`
 	LLM             *LLMMetadata           `json:"llm,omitempty"`
+	Scratchpad      string                 `json:"scratchpad,omitempty"`
 }
 
-// SnapshotUpdate appends new messages or notes to an existing snapshot.
+// SnapshotUpdate appends new messages or notes to an existing snapshot. A non-nil Scratchpad replaces
+// the paper's free-form scratchpad; an empty string clears it. A non-nil LLM replaces the recorded
+// provider and model; on its own it does not trigger a write.
 type SnapshotUpdate struct {
 	Messages        []ConversationMessage  `json:"messages,omitempty"`
 	Notes           []SnapshotNote         `json:"notes,omitempty"`
 	Brief           *BriefSnapshot         `json:"brief,omitempty"`
 	SectionMetadata []BriefSectionMetadata `json:"sectionMetadata,omitempty"`
+	Scratchpad      *string                `json:"scratchpad,omitempty"`
+	LLM             *LLMMetadata           `json:"llm,omitempty"`
 }
 
 // ConversationMessage records one transcript entry or user message.
@@ -36,6 +41,7 @@ type SnapshotNote struct {
 	Body      string    `json:"body"`
 	Kind      string    `json:"kind"`
 	CreatedAt time.Time `json:"createdAt"`
+	Tags      []string  `json:"tags,omitempty"`
 }
 
 // BriefSnapshot stores the generated brief content at snapshot time.
@@ -58,3 +64,20 @@ type LLMMetadata struct {
 	Provider string `json:"provider,omitempty"`
 	Model    string `json:"model,omitempty"`
 }
+
+// LastActivity is the newest of the snapshot's capture time and its message and note timestamps, so
+// a paper reopened long after it was first captured still counts as recent.
+func (s ConversationSnapshot) LastActivity() time.Time {
+	latest := s.CapturedAt
+	for _, msg := range s.Messages {
+		if msg.Timestamp.After(latest) {
+			latest = msg.Timestamp
+		}
+	}
+	for _, note := range s.Notes {
+		if note.CreatedAt.After(latest) {
+			latest = note.CreatedAt
+		}
+	}
+	return latest
+}
@@ -0,0 +1,269 @@
+package notes
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Format selects how the knowledge base is laid out on disk.
+type Format string
+
+const (
+	// FormatFile keeps every note and conversation snapshot in one JSON array file.
+	FormatFile Format = "file"
+	// FormatJSONL keeps one compact JSON entry per line, so saving a note appends a line instead of
+	// rewriting the file. A -zettel path ending in .jsonl selects it on its own.
+	FormatJSONL Format = "jsonl"
+	// FormatDir writes each note and snapshot to its own JSON file inside a directory, named by a
+	// stable ID, for filesystem-native zettelkasten tools.
+	FormatDir Format = "dir"
+)
+
+const (
+	noteFilePrefix         = "note-"
+	conversationFilePrefix = "conversation-"
+	entryFileExt           = ".json"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ParseFormat validates a -kb-format value; empty means FormatFile.
+func ParseFormat(value string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(value))); format {
+	case "", FormatFile:
+		return FormatFile, nil
+	case FormatJSONL:
+		return FormatJSONL, nil
+	case FormatDir:
+		return FormatDir, nil
+	default:
+		return "", fmt.Errorf("unknown knowledge base format %q (want file, jsonl, or dir)", value)
+	}
+}
+
+// ID is a stable identifier derived from the note's paper, title, and creation time; it names the
+// note's file in FormatDir and identifies it for Delete. Two notes can share an ID, so the second
+// one's file gets a numbered suffix rather than replacing the first.
+func (n Note) ID() string {
+	sum := sha1.Sum([]byte(n.PaperID + "\x00" + n.Title + "\x00" + n.CreatedAt.UTC().Format(time.RFC3339Nano)))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// Delete removes the first stored note with the same ID and reports whether one was found. Only
+// note entries are considered; conversation snapshots are written back as they were. A missing
+// knowledge base or note is not an error.
+func (s Store) Delete(note Note) (bool, error) {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	entries, err := s.loadEntries()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	id := note.ID()
+	for i, raw := range entries {
+		entryType, err := detectEntryType(raw)
+		if err != nil {
+			return false, err
+		}
+		if entryType != entryTypeNote {
+			continue
+		}
+		var stored Note
+		if err := json.Unmarshal(raw, &stored); err != nil {
+			return false, err
+		}
+		if stored.ID() != id {
+			continue
+		}
+		entries = append(entries[:i], entries[i+1:]...)
+		if err := s.prepareWrite(); err != nil {
+			return false, err
+		}
+		if err := s.writeEntries(entries); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// entryFileName names the file holding raw in a FormatDir knowledge base.
+func entryFileName(raw json.RawMessage) (string, error) {
+	entryType, err := detectEntryType(raw)
+	if err != nil {
+		return "", err
+	}
+	if entryType == entryTypeConversation {
+		var snapshot ConversationSnapshot
+		if err := json.Unmarshal(raw, &snapshot); err != nil {
+			return "", err
+		}
+		return conversationFilePrefix + unsafeFileChars.ReplaceAllString(snapshot.PaperID, "_") + entryFileExt, nil
+	}
+	var note Note
+	if err := json.Unmarshal(raw, &note); err != nil {
+		return "", err
+	}
+	return noteFilePrefix + note.ID() + entryFileExt, nil
+}
+
+func isEntryFile(name string) bool {
+	return strings.HasSuffix(name, entryFileExt) &&
+		(strings.HasPrefix(name, noteFilePrefix) || strings.HasPrefix(name, conversationFilePrefix))
+}
+
+// loadDirEntries reads every entry file in dir, oldest first so notes keep the order they were
+// taken in.
+func loadDirEntries(dir string) ([]json.RawMessage, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	type timedEntry struct {
+		raw json.RawMessage
+		at  time.Time
+	}
+	var timed []timedEntry
+	for _, file := range files {
+		if file.IsDir() || !isEntryFile(file.Name()) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var stamp struct {
+			CreatedAt  time.Time `json:"createdAt"`
+			CapturedAt time.Time `json:"capturedAt"`
+		}
+		if err := json.Unmarshal(data, &stamp); err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name(), err)
+		}
+		at := stamp.CreatedAt
+		if at.IsZero() {
+			at = stamp.CapturedAt
+		}
+		timed = append(timed, timedEntry{raw: json.RawMessage(data), at: at})
+	}
+	sort.SliceStable(timed, func(i, j int) bool { return timed[i].at.Before(timed[j].at) })
+	entries := make([]json.RawMessage, 0, len(timed))
+	for _, entry := range timed {
+		entries = append(entries, entry.raw)
+	}
+	return entries, nil
+}
+
+// noteFileName adds -2, -3, … to a note's file name until taken no longer reports it in use, so
+// notes with colliding IDs each keep their own file.
+func noteFileName(name string, taken func(string) bool) string {
+	if !taken(name) {
+		return name
+	}
+	base := strings.TrimSuffix(name, entryFileExt)
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d%s", base, n, entryFileExt)
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+}
+
+// appendDirEntries writes a file for each new entry and leaves the rest of the directory alone. A
+// conversation snapshot replaces its paper's file; a note never replaces another note's file.
+func appendDirEntries(dir string, entries []json.RawMessage) error {
+	exists := func(name string) bool {
+		_, err := os.Lstat(filepath.Join(dir, name))
+		return err == nil
+	}
+	for _, raw := range entries {
+		name, err := entryFileName(raw)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(name, noteFilePrefix) {
+			name = noteFileName(name, exists)
+		}
+		data, err := json.MarshalIndent(raw, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(filepath.Join(dir, name), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDirEntries makes the directory hold exactly entries: it writes the files whose contents
+// changed and removes entry files that are no longer present, which is how Delete and UpdateNote
+// reach the directory.
+func writeDirEntries(dir string, entries []json.RawMessage) error {
+	keep := map[string]bool{}
+	for _, raw := range entries {
+		name, err := entryFileName(raw)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(name, noteFilePrefix) {
+			name = noteFileName(name, func(name string) bool { return keep[name] })
+		}
+		keep[name] = true
+		data, err := json.MarshalIndent(raw, "", "  ")
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, name)
+		if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data) {
+			continue
+		}
+		if err := writeFileAtomic(path, data); err != nil {
+			return err
+		}
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.IsDir() || !isEntryFile(file.Name()) || keep[file.Name()] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, file.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateDirPath accepts a missing path or an existing, writable directory.
+func validateDirPath(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("%w: %v", ErrInvalidKnowledgeBase, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%w: %s is not a directory; -kb-format dir needs a folder for note files", ErrInvalidKnowledgeBase, path)
+	}
+	probe, err := os.CreateTemp(path, ".paperscout-write-check-*")
+	if err != nil {
+		return fmt.Errorf("%w: %s is not writable: %v", ErrInvalidKnowledgeBase, path, err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
@@ -0,0 +1,65 @@
+package notes
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+)
+
+// ErrNoteNotFound reports that the note being changed is no longer in the knowledge base, usually
+// because it was edited or deleted elsewhere.
+var ErrNoteNotFound = errors.New("note not found in the knowledge base")
+
+// UpdateNote rewrites the stored note matching target's PaperID, Title, and Body with newBody and,
+// when newTitle is not blank, newTitle. Every other entry, including conversation snapshots, is
+// written back untouched. It returns ErrNoteNotFound when no stored note matches.
+func (s Store) UpdateNote(target Note, newBody, newTitle string) error {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	entries, err := s.loadEntries()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNoteNotFound
+		}
+		return err
+	}
+	for i, raw := range entries {
+		stored, ok, err := matchNoteEntry(raw, target)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		stored.Body = newBody
+		stored.Tags = ExtractTags(newBody)
+		if title := strings.TrimSpace(newTitle); title != "" {
+			stored.Title = title
+		}
+		updated, err := json.Marshal(stored)
+		if err != nil {
+			return err
+		}
+		entries[i] = updated
+		if err := s.prepareWrite(); err != nil {
+			return err
+		}
+		return s.writeEntries(entries)
+	}
+	return ErrNoteNotFound
+}
+
+// matchNoteEntry decodes raw when it is a note with target's PaperID, Title, and Body.
+func matchNoteEntry(raw json.RawMessage, target Note) (Note, bool, error) {
+	entryType, err := detectEntryType(raw)
+	if err != nil || entryType != entryTypeNote {
+		return Note{}, false, err
+	}
+	var stored Note
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		return Note{}, false, err
+	}
+	matches := stored.PaperID == target.PaperID && stored.Title == target.Title && stored.Body == target.Body
+	return stored, matches, nil
+}
@@ -0,0 +1,179 @@
+package notes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// jsonlFileExt selects FormatJSONL for a knowledge base file even under the default FormatFile.
+const jsonlFileExt = ".jsonl"
+
+// format is the layout used for the store: FormatDir or FormatJSONL when configured, FormatJSONL
+// when the path ends in .jsonl, and FormatFile otherwise.
+func (s Store) format() Format {
+	if (s.Format == "" || s.Format == FormatFile) && strings.EqualFold(filepath.Ext(s.Path), jsonlFileExt) {
+		return FormatJSONL
+	}
+	if s.Format == "" {
+		return FormatFile
+	}
+	return s.Format
+}
+
+// encodeJSONL renders entries one per line. Entries loaded from an indented JSON array carry line
+// breaks, so each one is compacted first.
+func encodeJSONL(entries []json.RawMessage) ([]byte, error) {
+	var b bytes.Buffer
+	for _, raw := range entries {
+		if err := json.Compact(&b, raw); err != nil {
+			return nil, err
+		}
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
+
+// decodeJSONL parses one entry per line, skipping blank lines. A crash in the middle of an append
+// can leave a partial last line without its newline; it is dropped so the entries before it still
+// load, while a malformed line anywhere else is an error.
+func decodeJSONL(data []byte) ([]json.RawMessage, error) {
+	lines := bytes.Split(data, []byte("\n"))
+	entries := make([]json.RawMessage, 0, len(lines))
+	for i, line := range lines {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			if i == len(lines)-1 {
+				break
+			}
+			return nil, fmt.Errorf("line %d: invalid JSON entry", i+1)
+		}
+		entries = append(entries, json.RawMessage(line))
+	}
+	return entries, nil
+}
+
+// collapseSnapshots keeps only the last line written for each paper's conversation snapshot, in the
+// place of its first line, since JSONL snapshot updates are appended rather than rewritten. Any
+// full rewrite, and MigrateToJSONL, therefore stores one line per paper again.
+func collapseSnapshots(entries []json.RawMessage) ([]json.RawMessage, error) {
+	first := map[string]int{}
+	kept := make([]json.RawMessage, 0, len(entries))
+	for _, raw := range entries {
+		var header struct {
+			EntryType string `json:"entryType"`
+			PaperID   string `json:"paperId"`
+		}
+		if err := json.Unmarshal(raw, &header); err != nil {
+			return nil, err
+		}
+		if header.EntryType != entryTypeConversation {
+			kept = append(kept, raw)
+			continue
+		}
+		if idx, ok := first[header.PaperID]; ok {
+			kept[idx] = raw
+			continue
+		}
+		first[header.PaperID] = len(kept)
+		kept = append(kept, raw)
+	}
+	return kept, nil
+}
+
+func writeJSONLEntries(path string, entries []json.RawMessage) error {
+	data, err := encodeJSONL(entries)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// appendJSONLEntries adds one line per entry without rewriting the file. A file that is still a
+// JSON array, or whose last line was cut short, is rewritten as JSONL once instead.
+func appendJSONLEntries(path string, entries []json.RawMessage) error {
+	appendable, err := jsonlAppendable(path)
+	if err != nil {
+		return err
+	}
+	if !appendable {
+		existing, err := loadFileEntries(path)
+		if err != nil {
+			return err
+		}
+		return writeJSONLEntries(path, append(existing, entries...))
+	}
+	data, err := encodeJSONL(entries)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// jsonlAppendable reports whether path is missing, empty, or a JSONL file ending in a newline, so
+// new lines can go straight on the end.
+func jsonlAppendable(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return true, nil
+		}
+		return false, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+	if info.Size() == 0 {
+		return true, nil
+	}
+	first := make([]byte, 1)
+	if _, err := io.ReadFull(file, first); err != nil {
+		return false, err
+	}
+	last := make([]byte, 1)
+	if _, err := file.ReadAt(last, info.Size()-1); err != nil {
+		return false, err
+	}
+	return first[0] == '{' && last[0] == '\n', nil
+}
+
+// MigrateToJSONL copies every note and conversation snapshot from the JSON knowledge base at src
+// into a new newline-delimited file at dst and returns how many entries it wrote. src is left
+// untouched, and an existing dst is never overwritten. A JSONL src is compacted on the way, keeping
+// only the latest line for each snapshot.
+func MigrateToJSONL(src, dst string) (int, error) {
+	entries, err := loadFileEntries(src)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := os.Stat(dst); err == nil {
+		return 0, fmt.Errorf("%s already exists", dst)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return 0, err
+	}
+	if err := writeJSONLEntries(dst, entries); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
@@ -0,0 +1,119 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const maxSlugRunes = 80
+
+// ExportMarkdown writes every note in the knowledge base to its own Markdown file in
+// outDir, for Obsidian-style vaults. Each file is named by a slug of the note title (with -2, -3, …
+// appended when titles collide), starts with YAML frontmatter, and links the other notes from the
+// same paper as [[wikilinks]]. A missing or empty knowledge base exports nothing.
+func (s Store) ExportMarkdown(outDir string) error {
+	entries, err := s.Load()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+	names := markdownNames(entries)
+	byPaper := map[string][]int{}
+	for i, note := range entries {
+		byPaper[note.PaperID] = append(byPaper[note.PaperID], i)
+	}
+	for i, note := range entries {
+		var related []string
+		for _, j := range byPaper[note.PaperID] {
+			if j != i {
+				related = append(related, names[j])
+			}
+		}
+		data := noteMarkdown(note, related)
+		if err := os.WriteFile(filepath.Join(outDir, names[i]+".md"), []byte(data), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markdownNames assigns each note a unique file name stem, numbering repeated slugs in load order.
+func markdownNames(entries []Note) []string {
+	names := make([]string, len(entries))
+	used := map[string]bool{}
+	for i, note := range entries {
+		base := slugify(note.Title)
+		name := base
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+func noteMarkdown(note Note, related []string) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "paperId: %s\n", strconv.Quote(note.PaperID))
+	fmt.Fprintf(&b, "paperTitle: %s\n", strconv.Quote(note.PaperTitle))
+	fmt.Fprintf(&b, "kind: %s\n", strconv.Quote(note.Kind))
+	fmt.Fprintf(&b, "createdAt: %s\n", note.CreatedAt.UTC().Format(time.RFC3339))
+	if tags := NoteTags(note); len(tags) > 0 {
+		quoted := make([]string, len(tags))
+		for i, tag := range tags {
+			quoted[i] = strconv.Quote(tag)
+		}
+		fmt.Fprintf(&b, "tags: [%s]\n", strings.Join(quoted, ", "))
+	}
+	b.WriteString("---\n\n")
+	if title := strings.TrimSpace(note.Title); title != "" {
+		fmt.Fprintf(&b, "# %s\n\n", title)
+	}
+	if body := strings.TrimSpace(note.Body); body != "" {
+		b.WriteString(body)
+		b.WriteString("\n")
+	}
+	if len(related) > 0 {
+		b.WriteString("\n## Related\n\n")
+		for _, name := range related {
+			fmt.Fprintf(&b, "- [[%s]]\n", name)
+		}
+	}
+	return b.String()
+}
+
+// slugify lowercases title and joins its letters and digits with single hyphens.
+func slugify(title string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	runes := 0
+	for _, r := range strings.ToLower(title) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			pendingHyphen = b.Len() > 0
+			continue
+		}
+		if runes >= maxSlugRunes {
+			break
+		}
+		if pendingHyphen {
+			b.WriteByte('-')
+			pendingHyphen = false
+		}
+		b.WriteRune(r)
+		runes++
+	}
+	if b.Len() == 0 {
+		return "note"
+	}
+	return b.String()
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/csheth/browse/internal/textsim"
 )
 
 // Note represents a stored knowledge entry in the lightweight zettelkasten.
@@ -14,6 +16,9 @@ type Note struct {
 	Body       string    `json:"body"`
 	Kind       string    `json:"kind"`
 	CreatedAt  time.Time `json:"createdAt"`
+	// Tags are the #word tags found in Body when the note was written; older notes omit them (see
+	// NoteTags).
+	Tags []string `json:"tags,omitempty"`
 }
 
 // Candidate is a suggested note derived automatically from a paper.
@@ -96,7 +101,26 @@ func SuggestCandidates(title, abstract string, contributions []string) []Candida
 			Reason: "Fallback overview when heuristics fail.",
 		})
 	}
-	return suggestions
+	return dedupeCandidates(suggestions)
+}
+
+// dedupeCandidates keeps the first of any candidates whose titles or bodies are near duplicates,
+// such as an opening sentence that is also the first listed contribution.
+func dedupeCandidates(candidates []Candidate) []Candidate {
+	kept := candidates[:0]
+	for _, candidate := range candidates {
+		duplicate := false
+		for _, existing := range kept {
+			if textsim.NearDuplicate(existing.Title, candidate.Title) || textsim.NearDuplicate(existing.Body, candidate.Body) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			kept = append(kept, candidate)
+		}
+	}
+	return kept
 }
 
 func firstSentence(text string) string {
@@ -1,16 +1,24 @@
 package notes
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/csheth/browse/internal/arxiv"
 )
 
 func TestSuggestCandidatesUsesContributionsAndHeuristics(t *testing.T) {
 	t.Parallel()
 
-	abstract := "We introduce a framework. The approach improves performance. Experiments achieve strong results."
+	abstract := "We study long-context reasoning. The approach uses sparse routing. Experiments achieve strong results."
 	contribs := []string{
 		"Contribution A",
 		"Contribution B",
@@ -43,6 +51,20 @@ func TestSuggestCandidatesUsesContributionsAndHeuristics(t *testing.T) {
 	}
 }
 
+func TestSuggestCandidatesDropsNearDuplicates(t *testing.T) {
+	t.Parallel()
+
+	abstract := "We propose sparse routing for long documents. It achieves strong results."
+	got := SuggestCandidates("Routing", abstract, []string{"We propose sparse routing for long documents", "A new benchmark"})
+	var bodies []string
+	for _, candidate := range got {
+		bodies = append(bodies, candidate.Body)
+	}
+	if len(got) != 3 || got[0].Title != "Contribution #1" || got[1].Title != "Contribution #2" || got[2].Title != "Result Highlight" {
+		t.Fatalf("expected the restated opening sentence to be dropped, got %q", bodies)
+	}
+}
+
 func TestSuggestCandidatesFallsBackToOverview(t *testing.T) {
 	t.Parallel()
 
@@ -206,6 +228,44 @@ func TestAppendConversationSnapshotAppendsUpdates(t *testing.T) {
 	}
 }
 
+func TestConcurrentSavesAndSnapshotUpdatesKeepEveryWrite(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "zettel.json")
+	const writers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			errs <- Save(path, []Note{{PaperID: "paper-1", Title: fmt.Sprintf("Note %d", i), Body: "Body", Kind: "manual"}})
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			errs <- AppendConversationSnapshot(path, "paper-1", "Title", SnapshotUpdate{
+				Messages: []ConversationMessage{{Kind: "question", Content: fmt.Sprintf("Question %d", i)}},
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent write error = %v", err)
+		}
+	}
+
+	saved, err := Load(path)
+	if err != nil || len(saved) != writers {
+		t.Fatalf("expected all %d notes, got %d (%v)", writers, len(saved), err)
+	}
+	snapshots, err := LoadConversationSnapshots(path)
+	if err != nil || len(snapshots) != 1 || len(snapshots[0].Messages) != writers {
+		t.Fatalf("expected one snapshot with all %d messages, got %+v (%v)", writers, snapshots, err)
+	}
+}
+
 func TestAppendConversationSnapshotRejectsInvalidJSON(t *testing.T) {
 	t.Parallel()
 
@@ -224,3 +284,731 @@ func TestAppendConversationSnapshotRejectsInvalidJSON(t *testing.T) {
 		t.Fatal("expected error for invalid JSON")
 	}
 }
+
+func TestSaveWritesAtomically(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "zettel.json")
+	if err := Save(path, []Note{{PaperID: "p1", Title: "Kept", Body: "Written before the crash", Kind: "manual"}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The process dies after the temp file is written but before it replaces the knowledge base.
+	crash := errors.New("killed before rename")
+	renameFile = func(oldpath, newpath string) error { return crash }
+	t.Cleanup(func() { renameFile = os.Rename })
+	if err := Save(path, []Note{{PaperID: "p1", Title: "Lost", Body: "Never landed", Kind: "manual"}}); !errors.Is(err, crash) {
+		t.Fatalf("expected the rename failure, got %v", err)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Fatalf("expected the knowledge base untouched, got:\n%s", after)
+	}
+	assertOnlyFile := func() {
+		t.Helper()
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(files) != 1 || files[0].Name() != "zettel.json" {
+			var names []string
+			for _, file := range files {
+				names = append(names, file.Name())
+			}
+			t.Fatalf("expected no temp files left behind, got %v", names)
+		}
+	}
+	assertOnlyFile()
+
+	renameFile = os.Rename
+	if err := Save(path, []Note{{PaperID: "p1", Title: "Landed", Body: "After restart", Kind: "manual"}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	assertOnlyFile()
+	loaded, err := Load(path)
+	if err != nil || len(loaded) != 2 || loaded[0].Title != "Kept" || loaded[1].Title != "Landed" {
+		t.Fatalf("expected a consistent knowledge base after the rename, got %+v, %v", loaded, err)
+	}
+}
+
+func TestAtomicSaveKeepsPermissionsAndSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	real := filepath.Join(dir, "real.json")
+	if err := Save(real, []Note{{PaperID: "p1", Title: "First", Body: "One", Kind: "manual"}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if info, err := os.Stat(real); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected a new knowledge base readable only by its owner, got %v, %v", info, err)
+	}
+	if err := os.Chmod(real, 0o640); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "zettel.json")
+	if err := os.Symlink(real, link); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+
+	if err := Save(link, []Note{{PaperID: "p1", Title: "Second", Body: "Two", Kind: "manual"}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("expected the knowledge base link kept, got %v, %v", info, err)
+	}
+	if info, err := os.Stat(real); err != nil || info.Mode().Perm() != 0o640 {
+		t.Fatalf("expected the file's own permissions kept, got %v, %v", info, err)
+	}
+	if saved, err := Load(real); err != nil || len(saved) != 2 {
+		t.Fatalf("expected both notes in the linked file, got %+v, %v", saved, err)
+	}
+}
+
+func TestValidatePathRejectsDirectory(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	err := ValidatePath(dir)
+	if err == nil {
+		t.Fatal("expected error for directory path")
+	}
+	if !errors.Is(err, ErrInvalidKnowledgeBase) {
+		t.Fatalf("expected ErrInvalidKnowledgeBase, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "is a directory") {
+		t.Fatalf("expected descriptive error, got %q", err.Error())
+	}
+}
+
+func TestValidatePathAcceptsMissingAndRegularFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "zettel.json")
+	if err := ValidatePath(path); err != nil {
+		t.Fatalf("ValidatePath() on missing file error = %v", err)
+	}
+	if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := ValidatePath(path); err != nil {
+		t.Fatalf("ValidatePath() on regular file error = %v", err)
+	}
+}
+
+func TestSaveRejectsDirectoryPath(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	err := Save(dir, []Note{{PaperID: "1", Title: "Note", Body: "Body"}})
+	if !errors.Is(err, ErrInvalidKnowledgeBase) {
+		t.Fatalf("expected ErrInvalidKnowledgeBase, got %v", err)
+	}
+}
+
+func TestAppendConversationSnapshotReplacesScratchpad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "zettel.json")
+	for _, value := range []string{"first draft", "second draft"} {
+		scratchpad := value
+		if err := AppendConversationSnapshot(path, "paper-1", "Title", SnapshotUpdate{Scratchpad: &scratchpad}); err != nil {
+			t.Fatalf("AppendConversationSnapshot() error = %v", err)
+		}
+	}
+	snapshots, err := LoadConversationSnapshots(path)
+	if err != nil {
+		t.Fatalf("LoadConversationSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Scratchpad != "second draft" {
+		t.Fatalf("expected scratchpad to be replaced, got %#v", snapshots)
+	}
+
+	cleared := ""
+	if err := AppendConversationSnapshot(path, "paper-1", "Title", SnapshotUpdate{Scratchpad: &cleared}); err != nil {
+		t.Fatalf("AppendConversationSnapshot() error = %v", err)
+	}
+	snapshots, err = LoadConversationSnapshots(path)
+	if err != nil {
+		t.Fatalf("LoadConversationSnapshots() error = %v", err)
+	}
+	if snapshots[0].Scratchpad != "" {
+		t.Fatalf("expected scratchpad to be cleared, got %q", snapshots[0].Scratchpad)
+	}
+}
+
+func TestDirFormatRoundTripsOneFilePerNote(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "kb")
+	store := Store{Path: dir, Format: FormatDir}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	first := Note{PaperID: "2101.00001", Title: "First", Body: "one", CreatedAt: created}
+	second := Note{PaperID: "2101.00001", Title: "Second", Body: "two", CreatedAt: created.Add(time.Minute)}
+	if err := store.Save([]Note{second, first}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := store.AppendConversationSnapshot("2101.00001", "Dir Paper", SnapshotUpdate{Messages: []ConversationMessage{{Kind: "question", Content: "hi"}}}); err != nil {
+		t.Fatalf("AppendConversationSnapshot() error = %v", err)
+	}
+
+	for _, name := range []string{"note-" + first.ID() + ".json", "note-" + second.ID() + ".json", "conversation-2101.00001.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("expected %s in knowledge base directory: %v", name, err)
+		}
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].Title != "First" || loaded[1].Title != "Second" {
+		t.Fatalf("expected notes in creation order, got %+v", loaded)
+	}
+	snapshots, err := store.LoadConversationSnapshots()
+	if err != nil || len(snapshots) != 1 {
+		t.Fatalf("LoadConversationSnapshots() = %+v, %v", snapshots, err)
+	}
+
+	if removed, err := store.Delete(first); err != nil || !removed {
+		t.Fatalf("Delete() = %v, %v; want true, nil", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "note-"+first.ID()+".json")); !os.IsNotExist(err) {
+		t.Fatalf("expected deleted note file removed, stat err = %v", err)
+	}
+	loaded, err = store.Load()
+	if err != nil {
+		t.Fatalf("Load() after Delete error = %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Title != "Second" {
+		t.Fatalf("expected only the second note to remain, got %+v", loaded)
+	}
+}
+
+func TestDirFormatKeepsNotesWithCollidingIDs(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "kb")
+	store := Store{Path: dir, Format: FormatDir}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	first := Note{PaperID: "2101.00001", Title: "Same", Body: "one", CreatedAt: created}
+	second := Note{PaperID: "2101.00001", Title: "Same", Body: "two", CreatedAt: created}
+	if first.ID() != second.ID() {
+		t.Fatal("expected the two notes to share an ID")
+	}
+	if err := store.Save([]Note{first}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	firstPath := filepath.Join(dir, "note-"+first.ID()+".json")
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(firstPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save([]Note{second}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if info, err := os.Stat(firstPath); err != nil || !info.ModTime().Equal(old) {
+		t.Fatalf("expected the first note's file left alone by the append, got %v (%v)", info, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "note-"+second.ID()+"-2.json")); err != nil {
+		t.Fatalf("expected the colliding note under a suffixed name: %v", err)
+	}
+	loaded, err := store.Load()
+	if err != nil || len(loaded) != 2 {
+		t.Fatalf("expected both notes kept, got %+v (%v)", loaded, err)
+	}
+}
+
+func TestDirFormatRejectsRegularFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "kb.json")
+	if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := (Store{Path: path, Format: FormatDir}).Validate(); !errors.Is(err, ErrInvalidKnowledgeBase) {
+		t.Fatalf("expected ErrInvalidKnowledgeBase for a file under -kb-format dir, got %v", err)
+	}
+}
+
+func TestJSONLExtensionAppendsOneLinePerNote(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kb.jsonl")
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	first := Note{PaperID: "2101.00001", PaperTitle: "Sparse Routing", Title: "Router", Body: "Top-2\nrouting", Kind: "manual", CreatedAt: created}
+	if err := Save(path, []Note{first}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := Note{PaperID: "2101.00001", PaperTitle: "Sparse Routing", Title: "Capacity", Body: "Factor 1.25", Kind: "manual", CreatedAt: created.Add(time.Minute)}
+	if err := Save(path, []Note{second}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := AppendConversationSnapshot(path, "2101.00001", "Sparse Routing", SnapshotUpdate{
+		Messages: []ConversationMessage{{Kind: "question", Content: "Why top-2?", Timestamp: created}},
+	}); err != nil {
+		t.Fatalf("AppendConversationSnapshot() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected one line per entry, got %d:\n%s", len(lines), data)
+	}
+	if !strings.HasPrefix(string(data), string(before)) {
+		t.Fatalf("expected the second save to append after the first line, got:\n%s", data)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded) != 2 || loaded[0].Body != first.Body || loaded[1].Title != second.Title {
+		t.Fatalf("unexpected notes after round trip: %+v", loaded)
+	}
+	snapshots, err := LoadConversationSnapshots(path)
+	if err != nil {
+		t.Fatalf("LoadConversationSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 1 || len(snapshots[0].Messages) != 1 {
+		t.Fatalf("unexpected snapshots after round trip: %+v", snapshots)
+	}
+}
+
+func TestJSONLSnapshotUpdatesAppendOneLineEach(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kb.jsonl")
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := Save(path, []Note{{PaperID: "p1", Title: "Router", Body: "Top-2", Kind: "manual", CreatedAt: now}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	for i, question := range []string{"Why top-2?", "What capacity?", "Which baseline?"} {
+		before, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := AppendConversationSnapshot(path, "p1", "Sparse Routing", SnapshotUpdate{
+			Messages: []ConversationMessage{{Kind: "question", Content: question, Timestamp: now}},
+		}); err != nil {
+			t.Fatalf("AppendConversationSnapshot() error = %v", err)
+		}
+		after, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(after, before) || bytes.Count(after, []byte("\n")) != i+2 {
+			t.Fatalf("update %d: expected the file to grow by exactly one line, got:\n%s", i+1, after)
+		}
+	}
+
+	snapshots, err := LoadConversationSnapshots(path)
+	if err != nil || len(snapshots) != 1 || len(snapshots[0].Messages) != 3 {
+		t.Fatalf("expected the latest snapshot line to win, got %+v, %v", snapshots, err)
+	}
+	if saved, err := Load(path); err != nil || len(saved) != 1 {
+		t.Fatalf("expected the note alongside the snapshot lines, got %+v, %v", saved, err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "compact.jsonl")
+	if count, err := MigrateToJSONL(path, dst); err != nil || count != 2 {
+		t.Fatalf("expected migration to compact to one line per entry, got %d, %v", count, err)
+	}
+	compacted, err := LoadConversationSnapshots(dst)
+	if err != nil || len(compacted) != 1 || len(compacted[0].Messages) != 3 {
+		t.Fatalf("expected the compacted snapshot to keep every message, got %+v, %v", compacted, err)
+	}
+}
+
+func TestJSONLLoadDropsTornLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kb.jsonl")
+	if err := Save(path, []Note{{PaperID: "p1", Title: "Kept", Body: "Intact", Kind: "manual"}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteString(`{"paperId":"p1","title":"Cut`); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	loaded, err := Load(path)
+	if err != nil || len(loaded) != 1 || loaded[0].Title != "Kept" {
+		t.Fatalf("expected the intact note only, got %+v, %v", loaded, err)
+	}
+	if err := Save(path, []Note{{PaperID: "p1", Title: "Next", Body: "After the crash", Kind: "manual"}}); err != nil {
+		t.Fatalf("Save() after a torn line error = %v", err)
+	}
+	loaded, err = Load(path)
+	if err != nil || len(loaded) != 2 || loaded[1].Title != "Next" {
+		t.Fatalf("expected the torn line replaced by the new note, got %+v, %v", loaded, err)
+	}
+}
+
+func TestMigrateToJSONLCopiesEveryEntry(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "kb.json")
+	if err := Save(src, []Note{
+		{PaperID: "p1", Title: "One", Body: "First", Kind: "manual"},
+		{PaperID: "p2", Title: "Two", Body: "Second", Kind: "manual"},
+	}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := AppendConversationSnapshot(src, "p1", "Paper One", SnapshotUpdate{
+		Messages: []ConversationMessage{{Kind: "answer", Content: "Because."}},
+	}); err != nil {
+		t.Fatalf("AppendConversationSnapshot() error = %v", err)
+	}
+
+	dst := filepath.Join(dir, "kb.jsonl")
+	count, err := MigrateToJSONL(src, dst)
+	if err != nil || count != 3 {
+		t.Fatalf("MigrateToJSONL() = %d, %v; want 3 entries", count, err)
+	}
+	loaded, err := Load(dst)
+	if err != nil || len(loaded) != 2 || loaded[1].Title != "Two" {
+		t.Fatalf("expected both notes in the migrated file, got %+v, %v", loaded, err)
+	}
+	if snapshots, err := LoadConversationSnapshots(dst); err != nil || len(snapshots) != 1 {
+		t.Fatalf("expected the snapshot in the migrated file, got %+v, %v", snapshots, err)
+	}
+	if _, err := MigrateToJSONL(src, dst); err == nil {
+		t.Fatal("expected migrating onto an existing file to fail")
+	}
+}
+
+func TestExportMarkdownWritesFrontmatterAndWikilinks(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "zettel.json")
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	entries := []Note{
+		{PaperID: "2101.00001", PaperTitle: "Great Paper", Title: "Key Idea", Body: "First body", Kind: "manual", CreatedAt: created},
+		{PaperID: "2101.00001", PaperTitle: "Great Paper", Title: "Key idea!", Body: "Second body", Kind: "llm", CreatedAt: created},
+		{PaperID: "2202.00002", PaperTitle: "Other Paper", Title: "Unrelated", Body: "Third body", Kind: "manual", CreatedAt: created},
+	}
+	if err := Save(path, entries); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	outDir := filepath.Join(dir, "vault")
+	if err := ExportMarkdown(path, outDir); err != nil {
+		t.Fatalf("ExportMarkdown() error = %v", err)
+	}
+
+	first, err := os.ReadFile(filepath.Join(outDir, "key-idea.md"))
+	if err != nil {
+		t.Fatalf("expected key-idea.md: %v", err)
+	}
+	for _, want := range []string{
+		"---\npaperId: \"2101.00001\"\npaperTitle: \"Great Paper\"\nkind: \"manual\"\ncreatedAt: 2024-01-02T03:04:05Z\n---\n",
+		"First body",
+		"[[key-idea-2]]",
+	} {
+		if !strings.Contains(string(first), want) {
+			t.Fatalf("expected %q in export, got:\n%s", want, first)
+		}
+	}
+	second, err := os.ReadFile(filepath.Join(outDir, "key-idea-2.md"))
+	if err != nil {
+		t.Fatalf("expected colliding slug to get an index: %v", err)
+	}
+	if !strings.Contains(string(second), "[[key-idea]]") {
+		t.Fatalf("expected link back to the first note, got:\n%s", second)
+	}
+	third, err := os.ReadFile(filepath.Join(outDir, "unrelated.md"))
+	if err != nil {
+		t.Fatalf("expected unrelated.md: %v", err)
+	}
+	if strings.Contains(string(third), "[[") {
+		t.Fatalf("expected no links for a paper with one note, got:\n%s", third)
+	}
+}
+
+func TestExportMarkdownEmptyKnowledgeBase(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	outDir := filepath.Join(dir, "vault")
+	if err := ExportMarkdown(filepath.Join(dir, "missing.json"), outDir); err != nil {
+		t.Fatalf("ExportMarkdown() error = %v", err)
+	}
+	files, err := os.ReadDir(outDir)
+	if err != nil {
+		t.Fatalf("expected output directory created: %v", err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("expected no files for an empty knowledge base, got %d", len(files))
+	}
+}
+
+func TestSearchRanksByMatchCountIgnoringCase(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "zettel.json")
+	entries := []Note{
+		{PaperID: "1", Title: "Optimizers", Body: "Adam is a common choice."},
+		{PaperID: "2", Title: "ADAM variants", Body: "AdamW decouples weight decay from adam."},
+		{PaperID: "3", Title: "Datasets", Body: "ImageNet and CIFAR."},
+	}
+	if err := Save(path, entries); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	results, err := Search(path, "adam")
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected two matches, got %+v", results)
+	}
+	if results[0].Title != "ADAM variants" || results[1].Title != "Optimizers" {
+		t.Fatalf("expected notes ranked by match count, got %q then %q", results[0].Title, results[1].Title)
+	}
+}
+
+func TestSearchBlankQueryAndMissingKnowledgeBase(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	results, err := Search(filepath.Join(dir, "missing.json"), "adam")
+	if err != nil || len(results) != 0 {
+		t.Fatalf("expected no results and no error for a missing knowledge base, got %+v, %v", results, err)
+	}
+
+	path := filepath.Join(dir, "zettel.json")
+	if err := Save(path, []Note{{PaperID: "1", Title: "Adam", Body: "Adam"}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	results, err = Search(path, "   ")
+	if err != nil || len(results) != 0 {
+		t.Fatalf("expected no results for a blank query, got %+v, %v", results, err)
+	}
+}
+
+func TestFindRelatedMatchesSharedSubjectKeywords(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	paper := &arxiv.Paper{ID: "2402.00002", Title: "Sparse Routing for Mixture of Experts", Subjects: []string{"cs.LG"}}
+	results, err := FindRelated(filepath.Join(dir, "missing.json"), paper, 3)
+	if err != nil || len(results) != 0 {
+		t.Fatalf("expected no results and no error for a missing knowledge base, got %+v, %v", results, err)
+	}
+
+	path := filepath.Join(dir, "zettel.json")
+	entries := []Note{
+		{PaperID: "2402.00002", PaperTitle: paper.Title, Title: "Own note", Body: "Routing collapses in cs.LG experiments."},
+		{PaperID: "2301.00001", PaperTitle: "Load Balancing Experts", Title: "Balancing loss", Body: "An auxiliary loss keeps every expert busy (cs.LG)."},
+		{PaperID: "2201.00003", PaperTitle: "Protein Folding", Title: "Contact maps", Body: "Predicted contacts constrain the fold."},
+	}
+	if err := Save(path, entries); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	results, err = FindRelated(path, paper, 3)
+	if err != nil {
+		t.Fatalf("FindRelated() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Title != "Balancing loss" {
+		t.Fatalf("expected only the note from the other cs.LG paper, got %+v", results)
+	}
+}
+
+func TestUpdateNoteRewritesMatchingNoteInPlace(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "zettel.json")
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	first := Note{PaperID: "1234", PaperTitle: "Paper", Title: "Typo", Body: "Teh body.", Kind: "manual", CreatedAt: created}
+	second := Note{PaperID: "1234", PaperTitle: "Paper", Title: "Other", Body: "Untouched."}
+	if err := Save(path, []Note{first, second}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := SaveConversationSnapshots(path, []ConversationSnapshot{{PaperID: "1234", PaperTitle: "Paper"}}); err != nil {
+		t.Fatalf("SaveConversationSnapshots() error = %v", err)
+	}
+
+	if err := UpdateNote(path, first, "The body.", ""); err != nil {
+		t.Fatalf("UpdateNote() error = %v", err)
+	}
+	stored, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(stored) != 2 || stored[0].Body != "The body." || stored[0].Title != "Typo" || !stored[0].CreatedAt.Equal(created) || stored[0].Kind != "manual" {
+		t.Fatalf("expected the first note edited in place, got %+v", stored)
+	}
+	if !reflect.DeepEqual(stored[1], second) {
+		t.Fatalf("expected the other note untouched, got %+v", stored[1])
+	}
+	if conversations, err := LoadConversationSnapshots(path); err != nil || len(conversations) != 1 {
+		t.Fatalf("expected the conversation snapshot preserved, got %+v (%v)", conversations, err)
+	}
+
+	edited := stored[0]
+	if err := UpdateNote(path, edited, "Retitled body.", "  Fixed  "); err != nil {
+		t.Fatalf("UpdateNote() retitle error = %v", err)
+	}
+	if stored, _ := Load(path); stored[0].Title != "Fixed" || stored[0].Body != "Retitled body." {
+		t.Fatalf("expected the title replaced, got %+v", stored[0])
+	}
+}
+
+func TestUpdateNoteReportsMissingNote(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "zettel.json")
+	note := Note{PaperID: "1234", Title: "Gone", Body: "Deleted elsewhere."}
+	if err := UpdateNote(path, note, "new", ""); !errors.Is(err, ErrNoteNotFound) {
+		t.Fatalf("expected ErrNoteNotFound for a missing file, got %v", err)
+	}
+	if err := Save(path, []Note{{PaperID: "1234", Title: "Gone", Body: "Edited elsewhere."}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := UpdateNote(path, note, "new", ""); !errors.Is(err, ErrNoteNotFound) {
+		t.Fatalf("expected ErrNoteNotFound when the body no longer matches, got %v", err)
+	}
+}
+
+func TestDeleteRemovesOnlyMatchingNote(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "zettel.json")
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	doomed := Note{PaperID: "1234", PaperTitle: "Paper", Title: "Obsolete", Body: "Remove me.", CreatedAt: created}
+	kept := Note{PaperID: "1234", PaperTitle: "Paper", Title: "Obsolete", Body: "Same title, taken later.", CreatedAt: created.Add(time.Minute)}
+	if err := Save(path, []Note{doomed}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	// The snapshot's own copy of the note must survive; only note entries are candidates.
+	snapshot := ConversationSnapshot{PaperID: "1234", PaperTitle: "Paper", Notes: []SnapshotNote{{Title: doomed.Title, Body: doomed.Body}}}
+	if err := SaveConversationSnapshots(path, []ConversationSnapshot{snapshot}); err != nil {
+		t.Fatalf("SaveConversationSnapshots() error = %v", err)
+	}
+	if err := Save(path, []Note{kept}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	removed, err := Delete(path, doomed)
+	if err != nil || !removed {
+		t.Fatalf("Delete() = %v, %v; want true, nil", removed, err)
+	}
+	stored, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(stored) != 1 || stored[0].Body != kept.Body {
+		t.Fatalf("expected only the matching note removed, got %+v", stored)
+	}
+	conversations, err := LoadConversationSnapshots(path)
+	if err != nil || len(conversations) != 1 || len(conversations[0].Notes) != 1 {
+		t.Fatalf("expected the conversation snapshot untouched, got %+v (%v)", conversations, err)
+	}
+}
+
+func TestDeleteMissingNoteReturnsFalse(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "zettel.json")
+	note := Note{PaperID: "1234", Title: "Never saved", Body: "Nothing here."}
+	if removed, err := Delete(path, note); removed || err != nil {
+		t.Fatalf("Delete() on a missing file = %v, %v; want false, nil", removed, err)
+	}
+	if err := Save(path, []Note{{PaperID: "1234", Title: "Other", Body: "Kept."}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if removed, err := Delete(path, note); removed || err != nil {
+		t.Fatalf("Delete() on an unknown note = %v, %v; want false, nil", removed, err)
+	}
+	if stored, _ := Load(path); len(stored) != 1 {
+		t.Fatalf("expected the knowledge base unchanged, got %+v", stored)
+	}
+}
+
+func TestLatestConversationSnapshotUsesLastActivity(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zettel.json")
+	if _, ok, err := LatestConversationSnapshot(path); err != nil || ok {
+		t.Fatalf("expected no snapshot for a missing knowledge base, got ok=%v err=%v", ok, err)
+	}
+
+	base := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	snapshots := []ConversationSnapshot{
+		{PaperID: "2101.00001", PaperTitle: "Captured later", CapturedAt: base.Add(time.Hour)},
+		{
+			PaperID:    "2101.00002",
+			PaperTitle: "Reopened recently",
+			CapturedAt: base,
+			Messages:   []ConversationMessage{{Kind: "question", Content: "Back again?", Timestamp: base.Add(72 * time.Hour)}},
+		},
+	}
+	if err := SaveConversationSnapshots(path, snapshots); err != nil {
+		t.Fatalf("SaveConversationSnapshots() error = %v", err)
+	}
+	latest, ok, err := LatestConversationSnapshot(path)
+	if err != nil || !ok {
+		t.Fatalf("LatestConversationSnapshot() = %v, %v", ok, err)
+	}
+	if latest.PaperID != "2101.00002" {
+		t.Fatalf("expected the most recently active snapshot, got %q", latest.PaperID)
+	}
+}
+
+func TestExtractTagsFindsHashWords(t *testing.T) {
+	t.Parallel()
+
+	body := "Revisit the ablation #TODO, see (#important) and #todo again.\nNot C# or https://x.org/#anchor or issue #3. #follow-up_2!"
+	want := []string{"todo", "important", "follow-up_2"}
+	if got := ExtractTags(body); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ExtractTags() = %q, want %q", got, want)
+	}
+	if got := ExtractTags("No tags here."); got != nil {
+		t.Fatalf("expected no tags, got %q", got)
+	}
+}
+
+func TestLoadByTagMatchesStoredAndLegacyNotes(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "zettel.json")
+	tagged := Note{PaperID: "1", Title: "Tagged", Body: "Check the proof #todo", Tags: []string{"todo"}}
+	other := Note{PaperID: "1", Title: "Other", Body: "Key result #important", Tags: []string{"important"}}
+	if err := Save(path, []Note{tagged, other}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	// A note written before tags were recorded has no tags field at all.
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	legacy := `{"paperId":"2","paperTitle":"Old","title":"Legacy","body":"Old note #TODO","kind":"manual","createdAt":"2024-01-01T00:00:00Z"}`
+	updated := strings.Replace(string(raw), "[", "["+legacy+",", 1)
+	if err := os.WriteFile(path, []byte(updated), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	got, err := LoadByTag(path, "#Todo")
+	if err != nil {
+		t.Fatalf("LoadByTag() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Title != "Legacy" || got[1].Title != "Tagged" {
+		t.Fatalf("expected the legacy and tagged notes, got %+v", got)
+	}
+	if got, err := LoadByTag(path, "missing"); err != nil || len(got) != 0 {
+		t.Fatalf("expected no notes for an unused tag, got %+v (%v)", got, err)
+	}
+	if got, err := LoadByTag(filepath.Join(t.TempDir(), "absent.json"), "todo"); err != nil || got != nil {
+		t.Fatalf("expected nothing from a missing knowledge base, got %+v (%v)", got, err)
+	}
+}
@@ -0,0 +1,24 @@
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DefaultFileName is the knowledge base file used when no path is configured.
+	DefaultFileName = "zettelkasten.json"
+	// DefaultPathEnvVar points the default knowledge base somewhere stable, such as a notes folder,
+	// so launching from different directories does not scatter knowledge base files.
+	DefaultPathEnvVar = "PAPERSCOUT_ZETTEL"
+)
+
+// DefaultPath returns the knowledge base used when none is given: PAPERSCOUT_ZETTEL when set,
+// otherwise DefaultFileName in the working directory.
+func DefaultPath() string {
+	if path := strings.TrimSpace(os.Getenv(DefaultPathEnvVar)); path != "" {
+		return path
+	}
+	return filepath.Join(".", DefaultFileName)
+}
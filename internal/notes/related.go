@@ -0,0 +1,116 @@
+package notes
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/csheth/browse/internal/arxiv"
+)
+
+// relatedStopWords are title words too common in paper titles to link two papers.
+var relatedStopWords = map[string]bool{
+	"about": true, "across": true, "after": true, "against": true, "among": true, "approach": true,
+	"based": true, "beyond": true, "between": true, "from": true, "into": true, "more": true,
+	"over": true, "paper": true, "study": true, "than": true, "that": true, "their": true,
+	"them": true, "these": true, "this": true, "through": true, "toward": true, "towards": true,
+	"under": true, "using": true, "very": true, "when": true, "where": true, "which": true,
+	"while": true, "with": true, "without": true,
+}
+
+// FindRelated returns up to limit notes written on other papers that share keywords with paper:
+// its subjects (whole, such as "cs.LG", or word by word, such as "Neuroscience") and the
+// distinctive words of its title. Notes matching more keywords come first; ties keep knowledge
+// base order. A limit of zero or less returns every match, and a knowledge base that does not
+// exist yet yields none.
+func (s Store) FindRelated(paper *arxiv.Paper, limit int) ([]Note, error) {
+	if paper == nil {
+		return nil, nil
+	}
+	keywords := relatedKeywords(paper)
+	if len(keywords) == 0 {
+		return nil, nil
+	}
+	entries, err := s.Load()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	type match struct {
+		note  Note
+		score int
+	}
+	var matches []match
+	for _, note := range entries {
+		if note.PaperID == paper.ID {
+			continue
+		}
+		text := strings.ToLower(strings.Join([]string{note.PaperTitle, note.Title, note.Body}, "\n"))
+		words := keywordSet(text)
+		score := 0
+		for _, keyword := range keywords {
+			if words[keyword] || (strings.ContainsAny(keyword, ". ") && strings.Contains(text, keyword)) {
+				score++
+			}
+		}
+		if score > 0 {
+			matches = append(matches, match{note: note, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	results := make([]Note, 0, len(matches))
+	for _, m := range matches {
+		results = append(results, m.note)
+	}
+	return results, nil
+}
+
+// relatedKeywords lists paper's distinct lowercase keywords: each subject as written, the words of
+// multi-word or dotted subjects, and title words of four or more letters that are not stop words.
+func relatedKeywords(paper *arxiv.Paper) []string {
+	seen := map[string]bool{}
+	var keywords []string
+	add := func(keyword string) {
+		if keyword == "" || seen[keyword] {
+			return
+		}
+		seen[keyword] = true
+		keywords = append(keywords, keyword)
+	}
+	for _, subject := range paper.Subjects {
+		subject = strings.ToLower(strings.TrimSpace(subject))
+		add(subject)
+		for _, word := range keywordFields(subject) {
+			if len(word) >= 4 && !relatedStopWords[word] {
+				add(word)
+			}
+		}
+	}
+	for _, word := range keywordFields(strings.ToLower(paper.Title)) {
+		if len(word) >= 4 && !relatedStopWords[word] {
+			add(word)
+		}
+	}
+	return keywords
+}
+
+func keywordFields(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func keywordSet(text string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range keywordFields(text) {
+		set[word] = true
+	}
+	return set
+}
@@ -0,0 +1,42 @@
+package notes
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Search returns the notes whose title or body contains query, ignoring case, with the most
+// occurrences first; ties keep knowledge base order. A blank query or a knowledge base that does
+// not exist yet yields no results.
+func (s Store) Search(query string) ([]Note, error) {
+	needle := strings.ToLower(strings.TrimSpace(query))
+	if needle == "" {
+		return nil, nil
+	}
+	entries, err := s.Load()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	type match struct {
+		note  Note
+		count int
+	}
+	var matches []match
+	for _, note := range entries {
+		count := strings.Count(strings.ToLower(note.Title), needle) + strings.Count(strings.ToLower(note.Body), needle)
+		if count > 0 {
+			matches = append(matches, match{note: note, count: count})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].count > matches[j].count })
+	results := make([]Note, 0, len(matches))
+	for _, m := range matches {
+		results = append(results, m.note)
+	}
+	return results, nil
+}
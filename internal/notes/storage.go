@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sync"
 	"time"
 )
 
@@ -14,12 +17,24 @@ const (
 	entryTypeNote         = "note"
 )
 
+// ErrInvalidKnowledgeBase reports a knowledge base path that cannot hold the JSON store.
+var ErrInvalidKnowledgeBase = errors.New("invalid knowledge base path")
+
+// renameFile moves a finished temp file over the knowledge base; tests swap it to simulate a crash
+// before the rename.
+var renameFile = os.Rename
+
+// writeMu serializes every load-modify-write of a knowledge base. The TUI runs saves, autosaves,
+// and snapshot updates as concurrent jobs, and without it the last rename would drop the others'
+// changes.
+var writeMu sync.Mutex
+
 type entryHeader struct {
 	EntryType string `json:"entryType"`
 }
 
-// Save appends notes to the knowledge base file, creating it if necessary.
-func Save(path string, newNotes []Note) error {
+// Save appends notes to the knowledge base, creating it if necessary.
+func (s Store) Save(newNotes []Note) error {
 	if len(newNotes) == 0 {
 		return nil
 	}
@@ -31,11 +46,11 @@ func Save(path string, newNotes []Note) error {
 		}
 		entries = append(entries, raw)
 	}
-	return appendEntries(path, entries)
+	return s.appendEntries(entries)
 }
 
-// SaveConversationSnapshots appends conversation snapshots to the knowledge base file.
-func SaveConversationSnapshots(path string, snapshots []ConversationSnapshot) error {
+// SaveConversationSnapshots appends conversation snapshots to the knowledge base.
+func (s Store) SaveConversationSnapshots(snapshots []ConversationSnapshot) error {
 	if len(snapshots) == 0 {
 		return nil
 	}
@@ -48,28 +63,30 @@ func SaveConversationSnapshots(path string, snapshots []ConversationSnapshot) er
 		}
 		entries = append(entries, raw)
 	}
-	return appendEntries(path, entries)
+	return s.appendEntries(entries)
 }
 
 // AppendConversationSnapshot appends messages or notes to a per-paper snapshot.
-func AppendConversationSnapshot(path, paperID, paperTitle string, update SnapshotUpdate) error {
-	if path == "" || paperID == "" {
+func (s Store) AppendConversationSnapshot(paperID, paperTitle string, update SnapshotUpdate) error {
+	if s.Path == "" || paperID == "" {
 		return nil
 	}
-	if len(update.Messages) == 0 && len(update.Notes) == 0 && update.Brief == nil && len(update.SectionMetadata) == 0 {
+	if len(update.Messages) == 0 && len(update.Notes) == 0 && update.Brief == nil && len(update.SectionMetadata) == 0 && update.Scratchpad == nil {
 		return nil
 	}
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	if err := s.prepareWrite(); err != nil {
 		return err
 	}
-	entries, err := loadEntries(path)
+	entries, err := s.loadEntries()
 	if err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
 			return err
 		}
 		entries = nil
 	}
-	updated := false
+	var changed json.RawMessage
 	capturedAt := time.Now()
 	for i, raw := range entries {
 		entryType, err := detectEntryType(raw)
@@ -93,34 +110,16 @@ func AppendConversationSnapshot(path, paperID, paperTitle string, update Snapsho
 		if snapshot.CapturedAt.IsZero() {
 			snapshot.CapturedAt = capturedAt
 		}
-		snapshot.Messages = append(snapshot.Messages, update.Messages...)
-		snapshot.Notes = append(snapshot.Notes, update.Notes...)
-		if update.Brief != nil {
-			if snapshot.Brief == nil {
-				snapshot.Brief = &BriefSnapshot{}
-			}
-			if update.Brief.Summary != nil {
-				snapshot.Brief.Summary = append([]string(nil), update.Brief.Summary...)
-			}
-			if update.Brief.Technical != nil {
-				snapshot.Brief.Technical = append([]string(nil), update.Brief.Technical...)
-			}
-			if update.Brief.DeepDive != nil {
-				snapshot.Brief.DeepDive = append([]string(nil), update.Brief.DeepDive...)
-			}
-		}
-		if len(update.SectionMetadata) > 0 {
-			snapshot.SectionMetadata = mergeSectionMetadata(snapshot.SectionMetadata, update.SectionMetadata)
-		}
+		snapshot.Apply(update)
 		raw, err = json.Marshal(snapshot)
 		if err != nil {
 			return err
 		}
 		entries[i] = raw
-		updated = true
+		changed = raw
 		break
 	}
-	if !updated {
+	if changed == nil {
 		brief := copyBriefSnapshot(update.Brief)
 		snapshot := ConversationSnapshot{
 			EntryType:  entryTypeConversation,
@@ -133,18 +132,33 @@ func AppendConversationSnapshot(path, paperID, paperTitle string, update Snapsho
 			SectionMetadata: append([]BriefSectionMetadata(nil),
 				update.SectionMetadata...),
 		}
+		if update.Scratchpad != nil {
+			snapshot.Scratchpad = *update.Scratchpad
+		}
+		if update.LLM != nil {
+			llm := *update.LLM
+			snapshot.LLM = &llm
+		}
 		raw, err := json.Marshal(snapshot)
 		if err != nil {
 			return err
 		}
 		entries = append(entries, raw)
+		changed = raw
+	}
+	switch s.format() {
+	case FormatJSONL:
+		// The updated snapshot goes on a new line; loading keeps the last line for each paper.
+		return appendJSONLEntries(s.Path, []json.RawMessage{changed})
+	case FormatDir:
+		return appendDirEntries(s.Path, []json.RawMessage{changed})
 	}
-	return writeEntries(path, entries)
+	return s.writeEntries(entries)
 }
 
 // Load returns all stored notes from the knowledge base.
-func Load(path string) ([]Note, error) {
-	entries, err := loadEntries(path)
+func (s Store) Load() ([]Note, error) {
+	entries, err := s.loadEntries()
 	if err != nil {
 		return nil, err
 	}
@@ -170,9 +184,34 @@ func Load(path string) ([]Note, error) {
 	return notes, nil
 }
 
+// LatestConversationSnapshot returns the snapshot with the most recent LastActivity. ok is false
+// when the knowledge base is missing or holds no snapshots.
+func (s Store) LatestConversationSnapshot() (ConversationSnapshot, bool, error) {
+	snapshots, err := s.LoadConversationSnapshots()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ConversationSnapshot{}, false, nil
+		}
+		return ConversationSnapshot{}, false, err
+	}
+	latest := -1
+	for i, snapshot := range snapshots {
+		if snapshot.PaperID == "" {
+			continue
+		}
+		if latest < 0 || snapshot.LastActivity().After(snapshots[latest].LastActivity()) {
+			latest = i
+		}
+	}
+	if latest < 0 {
+		return ConversationSnapshot{}, false, nil
+	}
+	return snapshots[latest], true, nil
+}
+
 // LoadConversationSnapshots returns all stored conversation snapshots from the knowledge base.
-func LoadConversationSnapshots(path string) ([]ConversationSnapshot, error) {
-	entries, err := loadEntries(path)
+func (s Store) LoadConversationSnapshots() ([]ConversationSnapshot, error) {
+	entries, err := s.loadEntries()
 	if err != nil {
 		return nil, err
 	}
@@ -195,14 +234,22 @@ func LoadConversationSnapshots(path string) ([]ConversationSnapshot, error) {
 	return snapshots, nil
 }
 
-func appendEntries(path string, newEntries []json.RawMessage) error {
+func (s Store) appendEntries(newEntries []json.RawMessage) error {
 	if len(newEntries) == 0 {
 		return nil
 	}
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	if err := s.prepareWrite(); err != nil {
 		return err
 	}
-	entries, err := loadEntries(path)
+	switch s.format() {
+	case FormatJSONL:
+		return appendJSONLEntries(s.Path, newEntries)
+	case FormatDir:
+		return appendDirEntries(s.Path, newEntries)
+	}
+	entries, err := s.loadEntries()
 	if err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
 			return err
@@ -210,25 +257,157 @@ func appendEntries(path string, newEntries []json.RawMessage) error {
 		entries = nil
 	}
 	entries = append(entries, newEntries...)
-	return writeEntries(path, entries)
+	return s.writeEntries(entries)
+}
+
+// Validate reports whether the store's path can back the knowledge base: it must either not exist
+// yet or be a regular, writable file. Directories (including symlinks to directories) are rejected
+// up front so callers surface one actionable error instead of repeated read/write failures. Under
+// FormatDir the rule flips: the path must be a writable directory.
+func (s Store) Validate() error {
+	path := s.Path
+	if s.format() == FormatDir {
+		return validateDirPath(path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("%w: %v", ErrInvalidKnowledgeBase, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%w: %s is a directory; point -zettel at a JSON file such as %s", ErrInvalidKnowledgeBase, path, filepath.Join(path, DefaultFileName))
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%w: %s is not a regular file (mode %s)", ErrInvalidKnowledgeBase, path, info.Mode().Type())
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("%w: %s is not writable: %v", ErrInvalidKnowledgeBase, path, err)
+	}
+	return file.Close()
+}
+
+func (s Store) prepareWrite() error {
+	if s.format() == FormatDir {
+		if err := os.MkdirAll(s.Path, 0o755); err != nil {
+			return err
+		}
+		return s.Validate()
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+	return s.Validate()
 }
 
-func writeEntries(path string, entries []json.RawMessage) error {
+func (s Store) writeEntries(entries []json.RawMessage) error {
+	switch s.format() {
+	case FormatDir:
+		return writeDirEntries(s.Path, entries)
+	case FormatJSONL:
+		return writeJSONLEntries(s.Path, entries)
+	}
 	data, err := json.MarshalIndent(entries, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(path, data, 0o644)
+	return writeFileAtomic(s.Path, data)
+}
+
+// writeFileAtomic replaces path with data by writing a temp file in the same directory and renaming
+// it into place, the way the PDF cache finishes downloads, so a write cut short by a crash leaves
+// the previous contents intact instead of a truncated file. A symlinked path has its target
+// replaced, the file keeps its permissions, and the directory is synced so the rename survives a
+// power loss.
+func writeFileAtomic(path string, data []byte) error {
+	path, mode, err := atomicWriteTarget(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, mode)
+	}
+	if err == nil {
+		err = renameFile(tmpPath, path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// atomicWriteTarget resolves symlinks in path, so the rename replaces the file a link points at
+// rather than the link, and returns the permissions to give the new file: the existing file's, or
+// owner-only for a new one.
+func atomicWriteTarget(path string) (string, os.FileMode, error) {
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return path, 0o600, nil
+		}
+		return "", 0, err
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return "", 0, err
+	}
+	return target, info.Mode().Perm(), nil
+}
+
+// syncDir flushes dir's entries so a finished rename is on disk. Windows cannot sync a directory
+// handle, and its renames are journaled anyway.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func (s Store) loadEntries() ([]json.RawMessage, error) {
+	if s.format() == FormatDir {
+		return loadDirEntries(s.Path)
+	}
+	return loadFileEntries(s.Path)
 }
 
-func loadEntries(path string) ([]json.RawMessage, error) {
+// loadFileEntries reads a single-file knowledge base in either layout: a JSON array, or JSONL,
+// whatever the file is called.
+func loadFileEntries(path string) ([]json.RawMessage, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	if len(bytes.TrimSpace(data)) == 0 {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
 		return nil, nil
 	}
+	if trimmed[0] == '{' {
+		entries, err := decodeJSONL(data)
+		if err != nil {
+			return nil, err
+		}
+		return collapseSnapshots(entries)
+	}
 	var entries []json.RawMessage
 	if err := json.Unmarshal(data, &entries); err != nil {
 		return nil, err
@@ -271,6 +450,67 @@ func mergeSectionMetadata(existing, updates []BriefSectionMetadata) []BriefSecti
 	return result
 }
 
+// Apply adds update to the snapshot the way AppendConversationSnapshot stores it, so an update that
+// has not been written yet can be shown alongside the stored history.
+func (s *ConversationSnapshot) Apply(update SnapshotUpdate) {
+	s.Messages = append(s.Messages, update.Messages...)
+	s.Notes = append(s.Notes, update.Notes...)
+	if update.Brief != nil {
+		if s.Brief == nil {
+			s.Brief = &BriefSnapshot{}
+		}
+		if update.Brief.Summary != nil {
+			s.Brief.Summary = append([]string(nil), update.Brief.Summary...)
+		}
+		if update.Brief.Technical != nil {
+			s.Brief.Technical = append([]string(nil), update.Brief.Technical...)
+		}
+		if update.Brief.DeepDive != nil {
+			s.Brief.DeepDive = append([]string(nil), update.Brief.DeepDive...)
+		}
+	}
+	if len(update.SectionMetadata) > 0 {
+		s.SectionMetadata = mergeSectionMetadata(s.SectionMetadata, update.SectionMetadata)
+	}
+	if update.Scratchpad != nil {
+		s.Scratchpad = *update.Scratchpad
+	}
+	if update.LLM != nil {
+		llm := *update.LLM
+		s.LLM = &llm
+	}
+}
+
+// Merge folds a later update into u with the same semantics AppendConversationSnapshot applies when
+// the updates are written one after another, so batched writes produce the same snapshot.
+func (u *SnapshotUpdate) Merge(next SnapshotUpdate) {
+	u.Messages = append(u.Messages, next.Messages...)
+	u.Notes = append(u.Notes, next.Notes...)
+	if next.Brief != nil {
+		if u.Brief == nil {
+			u.Brief = &BriefSnapshot{}
+		}
+		if next.Brief.Summary != nil {
+			u.Brief.Summary = append([]string(nil), next.Brief.Summary...)
+		}
+		if next.Brief.Technical != nil {
+			u.Brief.Technical = append([]string(nil), next.Brief.Technical...)
+		}
+		if next.Brief.DeepDive != nil {
+			u.Brief.DeepDive = append([]string(nil), next.Brief.DeepDive...)
+		}
+	}
+	u.SectionMetadata = mergeSectionMetadata(u.SectionMetadata, next.SectionMetadata)
+	if next.Scratchpad != nil {
+		scratchpad := *next.Scratchpad
+		u.Scratchpad = &scratchpad
+	}
+	if next.LLM != nil {
+		llm := *next.LLM
+		u.LLM = &llm
+	}
+}
+
 func copyBriefSnapshot(source *BriefSnapshot) *BriefSnapshot {
 	if source == nil {
 		return nil
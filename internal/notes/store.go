@@ -0,0 +1,87 @@
+package notes
+
+import "github.com/csheth/browse/internal/arxiv"
+
+// Store is a knowledge base: where it lives and the layout its entries are kept in. main builds
+// one from -zettel and -kb-format and hands it to every caller. The zero Format is FormatFile,
+// which still reads and writes JSONL when Path ends in .jsonl.
+type Store struct {
+	Path   string
+	Format Format
+}
+
+// The functions below use the single-file knowledge base at path, in whichever layout its
+// extension selects. Use a Store to reach a FormatDir knowledge base.
+
+// Save appends notes to the knowledge base file at path; see Store.Save.
+func Save(path string, newNotes []Note) error {
+	return Store{Path: path}.Save(newNotes)
+}
+
+// SaveConversationSnapshots appends conversation snapshots to the knowledge base file at path.
+func SaveConversationSnapshots(path string, snapshots []ConversationSnapshot) error {
+	return Store{Path: path}.SaveConversationSnapshots(snapshots)
+}
+
+// AppendConversationSnapshot appends messages or notes to a per-paper snapshot in the knowledge
+// base file at path.
+func AppendConversationSnapshot(path, paperID, paperTitle string, update SnapshotUpdate) error {
+	return Store{Path: path}.AppendConversationSnapshot(paperID, paperTitle, update)
+}
+
+// Load returns all stored notes from the knowledge base file at path.
+func Load(path string) ([]Note, error) {
+	return Store{Path: path}.Load()
+}
+
+// LatestConversationSnapshot returns the most recently active snapshot in the knowledge base file
+// at path; see Store.LatestConversationSnapshot.
+func LatestConversationSnapshot(path string) (ConversationSnapshot, bool, error) {
+	return Store{Path: path}.LatestConversationSnapshot()
+}
+
+// LoadConversationSnapshots returns all conversation snapshots in the knowledge base file at path.
+func LoadConversationSnapshots(path string) ([]ConversationSnapshot, error) {
+	return Store{Path: path}.LoadConversationSnapshots()
+}
+
+// ValidatePath reports whether path can back a single-file knowledge base; see Store.Validate.
+func ValidatePath(path string) error {
+	return Store{Path: path}.Validate()
+}
+
+// Delete removes a note from the knowledge base file at path; see Store.Delete.
+func Delete(path string, note Note) (bool, error) {
+	return Store{Path: path}.Delete(note)
+}
+
+// UpdateNote rewrites a note in the knowledge base file at path; see Store.UpdateNote.
+func UpdateNote(path string, target Note, newBody, newTitle string) error {
+	return Store{Path: path}.UpdateNote(target, newBody, newTitle)
+}
+
+// Search finds notes in the knowledge base file at path; see Store.Search.
+func Search(path, query string) ([]Note, error) {
+	return Store{Path: path}.Search(query)
+}
+
+// LoadByTag returns the notes tagged tag in the knowledge base file at path; see Store.LoadByTag.
+func LoadByTag(path, tag string) ([]Note, error) {
+	return Store{Path: path}.LoadByTag(tag)
+}
+
+// TagCounts counts tags across the knowledge base file at path.
+func TagCounts(path string) (map[string]int, error) {
+	return Store{Path: path}.TagCounts()
+}
+
+// FindRelated returns notes related to paper from the knowledge base file at path; see
+// Store.FindRelated.
+func FindRelated(path string, paper *arxiv.Paper, limit int) ([]Note, error) {
+	return Store{Path: path}.FindRelated(paper, limit)
+}
+
+// ExportMarkdown exports the knowledge base file at path to outDir; see Store.ExportMarkdown.
+func ExportMarkdown(path, outDir string) error {
+	return Store{Path: path}.ExportMarkdown(outDir)
+}
@@ -0,0 +1,98 @@
+package notes
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// ExtractTags returns the lowercase #word tags in body in order of first use, without the #. A tag
+// must start the text or follow a space or opening bracket, so "C#" and URL fragments are not
+// tags, and it needs at least one letter, so "#3" stays a reference to item three. Tag words may
+// contain letters, digits, hyphens, and underscores.
+func ExtractTags(body string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	runes := []rune(body)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '#' || (i > 0 && !unicode.IsSpace(runes[i-1]) && !strings.ContainsRune("([{", runes[i-1])) {
+			continue
+		}
+		end := i + 1
+		hasLetter := false
+		for end < len(runes) && isTagRune(runes[end]) {
+			hasLetter = hasLetter || unicode.IsLetter(runes[end])
+			end++
+		}
+		tag := strings.ToLower(strings.TrimRight(string(runes[i+1:end]), "-_"))
+		if hasLetter && tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+		i = end - 1
+	}
+	return tags
+}
+
+func isTagRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_'
+}
+
+// normalizeTag lowercases tag and drops a leading #, so "#TODO" and "todo" name the same tag.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// NoteTags returns the note's tags. Notes saved before tags were recorded have none stored, so
+// their tags are read from the body instead.
+func NoteTags(note Note) []string {
+	if note.Tags != nil {
+		return note.Tags
+	}
+	return ExtractTags(note.Body)
+}
+
+// LoadByTag returns the notes tagged tag (with or without its #, ignoring case) in knowledge base
+// order. A blank tag or a knowledge base that does not exist yet yields no notes.
+func (s Store) LoadByTag(tag string) ([]Note, error) {
+	tag = normalizeTag(tag)
+	if tag == "" {
+		return nil, nil
+	}
+	entries, err := s.Load()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var tagged []Note
+	for _, note := range entries {
+		for _, candidate := range NoteTags(note) {
+			if candidate == tag {
+				tagged = append(tagged, note)
+				break
+			}
+		}
+	}
+	return tagged, nil
+}
+
+// TagCounts counts how many notes carry each tag across the knowledge base.
+func (s Store) TagCounts() (map[string]int, error) {
+	entries, err := s.Load()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	counts := map[string]int{}
+	for _, note := range entries {
+		for _, tag := range NoteTags(note) {
+			counts[tag]++
+		}
+	}
+	return counts, nil
+}
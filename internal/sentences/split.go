@@ -0,0 +1,95 @@
+// Package sentences splits prose into sentences without breaking on the abbreviations that are
+// everywhere in papers ("et al.", "e.g.", "Fig. 2").
+package sentences
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultAbbreviations lists the lowercase tokens (without their trailing period) that never end
+// a sentence in academic prose.
+var DefaultAbbreviations = []string{
+	"al", "e.g", "i.e", "cf", "vs", "viz", "approx", "resp",
+	"fig", "figs", "eq", "eqs", "sec", "secs", "tab", "ref", "refs", "ch", "vol", "pp",
+	"dr", "prof", "mr", "mrs", "ms", "st", "jr",
+}
+
+// Splitter splits text on '.', '!' and '?' unless the period follows a known abbreviation or a
+// single-letter initial, or is not followed by whitespace (decimals, URLs).
+type Splitter struct {
+	abbreviations map[string]struct{}
+}
+
+// New returns a Splitter that knows DefaultAbbreviations plus any extras.
+func New(extra ...string) *Splitter {
+	s := &Splitter{abbreviations: map[string]struct{}{}}
+	for _, list := range [][]string{DefaultAbbreviations, extra} {
+		for _, abbr := range list {
+			abbr = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(abbr)), ".")
+			if abbr != "" {
+				s.abbreviations[abbr] = struct{}{}
+			}
+		}
+	}
+	return s
+}
+
+var defaultSplitter = New()
+
+// Split splits text with the default abbreviation list.
+func Split(text string) []string {
+	return defaultSplitter.Split(text)
+}
+
+// Split returns the trimmed sentences in text, keeping their terminal punctuation.
+func (s *Splitter) Split(text string) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	var sentences []string
+	start := 0
+	for idx, r := range text {
+		if r != '.' && r != '!' && r != '?' {
+			continue
+		}
+		end := idx + utf8.RuneLen(r)
+		if end < len(text) {
+			next, _ := utf8.DecodeRuneInString(text[end:])
+			if !unicode.IsSpace(next) {
+				continue
+			}
+		}
+		if r == '.' && s.isAbbreviation(text[start:idx]) {
+			continue
+		}
+		if segment := strings.TrimSpace(text[start:end]); segment != "" {
+			sentences = append(sentences, segment)
+		}
+		start = end
+	}
+	if segment := strings.TrimSpace(text[start:]); segment != "" {
+		sentences = append(sentences, segment)
+	}
+	return sentences
+}
+
+// isAbbreviation inspects the word right before a period.
+func (s *Splitter) isAbbreviation(before string) bool {
+	word := before
+	if i := strings.LastIndexFunc(before, unicode.IsSpace); i >= 0 {
+		word = before[i+1:]
+	}
+	word = strings.TrimLeftFunc(word, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	if word == "" {
+		return false
+	}
+	if utf8.RuneCountInString(word) == 1 {
+		r, _ := utf8.DecodeRuneInString(word)
+		return unicode.IsUpper(r)
+	}
+	_, ok := s.abbreviations[strings.ToLower(word)]
+	return ok
+}
@@ -0,0 +1,33 @@
+package sentences
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitKeepsAbbreviationsTogether(t *testing.T) {
+	t.Parallel()
+
+	abstract := "Smith et al. propose a router. It improves accuracy, e.g. on ImageNet by 3.5 points! As Fig. 2 shows, J. Doe's baseline lags. Does it scale?"
+	want := []string{
+		"Smith et al. propose a router.",
+		"It improves accuracy, e.g. on ImageNet by 3.5 points!",
+		"As Fig. 2 shows, J. Doe's baseline lags.",
+		"Does it scale?",
+	}
+	if got := Split(abstract); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Split() = %q, want %q", got, want)
+	}
+}
+
+func TestSplitterExtraAbbreviations(t *testing.T) {
+	t.Parallel()
+
+	text := "See Thm. 3 for details. Done."
+	if got := Split(text); len(got) != 3 {
+		t.Fatalf("expected default splitter to break after Thm., got %q", got)
+	}
+	if got := New("thm").Split(text); len(got) != 2 {
+		t.Fatalf("expected custom abbreviation to be honored, got %q", got)
+	}
+}
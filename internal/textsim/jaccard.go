@@ -0,0 +1,43 @@
+// Package textsim spots near-duplicate prose, such as two suggested notes that make the same point
+// with different punctuation or casing.
+package textsim
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NearDuplicateThreshold is the token Jaccard similarity above which two texts count as the same.
+const NearDuplicateThreshold = 0.9
+
+// tokens returns the distinct lowercase words and numbers in text.
+func tokens(text string) map[string]struct{} {
+	set := map[string]struct{}{}
+	for _, field := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		set[field] = struct{}{}
+	}
+	return set
+}
+
+// Jaccard returns |A∩B| / |A∪B| over the normalized tokens of a and b. Texts without any words
+// share nothing and score 0.
+func Jaccard(a, b string) float64 {
+	left, right := tokens(a), tokens(b)
+	if len(left) == 0 || len(right) == 0 {
+		return 0
+	}
+	shared := 0
+	for token := range left {
+		if _, ok := right[token]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(left)+len(right)-shared)
+}
+
+// NearDuplicate reports whether a and b are more than NearDuplicateThreshold similar.
+func NearDuplicate(a, b string) bool {
+	return Jaccard(a, b) > NearDuplicateThreshold
+}
@@ -0,0 +1,25 @@
+package textsim
+
+import "testing"
+
+func TestJaccardNormalizesCaseAndPunctuation(t *testing.T) {
+	if got := Jaccard("Sparse attention cuts memory use.", "sparse ATTENTION: cuts memory use!"); got != 1 {
+		t.Fatalf("expected identical token sets, got %v", got)
+	}
+	if got := Jaccard("sparse attention", "dense attention"); got != 1.0/3 {
+		t.Fatalf("expected 1/3, got %v", got)
+	}
+	if got := Jaccard("", "anything"); got != 0 {
+		t.Fatalf("expected empty text to score 0, got %v", got)
+	}
+}
+
+func TestNearDuplicateUsesThreshold(t *testing.T) {
+	base := "the model reaches state of the art accuracy on imagenet with half the parameters"
+	if !NearDuplicate(base, "The model reaches state-of-the-art accuracy on ImageNet with half the parameters.") {
+		t.Fatal("expected a reformatted sentence to be a near duplicate")
+	}
+	if NearDuplicate(base, "the model reaches state of the art accuracy on coco with half the parameters") {
+		t.Fatal("expected a changed dataset to keep the sentences apart")
+	}
+}
@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// announce writes a terse plain-text description of entry to Config.Announcements so screen
+// readers can follow state changes without parsing the styled layout. Write errors are ignored;
+// the announcement stream is best-effort.
+func (m *model) announce(entry transcriptEntry) {
+	if m.config.Announcements == nil {
+		return
+	}
+	line := strings.TrimSpace(describeTranscriptEntry(entry))
+	if line == "" {
+		return
+	}
+	fmt.Fprintln(m.config.Announcements, line)
+}
+
+// announceTranscriptIndex announces the entry at idx, if it exists.
+func (m *model) announceTranscriptIndex(idx int) {
+	if idx < 0 || idx >= len(m.transcriptEntries) {
+		return
+	}
+	m.announce(m.transcriptEntries[idx])
+}
+
+// announceOnAppend reports whether a newly appended entry should be announced right away. Brief
+// and draft answer entries are created as soon as they start streaming, so they are announced when
+// they complete instead.
+func announceOnAppend(kind string) bool {
+	switch kind {
+	case "answer_draft", "brief", briefTranscriptKindSummary, briefTranscriptKindTechnical, briefTranscriptKindDeepDive:
+		return false
+	}
+	return true
+}
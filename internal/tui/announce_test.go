@@ -0,0 +1,27 @@
+package tui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/llm"
+)
+
+func TestBriefSectionCompletionAnnouncesPlainText(t *testing.T) {
+	var out bytes.Buffer
+	m := newTestModel(t)
+	m.config.Announcements = &out
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Accessible Paper"}
+	m.stage = stageDisplay
+
+	m.handleBriefSectionStream(briefSectionStreamMsg{paperID: m.paper.ID, kind: llm.BriefSummary, bullets: []string{"- Partial"}})
+	if out.Len() != 0 {
+		t.Fatalf("expected no announcement while streaming, got %q", out.String())
+	}
+
+	m.handleBriefSectionResult(briefSectionMsg{paperID: m.paper.ID, kind: llm.BriefSummary, bullets: []string{"- Problem framing"}})
+	if got := out.String(); got != "Brief summary ready\n" {
+		t.Fatalf("expected summary announcement, got %q", got)
+	}
+}
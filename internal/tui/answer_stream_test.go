@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/llm"
+)
+
+// chunkedAnswerLLM streams a fixed answer one chunk at a time.
+type chunkedAnswerLLM struct {
+	fakeLLM
+	chunks []string
+}
+
+func (f chunkedAnswerLLM) StreamAnswer(ctx context.Context, title, question, content string, handler llm.AnswerStreamHandler) error {
+	for i, chunk := range f.chunks {
+		if err := handler(llm.AnswerDelta{Text: chunk, Done: i == len(f.chunks)-1}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestQuestionAnswerStreamGrowsTranscriptEntry(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "1234.56789", Title: "Fixture", FullText: "We evaluate with BLEU."}
+	client := chunkedAnswerLLM{chunks: []string{"The metric", " is", " BLEU."}}
+	m.config.LLM = client
+	m.qaHistory = []qaExchange{{Question: "Which metric?", Pending: true, TranscriptIndex: -1}}
+	m.appendTranscript("question", "Which metric?")
+
+	runner, updates := questionAnswerJob(0, client, m.paper, "Which metric?", context.Background(), m.questionTimeout())
+	result, err := runner(context.Background())
+	if err != nil {
+		t.Fatalf("questionAnswerJob() error = %v", err)
+	}
+
+	var contents []string
+	next := waitAnswerStream(m.paper.ID, 0, updates)
+	for {
+		msg := next()
+		if msg == nil {
+			break
+		}
+		next = m.handleAnswerStream(msg.(answerStreamMsg))
+		idx := m.qaHistory[0].TranscriptIndex
+		if idx < 0 {
+			t.Fatal("expected the draft entry to exist after the first chunk")
+		}
+		contents = append(contents, m.transcriptEntries[idx].Content)
+	}
+	want := []string{"The metric", "The metric is", "The metric is BLEU."}
+	if len(contents) != len(want) {
+		t.Fatalf("expected %d in-place updates, got %q", len(want), contents)
+	}
+	for i := range want {
+		if contents[i] != want[i] {
+			t.Fatalf("update %d: expected %q, got %q", i, want[i], contents[i])
+		}
+	}
+	if len(m.transcriptEntries) != 2 || m.transcriptEntries[1].Kind != "answer_draft" {
+		t.Fatalf("expected one draft entry after the question, got %+v", m.transcriptEntries)
+	}
+
+	m.handleQuestionResult(result.(questionResultMsg))
+	if got := m.transcriptEntries[1]; got.Kind != "answer" || got.Content != "The metric is BLEU." {
+		t.Fatalf("expected the draft finalized in place, got %+v", got)
+	}
+	if len(m.transcriptEntries) != 2 {
+		t.Fatalf("expected no extra transcript entry, got %d", len(m.transcriptEntries))
+	}
+}
+
+func TestNewQuestionCancelsPreviousAnswerStream(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "1234.56789", Title: "Fixture", FullText: "We evaluate with BLEU."}
+	m.config.LLM = fakeLLM{}
+	m.config.AutoBrief = false
+
+	for _, question := range []string{"First?", "Second?"} {
+		m.setComposerMode(composerModeQuestion, composerQuestionPlaceholder, true)
+		m.composer.SetValue(question)
+		if _, handled := m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter}); !handled {
+			t.Fatalf("expected %q to be submitted", question)
+		}
+	}
+
+	superseded, cancel := context.WithCancel(context.Background())
+	cancel()
+	runner, _ := questionAnswerJob(0, chunkedAnswerLLM{chunks: []string{"partial"}}, m.paper, "First?", superseded, m.questionTimeout())
+	result, err := runner(context.Background())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the superseded stream to stop with context.Canceled, got %v", err)
+	}
+	m.handleQuestionResult(result.(questionResultMsg))
+	if m.qaHistory[0].Pending || m.qaHistory[0].Error == "" {
+		t.Fatalf("expected the first question marked superseded, got %+v", m.qaHistory[0])
+	}
+	if !m.questionLoading || !m.qaHistory[1].Pending {
+		t.Fatal("expected the newer question to keep loading")
+	}
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/notes"
+)
+
+// autosaveResultMsg reports one manual note written by autosaveNoteJob.
+type autosaveResultMsg struct {
+	paperID string
+	note    notes.Note
+	err     error
+}
+
+// autosaveNoteJob saves note and then runs snapshot, when set, so the paper's conversation snapshot
+// is updated in the same job instead of a second one rewriting the knowledge base alongside it. A
+// failed snapshot fails the job like a standalone snapshot job would, but the note still counts as
+// saved so it is not kept as a draft and written twice.
+func autosaveNoteJob(kb notes.Store, note notes.Note, snapshot jobRunner) jobRunner {
+	return func(parent context.Context) (tea.Msg, error) {
+		err := kb.Save([]notes.Note{note})
+		var snapshotErr error
+		if snapshot != nil {
+			_, snapshotErr = snapshot(parent)
+		}
+		if err != nil {
+			return autosaveResultMsg{paperID: note.PaperID, note: note, err: err}, err
+		}
+		return autosaveResultMsg{paperID: note.PaperID, note: note}, snapshotErr
+	}
+}
+
+// autosaveNoteCmd writes a manual note to the knowledge base as soon as it is captured. The note
+// never joins m.manualNotes, so a later save cannot write it a second time; only a failed autosave
+// leaves it behind as a draft for s to retry.
+func (m *model) autosaveNoteCmd(note notes.Note, snapshot jobRunner) tea.Cmd {
+	m.infoMessage = fmt.Sprintf("Saving note to %s…", m.knowledgeBasePath())
+	return m.jobBus.Start(jobKindSave, autosaveNoteJob(m.knowledgeBase(), note, snapshot))
+}
+
+func (m *model) handleAutosaveResult(msg autosaveResultMsg) tea.Cmd {
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("autosave failed: %v", msg.err)
+		m.appendTranscript("error", fmt.Sprintf("Autosave failed: %v", msg.err))
+		if m.paper != nil && m.paper.ID == msg.paperID {
+			m.manualNotes = append(m.manualNotes, msg.note)
+			m.draftCursor = len(m.manualNotes) - 1
+			m.infoMessage = "Autosave failed; the note is kept as a draft. Retry with s."
+		}
+		m.markViewportDirty()
+		return nil
+	}
+	if m.paper == nil || m.paper.ID != msg.paperID {
+		return nil
+	}
+	m.errorMessage = ""
+	m.infoMessage = fmt.Sprintf("Note saved to %s", m.knowledgeBasePath())
+	m.refreshPersistedState()
+	m.markViewportDirty()
+	return nil
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/notes"
+)
+
+// runJobs executes cmd, descending into batches and sequences, and hands every finished job's
+// payload to the model, which is how the program would deliver them.
+func runJobs(m *model, cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		for _, inner := range msg {
+			runJobs(m, inner)
+		}
+	case jobResultEnvelope:
+		m.handleJobPayload(msg.Payload)
+	default:
+		// tea.Sequence delivers an unexported slice of commands.
+		value := reflect.ValueOf(msg)
+		if value.Kind() == reflect.Slice && value.Type().Elem() == reflect.TypeOf(tea.Cmd(nil)) {
+			for i := 0; i < value.Len(); i++ {
+				runJobs(m, value.Index(i).Interface().(tea.Cmd))
+			}
+		}
+	}
+}
+
+func TestAutosaveWritesManualNoteWithoutSaving(t *testing.T) {
+	m := newTestModel(t)
+	m.config.Autosave = true
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Sparse Routing", FullText: "Body."}
+	m.stage = stageDisplay
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("Routing collapses without the balancing loss")
+
+	cmd := m.submitComposer()
+	if _, ok := cmd().(tea.BatchMsg); ok {
+		t.Fatal("expected the note and its snapshot update written by one job, not two racing ones")
+	}
+	runJobs(m, cmd)
+
+	saved, err := notes.Load(m.config.KnowledgeBasePath)
+	if err != nil || len(saved) != 1 || saved[0].Body != "Routing collapses without the balancing loss" {
+		t.Fatalf("expected the note in the knowledge base, got %+v, %v", saved, err)
+	}
+	snapshots, err := notes.LoadConversationSnapshots(m.config.KnowledgeBasePath)
+	if err != nil || len(snapshots) != 1 || len(snapshots[0].Notes) != 1 {
+		t.Fatalf("expected the note in the paper's conversation snapshot too, got %+v, %v", snapshots, err)
+	}
+	if len(m.manualNotes) != 0 {
+		t.Fatalf("expected no pending drafts, got %+v", m.manualNotes)
+	}
+	if len(m.persistedNotes) != 1 {
+		t.Fatalf("expected the note marked persisted, got %+v", m.persistedNotes)
+	}
+
+	m.composer.Blur()
+	if _, cmd := m.handleDisplayKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")}); cmd != nil {
+		t.Fatal("expected s to be a no-op with nothing pending")
+	}
+	if saved, _ := notes.Load(m.config.KnowledgeBasePath); len(saved) != 1 {
+		t.Fatalf("expected the note saved once, got %d", len(saved))
+	}
+}
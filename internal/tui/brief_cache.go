@@ -0,0 +1,48 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/llm"
+)
+
+// seedBriefFromSidecar restores a complete brief from the PDF cache sidecar so the paper opens
+// instantly, even when the LLM is offline. It reports whether the brief was seeded.
+func (m *model) seedBriefFromSidecar() bool {
+	if !m.config.CacheBriefs || m.paper == nil {
+		return false
+	}
+	sidecar, ok := arxiv.LoadBriefSidecar(m.paper.ID)
+	if !ok {
+		return false
+	}
+	m.brief = llm.ReadingBrief{
+		Summary:   append([]string(nil), sidecar.Summary...),
+		Technical: append([]string(nil), sidecar.Technical...),
+		DeepDive:  append([]string(nil), sidecar.DeepDive...),
+	}
+	m.ensureBriefSections()
+	for _, kind := range briefSectionKinds {
+		m.briefSections[kind] = briefSectionState{Completed: true}
+	}
+	m.briefLoading = false
+	return true
+}
+
+// cacheBriefSidecarCmd persists the brief once every section has content.
+func (m *model) cacheBriefSidecarCmd() tea.Cmd {
+	if !m.config.CacheBriefs || m.paper == nil {
+		return nil
+	}
+	sidecar := arxiv.BriefSidecar{
+		PaperID:   m.paper.ID,
+		Summary:   append([]string(nil), m.brief.Summary...),
+		Technical: append([]string(nil), m.brief.Technical...),
+		DeepDive:  append([]string(nil), m.brief.DeepDive...),
+	}
+	if !sidecar.Complete() {
+		return nil
+	}
+	return m.jobBus.Start(jobKindBriefCache, saveBriefSidecarJob(sidecar))
+}
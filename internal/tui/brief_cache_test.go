@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/llm"
+)
+
+func TestHandlePaperResultSeedsBriefFromSidecar(t *testing.T) {
+	t.Setenv("PAPERSCOUT_CACHE_DIR", t.TempDir())
+	sidecar := arxiv.BriefSidecar{
+		PaperID:   "2101.00001",
+		Summary:   []string{"- cached summary"},
+		Technical: []string{"- cached technical"},
+		DeepDive:  []string{"- cached deep dive"},
+	}
+	if err := arxiv.SaveBriefSidecar(sidecar); err != nil {
+		t.Fatalf("SaveBriefSidecar() error = %v", err)
+	}
+
+	m := newTestModel(t)
+	m.config.CacheBriefs = true
+	m.handlePaperResult(paperResultMsg{paper: &arxiv.Paper{ID: "2101.00001", Title: "Cached", FullText: "text"}})
+
+	if len(m.brief.Summary) != 1 || m.brief.Summary[0] != "- cached summary" {
+		t.Fatalf("expected brief seeded from sidecar, got %#v", m.brief)
+	}
+	if !m.briefReadyForQuestions() {
+		t.Fatal("expected cached sections to count as completed")
+	}
+	idx, ok := m.briefMessageIndex[llm.BriefSummary]
+	if !ok || m.transcriptEntries[idx].Content != "- cached summary" {
+		t.Fatalf("expected summary transcript entry from cache, got %#v", m.transcriptEntries)
+	}
+}
+
+func TestHandlePaperResultIgnoresSidecarWhenDisabled(t *testing.T) {
+	t.Setenv("PAPERSCOUT_CACHE_DIR", t.TempDir())
+	if err := arxiv.SaveBriefSidecar(arxiv.BriefSidecar{
+		PaperID:   "2101.00001",
+		Summary:   []string{"s"},
+		Technical: []string{"t"},
+		DeepDive:  []string{"d"},
+	}); err != nil {
+		t.Fatalf("SaveBriefSidecar() error = %v", err)
+	}
+
+	m := newTestModel(t)
+	m.handlePaperResult(paperResultMsg{paper: &arxiv.Paper{ID: "2101.00001", Title: "Cached"}})
+	if len(m.brief.Summary) != 0 {
+		t.Fatalf("expected sidecar ignored without -cache-briefs, got %#v", m.brief)
+	}
+}
@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/llm"
+)
+
+// stallingStreamLLM emits one delta and then stalls until the context expires.
+type stallingStreamLLM struct {
+	fakeLLM
+}
+
+func (stallingStreamLLM) StreamBriefSection(ctx context.Context, kind llm.BriefSectionKind, title, content string, detail llm.DetailLevel, handler llm.BriefSectionStreamHandler) error {
+	if err := handler(llm.BriefSectionDelta{Kind: kind, Bullets: []string{"- Partial bullet"}}); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestBriefSectionTimeoutKeepsPartialBullets(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Slow Paper", FullText: "Some paper text."}
+	m.stage = stageDisplay
+	m.markBriefSectionRunning(llm.BriefSummary)
+
+	runner, updates := briefSectionJob(llm.BriefSummary, 0, llm.DetailStandard, "Some paper text.", stallingStreamLLM{}, m.paper, context.Background(), 20*time.Millisecond, nil)
+	go func() {
+		for range updates {
+		}
+	}()
+	msg, err := runner(context.Background())
+	if err != nil {
+		t.Fatalf("expected timeout with bullets to succeed as partial, got %v", err)
+	}
+	result := msg.(briefSectionMsg)
+	if !result.partial {
+		t.Fatalf("expected partial result, got %+v", result)
+	}
+
+	m.handleBriefSectionResult(result)
+	if len(m.brief.Summary) != 1 || m.brief.Summary[0] != "- Partial bullet" {
+		t.Fatalf("expected partial bullets retained, got %q", m.brief.Summary)
+	}
+	state := m.briefSections[llm.BriefSummary]
+	if !state.Partial || !state.Completed || state.Error != "" {
+		t.Fatalf("expected completed partial state, got %+v", state)
+	}
+	if body := m.transcriptEntries[m.briefMessageIndex[llm.BriefSummary]].Content; !strings.Contains(body, "Partial") || !strings.Contains(body, "Partial bullet") {
+		t.Fatalf("expected partial notice with bullets, got %q", body)
+	}
+}
+
+func TestBriefSectionTimeoutScalesWithBudget(t *testing.T) {
+	m := newTestModel(t)
+	m.config.BriefSectionTimeout = time.Minute
+	if got := m.briefSectionTimeout(llm.BriefSummary); got != time.Minute {
+		t.Fatalf("expected summary to use the base timeout, got %s", got)
+	}
+	if got := m.briefSectionTimeout(llm.BriefTechnical); got <= time.Minute {
+		t.Fatalf("expected technical timeout above the base, got %s", got)
+	}
+	if got := m.briefSectionTimeout(llm.BriefDeepDive); got != time.Minute {
+		t.Fatalf("expected deep dive floored at the base timeout, got %s", got)
+	}
+}
+
+// silentStreamLLM never emits a delta, standing in for a model too slow to answer in time.
+type silentStreamLLM struct {
+	fakeLLM
+}
+
+func (silentStreamLLM) StreamBriefSection(ctx context.Context, kind llm.BriefSectionKind, title, content string, detail llm.DetailLevel, handler llm.BriefSectionStreamHandler) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestConfiguredBriefTimeoutCancelsSlowStream(t *testing.T) {
+	m := newTestModel(t)
+	m.config.BriefSectionTimeout = 20 * time.Millisecond
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Slow Paper", FullText: "Some paper text."}
+
+	runner, updates := briefSectionJob(llm.BriefSummary, 0, llm.DetailStandard, "Some paper text.", silentStreamLLM{}, m.paper, context.Background(), m.briefSectionTimeout(llm.BriefSummary), nil)
+	go func() {
+		for range updates {
+		}
+	}()
+	started := time.Now()
+	_, err := runner(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the configured timeout to cancel the stream, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("expected the stream cancelled after the short timeout, took %s", elapsed)
+	}
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// citationToken matches numeric citations such as [2], [2, 5], or [3-5].
+var citationToken = regexp.MustCompile(`\[(\d{1,3}(?:\s*[,–-]\s*\d{1,3})*)\]`)
+
+// maxCitationRange stops "[1-400]"-style tokens from expanding into the whole bibliography.
+const maxCitationRange = 10
+
+// appendCitationFootnotes resolves [N] citations in an answer against the paper's references and
+// appends a footnote block listing each resolved entry once, in order of first mention.
+func appendCitationFootnotes(answer string, references []string) string {
+	if len(references) == 0 || strings.Contains(answer, "\n\nReferences:\n") {
+		return answer
+	}
+	seen := map[int]bool{}
+	var footnotes []string
+	for _, match := range citationToken.FindAllStringSubmatch(answer, -1) {
+		for _, n := range citationNumbers(match[1]) {
+			if seen[n] || n < 1 || n > len(references) {
+				continue
+			}
+			seen[n] = true
+			footnotes = append(footnotes, fmt.Sprintf("- [%d] %s", n, references[n-1]))
+		}
+	}
+	if len(footnotes) == 0 {
+		return answer
+	}
+	return strings.TrimRight(answer, "\n") + "\n\nReferences:\n" + strings.Join(footnotes, "\n")
+}
+
+func citationNumbers(token string) []int {
+	var numbers []int
+	for _, part := range strings.Split(token, ",") {
+		part = strings.TrimSpace(part)
+		bounds := strings.FieldsFunc(part, func(r rune) bool { return r == '-' || r == '–' })
+		switch len(bounds) {
+		case 1:
+			if n, err := strconv.Atoi(strings.TrimSpace(bounds[0])); err == nil {
+				numbers = append(numbers, n)
+			}
+		case 2:
+			lo, errLo := strconv.Atoi(strings.TrimSpace(bounds[0]))
+			hi, errHi := strconv.Atoi(strings.TrimSpace(bounds[1]))
+			if errLo != nil || errHi != nil || hi < lo || hi-lo > maxCitationRange {
+				continue
+			}
+			for n := lo; n <= hi; n++ {
+				numbers = append(numbers, n)
+			}
+		}
+	}
+	return numbers
+}
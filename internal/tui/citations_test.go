@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csheth/browse/internal/arxiv"
+)
+
+func TestAppendCitationFootnotesResolvesReferences(t *testing.T) {
+	refs := []string{"A. First. 2019.", "B. Second. 2020.", "C. Third. 2021.", "D. Fourth. 2022."}
+
+	got := appendCitationFootnotes("The baseline follows [2] and extends [3-4]; see also [2].", refs)
+	want := "The baseline follows [2] and extends [3-4]; see also [2].\n\nReferences:\n- [2] B. Second. 2020.\n- [3] C. Third. 2021.\n- [4] D. Fourth. 2022."
+	if got != want {
+		t.Fatalf("unexpected footnotes:\n%s", got)
+	}
+
+	if got := appendCitationFootnotes("Unknown [9] citation.", refs); got != "Unknown [9] citation." {
+		t.Fatalf("expected unresolved citation to be left alone, got %q", got)
+	}
+}
+
+func TestQuestionResultAppendsCitationFootnotes(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Cited", References: []string{"A. First.", "B. Second."}}
+	m.qaHistory = []qaExchange{{Question: "Which prior work?", Pending: true, TranscriptIndex: -1}}
+
+	m.handleQuestionResult(questionResultMsg{paperID: m.paper.ID, index: 0, answer: "It builds on [2]."})
+	if got := m.qaHistory[0].Answer; !strings.HasSuffix(got, "References:\n- [2] B. Second.") {
+		t.Fatalf("expected resolved footnote, got %q", got)
+	}
+	last := m.transcriptEntries[len(m.transcriptEntries)-1]
+	if !strings.Contains(last.Content, "- [2] B. Second.") {
+		t.Fatalf("expected transcript to show the footnote, got %q", last.Content)
+	}
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var (
+	clipboardWrite = clipboard.WriteAll
+	clipboardRead  = clipboard.ReadAll
+	terminalExec   = tea.Exec
+)
+
+type osc52ResultMsg struct {
+	err error
+}
+
+// SystemClipboardUnavailable reports whether no clipboard helper (xclip, xsel, wl-copy) was found,
+// which is the norm on headless servers and over SSH. The check runs once when the program starts.
+func SystemClipboardUnavailable() bool {
+	return clipboard.Unsupported
+}
+
+// copyText writes text to the system clipboard, falling back to the OSC 52 escape when the
+// clipboard is missing. The first failed write switches the session to OSC 52 so later copies do
+// not keep surfacing the same error. A non-nil command means the terminal fallback is used; the
+// caller returns it so the escape is written once the program hands over its output.
+func (m *model) copyText(text string) tea.Cmd {
+	if !m.osc52Clipboard {
+		err := clipboardWrite(text)
+		if err == nil {
+			return nil
+		}
+		m.useOSC52Clipboard(fmt.Sprintf("the system clipboard failed (%v)", err))
+	}
+	return terminalExec(&osc52Command{text: text}, func(err error) tea.Msg {
+		return osc52ResultMsg{err: err}
+	})
+}
+
+// useOSC52Clipboard sends every later copy through the terminal; reason is reported with the first.
+func (m *model) useOSC52Clipboard(reason string) {
+	m.osc52Clipboard = true
+	m.osc52Notice = fmt.Sprintf("Copies go through the terminal (OSC 52) because %s.", reason)
+}
+
+func (m *model) handleOSC52Result(msg osc52ResultMsg) tea.Cmd {
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("Clipboard copy failed: %v", msg.err)
+		return nil
+	}
+	if m.osc52Notice != "" {
+		m.infoMessage = strings.TrimSpace(m.infoMessage + " " + m.osc52Notice)
+		m.osc52Notice = ""
+	}
+	return nil
+}
+
+// osc52Command asks the terminal to set its clipboard. Most modern terminals honour it, including
+// through SSH, since the escape travels with the normal output stream. It runs through tea.Exec so
+// the escape goes to the program's own output while the renderer is paused, rather than racing a
+// frame on stdout.
+type osc52Command struct {
+	text   string
+	stdout io.Writer
+}
+
+func (c *osc52Command) SetStdin(io.Reader) {}
+
+func (c *osc52Command) SetStdout(w io.Writer) {
+	c.stdout = w
+}
+
+func (c *osc52Command) SetStderr(io.Writer) {}
+
+func (c *osc52Command) Run() error {
+	if c.stdout == nil {
+		return fmt.Errorf("no terminal output for OSC 52")
+	}
+	_, err := fmt.Fprintf(c.stdout, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(c.text)))
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -16,19 +17,27 @@ import (
 	"github.com/csheth/browse/internal/notes"
 )
 
-const fetchTimeout = 3 * time.Minute
-
-func fetchPaperJob(url string) jobRunner {
+// fetchPaperJob loads url under fetchCtx, which the model cancels when a newer URL is submitted.
+// A preview loads only the metadata.
+func fetchPaperJob(fetchCtx context.Context, seq int, url string, preview bool, timeout time.Duration) jobRunner {
+	fetch := arxiv.FetchPaper
+	if preview {
+		fetch = arxiv.FetchMetadata
+	}
 	return func(parent context.Context) (tea.Msg, error) {
-		ctx, cancel := context.WithTimeout(parent, fetchTimeout)
+		jobCtx, stop := withStop(parent, fetchCtx)
+		defer stop()
+		ctx, cancel := context.WithTimeout(jobCtx, timeout)
 		defer cancel()
-		paper, err := arxiv.FetchPaper(ctx, url)
-		if err != nil {
-			return paperResultMsg{err: err}, err
+		paper, err := fetch(ctx, url)
+		// A paper whose PDF is unavailable still opens from its metadata; its Notice explains why.
+		if err != nil && !(errors.Is(err, arxiv.ErrPDFUnavailable) && paper != nil) {
+			return paperResultMsg{seq: seq, err: err}, err
 		}
-		steps := guide.Build(guide.Metadata{Title: paper.Title, Authors: paper.Authors})
+		steps := guide.Build(guideMetadata(paper, ""))
 		suggestions := notes.SuggestCandidates(paper.Title, paper.Abstract, paper.KeyContributions)
 		return paperResultMsg{
+			seq:         seq,
 			paper:       paper,
 			guide:       steps,
 			suggestions: suggestions,
@@ -36,24 +45,66 @@ func fetchPaperJob(url string) jobRunner {
 	}
 }
 
-func saveNotesJob(path string, entries []notes.Note) jobRunner {
+// guideMetadata feeds the paper's subject and contributions into the guide. A non-empty focus
+// overrides the arXiv primary category.
+func guideMetadata(paper *arxiv.Paper, focus string) guide.Metadata {
+	subject := strings.TrimSpace(focus)
+	if subject == "" {
+		subject = paper.PrimaryCategory
+	}
+	return guide.Metadata{
+		Title:         paper.Title,
+		Authors:       paper.Authors,
+		Subject:       subject,
+		Contributions: paper.KeyContributions,
+	}
+}
+
+func saveNotesJob(kb notes.Store, entries []notes.Note) jobRunner {
 	toPersist := append([]notes.Note(nil), entries...)
 	return func(parent context.Context) (tea.Msg, error) {
-		if err := notes.Save(path, toPersist); err != nil {
+		if err := kb.Save(toPersist); err != nil {
 			return saveResultMsg{err: err}, err
 		}
 		return saveResultMsg{count: len(toPersist)}, nil
 	}
 }
 
-func ensureConversationSnapshotJob(path string, paper *arxiv.Paper) jobRunner {
+func exportBriefJob(path, content string) jobRunner {
+	return exportMarkdownJob("brief", path, content)
+}
+
+func exportTranscriptJob(path, content string) jobRunner {
+	return exportMarkdownJob("transcript", path, content)
+}
+
+// exportMarkdownJob writes content to path; what names the export in the result message.
+func exportMarkdownJob(what, path, content string) jobRunner {
+	return func(parent context.Context) (tea.Msg, error) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return exportResultMsg{what: what, path: path, err: err}, err
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return exportResultMsg{what: what, path: path, err: err}, err
+		}
+		return exportResultMsg{what: what, path: path}, nil
+	}
+}
+
+func saveBriefSidecarJob(sidecar arxiv.BriefSidecar) jobRunner {
+	return func(parent context.Context) (tea.Msg, error) {
+		return nil, arxiv.SaveBriefSidecar(sidecar)
+	}
+}
+
+func ensureConversationSnapshotJob(kb notes.Store, paper *arxiv.Paper) jobRunner {
 	paperID := paper.ID
 	title := paper.Title
 	return func(parent context.Context) (tea.Msg, error) {
-		if path == "" || paperID == "" {
+		if kb.Path == "" || paperID == "" {
 			return nil, nil
 		}
-		snapshots, err := notes.LoadConversationSnapshots(path)
+		snapshots, err := kb.LoadConversationSnapshots()
 		if err != nil {
 			if !errors.Is(err, os.ErrNotExist) {
 				return nil, err
@@ -70,14 +121,23 @@ func ensureConversationSnapshotJob(path string, paper *arxiv.Paper) jobRunner {
 			PaperTitle: title,
 			CapturedAt: time.Now(),
 		}
-		if err := notes.SaveConversationSnapshots(path, []notes.ConversationSnapshot{newSnapshot}); err != nil {
+		if err := kb.SaveConversationSnapshots([]notes.ConversationSnapshot{newSnapshot}); err != nil {
 			return nil, err
 		}
 		return nil, nil
 	}
 }
 
-func appendConversationSnapshotJob(path string, paper *arxiv.Paper, update notes.SnapshotUpdate) jobRunner {
+// llmMetadata records which provider and model client talks to, or nil without a client.
+func llmMetadata(client llm.Client) *notes.LLMMetadata {
+	provider, model := llm.Describe(client)
+	if provider == "" && model == "" {
+		return nil
+	}
+	return &notes.LLMMetadata{Provider: provider, Model: model}
+}
+
+func appendConversationSnapshotJob(kb notes.Store, paper *arxiv.Paper, update notes.SnapshotUpdate) jobRunner {
 	paperID := paper.ID
 	title := paper.Title
 	messages := append([]notes.ConversationMessage(nil), update.Messages...)
@@ -97,26 +157,45 @@ func appendConversationSnapshotJob(path string, paper *arxiv.Paper, update notes
 		Brief:           briefCopy,
 		SectionMetadata: metadata,
 	}
+	if update.Scratchpad != nil {
+		scratchpad := *update.Scratchpad
+		updateCopy.Scratchpad = &scratchpad
+	}
+	if update.LLM != nil {
+		llmCopy := *update.LLM
+		updateCopy.LLM = &llmCopy
+	}
 	return func(parent context.Context) (tea.Msg, error) {
-		if path == "" || paperID == "" {
+		if kb.Path == "" || paperID == "" {
 			return nil, nil
 		}
-		if len(updateCopy.Messages) == 0 && len(updateCopy.Notes) == 0 && updateCopy.Brief == nil && len(updateCopy.SectionMetadata) == 0 {
+		if len(updateCopy.Messages) == 0 && len(updateCopy.Notes) == 0 && updateCopy.Brief == nil && len(updateCopy.SectionMetadata) == 0 && updateCopy.Scratchpad == nil {
 			return nil, nil
 		}
-		if err := notes.AppendConversationSnapshot(path, paperID, title, updateCopy); err != nil {
+		if err := kb.AppendConversationSnapshot(paperID, title, updateCopy); err != nil {
 			return nil, err
 		}
 		return nil, nil
 	}
 }
 
-func briefSectionJob(kind llm.BriefSectionKind, contextText string, client llm.Client, paper *arxiv.Paper, streamCtx context.Context) (jobRunner, <-chan llm.BriefSectionDelta) {
+// briefSectionJob streams one section. When the timeout expires after some bullets arrived, the
+// streamed bullets are kept and reported as partial instead of failing the section.
+func briefSectionJob(kind llm.BriefSectionKind, seq int, detail llm.DetailLevel, contextText string, client llm.Client, paper *arxiv.Paper, streamCtx context.Context, timeout time.Duration, slot *jobTicket) (jobRunner, <-chan llm.BriefSectionDelta) {
 	title := paper.Title
 	paperID := paper.ID
 	updates := make(chan llm.BriefSectionDelta, 4)
 	runner := func(parent context.Context) (tea.Msg, error) {
-		ctx, cancel := context.WithTimeout(streamCtx, 2*time.Minute)
+		jobCtx, stop := withStop(parent, streamCtx)
+		defer stop()
+		// The timeout only starts once the section holds a slot, so queued sections keep their full
+		// time budget.
+		if err := slot.wait(jobCtx); err != nil {
+			close(updates)
+			return briefSectionMsg{paperID: paperID, kind: kind, seq: seq, err: err}, err
+		}
+		defer slot.release()
+		ctx, cancel := context.WithTimeout(jobCtx, timeout)
 		defer cancel()
 		content := contextText
 		if strings.TrimSpace(content) == "" {
@@ -124,7 +203,7 @@ func briefSectionJob(kind llm.BriefSectionKind, contextText string, client llm.C
 		}
 		var final []string
 		defer close(updates)
-		err := client.StreamBriefSection(ctx, kind, title, content, func(delta llm.BriefSectionDelta) error {
+		err := client.StreamBriefSection(ctx, kind, title, content, detail, func(delta llm.BriefSectionDelta) error {
 			if len(delta.Bullets) > 0 {
 				final = append([]string(nil), delta.Bullets...)
 			}
@@ -135,41 +214,96 @@ func briefSectionJob(kind llm.BriefSectionKind, contextText string, client llm.C
 				return ctx.Err()
 			}
 		})
+		if err != nil && len(final) > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return briefSectionMsg{paperID: paperID, kind: kind, seq: seq, bullets: final, partial: true}, nil
+		}
 		if err != nil {
-			return briefSectionMsg{paperID: paperID, kind: kind, err: err}, err
+			return briefSectionMsg{paperID: paperID, kind: kind, seq: seq, err: err}, err
 		}
-		return briefSectionMsg{paperID: paperID, kind: kind, bullets: final}, nil
+		return briefSectionMsg{paperID: paperID, kind: kind, seq: seq, bullets: final}, nil
 	}
 	return runner, updates
 }
 
-func suggestNotesJob(client llm.Client, paper *arxiv.Paper) jobRunner {
+// suggestNotesJob streams note suggestions, forwarding each one on the returned channel as soon as
+// its JSON object closes so the list fills in while the LLM is still writing. The result message
+// carries every suggestion, including the ones streamed before a failure.
+func suggestNotesJob(client llm.Client, paper *arxiv.Paper) (jobRunner, <-chan notes.Candidate) {
 	title := paper.Title
 	abstract := paper.Abstract
 	contributions := append([]string{}, paper.KeyContributions...)
 	content := paper.FullText
 	paperID := paper.ID
-	return func(parent context.Context) (tea.Msg, error) {
+	updates := make(chan notes.Candidate, 4)
+	runner := func(parent context.Context) (tea.Msg, error) {
 		ctx, cancel := context.WithTimeout(parent, 2*time.Minute)
 		defer cancel()
-		suggestions, err := client.SuggestNotes(ctx, title, abstract, contributions, content)
+		defer close(updates)
+		suggestions, err := client.StreamSuggestNotes(ctx, title, abstract, contributions, content, func(note llm.SuggestedNote) error {
+			select {
+			case updates <- mapSuggestedNotes([]llm.SuggestedNote{note})[0]:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
 		if err != nil {
-			return suggestionResultMsg{paperID: paperID, err: err}, err
+			return suggestionResultMsg{paperID: paperID, suggestions: mapSuggestedNotes(suggestions), err: err}, err
 		}
 		return suggestionResultMsg{paperID: paperID, suggestions: mapSuggestedNotes(suggestions), err: nil}, nil
 	}
+	return runner, updates
 }
 
-func questionAnswerJob(index int, client llm.Client, paper *arxiv.Paper, question string) jobRunner {
+func noteGapsJob(client llm.Client, paper *arxiv.Paper, saved []notes.Note) jobRunner {
 	title := paper.Title
 	content := paper.FullText
 	paperID := paper.ID
+	existing := make([]llm.SuggestedNote, 0, len(saved))
+	for _, note := range saved {
+		existing = append(existing, llm.SuggestedNote{Title: note.Title, Body: note.Body, Kind: note.Kind})
+	}
 	return func(parent context.Context) (tea.Msg, error) {
 		ctx, cancel := context.WithTimeout(parent, 2*time.Minute)
 		defer cancel()
-		answer, err := client.Answer(ctx, title, question, content)
-		return questionResultMsg{paperID: paperID, index: index, answer: answer, err: err}, err
+		suggestions, err := client.NoteGaps(ctx, title, existing, content)
+		if err != nil {
+			return gapResultMsg{paperID: paperID, err: err}, err
+		}
+		return gapResultMsg{paperID: paperID, suggestions: mapSuggestedNotes(suggestions)}, nil
+	}
+}
+
+// questionAnswerJob streams the answer under streamCtx, which the model cancels when a newer
+// question supersedes this one. Each chunk is forwarded on the returned channel so the transcript
+// grows while the LLM generates; the result message carries the complete answer.
+func questionAnswerJob(index int, client llm.Client, paper *arxiv.Paper, question string, streamCtx context.Context, timeout time.Duration) (jobRunner, <-chan llm.AnswerDelta) {
+	title := paper.Title
+	content := paper.FullText
+	paperID := paper.ID
+	updates := make(chan llm.AnswerDelta, 16)
+	runner := func(parent context.Context) (tea.Msg, error) {
+		jobCtx, stop := withStop(parent, streamCtx)
+		defer stop()
+		ctx, cancel := context.WithTimeout(jobCtx, timeout)
+		defer cancel()
+		defer close(updates)
+		var answer strings.Builder
+		err := client.StreamAnswer(ctx, title, question, content, func(delta llm.AnswerDelta) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			answer.WriteString(delta.Text)
+			select {
+			case updates <- delta:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		return questionResultMsg{paperID: paperID, index: index, answer: strings.TrimSpace(answer.String()), err: err}, err
 	}
+	return runner, updates
 }
 
 func trimmedTitle(value string) string {
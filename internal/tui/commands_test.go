@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,23 +19,36 @@ func (fakeLLM) Summarize(ctx context.Context, title, content string) (string, er
 func (fakeLLM) Answer(ctx context.Context, title, question, content string) (string, error) {
 	return "", nil
 }
+func (fakeLLM) StreamAnswer(ctx context.Context, title, question, content string, handler llm.AnswerStreamHandler) error {
+	return handler(llm.AnswerDelta{Done: true})
+}
 func (fakeLLM) SuggestNotes(ctx context.Context, title, abstract string, contributions []string, content string) ([]llm.SuggestedNote, error) {
 	return nil, nil
 }
+func (fakeLLM) StreamSuggestNotes(ctx context.Context, title, abstract string, contributions []string, content string, handler llm.SuggestedNoteHandler) ([]llm.SuggestedNote, error) {
+	return nil, nil
+}
+func (fakeLLM) NoteGaps(ctx context.Context, title string, existing []llm.SuggestedNote, content string) ([]llm.SuggestedNote, error) {
+	return nil, nil
+}
 func (fakeLLM) ReadingBrief(ctx context.Context, title, content string) (llm.ReadingBrief, error) {
 	return llm.ReadingBrief{}, nil
 }
 func (fakeLLM) BriefSection(ctx context.Context, kind llm.BriefSectionKind, title, content string) ([]string, error) {
 	return nil, nil
 }
-func (fakeLLM) StreamBriefSection(ctx context.Context, kind llm.BriefSectionKind, title, content string, handler llm.BriefSectionStreamHandler) error {
+func (fakeLLM) ContinueBriefSection(ctx context.Context, kind llm.BriefSectionKind, title, content string, existing []string, missing int) ([]string, error) {
+	return nil, nil
+}
+func (fakeLLM) StreamBriefSection(ctx context.Context, kind llm.BriefSectionKind, title, content string, detail llm.DetailLevel, handler llm.BriefSectionStreamHandler) error {
 	return handler(llm.BriefSectionDelta{Kind: kind, Bullets: []string{"bullet"}, Done: true})
 }
 func (fakeLLM) Name() string { return "fake" }
 
 func newTestModel(t *testing.T) *model {
 	t.Helper()
-	teaModel, ok := New(Config{}).(*model)
+	// An explicit temp knowledge base keeps tests from reading or writing the default path.
+	teaModel, ok := New(Config{AutoBrief: true, KnowledgeBasePath: filepath.Join(t.TempDir(), "zettel.json")}).(*model)
 	if !ok {
 		t.Fatalf("expected *model, got %T", teaModel)
 	}
@@ -108,12 +122,65 @@ func TestActionAskQuestionRequiresSetup(t *testing.T) {
 	}
 }
 
+func TestOfflineModeExplainsDisabledLLMFeatures(t *testing.T) {
+	client, err := llm.NewFromEnv(llm.Config{Offline: true})
+	if err != nil {
+		t.Fatalf("NewFromEnv() error = %v", err)
+	}
+	if !llm.IsOffline(client) {
+		t.Fatalf("expected an offline client, got %s", client.Name())
+	}
+	m := New(Config{KnowledgeBasePath: filepath.Join(t.TempDir(), "notes.json"), LLM: client}).(*model)
+	if m.config.LLM != nil {
+		t.Fatalf("expected offline mode to drop the LLM client, got %s", m.config.LLM.Name())
+	}
+	m.paper = &arxiv.Paper{ID: "1234", Title: "Test"}
+	if cmd := m.actionAskQuestionCmd(); cmd != nil {
+		t.Fatalf("expected nil command offline, got %T", cmd)
+	}
+	if m.infoMessage != offlineNotice {
+		t.Fatalf("expected %q, got %q", offlineNotice, m.infoMessage)
+	}
+}
+
+// namedLLM is a fakeLLM that reports a provider and model.
+type namedLLM struct {
+	fakeLLM
+	name string
+}
+
+func (n namedLLM) Name() string { return n.name }
+
+func TestBriefSnapshotRecordsProviderAndModel(t *testing.T) {
+	m := newTestModel(t)
+	m.config.LLM = namedLLM{name: "Anthropic (claude-3-5-sonnet-latest)"}
+	m.config.SnapshotFlushInterval = time.Hour
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Sparse Routing"}
+	m.appendConversationSnapshotCmd(briefSectionSnapshotUpdate(llm.BriefSummary, []string{"- Routes sparsely"}, "Summary"))
+	runJobs(m, m.flushConversationSnapshotCmd())
+
+	snapshots, err := notes.LoadConversationSnapshots(m.config.KnowledgeBasePath)
+	if err != nil || len(snapshots) != 1 {
+		t.Fatalf("LoadConversationSnapshots() = %+v, %v", snapshots, err)
+	}
+	if got := snapshots[0].LLM; got == nil || got.Provider != "anthropic" || got.Model != "claude-3-5-sonnet-latest" {
+		t.Fatalf("expected the snapshot to record the provider and model, got %+v", got)
+	}
+
+	reopened := New(Config{KnowledgeBasePath: m.config.KnowledgeBasePath}).(*model)
+	reopened.paper = m.paper
+	reopened.hydrateConversationHistory()
+	if hero := stripANSI(reopened.heroView()); !strings.Contains(hero, "Brief by: anthropic · claude-3-5-sonnet-latest") {
+		t.Fatalf("expected the hero to name the brief's model, got %q", hero)
+	}
+}
+
 func TestEnsureConversationSnapshotJobCreatesEntry(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "zettel.json")
 	paper := &arxiv.Paper{ID: "1234", Title: "Snapshot"}
 
-	runner := ensureConversationSnapshotJob(path, paper)
+	runner := ensureConversationSnapshotJob(notes.Store{Path: path}, paper)
 	if _, err := runner(context.Background()); err != nil {
 		t.Fatalf("ensureConversationSnapshotJob() error = %v", err)
 	}
@@ -146,7 +213,7 @@ func TestEnsureConversationSnapshotJobRejectsInvalidJSON(t *testing.T) {
 	}
 
 	paper := &arxiv.Paper{ID: "1234", Title: "Snapshot"}
-	runner := ensureConversationSnapshotJob(path, paper)
+	runner := ensureConversationSnapshotJob(notes.Store{Path: path}, paper)
 	if _, err := runner(context.Background()); err == nil {
 		t.Fatal("expected error for invalid JSON")
 	}
@@ -167,7 +234,7 @@ func TestAppendConversationSnapshotJobPersistsBriefUpdates(t *testing.T) {
 		},
 	}
 
-	runner := appendConversationSnapshotJob(path, paper, update)
+	runner := appendConversationSnapshotJob(notes.Store{Path: path}, paper, update)
 	if _, err := runner(context.Background()); err != nil {
 		t.Fatalf("appendConversationSnapshotJob() error = %v", err)
 	}
@@ -198,7 +265,7 @@ func TestAppendConversationSnapshotJobPersistsMessages(t *testing.T) {
 		},
 	}
 
-	runner := appendConversationSnapshotJob(path, paper, update)
+	runner := appendConversationSnapshotJob(notes.Store{Path: path}, paper, update)
 	if _, err := runner(context.Background()); err != nil {
 		t.Fatalf("appendConversationSnapshotJob() error = %v", err)
 	}
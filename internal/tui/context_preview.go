@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/reflow/wordwrap"
+
+	"github.com/csheth/browse/internal/llm"
+)
+
+// actionContextPreviewCmd opens an overlay showing the exact context a brief section is sent, so
+// the brief's grounding can be checked against the paper text.
+func (m *model) actionContextPreviewCmd(args string) tea.Cmd {
+	if m.paper == nil {
+		m.infoMessage = "Load a paper before previewing brief context."
+		return nil
+	}
+	kind := llm.BriefSummary
+	if strings.TrimSpace(args) != "" {
+		parsed, ok := parseBriefSectionName(args)
+		if !ok {
+			m.infoMessage = "Name a section: /context summary, /context technical, or /context deep-dive."
+			return nil
+		}
+		kind = parsed
+	}
+	m.errorsView = false
+	m.contextPreview = kind
+	m.infoMessage = fmt.Sprintf("Showing %s context. Esc closes the preview.", briefSectionTitle(kind))
+	m.markViewportDirty()
+	return nil
+}
+
+func (m *model) closeContextPreview() {
+	m.contextPreview = ""
+	m.infoMessage = "Context preview closed."
+	m.markViewportDirty()
+}
+
+// sectionContextChunks counts the paper chunks packed into a section's context. Chunks are split on
+// blank lines and joined with one, so the paragraphs of the context map one-to-one onto chunks.
+func (m *model) sectionContextChunks(kind llm.BriefSectionKind) int {
+	context := strings.TrimSpace(m.ensureBriefContexts()[kind])
+	if context == "" {
+		return 0
+	}
+	return len(strings.Split(context, "\n\n"))
+}
+
+// writeContextPreview replaces the conversation with the context for m.contextPreview.
+func (m *model) writeContextPreview(cb *contentBuilder) {
+	kind := m.contextPreview
+	context := m.contextForSection(kind)
+	cb.WriteString(sectionHeaderStyle.Render(fmt.Sprintf("%s context", briefSectionTitle(kind))))
+	cb.WriteRune('\n')
+	cb.WriteString(helperStyle.Render(fmt.Sprintf("%d characters · %d of %d chunks · Esc closes",
+		len([]rune(context)), m.sectionContextChunks(kind), len(m.briefChunks))))
+	cb.WriteRune('\n')
+	cb.WriteRune('\n')
+	if strings.TrimSpace(context) == "" {
+		cb.WriteString(helperStyle.Render("No paper text is available for this section."))
+		cb.WriteRune('\n')
+		return
+	}
+	cb.WriteString(indentMultiline(wordwrap.String(context, m.wrapWidth(4)), "  "))
+	cb.WriteRune('\n')
+}
+
+// paperContextText joins every cleaned, deduplicated chunk of the paper, the same text the brief
+// sections are cut from, for pasting into other tools.
+func (m *model) paperContextText() string {
+	m.ensureBriefContexts()
+	parts := make([]string, 0, len(m.briefChunks))
+	for _, chunk := range m.briefChunks {
+		parts = append(parts, chunk.Text)
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func (m *model) actionCopyPaperContextCmd(string) tea.Cmd {
+	if m.paper == nil {
+		m.infoMessage = "Load a paper before copying its text."
+		return nil
+	}
+	text := m.paperContextText()
+	if strings.TrimSpace(text) == "" {
+		m.infoMessage = "No paper text is available to copy."
+		return nil
+	}
+	osc52 := m.copyText(text)
+	m.errorMessage = ""
+	summary := fmt.Sprintf("%d characters in %d chunks", len([]rune(text)), len(m.briefChunks))
+	if osc52 != nil {
+		m.infoMessage = fmt.Sprintf("Paper text sent to the terminal clipboard (OSC 52): %s.", summary)
+		return osc52
+	}
+	m.infoMessage = fmt.Sprintf("Paper text copied to clipboard: %s.", summary)
+	return nil
+}
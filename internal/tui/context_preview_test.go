@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/llm"
+)
+
+func TestContextPreviewShowsTechnicalContextAndChunkCount(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{
+		ID:    "2101.00001",
+		Title: "Context Paper",
+		FullText: strings.Join([]string{
+			"We train a sparse transformer on long documents and compare it with dense attention baselines.",
+			"Our method uses block-sparse kernels with a learned routing table of 64 blocks per layer.",
+			"Experiments on three benchmarks show a 2.1x speedup with matching accuracy.",
+		}, "\n\n"),
+	}
+	m.stage = stageDisplay
+
+	m.composer.SetValue("/context technical")
+	if _, handled := m.runSlashCommand(m.composer.Value()); !handled {
+		t.Fatal("expected /context to be handled")
+	}
+	if m.contextPreview != llm.BriefTechnical {
+		t.Fatalf("expected technical preview, got %q", m.contextPreview)
+	}
+
+	view := m.buildDisplayContent().body
+	chunks := m.sectionContextChunks(llm.BriefTechnical)
+	if chunks != 3 {
+		t.Fatalf("expected all 3 chunks in the technical context, got %d", chunks)
+	}
+	for _, want := range []string{
+		"Technical context",
+		fmt.Sprintf("%d of %d chunks", chunks, len(m.briefChunks)),
+		"block-sparse kernels",
+		"Title: Context Paper",
+	} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected preview to contain %q, got:\n%s", want, view)
+		}
+	}
+
+	m.closeContextPreview()
+	if strings.Contains(m.buildDisplayContent().body, "Technical context") {
+		t.Fatal("expected preview closed")
+	}
+}
+
+func TestCopyPaperCopiesDeduplicatedChunks(t *testing.T) {
+	var copied string
+	originalClipboard := clipboardWrite
+	clipboardWrite = func(text string) error {
+		copied = text
+		return nil
+	}
+	t.Cleanup(func() { clipboardWrite = originalClipboard })
+
+	intro := "We train a sparse transformer on long documents and compare it with dense attention baselines."
+	method := "Our method uses block-sparse kernels with a learned routing table of 64 blocks per layer."
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{
+		ID:       "2101.00001",
+		Title:    "Context Paper",
+		FullText: strings.Join([]string{intro, "arXiv:2101.00001v1 [cs.LG] 1 Jan 2021", method, intro}, "\n\n"),
+	}
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/copy-paper")
+	m.submitComposer()
+
+	if want := intro + "\n\n" + method; copied != want {
+		t.Fatalf("expected deduplicated chunks on the clipboard, got %q", copied)
+	}
+	if copied == m.paper.FullText {
+		t.Fatal("expected cleaned text rather than the raw full text")
+	}
+	if !strings.Contains(m.infoMessage, "2 chunks") {
+		t.Fatalf("unexpected info message %q", m.infoMessage)
+	}
+}
@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+	briefctx "github.com/csheth/browse/internal/brief/context"
+	"github.com/csheth/browse/internal/llm"
+)
+
+var topLevelBulletRegexp = regexp.MustCompile(`^(?:[-*+•]|\d+[.)])\s+\S`)
+
+// countTopLevelBullets counts unindented list items across the section text, ignoring headings
+// and nested sub-bullets.
+func countTopLevelBullets(bullets []string) int {
+	count := 0
+	for _, bullet := range bullets {
+		for _, line := range strings.Split(bullet, "\n") {
+			if topLevelBulletRegexp.MatchString(line) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// maybeContinueTechnicalCmd tops up a technical section that came back with fewer than
+// Config.MinTechnicalBullets bullets. The continuation runs once per generation and draws on
+// technical chunks the first prompt did not include.
+func (m *model) maybeContinueTechnicalCmd(msg briefSectionMsg) tea.Cmd {
+	minimum := m.config.MinTechnicalBullets
+	if msg.kind != llm.BriefTechnical || msg.continued || minimum <= 0 || m.config.LLM == nil || m.paper == nil {
+		return nil
+	}
+	have := countTopLevelBullets(msg.bullets)
+	if have >= minimum {
+		return nil
+	}
+	m.ensureBriefContexts()
+	remainder := briefctx.TechnicalRemainder(m.briefChunks, m.briefContexts[llm.BriefTechnical], llm.BriefSectionLimit(llm.BriefTechnical), m.config.TokenEstimator)
+	if strings.TrimSpace(remainder) == "" {
+		return nil
+	}
+	m.markBriefSectionRunning(llm.BriefTechnical)
+	m.infoMessage = fmt.Sprintf("Technical section has %d of %d bullets; asking for more…", have, minimum)
+	job := briefContinuationJob(llm.BriefTechnical, msg.seq, m.config.LLM, m.paper, msg.bullets, remainder, minimum-have)
+	return tea.Batch(m.spinner.Tick, m.jobBus.Start(jobKindForSection(llm.BriefTechnical), job))
+}
+
+// briefContinuationJob appends the continuation bullets to existing. A failed continuation keeps
+// the original bullets rather than surfacing an error for a section that did generate.
+func briefContinuationJob(kind llm.BriefSectionKind, seq int, client llm.Client, paper *arxiv.Paper, existing []string, contextText string, missing int) jobRunner {
+	title := paper.Title
+	paperID := paper.ID
+	existing = append([]string(nil), existing...)
+	return func(parent context.Context) (tea.Msg, error) {
+		ctx, cancel := context.WithTimeout(parent, 2*time.Minute)
+		defer cancel()
+		extra, err := client.ContinueBriefSection(ctx, kind, title, contextText, existing, missing)
+		if err != nil {
+			return briefSectionMsg{paperID: paperID, kind: kind, seq: seq, bullets: existing, continued: true}, nil
+		}
+		return briefSectionMsg{paperID: paperID, kind: kind, seq: seq, bullets: append(existing, extra...), continued: true}, nil
+	}
+}
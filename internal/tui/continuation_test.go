@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/llm"
+)
+
+type continuationLLM struct {
+	fakeLLM
+	missing *int
+	content *string
+}
+
+func (f continuationLLM) ContinueBriefSection(ctx context.Context, kind llm.BriefSectionKind, title, content string, existing []string, missing int) ([]string, error) {
+	*f.missing = missing
+	*f.content = content
+	return []string{"- Uses block-sparse kernels", "- Trains for 100k steps"}, nil
+}
+
+func TestTechnicalSectionContinuesToMinimumBullets(t *testing.T) {
+	var missing int
+	var content string
+	m := newTestModel(t)
+	m.config.LLM = continuationLLM{missing: &missing, content: &content}
+	m.config.MinTechnicalBullets = 3
+	m.paper = &arxiv.Paper{
+		ID:    "2101.00001",
+		Title: "Dense Paper",
+		FullText: strings.Join([]string{
+			"We propose a sparse attention method for long documents and evaluate it on three benchmarks.",
+			"The architecture uses block-sparse kernels with 64 blocks per layer and a learned router.",
+			"Training runs for 100k steps with a learning rate of 3e-4 on 8 GPUs.",
+		}, "\n\n"),
+	}
+	m.stage = stageDisplay
+	first := []string{"### Technical\n- Sparse attention over long documents"}
+
+	if cmd := m.handleBriefSectionResult(briefSectionMsg{paperID: m.paper.ID, kind: llm.BriefTechnical, bullets: first}); cmd == nil {
+		t.Fatal("expected a continuation command")
+	}
+	if !m.briefSections[llm.BriefTechnical].Loading {
+		t.Fatal("expected technical section to be continuing")
+	}
+
+	m.ensureBriefContexts()
+	job := briefContinuationJob(llm.BriefTechnical, 0, m.config.LLM, m.paper, first, m.contextForSection(llm.BriefTechnical), 2)
+	msg, err := job(context.Background())
+	if err != nil {
+		t.Fatalf("continuation job error = %v", err)
+	}
+	if missing != 2 {
+		t.Fatalf("expected continuation to ask for 2 more bullets, got %d", missing)
+	}
+	m.handleBriefSectionResult(msg.(briefSectionMsg))
+
+	if got := countTopLevelBullets(m.brief.Technical); got != 3 {
+		t.Fatalf("expected technical section filled to 3 bullets, got %d: %q", got, m.brief.Technical)
+	}
+	state := m.briefSections[llm.BriefTechnical]
+	if state.Loading || !state.Completed {
+		t.Fatalf("expected technical section complete without another continuation, got %#v", state)
+	}
+	if body := m.transcriptEntries[m.briefMessageIndex[llm.BriefTechnical]].Content; !strings.Contains(body, "Trains for 100k steps") {
+		t.Fatalf("expected continued bullets in the transcript, got %q", body)
+	}
+}
+
+func TestTechnicalSectionSkipsContinuationWhenDisabled(t *testing.T) {
+	m := newTestModel(t)
+	m.config.LLM = fakeLLM{}
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Dense Paper", FullText: "Some technical text about kernels and training."}
+	m.handleBriefSectionResult(briefSectionMsg{paperID: m.paper.ID, kind: llm.BriefTechnical, bullets: []string{"- one"}})
+	if m.briefSections[llm.BriefTechnical].Loading {
+		t.Fatal("expected no continuation without a minimum")
+	}
+}
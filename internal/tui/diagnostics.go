@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/version"
+)
+
+// diagnosticsText summarizes where PaperScout keeps its files and which model it talks to, so bug
+// reports can include the essentials without digging through flags and environment variables.
+func (m *model) diagnosticsText() string {
+	provider := "not configured"
+	if m.config.LLM != nil {
+		provider = m.config.LLM.Name()
+	}
+	path := m.knowledgeBasePath()
+	knowledgeBase := fmt.Sprintf("`%s`", path)
+	if info, err := os.Stat(path); err == nil {
+		knowledgeBase += fmt.Sprintf(" (%s)", formatByteSize(info.Size()))
+	} else if os.IsNotExist(err) {
+		knowledgeBase += " (not created yet)"
+	}
+	var cached string
+	if count, size, err := arxiv.CacheUsage(); err == nil {
+		cached = fmt.Sprintf("%d (%s)", count, formatByteSize(size))
+	} else {
+		cached = fmt.Sprintf("unavailable: %v", err)
+	}
+	lines := []string{
+		fmt.Sprintf("- Version: %s", version.String()),
+		fmt.Sprintf("- LLM: %s", provider),
+		fmt.Sprintf("- Knowledge base: %s", knowledgeBase),
+		fmt.Sprintf("- PDF cache: `%s`", arxiv.CacheDir()),
+		fmt.Sprintf("- Cached PDFs: %s", cached),
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *model) slashDiagnosticsCmd(string) tea.Cmd {
+	m.appendTranscript("diagnostics", m.diagnosticsText())
+	m.infoMessage = "Diagnostics listed in the transcript."
+	return nil
+}
+
+func formatByteSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiagnosticsTextIncludesPathsAndProvider(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("PAPERSCOUT_CACHE_DIR", cacheDir)
+	m := newTestModel(t)
+	m.config.KnowledgeBasePath = filepath.Join(t.TempDir(), "zettel.json")
+	m.config.LLM = fakeLLM{}
+
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/diag")
+	m.submitComposer()
+	if len(m.transcriptEntries) == 0 {
+		t.Fatal("expected /diag to post diagnostics")
+	}
+	got := m.transcriptEntries[len(m.transcriptEntries)-1].Content
+	for _, want := range []string{
+		cacheDir,
+		m.config.KnowledgeBasePath,
+		fakeLLM{}.Name(),
+		"Cached PDFs: 0 (0 B)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected diagnostics to contain %q, got:\n%s", want, got)
+		}
+	}
+}
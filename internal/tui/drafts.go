@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handleDraftNoteKey moves the draft-note selection with Ctrl+Up/Down and the selected note itself
+// with Alt+Up/Down. The order of m.manualNotes is the order notes are saved in, so reordering
+// only needs to swap slice entries.
+func (m *model) handleDraftNoteKey(key tea.KeyMsg) bool {
+	if len(m.manualNotes) == 0 {
+		return false
+	}
+	switch {
+	case key.Type == tea.KeyCtrlUp:
+		m.selectDraftNote(-1)
+	case key.Type == tea.KeyCtrlDown:
+		m.selectDraftNote(1)
+	case key.Type == tea.KeyUp && key.Alt:
+		m.moveDraftNote(-1)
+	case key.Type == tea.KeyDown && key.Alt:
+		m.moveDraftNote(1)
+	default:
+		return false
+	}
+	m.markViewportDirty()
+	return true
+}
+
+func (m *model) selectDraftNote(delta int) {
+	m.draftCursor = clampIndex(m.draftCursor+delta, len(m.manualNotes))
+	m.infoMessage = fmt.Sprintf("Draft note %d of %d selected.", m.draftCursor+1, len(m.manualNotes))
+}
+
+func (m *model) moveDraftNote(delta int) {
+	from := clampIndex(m.draftCursor, len(m.manualNotes))
+	to := clampIndex(from+delta, len(m.manualNotes))
+	if from == to {
+		m.draftCursor = from
+		m.infoMessage = "Draft note is already at that end of the list."
+		return
+	}
+	m.manualNotes[from], m.manualNotes[to] = m.manualNotes[to], m.manualNotes[from]
+	m.draftCursor = to
+	m.infoMessage = fmt.Sprintf("Moved draft note to position %d of %d.", to+1, len(m.manualNotes))
+}
+
+func clampIndex(index, length int) int {
+	if index < 0 || length == 0 {
+		return 0
+	}
+	if index >= length {
+		return length - 1
+	}
+	return index
+}
+
+// writeDraftNotes lists unsaved manual notes in save order below the conversation, marking the one
+// the reorder keys act on.
+func (m *model) writeDraftNotes(cb *contentBuilder) {
+	if len(m.manualNotes) == 0 {
+		return
+	}
+	cursor := clampIndex(m.draftCursor, len(m.manualNotes))
+	cb.WriteRune('\n')
+	cb.WriteString(helperStyle.Render("Drafted notes · Ctrl+↑/↓ select · Alt+↑/↓ reorder"))
+	cb.WriteRune('\n')
+	for idx, note := range m.manualNotes {
+		line := fmt.Sprintf("%d. %s", idx+1, previewText(note.Title, m.wrapWidth(8)))
+		if idx == cursor {
+			cb.WriteString("  " + currentLineStyle.Render("› "+line))
+		} else {
+			cb.WriteString("    " + line)
+		}
+		cb.WriteRune('\n')
+	}
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+)
+
+func TestDraftNotesReorderBeforeSave(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Reorder Paper"}
+	m.stage = stageDisplay
+	for _, body := range []string{"First note", "Second note", "Third note"} {
+		m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+		m.composer.SetValue(body)
+		if _, handled := m.processComposerKey(tea.KeyMsg{Type: tea.KeyCtrlJ}); !handled {
+			t.Fatalf("expected Ctrl+Enter to draft %q", body)
+		}
+	}
+	if m.draftCursor != 2 {
+		t.Fatalf("expected newest draft selected, got %d", m.draftCursor)
+	}
+
+	// Move "Third" to the top, then select "First" and push it to the bottom.
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyUp, Alt: true},
+		{Type: tea.KeyUp, Alt: true},
+		{Type: tea.KeyCtrlDown},
+		{Type: tea.KeyDown, Alt: true},
+	} {
+		if _, handled := m.processComposerKey(key); !handled {
+			t.Fatalf("expected %q to be handled", key.String())
+		}
+	}
+
+	got := m.collectSelectedNotes()
+	want := []string{"Third note", "Second note", "First note"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d notes, got %d", len(want), len(got))
+	}
+	for idx, note := range got {
+		if note.Body != want[idx] {
+			t.Fatalf("expected note %d to be %q, got %q", idx, want[idx], note.Body)
+		}
+	}
+
+	view := m.buildDisplayContent().body
+	if third, first := strings.Index(view, "1. Third note"), strings.Index(view, "3. First note"); third < 0 || first < third {
+		t.Fatalf("expected drafted notes rendered in save order, got:\n%s", view)
+	}
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+// actionShowErrorsCmd replaces the conversation with every error posted to the transcript, so a
+// failure the status line has since overwritten can still be read.
+func (m *model) actionShowErrorsCmd(string) tea.Cmd {
+	m.contextPreview = ""
+	m.errorsView = true
+	m.markErrorsSeen()
+	m.infoMessage = "Showing the error history. Esc closes it."
+	m.markViewportDirty()
+	m.viewport.SetYOffset(0)
+	return nil
+}
+
+func (m *model) closeErrorsView() {
+	m.errorsView = false
+	m.infoMessage = "Error history closed."
+	m.markViewportDirty()
+}
+
+// errorEntries returns the transcript's error entries, oldest first.
+func (m *model) errorEntries() []transcriptEntry {
+	var entries []transcriptEntry
+	for _, entry := range m.transcriptEntries {
+		if entry.Kind == "error" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// markErrorsSeen clears the status bar badge up to the newest error posted so far.
+func (m *model) markErrorsSeen() {
+	for _, entry := range m.errorEntries() {
+		if entry.Timestamp.After(m.errorsSeenAt) {
+			m.errorsSeenAt = entry.Timestamp
+		}
+	}
+}
+
+// unseenErrorCount counts errors posted since the error history was last opened. Errors restored
+// from an earlier session predate errorsSeenAt, which starts at launch, so they never count.
+func (m *model) unseenErrorCount() int {
+	count := 0
+	for _, entry := range m.errorEntries() {
+		if entry.Timestamp.After(m.errorsSeenAt) {
+			count++
+		}
+	}
+	return count
+}
+
+// errorBadge is the status bar notice for unseen errors, or "" when there are none.
+func (m *model) errorBadge() string {
+	switch count := m.unseenErrorCount(); count {
+	case 0:
+		return ""
+	case 1:
+		return "⚠ 1 new error (/errors)"
+	default:
+		return fmt.Sprintf("⚠ %d new errors (/errors)", count)
+	}
+}
+
+// writeErrorsView lists each error with the time it was posted.
+func (m *model) writeErrorsView(cb *contentBuilder) {
+	entries := m.errorEntries()
+	cb.WriteString(sectionHeaderStyle.Render("Errors"))
+	cb.WriteRune('\n')
+	cb.WriteString(helperStyle.Render(fmt.Sprintf("%d in this conversation · Esc closes", len(entries))))
+	cb.WriteRune('\n')
+	cb.WriteRune('\n')
+	if len(entries) == 0 {
+		cb.WriteString(helperStyle.Render("No errors so far."))
+		cb.WriteRune('\n')
+		return
+	}
+	for idx, entry := range entries {
+		if idx > 0 {
+			cb.WriteRune('\n')
+		}
+		cb.WriteString(errorStyle.Render(entry.Timestamp.Format("15:04:05")))
+		cb.WriteRune('\n')
+		cb.WriteString(indentMultiline(wordwrap.String(strings.TrimSpace(entry.Content), m.wrapWidth(4)), "  "))
+		cb.WriteRune('\n')
+	}
+}
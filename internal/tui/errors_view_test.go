@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+)
+
+func TestErrorsViewListsEveryFailure(t *testing.T) {
+	m := newTestModel(t)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 16})
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Errors Paper", FullText: "Body text."}
+	m.stage = stageDisplay
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+
+	m.Update(suggestionResultMsg{paperID: "2101.00001", err: errors.New("rate limited")})
+	m.Update(gapResultMsg{paperID: "2101.00001", err: errors.New("context too long")})
+	if strings.Contains(m.errorMessage, "rate limited") {
+		t.Fatalf("expected the second failure to replace the first on the status line, got %q", m.errorMessage)
+	}
+	if footer := stripANSI(m.footerTickerView()); !strings.Contains(footer, "2 new errors") {
+		t.Fatalf("expected an unseen error badge, got %q", footer)
+	}
+
+	if _, handled := m.runSlashCommand("/errors"); !handled {
+		t.Fatal("expected /errors to be handled")
+	}
+	view := stripANSI(m.buildDisplayContent().body)
+	first := strings.Index(view, "Suggestion failed: rate limited")
+	second := strings.Index(view, "Note gap analysis failed: context too long")
+	if first < 0 || second < first {
+		t.Fatalf("expected both failures in order, got:\n%s", view)
+	}
+	if !strings.Contains(view, "2 in this conversation") {
+		t.Fatalf("expected the error count, got:\n%s", view)
+	}
+	if footer := stripANSI(m.footerTickerView()); strings.Contains(footer, "new error") {
+		t.Fatalf("expected the badge cleared once the errors were shown, got %q", footer)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.errorsView {
+		t.Fatal("expected Esc to close the error history")
+	}
+	m.Update(suggestionResultMsg{paperID: "2101.00001", err: errors.New("timeout")})
+	if footer := stripANSI(m.footerTickerView()); !strings.Contains(footer, "1 new error ") {
+		t.Fatalf("expected only the later failure badged, got %q", footer)
+	}
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/llm"
+)
+
+// parseBriefSectionName maps user-typed section names ("technical", "deep-dive", …) to a kind.
+func parseBriefSectionName(name string) (llm.BriefSectionKind, bool) {
+	normalized := strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+	switch normalized {
+	case "summary", "sum":
+		return llm.BriefSummary, true
+	case "technical", "tech":
+		return llm.BriefTechnical, true
+	case "deepdive", "deep", "dive":
+		return llm.BriefDeepDive, true
+	default:
+		return "", false
+	}
+}
+
+// briefSectionCommandName is the section name shown in /brief and /more hints.
+func briefSectionCommandName(kind llm.BriefSectionKind) string {
+	switch kind {
+	case llm.BriefTechnical:
+		return "technical"
+	case llm.BriefDeepDive:
+		return "deep-dive"
+	default:
+		return "summary"
+	}
+}
+
+// actionExpandBriefSectionCmd regenerates one brief section with a prompt asking for more depth,
+// leaving the other sections untouched.
+func (m *model) actionExpandBriefSectionCmd(args string) tea.Cmd {
+	kind, ok := parseBriefSectionName(args)
+	if !ok {
+		m.infoMessage = "Name a section to expand: /more summary, /more technical, or /more deep-dive."
+		return nil
+	}
+	if m.paper == nil {
+		m.infoMessage = "Load a paper before expanding the brief."
+		return nil
+	}
+	return m.regenerateBriefSectionCmd(kind, llm.DetailExpanded)
+}
+
+// actionBriefCmd runs /brief: with no argument it rebuilds the whole brief, and with a section name
+// it regenerates just that section at the usual level of detail.
+func (m *model) actionBriefCmd(args string) tea.Cmd {
+	if strings.TrimSpace(args) == "" {
+		return m.actionSummarizeCmd()
+	}
+	kind, ok := parseBriefSectionName(args)
+	if !ok {
+		m.infoMessage = "Unknown section. Use /brief, or /brief summary, /brief technical, or /brief deep-dive."
+		return nil
+	}
+	if m.paper == nil {
+		m.infoMessage = "Load a paper before summarizing."
+		return nil
+	}
+	return m.regenerateBriefSectionCmd(kind, llm.DetailStandard)
+}
+
+func (m *model) regenerateBriefSectionCmd(kind llm.BriefSectionKind, detail llm.DetailLevel) tea.Cmd {
+	if m.config.LLM == nil {
+		m.infoMessage = m.llmUnavailableNotice("Configure Ollama via flags to enable summaries.")
+		return nil
+	}
+	if notice := m.paper.Notice(); notice != "" {
+		m.infoMessage = notice
+		return nil
+	}
+	if strings.TrimSpace(m.paper.FullText) == "" {
+		m.infoMessage = "PDF text missing; cannot build the reading brief."
+		return nil
+	}
+	title := briefSectionTitle(kind)
+	// launchBriefSection cancels a run already in flight, so asking again restarts the section.
+	switch {
+	case m.briefSections[kind].Loading:
+		m.infoMessage = fmt.Sprintf("Restarting %s…", title)
+	case detail == llm.DetailExpanded:
+		m.infoMessage = fmt.Sprintf("Regenerating %s with more detail…", title)
+	default:
+		m.infoMessage = fmt.Sprintf("Regenerating %s…", title)
+	}
+	m.infoMessage += fmt.Sprintf(" Sending %s.", m.briefContextEstimate(kind))
+	m.markViewportDirty()
+	return tea.Batch(m.spinner.Tick, m.launchBriefSection(kind, detail))
+}
@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/llm"
+)
+
+type detailRecordingLLM struct {
+	fakeLLM
+	detail  *llm.DetailLevel
+	content *string
+}
+
+func (f detailRecordingLLM) StreamBriefSection(ctx context.Context, kind llm.BriefSectionKind, title, content string, detail llm.DetailLevel, handler llm.BriefSectionStreamHandler) error {
+	*f.detail = detail
+	*f.content = content
+	return handler(llm.BriefSectionDelta{Kind: kind, Bullets: []string{"- deeper bullet"}, Done: true})
+}
+
+func TestSlashMoreRegeneratesOneSectionWithDetail(t *testing.T) {
+	m := newTestModel(t)
+	m.config.LLM = fakeLLM{}
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Terse Paper", FullText: "full text"}
+	m.briefContexts = map[llm.BriefSectionKind]string{llm.BriefTechnical: "cached technical context"}
+	m.stage = stageDisplay
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/more technical")
+
+	if cmd := m.submitComposer(); cmd == nil {
+		t.Fatal("expected /more to launch a section job")
+	}
+	if !m.briefSections[llm.BriefTechnical].Loading {
+		t.Fatal("expected technical section to be regenerating")
+	}
+	for _, kind := range []llm.BriefSectionKind{llm.BriefSummary, llm.BriefDeepDive} {
+		if m.briefSections[kind].Loading {
+			t.Fatalf("expected %s section to be left alone", kind)
+		}
+		if _, ok := m.briefStreamCancels[kind]; ok {
+			t.Fatalf("expected no stream for %s", kind)
+		}
+	}
+	if !strings.Contains(m.infoMessage, "more detail") {
+		t.Fatalf("unexpected info message %q", m.infoMessage)
+	}
+
+	var detail llm.DetailLevel
+	var content string
+	client := detailRecordingLLM{detail: &detail, content: &content}
+	runner, _ := briefSectionJob(llm.BriefTechnical, 0, llm.DetailExpanded, m.contextForSection(llm.BriefTechnical), client, m.paper, context.Background(), time.Minute, nil)
+	if _, err := runner(context.Background()); err != nil {
+		t.Fatalf("runner error = %v", err)
+	}
+	if detail != llm.DetailExpanded {
+		t.Fatalf("expected expanded detail, got %v", detail)
+	}
+	if !strings.Contains(content, "cached technical context") {
+		t.Fatalf("expected cached section context to be reused, got %q", content)
+	}
+}
+
+func TestSlashBriefRestartsOnlyTheNamedSection(t *testing.T) {
+	m := newTestModel(t)
+	m.config.LLM = fakeLLM{}
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Terse Paper", FullText: "full text"}
+	m.stage = stageDisplay
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/brief deep-dive")
+
+	if cmd := m.submitComposer(); cmd == nil {
+		t.Fatal("expected /brief deep-dive to launch a section job")
+	}
+	for _, kind := range []llm.BriefSectionKind{llm.BriefSummary, llm.BriefTechnical} {
+		if m.briefSections[kind].Loading {
+			t.Fatalf("expected %s section to be left alone", kind)
+		}
+	}
+	if !m.briefSections[llm.BriefDeepDive].Loading {
+		t.Fatal("expected deep dive section to be regenerating")
+	}
+	if !strings.Contains(m.infoMessage, "Sending 3/10k tokens.") {
+		t.Fatalf("expected the context estimate in %q", m.infoMessage)
+	}
+
+	cancelled := false
+	m.briefStreamCancels[llm.BriefDeepDive] = func() { cancelled = true }
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/brief deep-dive")
+	if cmd := m.submitComposer(); cmd == nil {
+		t.Fatal("expected a second /brief deep-dive to restart the section")
+	}
+	if !cancelled {
+		t.Fatal("expected the in-flight deep dive stream to be cancelled")
+	}
+	if !strings.Contains(m.infoMessage, "Restarting") {
+		t.Fatalf("unexpected info message %q", m.infoMessage)
+	}
+
+	m.handleBriefSectionResult(briefSectionMsg{paperID: m.paper.ID, kind: llm.BriefDeepDive, err: context.Canceled})
+	if state := m.briefSections[llm.BriefDeepDive]; !state.Loading || state.Error != "" {
+		t.Fatalf("expected the superseded run's result to be ignored, got %+v", state)
+	}
+}
+
+func TestRestartedSectionDropsStaleStreamDeltas(t *testing.T) {
+	m := newTestModel(t)
+	m.config.LLM = fakeLLM{}
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Terse Paper", FullText: "full text"}
+	m.stage = stageDisplay
+
+	m.launchBriefSection(llm.BriefDeepDive, llm.DetailStandard)
+	stale := m.briefStreamSeqs[llm.BriefDeepDive]
+	m.launchBriefSection(llm.BriefDeepDive, llm.DetailStandard)
+	current := m.briefStreamSeqs[llm.BriefDeepDive]
+	if current == stale {
+		t.Fatal("expected the restart to get a new stream sequence")
+	}
+
+	old := briefSectionStreamMsg{paperID: m.paper.ID, kind: llm.BriefDeepDive, seq: stale, bullets: []string{"- Old run"}, updates: make(chan llm.BriefSectionDelta)}
+	if cmd := m.handleBriefSectionStream(old); cmd != nil {
+		t.Fatal("expected no further reads from the superseded stream")
+	}
+	if len(m.brief.DeepDive) != 0 {
+		t.Fatalf("expected the stale delta dropped, got %v", m.brief.DeepDive)
+	}
+
+	m.handleBriefSectionStream(briefSectionStreamMsg{paperID: m.paper.ID, kind: llm.BriefDeepDive, seq: current, bullets: []string{"- New run"}, done: true})
+	if len(m.brief.DeepDive) != 1 || m.brief.DeepDive[0] != "- New run" {
+		t.Fatalf("expected the restarted stream applied, got %v", m.brief.DeepDive)
+	}
+}
+
+func TestRestartedSectionDropsStaleResults(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		stale briefSectionMsg
+	}{
+		{"success", briefSectionMsg{bullets: []string{"- Old run"}}},
+		{"partial timeout", briefSectionMsg{bullets: []string{"- Old run"}, partial: true}},
+		{"failure", briefSectionMsg{err: errors.New("old run failed")}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newTestModel(t)
+			m.config.LLM = fakeLLM{}
+			m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Terse Paper", FullText: "full text"}
+			m.stage = stageDisplay
+
+			m.launchBriefSection(llm.BriefTechnical, llm.DetailStandard)
+			stale := tc.stale
+			stale.paperID, stale.kind, stale.seq = m.paper.ID, llm.BriefTechnical, m.briefStreamSeqs[llm.BriefTechnical]
+			m.launchBriefSection(llm.BriefTechnical, llm.DetailStandard)
+
+			if cmd := m.handleBriefSectionResult(stale); cmd != nil {
+				t.Fatal("expected no snapshot from the superseded run")
+			}
+			if state := m.briefSections[llm.BriefTechnical]; !state.Loading || state.Error != "" || state.Partial {
+				t.Fatalf("expected the restarted section still loading, got %+v", state)
+			}
+			if len(m.brief.Technical) != 0 || m.errorMessage != "" {
+				t.Fatalf("expected the stale result ignored, got bullets %v error %q", m.brief.Technical, m.errorMessage)
+			}
+		})
+	}
+}
+
+func TestSlashMoreRequiresKnownSection(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Terse Paper", FullText: "full text"}
+	if cmd := m.actionExpandBriefSectionCmd("appendix"); cmd != nil {
+		t.Fatalf("expected nil command, got %T", cmd)
+	}
+	if !strings.Contains(m.infoMessage, "/more technical") {
+		t.Fatalf("unexpected info message %q", m.infoMessage)
+	}
+}
@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/export"
+	"github.com/csheth/browse/internal/llm"
+	"github.com/csheth/browse/internal/notes"
+)
+
+type exportResultMsg struct {
+	// what is "brief" or "transcript".
+	what string
+	path string
+	err  error
+}
+
+func (m *model) briefExport() export.Brief {
+	brief := export.Brief{
+		Summary:   append([]string(nil), m.brief.Summary...),
+		Technical: append([]string(nil), m.brief.Technical...),
+		DeepDive:  append([]string(nil), m.brief.DeepDive...),
+		Guide:     m.guide,
+	}
+	if m.paper != nil {
+		brief.PaperID = m.paper.ID
+		brief.SourceName = m.paper.SourceName()
+		brief.URL = m.paper.AbsURL()
+		brief.Title = m.paper.Title
+		brief.Authors = append([]string(nil), m.paper.Authors...)
+	}
+	return brief
+}
+
+func (m *model) briefExportMarkdown() string {
+	return export.BriefMarkdown(m.briefExport(), export.Options{IncludeGuide: m.config.ExportIncludeGuide})
+}
+
+// briefClipboardMarkdown renders the brief like /export but fills sections the LLM has not
+// produced yet with the abstract-based fallbacks shown on screen, so a copy never comes out blank.
+func (m *model) briefClipboardMarkdown() string {
+	brief := m.briefExport()
+	for _, section := range []struct {
+		kind    llm.BriefSectionKind
+		bullets *[]string
+	}{
+		{llm.BriefSummary, &brief.Summary},
+		{llm.BriefTechnical, &brief.Technical},
+		{llm.BriefDeepDive, &brief.DeepDive},
+	} {
+		if len(*section.bullets) == 0 {
+			*section.bullets = append([]string(nil), m.fallbackForSection(section.kind)...)
+		}
+	}
+	return export.BriefMarkdown(brief, export.Options{})
+}
+
+// briefExportPath places exports next to the knowledge base so notes and reading plans travel
+// together, naming the file from Config.ExportFileTemplate.
+func (m *model) briefExportPath() string {
+	return m.exportPath(m.config.ExportFileTemplate)
+}
+
+// transcriptExportPath names transcript exports from Config.TranscriptFileTemplate, falling back to
+// export.DefaultTranscriptTemplate.
+func (m *model) transcriptExportPath() string {
+	template := m.config.TranscriptFileTemplate
+	if strings.TrimSpace(template) == "" {
+		template = export.DefaultTranscriptTemplate
+	}
+	return m.exportPath(template)
+}
+
+func (m *model) exportPath(template string) string {
+	fields := export.FileNameFields{Date: time.Now()}
+	if m.paper != nil {
+		fields.ID = m.paper.ID
+		fields.Title = m.paper.Title
+	}
+	name := export.RenderFileName(template, fields)
+	return filepath.Join(filepath.Dir(m.knowledgeBasePath()), name)
+}
+
+// transcriptExportMarkdown renders every transcript entry, in the order it was added, under the
+// speaker label the conversation shows.
+func (m *model) transcriptExportMarkdown() string {
+	brief := m.briefExport()
+	transcript := export.Transcript{
+		PaperID:    brief.PaperID,
+		SourceName: brief.SourceName,
+		URL:        brief.URL,
+		Title:      brief.Title,
+		Authors:    brief.Authors,
+	}
+	for _, entry := range m.transcriptEntries {
+		transcript.Entries = append(transcript.Entries, export.TranscriptEntry{
+			Label:     transcriptLabel(entry.Kind),
+			Timestamp: entry.Timestamp,
+			Content:   entry.Content,
+		})
+	}
+	return export.TranscriptMarkdown(transcript)
+}
+
+// actionExportTranscriptCmd writes the whole conversation to a Markdown file next to the knowledge
+// base.
+func (m *model) actionExportTranscriptCmd(string) tea.Cmd {
+	if m.paper == nil {
+		m.infoMessage = "Load a paper before exporting the transcript."
+		return nil
+	}
+	if len(m.transcriptEntries) == 0 {
+		m.infoMessage = "The conversation is empty; nothing to export."
+		return nil
+	}
+	path := m.transcriptExportPath()
+	m.infoMessage = fmt.Sprintf("Exporting transcript to %s…", path)
+	return m.jobBus.Start(jobKindExport, exportTranscriptJob(path, m.transcriptExportMarkdown()))
+}
+
+// knowledgeBasePath is where notes are saved and snapshots are read from; New resolves it once so
+// every flow agrees on the file.
+func (m *model) knowledgeBasePath() string {
+	return m.config.KnowledgeBasePath
+}
+
+// knowledgeBase is the store every note and snapshot read and write goes through.
+func (m *model) knowledgeBase() notes.Store {
+	return notes.Store{Path: m.config.KnowledgeBasePath, Format: m.config.KnowledgeBaseFormat}
+}
+
+// resolveKnowledgeBasePath returns the configured path, or notes.DefaultPath when the program was
+// started without one.
+func resolveKnowledgeBasePath(path string) string {
+	if path = strings.TrimSpace(path); path != "" {
+		return path
+	}
+	return notes.DefaultPath()
+}
+
+func (m *model) actionExportBriefCmd() tea.Cmd {
+	if m.paper == nil {
+		m.infoMessage = "Load a paper before exporting the brief."
+		return nil
+	}
+	path := m.briefExportPath()
+	m.infoMessage = fmt.Sprintf("Exporting brief to %s…", path)
+	return m.jobBus.Start(jobKindExport, exportBriefJob(path, m.briefExportMarkdown()))
+}
+
+// actionCopyBriefCmd copies the reading brief to the clipboard as Markdown.
+func (m *model) actionCopyBriefCmd(string) tea.Cmd {
+	if m.paper == nil {
+		m.infoMessage = "Load a paper before copying the brief."
+		return nil
+	}
+	osc52 := m.copyText(m.briefClipboardMarkdown())
+	m.errorMessage = ""
+	if osc52 != nil {
+		m.infoMessage = "Reading brief sent to the terminal clipboard (OSC 52)."
+		return osc52
+	}
+	m.infoMessage = "Reading brief copied to clipboard."
+	return nil
+}
+
+func (m *model) handleExportResult(msg exportResultMsg) tea.Cmd {
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("Export failed: %v", msg.err)
+		m.appendTranscript("error", m.errorMessage)
+		return nil
+	}
+	what := msg.what
+	if what == "" {
+		what = "brief"
+	}
+	m.errorMessage = ""
+	m.infoMessage = fmt.Sprintf("%s%s exported to %s", strings.ToUpper(what[:1]), what[1:], msg.path)
+	m.appendTranscript("export", fmt.Sprintf("Exported %s to %s", what, msg.path))
+	return nil
+}
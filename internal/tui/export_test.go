@@ -0,0 +1,220 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/guide"
+	"github.com/csheth/browse/internal/llm"
+	"github.com/csheth/browse/internal/notes"
+)
+
+func TestBriefExportMarkdownHonorsGuideFlag(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Guided Paper"}
+	m.guide = guide.Build(guide.Metadata{Title: m.paper.Title})
+	m.brief.Summary = []string{"- Summary bullet"}
+
+	if got := m.briefExportMarkdown(); strings.Contains(got, m.guide[0].Title) {
+		t.Fatalf("expected guide omitted without flag, got:\n%s", got)
+	}
+
+	m.config.ExportIncludeGuide = true
+	got := m.briefExportMarkdown()
+	for _, step := range m.guide {
+		if !strings.Contains(got, step.Title) {
+			t.Fatalf("expected guide step %q in export, got:\n%s", step.Title, got)
+		}
+	}
+}
+
+func TestSlashExportWritesBriefNextToKnowledgeBase(t *testing.T) {
+	dir := t.TempDir()
+	m := newTestModel(t)
+	m.config.KnowledgeBasePath = filepath.Join(dir, "zettel.json")
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Export Paper"}
+	m.stage = stageDisplay
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/export")
+
+	cmd, handled := m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if !handled || cmd == nil {
+		t.Fatal("expected /export to start an export job")
+	}
+	if len(m.qaHistory) != 0 {
+		t.Fatalf("slash command should not be sent as a question, got %d entries", len(m.qaHistory))
+	}
+
+	path := filepath.Join(dir, "2101.00001-brief.md")
+	msg, err := exportBriefJob(path, m.briefExportMarkdown())(context.Background())
+	if err != nil {
+		t.Fatalf("exportBriefJob() error = %v", err)
+	}
+	m.handleExportResult(msg.(exportResultMsg))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !strings.Contains(string(data), "# Export Paper") {
+		t.Fatalf("unexpected export contents: %s", data)
+	}
+	if !strings.Contains(m.infoMessage, path) {
+		t.Fatalf("expected info message to mention %s, got %q", path, m.infoMessage)
+	}
+}
+
+func TestSlashExportTranscriptWritesConversationInOrder(t *testing.T) {
+	dir := t.TempDir()
+	m := newTestModel(t)
+	m.config.KnowledgeBasePath = filepath.Join(dir, "zettel.json")
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Chatty Paper"}
+	m.stage = stageDisplay
+	m.appendTranscript("summary", "- Routing halves compute")
+	m.appendTranscript("question", "Which datasets were used?")
+	m.appendTranscript("answer", "ImageNet and COCO.")
+	m.appendTranscript("note", "Check the COCO ablation.")
+
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/export-transcript")
+	runJobs(m, m.submitComposer())
+
+	path := filepath.Join(dir, "2101.00001-transcript.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	text := string(data)
+	order := []string{"# Chatty Paper", "Routing halves compute", "### You ·", "Which datasets were used?", "### Scout ·", "ImageNet and COCO.", "### You (note) ·", "Check the COCO ablation."}
+	last := -1
+	for _, want := range order {
+		index := strings.Index(text, want)
+		if index <= last {
+			t.Fatalf("expected %q after position %d in transcript:\n%s", want, last, text)
+		}
+		last = index
+	}
+	if !strings.Contains(m.infoMessage, "Transcript exported to "+path) {
+		t.Fatalf("unexpected info message %q", m.infoMessage)
+	}
+}
+
+func TestUnknownSlashCommandReportsHelp(t *testing.T) {
+	m := newTestModel(t)
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/bogus")
+
+	if cmd := m.submitComposer(); cmd != nil {
+		t.Fatalf("expected nil command, got %T", cmd)
+	}
+	if !strings.Contains(m.infoMessage, "Unknown command /bogus") {
+		t.Fatalf("unexpected info message %q", m.infoMessage)
+	}
+	if m.composer.Value() != "/bogus" {
+		t.Fatalf("expected the mistyped command kept in the composer, got %q", m.composer.Value())
+	}
+}
+
+func TestSlashPrefixedProseIsSubmittedAsText(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Paths", FullText: "Body."}
+	m.stage = stageDisplay
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/usr/lib path breaks the build")
+	m.submitComposer()
+
+	if len(m.manualNotes) != 1 || m.manualNotes[0].Body != "/usr/lib path breaks the build" {
+		t.Fatalf("expected the text kept as a note, got %+v (info %q)", m.manualNotes, m.infoMessage)
+	}
+
+	if name, args, ok := parseSlashCommand("/search\nattention heads"); !ok || name != "search" || args != "attention heads" {
+		t.Fatalf("expected the name to end at the line break, got %q %q %v", name, args, ok)
+	}
+}
+
+func TestSaveAndExportHonorDefaultKnowledgeBasePath(t *testing.T) {
+	dir := t.TempDir()
+	kbPath := filepath.Join(dir, "notes", "kb.json")
+	t.Setenv(notes.DefaultPathEnvVar, kbPath)
+	m := New(Config{}).(*model)
+	m.config.ExportFileTemplate = "{date}-{title}"
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Default Paths"}
+	m.manualNotes = []notes.Note{{PaperID: m.paper.ID, Title: "Draft", Body: "Body"}}
+
+	if cmd := m.actionSaveCmd(); cmd == nil {
+		t.Fatal("expected save job")
+	}
+	if !strings.Contains(m.infoMessage, kbPath) {
+		t.Fatalf("expected save to target %s, got %q", kbPath, m.infoMessage)
+	}
+	if _, err := saveNotesJob(m.knowledgeBase(), m.manualNotes)(context.Background()); err != nil {
+		t.Fatalf("saveNotesJob() error = %v", err)
+	}
+	if _, err := os.Stat(kbPath); err != nil {
+		t.Fatalf("expected knowledge base at default path: %v", err)
+	}
+
+	want := filepath.Join(dir, "notes", time.Now().Format("2006-01-02")+"-Default_Paths.md")
+	if got := m.briefExportPath(); got != want {
+		t.Fatalf("expected export path %s, got %s", want, got)
+	}
+}
+
+func TestDefaultKnowledgeBaseSavedNotesAreFoundOnReopen(t *testing.T) {
+	kbPath := filepath.Join(t.TempDir(), "kb.json")
+	t.Setenv(notes.DefaultPathEnvVar, kbPath)
+	paper := &arxiv.Paper{ID: "2101.00001", Title: "Default Paths"}
+
+	m := New(Config{}).(*model)
+	m.paper = paper
+	m.manualNotes = []notes.Note{{PaperID: paper.ID, Title: "Draft", Body: "Body"}}
+	if _, err := saveNotesJob(m.knowledgeBase(), m.manualNotes)(context.Background()); err != nil {
+		t.Fatalf("saveNotesJob() error = %v", err)
+	}
+
+	reopened := New(Config{}).(*model)
+	reopened.paper = paper
+	reopened.refreshPersistedState()
+	if len(reopened.persistedNotes) != 1 || reopened.persistedNotes[0].Body != "Body" {
+		t.Fatalf("expected saved note visible on reopen, got %+v", reopened.persistedNotes)
+	}
+}
+
+func TestCopyBriefKeyCopiesAllSectionsWithFallbacks(t *testing.T) {
+	var copied string
+	originalClipboard := clipboardWrite
+	clipboardWrite = func(text string) error {
+		copied = text
+		return nil
+	}
+	t.Cleanup(func() { clipboardWrite = originalClipboard })
+
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Copied Paper", Abstract: "We propose a method. It beats the baseline."}
+	m.stage = stageDisplay
+	m.prepareBriefFallbacks()
+	m.brief.Technical = []string{"- Streamed technical bullet"}
+	m.composer.Blur()
+
+	m.handleDisplayKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	for _, heading := range []string{"## Summary", "## Technical", "## Deep Dive"} {
+		if !strings.Contains(copied, heading) {
+			t.Fatalf("expected %q in copied brief, got:\n%s", heading, copied)
+		}
+	}
+	if !strings.Contains(copied, "Streamed technical bullet") {
+		t.Fatalf("expected generated bullets in copied brief, got:\n%s", copied)
+	}
+	if summary := m.fallbackForSection(llm.BriefSummary); len(summary) == 0 || !strings.Contains(copied, strings.TrimPrefix(summary[0], "- ")) {
+		t.Fatalf("expected the fallback summary in copied brief, got:\n%s", copied)
+	}
+	if m.infoMessage != "Reading brief copied to clipboard." {
+		t.Fatalf("unexpected info message %q", m.infoMessage)
+	}
+}
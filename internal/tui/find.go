@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// actionFindCmd opens the composer to search the conversation on screen. Unlike /search, which
+// looks through the knowledge base, matches are highlighted in place and n/N step through them.
+func (m *model) actionFindCmd() tea.Cmd {
+	m.composer.SetValue("")
+	m.setComposerMode(composerModeFind, composerFindPlaceholder, true)
+	m.infoMessage = "Find in the conversation. Enter highlights matches, Esc cancels."
+	m.markViewportDirty()
+	return nil
+}
+
+// runFind highlights every line matching query and scrolls to the first one. The composer is
+// blurred afterwards so n/N reach the display keys.
+func (m *model) runFind(query string) {
+	m.composer.SetValue("")
+	m.blurComposer()
+	m.findQuery = query
+	m.findCursor = 0
+	m.refreshViewport()
+	if len(m.findMatches) == 0 {
+		m.clearFind()
+		m.infoMessage = fmt.Sprintf("No matches for %q.", query)
+		return
+	}
+	m.moveFindMatch(0)
+}
+
+// moveFindMatch steps delta matches forward (or back), wrapping around, and scrolls the viewport
+// so the match is the top line.
+func (m *model) moveFindMatch(delta int) {
+	if len(m.findMatches) == 0 {
+		m.infoMessage = fmt.Sprintf("No matches for %q.", m.findQuery)
+		return
+	}
+	count := len(m.findMatches)
+	m.findCursor = ((m.findCursor+delta)%count + count) % count
+	line := m.findMatches[m.findCursor]
+	m.cursorLine = line
+	m.viewport.SetYOffset(m.clampYOffset(line))
+	m.infoMessage = fmt.Sprintf("Match %d of %d for %q. n/N move, Esc clears.", m.findCursor+1, count, m.findQuery)
+	m.markViewportDirty()
+}
+
+// clearFind drops the query and its highlights.
+func (m *model) clearFind() {
+	m.findQuery = ""
+	m.findMatches = nil
+	m.findCursor = 0
+	m.markViewportDirty()
+}
+
+// findPattern matches query literally, ignoring case.
+func findPattern(query string) *regexp.Regexp {
+	return regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
+}
+
+// findMatchLines returns the indices of lines whose visible text contains a match.
+func findMatchLines(lines []string, pattern *regexp.Regexp) []int {
+	var matches []int
+	for idx, line := range lines {
+		if pattern.MatchString(stripANSI(line)) {
+			matches = append(matches, idx)
+		}
+	}
+	return matches
+}
+
+// highlightFindMatches returns body with each match styled; the current match gets the cursor
+// style. Highlights go into the styled lines, so colors and links around a match survive.
+func (m *model) highlightFindMatches(lines []string) string {
+	pattern := findPattern(m.findQuery)
+	m.findMatches = findMatchLines(lines, pattern)
+	if len(m.findMatches) == 0 {
+		return strings.Join(lines, "\n")
+	}
+	m.findCursor = clampIndex(m.findCursor, len(m.findMatches))
+	highlighted := append([]string(nil), lines...)
+	for idx, line := range m.findMatches {
+		style := findMatchStyle
+		if idx == m.findCursor {
+			style = currentLineStyle
+		}
+		open, close := styleBounds(style)
+		highlighted[line] = highlightLine(lines[line], pattern, open, close)
+	}
+	return strings.Join(highlighted, "\n")
+}
+
+// styleBounds splits what style renders around a text into the escapes before and after it.
+func styleBounds(style lipgloss.Style) (string, string) {
+	rendered := style.Render("x")
+	idx := strings.Index(rendered, "x")
+	if idx < 0 {
+		return "", ""
+	}
+	return rendered[:idx], rendered[idx+1:]
+}
+
+// highlightLine wraps each match of pattern in the visible text of line with open and close. The
+// line's own escapes are kept: links pass through untouched, color codes inside a match are held
+// back so the highlight stays whole, and the codes in effect are replayed after each match so the
+// rest of the line keeps its styling. Only escape sequences are inserted, so the line strips to
+// the same text.
+func highlightLine(line string, pattern *regexp.Regexp, open, close string) string {
+	var matches [][]int
+	for _, match := range pattern.FindAllStringIndex(stripANSI(line), -1) {
+		if match[1] > match[0] {
+			matches = append(matches, match)
+		}
+	}
+	if len(matches) == 0 {
+		return line
+	}
+	var b strings.Builder
+	var active []string
+	pos, next, inMatch := 0, 0, false
+	writeText := func(text string) {
+		for {
+			if inMatch && pos == matches[next][1] {
+				b.WriteString(close)
+				b.WriteString(strings.Join(active, ""))
+				inMatch = false
+				next++
+			}
+			if text == "" {
+				return
+			}
+			if !inMatch && next < len(matches) && pos == matches[next][0] {
+				b.WriteString(open)
+				inMatch = true
+			}
+			n := len(text)
+			if inMatch {
+				n = min(n, matches[next][1]-pos)
+			} else if next < len(matches) {
+				n = min(n, matches[next][0]-pos)
+			}
+			b.WriteString(text[:n])
+			pos += n
+			text = text[n:]
+		}
+	}
+	last := 0
+	for _, loc := range ansiEscapeCodes.FindAllStringIndex(line, -1) {
+		writeText(line[last:loc[0]])
+		last = loc[1]
+		escape := line[loc[0]:loc[1]]
+		if !strings.HasSuffix(escape, "m") || !strings.HasPrefix(escape, "\x1b[") {
+			b.WriteString(escape)
+			continue
+		}
+		if escape == "\x1b[0m" || escape == "\x1b[m" {
+			active = nil
+		} else {
+			active = append(active, escape)
+		}
+		if !inMatch {
+			b.WriteString(escape)
+		}
+	}
+	writeText(line[last:])
+	return b.String()
+}
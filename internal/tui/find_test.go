@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+)
+
+func TestFindHighlightsMatchingLinesAndSteps(t *testing.T) {
+	m := newTestModel(t)
+	m.config.AutoBrief = false
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 16})
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: &arxiv.Paper{ID: "2101.00001", Title: "Findable", Abstract: "We study routing.", FullText: "Body."}})
+	var bullets []string
+	for i := 0; i < 12; i++ {
+		word := "filler"
+		if i%2 == 0 {
+			word = "Gradient"
+		}
+		bullets = append(bullets, fmt.Sprintf("- %s point %d", word, i))
+	}
+	m.setBriefMessage(briefSectionKinds[0], briefMessageContent(briefSectionKinds[0], bullets))
+	m.markViewportDirty()
+	m.refreshViewportIfDirty()
+
+	var want []int
+	for idx, line := range m.viewportLines {
+		if strings.Contains(strings.ToLower(stripANSI(line)), "gradient") {
+			want = append(want, idx)
+		}
+	}
+	if len(want) < 3 {
+		t.Fatalf("expected the fixture to render several matching lines, got %v", want)
+	}
+
+	m.composer.Blur()
+	m.handleDisplayKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if m.composerMode != composerModeFind {
+		t.Fatalf("expected / to open the find prompt, got mode %v", m.composerMode)
+	}
+	m.composer.SetValue("gRaDiEnT")
+	m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if !reflect.DeepEqual(m.findMatches, want) {
+		t.Fatalf("expected matches on lines %v, got %v", want, m.findMatches)
+	}
+	if m.viewport.YOffset != m.clampYOffset(want[0]) {
+		t.Fatalf("expected the first match scrolled into view, offset %d", m.viewport.YOffset)
+	}
+	if got := stripANSI(strings.Join(m.viewportLines, "\n")); got != stripANSI(m.viewportContent) {
+		t.Fatal("expected highlights to leave the selectable lines unchanged")
+	}
+
+	m.handleDisplayKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m.cursorLine != want[1] {
+		t.Fatalf("expected n to move to line %d, got %d", want[1], m.cursorLine)
+	}
+	m.handleDisplayKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	m.handleDisplayKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	if m.cursorLine != want[len(want)-1] {
+		t.Fatalf("expected N to wrap to the last match at %d, got %d", want[len(want)-1], m.cursorLine)
+	}
+
+	m.handleDisplayKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.findQuery != "" || m.findMatches != nil {
+		t.Fatalf("expected Esc to clear the find, got %q %v", m.findQuery, m.findMatches)
+	}
+}
+
+func TestFindIsClearedWhenAnotherPaperLoads(t *testing.T) {
+	m := newTestModel(t)
+	m.config.AutoBrief = false
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 16})
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: &arxiv.Paper{ID: "2101.00001", Title: "Findable", Abstract: "We study routing.", FullText: "Body."}})
+	m.runFind("routing")
+	if m.findQuery == "" {
+		t.Fatal("expected the fixture to match")
+	}
+
+	m.actionLoadNewCmd()
+	if m.findQuery != "" || m.findMatches != nil {
+		t.Fatalf("expected a new tab to start without a find, got %q %v", m.findQuery, m.findMatches)
+	}
+	m.switchSession(-1)
+	if m.findQuery != "" {
+		t.Fatalf("expected switching tabs to clear the find, got %q", m.findQuery)
+	}
+
+	m.runFind("routing")
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: &arxiv.Paper{ID: "2101.00002", Title: "Other", Abstract: "Routing again.", FullText: "Body."}})
+	if m.findQuery != "" || m.findMatches != nil {
+		t.Fatalf("expected loading a paper to clear the find, got %q %v", m.findQuery, m.findMatches)
+	}
+}
+
+func TestHighlightLineKeepsStylingAndLinks(t *testing.T) {
+	line := "\x1b[1mSee \x1b]8;;https://arxiv.org\x1b\\gradient\x1b]8;;\x1b\\ descent\x1b[0m"
+	got := highlightLine(line, findPattern("GRADIENT"), "<", ">")
+	want := "\x1b[1mSee \x1b]8;;https://arxiv.org\x1b\\<gradient>\x1b[1m\x1b]8;;\x1b\\ descent\x1b[0m"
+	if got != want {
+		t.Fatalf("highlightLine() = %q, want %q", got, want)
+	}
+
+	split := "gra\x1b[31mdient\x1b[0m and gradient"
+	got = highlightLine(split, findPattern("gradient"), "<", ">")
+	want = "<gradient>\x1b[31m\x1b[0m and <gradient>"
+	if got != want {
+		t.Fatalf("highlightLine() = %q, want %q", got, want)
+	}
+}
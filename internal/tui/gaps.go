@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/notes"
+)
+
+type gapResultMsg struct {
+	paperID     string
+	suggestions []notes.Candidate
+	err         error
+}
+
+// actionNoteGapsCmd compares the saved notes for the open paper against its text and asks the LLM
+// for the insights those notes have not captured yet.
+func (m *model) actionNoteGapsCmd() tea.Cmd {
+	if m.paper == nil {
+		m.infoMessage = "Load a paper before comparing notes."
+		return nil
+	}
+	if m.config.LLM == nil {
+		m.infoMessage = m.llmUnavailableNotice("Configure Ollama to unlock note gap analysis.")
+		return nil
+	}
+	if strings.TrimSpace(m.paper.FullText) == "" {
+		m.infoMessage = "PDF text missing; cannot compare notes against the paper."
+		return nil
+	}
+	if len(m.persistedNotes) == 0 {
+		m.infoMessage = "Save a few notes for this paper before asking for gaps."
+		return nil
+	}
+	if m.suggestionLoading {
+		m.infoMessage = "Note gap analysis already running…"
+		return nil
+	}
+	m.suggestionLoading = true
+	m.infoMessage = fmt.Sprintf("Comparing %d saved notes against the paper…", len(m.persistedNotes))
+	return tea.Batch(m.spinner.Tick, m.jobBus.Start(jobKindGaps, noteGapsJob(m.config.LLM, m.paper, m.persistedNotes)))
+}
+
+func (m *model) handleGapResult(msg gapResultMsg) tea.Cmd {
+	if m.paper == nil || m.paper.ID != msg.paperID {
+		return nil
+	}
+	m.suggestionLoading = false
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("gap analysis error: %v", msg.err)
+		m.appendTranscript("error", fmt.Sprintf("Note gap analysis failed: %v", msg.err))
+		return nil
+	}
+	m.errorMessage = ""
+	if len(msg.suggestions) == 0 {
+		m.infoMessage = "Your notes already cover this paper."
+		m.appendTranscript("gaps", "No gaps found; your saved notes already cover the paper.")
+		return nil
+	}
+	m.infoMessage = fmt.Sprintf("%d suggested notes for gaps in your notes.", len(msg.suggestions))
+	m.appendTranscript("gaps", formatGapSuggestions(msg.suggestions))
+	return nil
+}
+
+func formatGapSuggestions(candidates []notes.Candidate) string {
+	lines := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		line := fmt.Sprintf("- **%s** (%s): %s", strings.TrimSpace(candidate.Title), candidate.Kind, strings.TrimSpace(candidate.Body))
+		if reason := strings.TrimSpace(candidate.Reason); reason != "" {
+			line += fmt.Sprintf("\n  - _Why:_ %s", reason)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
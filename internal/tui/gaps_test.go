@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/llm"
+	"github.com/csheth/browse/internal/notes"
+)
+
+type gapLLM struct {
+	fakeLLM
+	existing []llm.SuggestedNote
+}
+
+func (g *gapLLM) NoteGaps(ctx context.Context, title string, existing []llm.SuggestedNote, content string) ([]llm.SuggestedNote, error) {
+	g.existing = existing
+	return []llm.SuggestedNote{{Title: "Ablation gap", Body: "The ablation drops the cache.", Reason: "Notes skip the ablations.", Kind: "result"}}, nil
+}
+
+func TestSlashGapsLaunchesJobFromPersistedNotes(t *testing.T) {
+	client := &gapLLM{}
+	m := newTestModel(t)
+	m.config.LLM = client
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Gap Paper", FullText: "Full paper text."}
+	m.stage = stageDisplay
+	m.persistedNotes = []notes.Note{
+		{PaperID: "2101.00001", Title: "Core idea", Body: "Local attention windows.", Kind: "method"},
+	}
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/gaps")
+
+	cmd, handled := m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if !handled || cmd == nil {
+		t.Fatal("expected /gaps to start a gap analysis job")
+	}
+	if !m.suggestionLoading {
+		t.Fatal("expected gap analysis to mark loading state")
+	}
+
+	msg, err := noteGapsJob(client, m.paper, m.persistedNotes)(context.Background())
+	if err != nil {
+		t.Fatalf("noteGapsJob() error = %v", err)
+	}
+	if len(client.existing) != 1 || client.existing[0].Title != "Core idea" {
+		t.Fatalf("expected persisted notes forwarded to the LLM, got %+v", client.existing)
+	}
+	m.handleGapResult(msg.(gapResultMsg))
+	if m.suggestionLoading {
+		t.Fatal("expected loading state cleared")
+	}
+	last := m.transcriptEntries[len(m.transcriptEntries)-1]
+	if last.Kind != "gaps" || !strings.Contains(last.Content, "**Ablation gap** (result)") || !strings.Contains(last.Content, "Notes skip the ablations.") {
+		t.Fatalf("unexpected gap transcript entry: %+v", last)
+	}
+}
+
+func TestSlashGapsRequiresSavedNotes(t *testing.T) {
+	m := newTestModel(t)
+	m.config.LLM = fakeLLM{}
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Gap Paper", FullText: "Full paper text."}
+
+	if cmd := m.actionNoteGapsCmd(); cmd != nil {
+		t.Fatalf("expected nil command, got %T", cmd)
+	}
+	if want, got := "Save a few notes for this paper before asking for gaps.", m.infoMessage; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
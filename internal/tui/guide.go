@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/guide"
+)
+
+// actionRebuildGuideCmd recomputes the reading guide from the current paper metadata. An argument
+// switches the guide's focus subject for the rest of the session; "/guide reset" drops it.
+func (m *model) actionRebuildGuideCmd(args string) tea.Cmd {
+	if m.paper == nil {
+		m.infoMessage = "Load a paper before rebuilding the guide."
+		return nil
+	}
+	switch focus := strings.TrimSpace(args); {
+	case strings.EqualFold(focus, "reset"):
+		m.guideFocus = ""
+	case focus != "":
+		m.guideFocus = focus
+	}
+	meta := guideMetadata(m.paper, m.guideFocus)
+	steps, changed := guide.Rebuild(m.guide, meta)
+	m.guide = steps
+	m.appendTranscript("guide", formatGuideSteps(steps))
+	switch {
+	case !changed:
+		m.infoMessage = "Reading guide already up to date."
+	case meta.Subject != "":
+		m.infoMessage = fmt.Sprintf("Reading guide rebuilt for %s.", meta.Subject)
+	default:
+		m.infoMessage = "Reading guide rebuilt."
+	}
+	return nil
+}
+
+func formatGuideSteps(steps []guide.Step) string {
+	lines := make([]string, 0, len(steps))
+	for idx, step := range steps {
+		lines = append(lines, fmt.Sprintf("%d. **%s** – %s", idx+1, step.Title, step.Description))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/guide"
+)
+
+func TestRebuildGuideReflectsUpdatedSubject(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Guided Paper", PrimaryCategory: "cs.LG"}
+	m.guide = guide.Build(guideMetadata(m.paper, ""))
+	if !strings.Contains(m.guide[0].Description, "within cs.LG") {
+		t.Fatalf("expected primary category in initial guide, got %q", m.guide[0].Description)
+	}
+
+	m.paper.KeyContributions = []string{"A sparse router.", "A new benchmark"}
+	if cmd := m.actionRebuildGuideCmd("stat.ML"); cmd != nil {
+		t.Fatalf("expected nil command, got %T", cmd)
+	}
+	if !strings.Contains(m.guide[0].Description, "within stat.ML") || strings.Contains(m.guide[0].Description, "cs.LG") {
+		t.Fatalf("expected rebuilt guide to use new focus, got %q", m.guide[0].Description)
+	}
+	if !strings.Contains(m.guide[1].Description, "A sparse router; A new benchmark") {
+		t.Fatalf("expected contributions in pass 2, got %q", m.guide[1].Description)
+	}
+	if want := "Reading guide rebuilt for stat.ML."; m.infoMessage != want {
+		t.Fatalf("expected %q, got %q", want, m.infoMessage)
+	}
+	last := m.transcriptEntries[len(m.transcriptEntries)-1]
+	if last.Kind != "guide" || !strings.Contains(last.Content, "1. **Pass 1 – Quick skim**") {
+		t.Fatalf("unexpected guide transcript entry: %+v", last)
+	}
+
+	m.actionRebuildGuideCmd("")
+	if want := "Reading guide already up to date."; m.infoMessage != want {
+		t.Fatalf("expected %q, got %q", want, m.infoMessage)
+	}
+	m.actionRebuildGuideCmd("reset")
+	if !strings.Contains(m.guide[0].Description, "within cs.LG") {
+		t.Fatalf("expected reset to restore primary category, got %q", m.guide[0].Description)
+	}
+}
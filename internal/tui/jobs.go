@@ -2,9 +2,11 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
-	"os"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -24,12 +26,18 @@ const (
 	jobKindSave           jobKind = "save"
 	jobKindZettel         jobKind = "zettel"
 	jobKindQuestion       jobKind = "question"
+	jobKindExport         jobKind = "export"
+	jobKindBriefCache     jobKind = "brief_cache"
+	jobKindGaps           jobKind = "gaps"
+	jobKindOpenPDF        jobKind = "open_pdf"
+	jobKindFullText       jobKind = "full_text"
 )
 
 const (
 	jobStatusRunning   jobStatus = "running"
 	jobStatusSucceeded jobStatus = "succeeded"
 	jobStatusFailed    jobStatus = "failed"
+	jobStatusCanceled  jobStatus = "canceled"
 )
 
 type jobSnapshot struct {
@@ -55,10 +63,19 @@ type jobRunner func(context.Context) (tea.Msg, error)
 
 type jobBus struct {
 	counter int64
+
+	mu sync.Mutex
+	// running holds the cancel func of every started job that has not finished, keyed by job ID.
+	running map[string]runningJob
+}
+
+type runningJob struct {
+	kind   jobKind
+	cancel context.CancelFunc
 }
 
 func newJobBus() *jobBus {
-	return &jobBus{}
+	return &jobBus{running: map[string]runningJob{}}
 }
 
 func (b *jobBus) nextID(kind jobKind) string {
@@ -66,8 +83,14 @@ func (b *jobBus) nextID(kind jobKind) string {
 	return fmt.Sprintf("%s-%d", kind, idx)
 }
 
+// Start runs runner under a context that Cancel(kind) ends. The context is created here rather than
+// when the job runs, so a job cancelled before its goroutine is scheduled still stops.
 func (b *jobBus) Start(kind jobKind, runner jobRunner) tea.Cmd {
 	id := b.nextID(kind)
+	ctx, cancel := context.WithCancel(context.Background())
+	b.mu.Lock()
+	b.running[id] = runningJob{kind: kind, cancel: cancel}
+	b.mu.Unlock()
 	started := time.Now()
 	startSnapshot := jobSnapshot{ID: id, Kind: kind, Status: jobStatusRunning, StartedAt: started}
 	startCmd := func() tea.Msg {
@@ -75,30 +98,198 @@ func (b *jobBus) Start(kind jobKind, runner jobRunner) tea.Cmd {
 	}
 
 	runCmd := func() tea.Msg {
-		ctx := context.Background()
 		payload, err := runner(ctx)
+		b.finish(id)
 		snapshot := jobSnapshot{
 			ID:          id,
 			Kind:        kind,
 			StartedAt:   started,
 			CompletedAt: time.Now(),
 		}
-		if err != nil {
+		switch {
+		case err != nil && errors.Is(err, context.Canceled):
+			snapshot.Status = jobStatusCanceled
+			snapshot.Err = err.Error()
+		case err != nil:
 			snapshot.Status = jobStatusFailed
 			snapshot.Err = err.Error()
-		} else {
+		default:
 			snapshot.Status = jobStatusSucceeded
 		}
 		snapshot.Duration = snapshot.CompletedAt.Sub(started)
-		if shouldLogJobs() {
-			log.Printf("[jobs] %s %s (duration=%s, err=%v)", kind, snapshot.Status, snapshot.Duration, err)
-		}
+		logJob(snapshot)
 		return jobResultEnvelope{Snapshot: snapshot, Payload: payload}
 	}
 
 	return tea.Sequence(startCmd, runCmd)
 }
 
-func shouldLogJobs() bool {
-	return os.Getenv("PAPERSCOUT_DEBUG") != ""
+// Cancel ends the context of every running job of kind and reports how many there were. The
+// runners still return (usually with context.Canceled), so their results arrive as usual.
+func (b *jobBus) Cancel(kind jobKind) int {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	canceled := 0
+	for id, job := range b.running {
+		if job.kind != kind {
+			continue
+		}
+		job.cancel()
+		delete(b.running, id)
+		canceled++
+	}
+	return canceled
+}
+
+func (b *jobBus) finish(id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if job, ok := b.running[id]; ok {
+		job.cancel()
+		delete(b.running, id)
+	}
+}
+
+// withStop derives a context from parent that also ends when stop does. Jobs whose model keeps its
+// own cancel func (a superseded stream or fetch) use it so jobBus.Cancel stops them too.
+func withStop(parent, stop context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	unregister := context.AfterFunc(stop, cancel)
+	// AfterFunc calls cancel on its own goroutine, so an already-stopped context is handled here.
+	if stop.Err() != nil {
+		cancel()
+	}
+	return ctx, func() {
+		unregister()
+		cancel()
+	}
+}
+
+// jobLimiter caps how many jobs sharing it run at once. Slots are reserved when a job is launched
+// and granted in that order, so queued jobs start in the order they were requested no matter which
+// goroutine gets scheduled first.
+type jobLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	running int
+	queue   []*jobTicket
+}
+
+// jobTicket is one reserved slot; ready is closed once the job may run.
+type jobTicket struct {
+	limiter *jobLimiter
+	ready   chan struct{}
+	granted bool
+}
+
+// newJobLimiter allows limit concurrent jobs; limit < 1 is treated as 1.
+func newJobLimiter(limit int) *jobLimiter {
+	if limit < 1 {
+		limit = 1
+	}
+	return &jobLimiter{limit: limit}
+}
+
+// reserve queues a ticket behind every earlier reservation. Call it synchronously when the job is
+// launched, not from the job's goroutine, so the launch order is what decides the run order.
+func (l *jobLimiter) reserve() *jobTicket {
+	ticket := &jobTicket{limiter: l, ready: make(chan struct{})}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.queue = append(l.queue, ticket)
+	l.grantLocked()
+	return ticket
+}
+
+func (l *jobLimiter) grantLocked() {
+	for l.running < l.limit && len(l.queue) > 0 {
+		next := l.queue[0]
+		l.queue = l.queue[1:]
+		next.granted = true
+		l.running++
+		close(next.ready)
+	}
+}
+
+// wait blocks until the ticket's slot is free. When ctx ends first the reservation is dropped (or
+// the slot handed on, if it was granted meanwhile) and ctx's error returned. A nil ticket never
+// waits.
+func (t *jobTicket) wait(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	select {
+	case <-t.ready:
+		return nil
+	case <-ctx.Done():
+	}
+	l := t.limiter
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t.granted {
+		t.granted = false
+		l.running--
+		l.grantLocked()
+		return ctx.Err()
+	}
+	for i, queued := range l.queue {
+		if queued == t {
+			l.queue = append(l.queue[:i], l.queue[i+1:]...)
+			break
+		}
+	}
+	return ctx.Err()
+}
+
+// release frees the slot for the next queued job. It is safe to call on a nil ticket, on one whose
+// wait failed, and more than once.
+func (t *jobTicket) release() {
+	if t == nil {
+		return
+	}
+	l := t.limiter
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !t.granted {
+		return
+	}
+	t.granted = false
+	l.running--
+	l.grantLocked()
+}
+
+// jobLog receives one line per finished job; nil (the default) keeps jobs silent, since anything
+// written to stderr would draw over the TUI.
+var jobLog atomic.Pointer[log.Logger]
+
+// SetJobLog sends a key=value line for every finished job (kind, id, status, duration, and error)
+// to w. A nil w turns job logging off.
+func SetJobLog(w io.Writer) {
+	if w == nil {
+		jobLog.Store(nil)
+		return
+	}
+	jobLog.Store(log.New(w, "", 0))
+}
+
+func logJob(snapshot jobSnapshot) {
+	logger := jobLog.Load()
+	if logger == nil {
+		return
+	}
+	logger.Print(formatJobLogLine(snapshot))
+}
+
+// formatJobLogLine renders a finished job as
+// time=… kind=fetch id=fetch-1 status=succeeded duration_ms=1200, adding err="…" on failure.
+func formatJobLogLine(snapshot jobSnapshot) string {
+	line := fmt.Sprintf("time=%s kind=%s id=%s status=%s duration_ms=%d",
+		snapshot.CompletedAt.UTC().Format(time.RFC3339Nano), snapshot.Kind, snapshot.ID, snapshot.Status, snapshot.Duration.Milliseconds())
+	if snapshot.Err != "" {
+		line += fmt.Sprintf(" err=%q", snapshot.Err)
+	}
+	return line
 }
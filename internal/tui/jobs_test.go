@@ -0,0 +1,226 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/llm"
+)
+
+// countingStreamLLM records how many brief sections stream at once and the order they start in.
+type countingStreamLLM struct {
+	fakeLLM
+	running *int32
+	peak    *int32
+	mu      *sync.Mutex
+	order   *[]llm.BriefSectionKind
+}
+
+func (c countingStreamLLM) StreamBriefSection(ctx context.Context, kind llm.BriefSectionKind, title, content string, detail llm.DetailLevel, handler llm.BriefSectionStreamHandler) error {
+	now := atomic.AddInt32(c.running, 1)
+	defer atomic.AddInt32(c.running, -1)
+	for {
+		seen := atomic.LoadInt32(c.peak)
+		if now <= seen || atomic.CompareAndSwapInt32(c.peak, seen, now) {
+			break
+		}
+	}
+	c.mu.Lock()
+	*c.order = append(*c.order, kind)
+	c.mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	return handler(llm.BriefSectionDelta{Kind: kind, Bullets: []string{"- Bullet"}, Done: true})
+}
+
+func TestBriefSectionJobsRespectConcurrencyLimit(t *testing.T) {
+	paper := &arxiv.Paper{ID: "2101.00001", Title: "Busy GPU", FullText: "Some paper text."}
+	for _, limit := range []int{1, 2, 3} {
+		var running, peak int32
+		var mu sync.Mutex
+		var order []llm.BriefSectionKind
+		client := countingStreamLLM{running: &running, peak: &peak, mu: &mu, order: &order}
+		limiter := newJobLimiter(limit)
+
+		var runners []jobRunner
+		for _, kind := range briefSectionKinds {
+			runner, updates := briefSectionJob(kind, 0, llm.DetailStandard, "Some paper text.", client, paper, context.Background(), time.Minute, limiter.reserve())
+			go func() {
+				for range updates {
+				}
+			}()
+			runners = append(runners, runner)
+		}
+		// Start the goroutines in reverse so only the reservation order can explain the run order.
+		var wg sync.WaitGroup
+		for i := len(runners) - 1; i >= 0; i-- {
+			wg.Add(1)
+			go func(runner jobRunner) {
+				defer wg.Done()
+				if _, err := runner(context.Background()); err != nil {
+					t.Errorf("runner error = %v", err)
+				}
+			}(runners[i])
+		}
+		wg.Wait()
+
+		if got := atomic.LoadInt32(&peak); got > int32(limit) {
+			t.Fatalf("limit %d: %d sections ran at once", limit, got)
+		}
+		if len(order) != len(briefSectionKinds) {
+			t.Fatalf("limit %d: expected every section to run, got %v", limit, order)
+		}
+		if limit == 1 {
+			for i, kind := range briefSectionKinds {
+				if order[i] != kind {
+					t.Fatalf("expected Summary → Technical → Deep Dive, got %v", order)
+				}
+			}
+		}
+	}
+}
+
+func TestJobLimiterSkipsCanceledReservations(t *testing.T) {
+	limiter := newJobLimiter(1)
+	first, queued, last := limiter.reserve(), limiter.reserve(), limiter.reserve()
+	if err := first.wait(context.Background()); err != nil {
+		t.Fatalf("first wait error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := queued.wait(ctx); err == nil {
+		t.Fatal("expected the canceled reservation to give up")
+	}
+	queued.release()
+	first.release()
+
+	waitCtx, stop := context.WithTimeout(context.Background(), time.Second)
+	defer stop()
+	if err := last.wait(waitCtx); err != nil {
+		t.Fatalf("expected the slot to pass over the canceled reservation, got %v", err)
+	}
+	last.release()
+}
+
+func TestBriefConcurrencyDefaultsByProvider(t *testing.T) {
+	if got := briefConcurrency(Config{LLM: fakeLLM{}}); got != 3 {
+		t.Fatalf("expected 3 concurrent sections for cloud providers, got %d", got)
+	}
+	if got := briefConcurrency(Config{LLM: fakeLLM{}, BriefConcurrency: 2}); got != 2 {
+		t.Fatalf("expected the configured limit, got %d", got)
+	}
+	ollama, err := llm.NewFromEnv(llm.Config{Provider: llm.ProviderOllama})
+	if err != nil {
+		t.Fatalf("NewFromEnv() error = %v", err)
+	}
+	if got := briefConcurrency(Config{LLM: ollama}); got != 1 {
+		t.Fatalf("expected Ollama to generate one section at a time, got %d", got)
+	}
+}
+
+func TestJobBusLogsFinishedJobAsKeyValues(t *testing.T) {
+	var buf bytes.Buffer
+	SetJobLog(&buf)
+	defer SetJobLog(nil)
+
+	runJobs(newTestModel(t), newJobBus().Start(jobKindSave, func(context.Context) (tea.Msg, error) {
+		return nil, nil
+	}))
+
+	line := strings.TrimSpace(buf.String())
+	for _, field := range []string{"kind=save", "id=save-1", "status=succeeded", "duration_ms="} {
+		if !strings.Contains(line, field) {
+			t.Fatalf("expected %q in job log line %q", field, line)
+		}
+	}
+	if !strings.HasPrefix(line, "time=") || strings.Contains(line, "err=") || strings.Count(line, "\n") != 0 {
+		t.Fatalf("unexpected job log line %q", line)
+	}
+}
+
+func TestJobBusCancelStopsRunningJob(t *testing.T) {
+	bus := newJobBus()
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	cmd := bus.Start(jobKindSuggest, func(ctx context.Context) (tea.Msg, error) {
+		close(started)
+		select {
+		case <-ctx.Done():
+			done <- ctx.Err()
+			return nil, ctx.Err()
+		case <-time.After(5 * time.Second):
+			done <- nil
+			return nil, nil
+		}
+	})
+	result := make(chan jobResultEnvelope, 1)
+	go func() {
+		runJobsInto(cmd, result)
+	}()
+
+	<-started
+	if got := bus.Cancel(jobKindGaps); got != 0 {
+		t.Fatalf("expected no gaps job to cancel, got %d", got)
+	}
+	if got := bus.Cancel(jobKindSuggest); got != 1 {
+		t.Fatalf("expected one suggest job to cancel, got %d", got)
+	}
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the runner's context to be canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("runner kept going after Cancel")
+	}
+	if envelope := <-result; envelope.Snapshot.Status != jobStatusCanceled {
+		t.Fatalf("expected a canceled snapshot, got %+v", envelope.Snapshot)
+	}
+	if got := bus.Cancel(jobKindSuggest); got != 0 {
+		t.Fatalf("expected the finished job to be forgotten, got %d", got)
+	}
+}
+
+func TestLoadNewCancelsRunningBriefJob(t *testing.T) {
+	m := newTestModel(t)
+	m.config.LLM = fakeLLM{}
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Wrong Paper", FullText: "Some paper text."}
+	canceled := make(chan error, 1)
+	cmd := m.jobBus.Start(jobKindForSection(llm.BriefTechnical), func(ctx context.Context) (tea.Msg, error) {
+		<-ctx.Done()
+		canceled <- ctx.Err()
+		return nil, ctx.Err()
+	})
+	go runJobsInto(cmd, make(chan jobResultEnvelope, 1))
+
+	m.actionLoadNewCmd()
+	select {
+	case err := <-canceled:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected loading a new paper to cancel the running brief job")
+	}
+}
+
+// runJobsInto runs the commands tea.Sequence wraps a job in and forwards the job's result. Unlike
+// runJobs it leaves the model alone, so it is safe to call from another goroutine.
+func runJobsInto(cmd tea.Cmd, result chan<- jobResultEnvelope) {
+	value := reflect.ValueOf(cmd())
+	for i := 0; i < value.Len(); i++ {
+		if envelope, ok := value.Index(i).Interface().(tea.Cmd)().(jobResultEnvelope); ok {
+			result <- envelope
+		}
+	}
+}
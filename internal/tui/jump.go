@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sectionJumpName is how an anchor is typed after /jump ("deep_dive" reads as "deep-dive").
+func sectionJumpName(anchor string) string {
+	return strings.ReplaceAll(anchor, "_", "-")
+}
+
+// normalizeSectionQuery drops case and separators so "Deep Dive", "deep-dive", and "deepdive" agree.
+func normalizeSectionQuery(value string) string {
+	return strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(value)))
+}
+
+// sectionMatchScore ranks how well query names candidate: 3 for an exact match, 2 for a prefix, 1
+// when the query's letters appear in order (so "dd" finds deep-dive), and 0 otherwise.
+func sectionMatchScore(query, candidate string) int {
+	query, candidate = normalizeSectionQuery(query), normalizeSectionQuery(candidate)
+	switch {
+	case query == "":
+		return 0
+	case query == candidate:
+		return 3
+	case strings.HasPrefix(candidate, query):
+		return 2
+	}
+	rest := candidate
+	for _, r := range query {
+		index := strings.IndexRune(rest, r)
+		if index < 0 {
+			return 0
+		}
+		rest = rest[index+len(string(r)):]
+	}
+	return 1
+}
+
+// matchSections returns the available anchors that best match query, in display order. More than
+// one result means the query is ambiguous.
+func matchSections(query string, anchors []string) []string {
+	best := 0
+	var matches []string
+	for _, anchor := range anchors {
+		score := sectionMatchScore(query, sectionJumpName(anchor))
+		switch {
+		case score == 0 || score < best:
+		case score > best:
+			best = score
+			matches = []string{anchor}
+		default:
+			matches = append(matches, anchor)
+		}
+	}
+	return matches
+}
+
+// actionJumpSectionCmd jumps straight to the section whose name fuzzily matches args, or lists the
+// sections that can be jumped to when args is empty.
+func (m *model) actionJumpSectionCmd(args string) tea.Cmd {
+	anchors := m.availableSections()
+	if len(anchors) == 0 {
+		m.infoMessage = "Load a paper to jump between sections."
+		return nil
+	}
+	names := make([]string, len(anchors))
+	for i, anchor := range anchors {
+		names[i] = sectionJumpName(anchor)
+	}
+	if strings.TrimSpace(args) == "" {
+		m.infoMessage = fmt.Sprintf("Jump to: %s. Type /jump <section>; partial names work.", strings.Join(names, ", "))
+		return nil
+	}
+	matches := matchSections(args, anchors)
+	switch len(matches) {
+	case 0:
+		m.infoMessage = fmt.Sprintf("No section matches %q. Try: %s.", args, strings.Join(names, ", "))
+	case 1:
+		m.jumpToSection(matches[0])
+	default:
+		for i, anchor := range matches {
+			matches[i] = sectionJumpName(anchor)
+		}
+		m.infoMessage = fmt.Sprintf("%q matches %s; be more specific.", args, strings.Join(matches, " and "))
+	}
+	return nil
+}
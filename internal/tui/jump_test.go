@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+)
+
+func TestJumpCommandScrollsToFuzzyMatchedSection(t *testing.T) {
+	m := newTestModel(t)
+	m.config.AutoBrief = false
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 16})
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: &arxiv.Paper{ID: "2101.00001", Title: "Jumpy", Abstract: "We study jumping.", FullText: "Body."}})
+	for _, kind := range briefSectionKinds {
+		var bullets []string
+		for i := 0; i < 12; i++ {
+			bullets = append(bullets, fmt.Sprintf("- %s point %d", briefSectionTitle(kind), i))
+		}
+		m.setBriefMessage(kind, briefMessageContent(kind, bullets))
+	}
+	m.markViewportDirty()
+	m.refreshViewportIfDirty()
+	technical, ok := m.sectionAnchors[anchorTechnical]
+	if !ok || technical == 0 {
+		t.Fatalf("expected a technical anchor below the top, got %v", m.sectionAnchors)
+	}
+
+	m.actionJumpSectionCmd("technical")
+	if m.viewport.YOffset != technical {
+		t.Fatalf("expected offset %d for technical, got %d", technical, m.viewport.YOffset)
+	}
+
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/jump dd")
+	m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if want := m.sectionAnchors[anchorDeepDive]; m.cursorLine != want {
+		t.Fatalf("expected /jump dd to land on the deep dive at %d, got %d", want, m.cursorLine)
+	}
+
+	m.actionJumpSectionCmd("e")
+	if want := `"e" matches technical and deep-dive; be more specific.`; m.infoMessage != want {
+		t.Fatalf("expected %q, got %q", want, m.infoMessage)
+	}
+	m.actionJumpSectionCmd("zzz")
+	if want := `No section matches "zzz". Try: summary, technical, deep-dive.`; m.infoMessage != want {
+		t.Fatalf("expected %q, got %q", want, m.infoMessage)
+	}
+}
+
+func TestJumpFindsBriefSectionsPastTheTranscriptCap(t *testing.T) {
+	m := newTestModel(t)
+	m.config.AutoBrief = false
+	m.config.MaxTranscriptEntries = 2
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 16})
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: &arxiv.Paper{ID: "2101.00001", Title: "Jumpy", Abstract: "We study jumping.", FullText: "Body."}})
+	for _, kind := range briefSectionKinds {
+		m.setBriefMessage(kind, briefMessageContent(kind, []string{"- " + briefSectionTitle(kind) + " point"}))
+	}
+	for i := 1; i <= 6; i++ {
+		m.appendTranscript("note", fmt.Sprintf("follow-up note %d", i))
+	}
+	m.markViewportDirty()
+	m.refreshViewportIfDirty()
+
+	view := stripANSI(m.viewportContent)
+	if !strings.Contains(view, "earlier message") || strings.Contains(view, "follow-up note 4\n") || !strings.Contains(view, "follow-up note 6") {
+		t.Fatalf("expected older notes collapsed and the newest shown, got:\n%s", view)
+	}
+	m.actionJumpSectionCmd("summary")
+	if _, ok := m.sectionAnchors[anchorSummary]; !ok || strings.Contains(m.infoMessage, "unavailable") {
+		t.Fatalf("expected /jump summary to find the summary, anchors %v, info %q", m.sectionAnchors, m.infoMessage)
+	}
+	for _, kind := range briefSectionKinds {
+		if !strings.Contains(view, briefSectionTitle(kind)+" point") {
+			t.Fatalf("expected the %s section rendered despite the cap:\n%s", kind, view)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Display key actions that KeyBindings can remap.
+const (
+	keyActionManualNote       = "manual-note"
+	keyActionTop              = "top"
+	keyActionBottom           = "bottom"
+	keyActionNextSection      = "next-section"
+	keyActionPrevSection      = "prev-section"
+	keyActionLoadNew          = "load-new"
+	keyActionSave             = "save"
+	keyActionCopyBrief        = "copy-brief"
+	keyActionNextSuggestion   = "next-suggestion"
+	keyActionPrevSuggestion   = "prev-suggestion"
+	keyActionToggleSuggestion = "toggle-suggestion"
+	keyActionFind             = "find"
+	keyActionPrevMatch        = "prev-match"
+	keyActionNextTab          = "next-tab"
+	keyActionPrevTab          = "prev-tab"
+	keyActionHalfPageDown     = "half-page-down"
+	keyActionHalfPageUp       = "half-page-up"
+	keyActionPageDown         = "page-down"
+	keyActionPageUp           = "page-up"
+	keyActionOpenPDF          = "open-pdf"
+)
+
+// KeyBindings maps display action names (see DefaultKeyBindings) to the key that triggers them, in
+// Bubble Tea's key.String() form ("g", "G", "ctrl+g"). Actions left out keep their default key.
+type KeyBindings map[string]string
+
+// DefaultKeyBindings returns the built-in display keys.
+func DefaultKeyBindings() KeyBindings {
+	return KeyBindings{
+		keyActionManualNote:       "m",
+		keyActionTop:              "g",
+		keyActionBottom:           "G",
+		keyActionNextSection:      "]",
+		keyActionPrevSection:      "[",
+		keyActionLoadNew:          "r",
+		keyActionSave:             "s",
+		keyActionCopyBrief:        "c",
+		keyActionNextSuggestion:   "n",
+		keyActionPrevSuggestion:   "p",
+		keyActionToggleSuggestion: "x",
+		keyActionFind:             "/",
+		keyActionPrevMatch:        "N",
+		// Most terminals send Ctrl+Tab and Ctrl+Shift+Tab as plain Tab and Shift+Tab.
+		keyActionNextTab:      "tab",
+		keyActionPrevTab:      "shift+tab",
+		keyActionHalfPageDown: "ctrl+d",
+		keyActionHalfPageUp:   "ctrl+u",
+		keyActionPageDown:     "ctrl+f",
+		keyActionPageUp:       "ctrl+b",
+		keyActionOpenPDF:      "o",
+	}
+}
+
+// DefaultKeyMapPath is where LoadKeyBindings looks without -keymap:
+// <user config dir>/paperscout/keys.json (~/.config/paperscout/keys.json on Linux).
+func DefaultKeyMapPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "paperscout", "keys.json")
+}
+
+// LoadKeyBindings reads a JSON object of action → key overrides from path. A missing file (or an
+// empty path) is not an error and yields no overrides.
+func LoadKeyBindings(path string) (KeyBindings, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var bindings KeyBindings
+	if err := json.Unmarshal(data, &bindings); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return bindings, nil
+}
+
+// keyActions resolves overrides on top of the defaults into a key → action lookup. Bindings that
+// name an unknown action or an empty key are ignored; a remapped key takes over from whichever
+// default action held it.
+func (b KeyBindings) keyActions() map[string]string {
+	defaults := DefaultKeyBindings()
+	actions := make(map[string]string, len(defaults))
+	for action, key := range defaults {
+		actions[key] = action
+	}
+	names := make([]string, 0, len(b))
+	for action := range b {
+		names = append(names, action)
+	}
+	sort.Strings(names)
+	for _, action := range names {
+		key := strings.TrimSpace(b[action])
+		if _, known := defaults[action]; !known || key == "" {
+			continue
+		}
+		if actions[defaults[action]] == action {
+			delete(actions, defaults[action])
+		}
+		actions[key] = action
+	}
+	return actions
+}
@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func runeKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func TestRemappedKeyTriggersAction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, []byte(`{"save": "o"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bindings, err := LoadKeyBindings(path)
+	if err != nil {
+		t.Fatalf("LoadKeyBindings() error = %v", err)
+	}
+	m := New(Config{KnowledgeBasePath: filepath.Join(t.TempDir(), "zettel.json"), KeyBindings: bindings}).(*model)
+	m.composer.Blur()
+
+	m.handleDisplayKey(runeKey('s'))
+	if m.infoMessage == "No manual notes captured yet." {
+		t.Fatal("expected the default save key to be released after remapping")
+	}
+	m.handleDisplayKey(runeKey('o'))
+	if m.infoMessage != "No manual notes captured yet." {
+		t.Fatalf("expected the remapped key to save, got %q", m.infoMessage)
+	}
+}
+
+func TestInvalidKeyBindingsAreIgnored(t *testing.T) {
+	m := New(Config{
+		KnowledgeBasePath: filepath.Join(t.TempDir(), "zettel.json"),
+		KeyBindings:       KeyBindings{"launch-rockets": "x", "save": "  "},
+	}).(*model)
+	m.composer.Blur()
+
+	m.handleDisplayKey(runeKey('x'))
+	if m.infoMessage == "No manual notes captured yet." {
+		t.Fatal("expected a binding for an unknown action to be ignored")
+	}
+	m.handleDisplayKey(runeKey('s'))
+	if m.infoMessage != "No manual notes captured yet." {
+		t.Fatalf("expected the default save key to survive a blank binding, got %q", m.infoMessage)
+	}
+}
+
+func TestLoadKeyBindingsMissingFileKeepsDefaults(t *testing.T) {
+	bindings, err := LoadKeyBindings(filepath.Join(t.TempDir(), "absent.json"))
+	if err != nil || bindings != nil {
+		t.Fatalf("expected no overrides for a missing file, got %v, %v", bindings, err)
+	}
+}
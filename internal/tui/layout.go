@@ -107,6 +107,10 @@ type displayView struct {
 type contentBuilder struct {
 	builder strings.Builder
 	lines   int
+	// anchors records the line each section starts on; nil when the view has no sections.
+	anchors map[string]int
+	// suggestionLines maps a viewport line to the suggested note drawn on it.
+	suggestionLines map[int]int
 }
 
 type markdownLineKind int
@@ -132,13 +136,28 @@ func (m *model) writeConversationStream(cb *contentBuilder) {
 		return
 	}
 	wrap := m.wrapWidth(4)
-	for idx, entry := range m.transcriptEntries {
+	cutoff, hidden := m.transcriptCutoff()
+	if hidden > 0 {
+		cb.WriteString(helperStyle.Render(collapsedTranscriptPlaceholder(hidden)))
+		cb.WriteString("\n\n")
+	}
+	for idx := 0; idx < len(m.transcriptEntries); idx++ {
+		entry := m.transcriptEntries[idx]
+		if idx < cutoff && !alwaysRenderedEntry(entry) {
+			continue
+		}
+		if kind, ok := briefSectionKindFromTranscriptKind(entry.Kind); ok {
+			cb.markAnchor(briefSectionAnchor(kind))
+		}
 		label := transcriptLabel(entry.Kind)
 		if label != "" {
 			cb.WriteString(helperStyle.Render(label))
 			cb.WriteRune('\n')
 		}
 		body := formatConversationEntry(entry.Content, wrap)
+		if m.rawMarkdown {
+			body = entry.Content
+		}
 		cb.WriteString(indentMultiline(body, "  "))
 		if idx < len(m.transcriptEntries)-1 {
 			cb.WriteRune('\n')
@@ -149,9 +168,55 @@ func (m *model) writeConversationStream(cb *contentBuilder) {
 	}
 }
 
+// transcriptCutoff returns the index before which entries collapse into one placeholder, and how
+// many entries that hides. With MaxTranscriptEntries set, only the newest that many entries render,
+// so long sessions do not re-render hundreds of entries every frame; brief sections are neither
+// counted nor hidden, so they stay readable and /jump can still find them.
+func (m *model) transcriptCutoff() (int, int) {
+	limit := m.config.MaxTranscriptEntries
+	if limit <= 0 {
+		return 0, 0
+	}
+	cutoff, kept := 0, 0
+	for idx := len(m.transcriptEntries) - 1; idx >= 0; idx-- {
+		if alwaysRenderedEntry(m.transcriptEntries[idx]) {
+			continue
+		}
+		if kept == limit {
+			cutoff = idx + 1
+			break
+		}
+		kept++
+	}
+	hidden := 0
+	for _, entry := range m.transcriptEntries[:cutoff] {
+		if !alwaysRenderedEntry(entry) {
+			hidden++
+		}
+	}
+	return cutoff, hidden
+}
+
+// alwaysRenderedEntry reports whether entry escapes the MaxTranscriptEntries collapse.
+func alwaysRenderedEntry(entry transcriptEntry) bool {
+	_, ok := briefSectionKindFromTranscriptKind(entry.Kind)
+	return ok
+}
+
+// collapsedTranscriptPlaceholder stands in for the hidden entries, e.g. "… 12 earlier messages …".
+func collapsedTranscriptPlaceholder(hidden int) string {
+	if hidden == 1 {
+		return "… 1 earlier message …"
+	}
+	return fmt.Sprintf("… %d earlier messages …", hidden)
+}
+
 func (m *model) writeComposerBlock(cb *contentBuilder) {
 	cb.WriteRune('\n')
 	cb.WriteString(helperStyle.Render("Command"))
+	if counter := m.composerCounterText(); counter != "" {
+		cb.WriteString("  " + helperStyle.Render(counter))
+	}
 	cb.WriteRune('\n')
 	cb.WriteString(indentMultiline(m.composer.View(), "  "))
 	cb.WriteRune('\n')
@@ -178,15 +243,36 @@ func (cb *contentBuilder) Line() int {
 	return cb.lines
 }
 
+// markAnchor records that the section named anchor starts on the current line. A later entry for
+// the same section moves the anchor, so a regenerated section is the one jumped to.
+func (cb *contentBuilder) markAnchor(anchor string) {
+	if cb.anchors != nil {
+		cb.anchors[anchor] = cb.lines
+	}
+}
+
 func (m *model) buildDisplayContent() displayView {
-	cb := &contentBuilder{}
-	m.writeConversationStream(cb)
+	cb := &contentBuilder{anchors: map[string]int{}, suggestionLines: map[int]int{}}
+	switch {
+	case m.composerMode == composerModeScratchpad:
+		m.writeScratchpadOverlay(cb)
+	case m.composerMode == composerModePickNote:
+		m.writeNotePicker(cb)
+	case m.errorsView:
+		m.writeErrorsView(cb)
+	case m.contextPreview != "":
+		m.writeContextPreview(cb)
+	default:
+		m.writeConversationStream(cb)
+		m.writeSuggestedNotes(cb)
+		m.writeDraftNotes(cb)
+	}
 	m.writeComposerBlock(cb)
 
 	return displayView{
 		body:            cb.String(),
-		suggestionLines: map[int]int{},
-		anchors:         map[string]int{},
+		suggestionLines: cb.suggestionLines,
+		anchors:         cb.anchors,
 	}
 }
 
@@ -310,7 +396,7 @@ func formatConversationEntry(content string, wrap int) string {
 			if formatted.prefix != "" {
 				content = wrapWithPrefix(content, formatted.prefix, wrap)
 			} else {
-				content = wordwrap.String(content, wrap)
+				content = wrapProse(content, wrap)
 			}
 		}
 		if formatted.kind != markdownLineTable {
@@ -545,8 +631,8 @@ func styleMarkdownLine(line string, kind markdownLineKind) string {
 
 func stylizeInlineElements(line string) string {
 	line = markdownInlineCodePattern.ReplaceAllString(line, markdownInlineCodeStyle.Render("$1"))
-	line = latexInlineDoublePattern.ReplaceAllString(line, latexStyle.Render("$1"))
-	line = latexInlineSinglePattern.ReplaceAllString(line, latexStyle.Render("$1"))
+	line = latexInlineDoublePattern.ReplaceAllStringFunc(line, renderLatexMatch(latexInlineDoublePattern))
+	line = latexInlineSinglePattern.ReplaceAllStringFunc(line, renderLatexMatch(latexInlineSinglePattern))
 	line = markdownBoldPattern.ReplaceAllString(line, markdownBoldStyle.Render("$1"))
 	line = markdownBoldUnderscorePattern.ReplaceAllString(line, markdownBoldStyle.Render("$1"))
 	line = markdownItalicPattern.ReplaceAllString(line, markdownItalicStyle.Render("$1"))
@@ -555,6 +641,181 @@ func stylizeInlineElements(line string) string {
 	return line
 }
 
+// renderLatexMatch styles a math region matched by pattern after converting its body with
+// latexToUnicode, so only text between the delimiters is rewritten.
+func renderLatexMatch(pattern *regexp.Regexp) func(string) string {
+	return func(match string) string {
+		parts := pattern.FindStringSubmatch(match)
+		if len(parts) < 2 {
+			return match
+		}
+		return latexStyle.Render(latexToUnicode(parts[1]))
+	}
+}
+
+// latexSymbols maps LaTeX commands to their closest unicode character.
+var latexSymbols = map[string]string{
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ε", "varepsilon": "ε",
+	"zeta": "ζ", "eta": "η", "theta": "θ", "vartheta": "ϑ", "iota": "ι", "kappa": "κ",
+	"lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ", "pi": "π", "rho": "ρ", "sigma": "σ",
+	"tau": "τ", "upsilon": "υ", "phi": "φ", "varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ", "Pi": "Π",
+	"Sigma": "Σ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+	"sum": "∑", "prod": "∏", "int": "∫", "oint": "∮", "partial": "∂", "nabla": "∇",
+	"infty": "∞", "sqrt": "√", "pm": "±", "mp": "∓", "times": "×", "div": "÷", "cdot": "·",
+	"circ": "∘", "ast": "∗", "star": "⋆", "ldots": "…", "cdots": "⋯", "dots": "…",
+	"leq": "≤", "le": "≤", "geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠", "approx": "≈",
+	"sim": "∼", "simeq": "≃", "equiv": "≡", "propto": "∝", "ll": "≪", "gg": "≫",
+	"in": "∈", "notin": "∉", "subset": "⊂", "subseteq": "⊆", "supset": "⊃", "supseteq": "⊇",
+	"cup": "∪", "cap": "∩", "emptyset": "∅", "forall": "∀", "exists": "∃", "neg": "¬",
+	"land": "∧", "wedge": "∧", "lor": "∨", "vee": "∨", "oplus": "⊕", "otimes": "⊗",
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "Rightarrow": "⇒", "Leftarrow": "⇐",
+	"leftrightarrow": "↔", "Leftrightarrow": "⇔", "iff": "⇔", "mapsto": "↦", "implies": "⇒",
+	"langle": "⟨", "rangle": "⟩", "lfloor": "⌊", "rfloor": "⌋", "lceil": "⌈", "rceil": "⌉",
+	"top": "⊤", "perp": "⊥", "mid": "∣", "parallel": "∥", "ell": "ℓ", "hbar": "ℏ",
+	"mathbb": "", "mathbf": "", "mathrm": "", "mathit": "", "mathcal": "", "boldsymbol": "",
+	"operatorname": "", "text": "", "textrm": "", "left": "", "right": "", "displaystyle": "",
+	"quad": " ", "qquad": "  ",
+}
+
+// latexSuperscripts and latexSubscripts hold the characters unicode can raise or lower.
+var (
+	latexSuperscripts = map[rune]rune{
+		'0': '⁰', '1': '¹', '2': '²', '3': '³', '4': '⁴', '5': '⁵', '6': '⁶', '7': '⁷', '8': '⁸', '9': '⁹',
+		'+': '⁺', '-': '⁻', '=': '⁼', '(': '⁽', ')': '⁾', 'i': 'ⁱ', 'n': 'ⁿ', 'T': 'ᵀ',
+		'a': 'ᵃ', 'b': 'ᵇ', 'c': 'ᶜ', 'd': 'ᵈ', 'e': 'ᵉ', 'k': 'ᵏ', 'm': 'ᵐ', 't': 'ᵗ', 'x': 'ˣ', 'y': 'ʸ',
+		'*': '*', '′': '′', '−': '⁻',
+	}
+	latexSubscripts = map[rune]rune{
+		'0': '₀', '1': '₁', '2': '₂', '3': '₃', '4': '₄', '5': '₅', '6': '₆', '7': '₇', '8': '₈', '9': '₉',
+		'+': '₊', '-': '₋', '=': '₌', '(': '₍', ')': '₎', 'a': 'ₐ', 'e': 'ₑ', 'h': 'ₕ', 'i': 'ᵢ',
+		'j': 'ⱼ', 'k': 'ₖ', 'l': 'ₗ', 'm': 'ₘ', 'n': 'ₙ', 'o': 'ₒ', 'p': 'ₚ', 'r': 'ᵣ', 's': 'ₛ',
+		't': 'ₜ', 'u': 'ᵤ', 'v': 'ᵥ', 'x': 'ₓ', '−': '₋',
+	}
+)
+
+// latexToUnicode approximates a LaTeX math expression with unicode: known commands become symbols,
+// grouping braces are dropped, and ^/_ scripts are raised or lowered when every character has a
+// unicode form (otherwise they are kept as ^(…) or _(…)). Unknown commands lose their backslash.
+func latexToUnicode(expr string) string {
+	runes := []rune(expr)
+	var out strings.Builder
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch r {
+		case '\\':
+			name, next := readLatexCommand(runes, i+1)
+			i = next
+			if name == "frac" {
+				num, afterNum := readLatexArgument(runes, i)
+				den, afterDen := readLatexArgument(runes, afterNum)
+				out.WriteString(latexToUnicode(num) + "/" + latexToUnicode(den))
+				i = afterDen
+				continue
+			}
+			out.WriteString(latexCommandText(name))
+		case '{', '}':
+			i++
+		case '^', '_':
+			arg, next := readLatexArgument(runes, i+1)
+			i = next
+			out.WriteString(latexScript(r, latexToUnicode(arg)))
+		default:
+			out.WriteRune(r)
+			i++
+		}
+	}
+	return out.String()
+}
+
+// readLatexCommand reads the command name starting at start (just past the backslash): a run of
+// letters, or a single other character such as the brace in \{.
+func readLatexCommand(runes []rune, start int) (string, int) {
+	if start >= len(runes) {
+		return "", start
+	}
+	end := start
+	for end < len(runes) && isASCIILetter(runes[end]) {
+		end++
+	}
+	if end == start {
+		end++
+	}
+	return string(runes[start:end]), end
+}
+
+// readLatexArgument reads one argument at start: a braced group (without its braces), a command, or
+// a single character. Leading spaces are skipped as LaTeX does.
+func readLatexArgument(runes []rune, start int) (string, int) {
+	for start < len(runes) && runes[start] == ' ' {
+		start++
+	}
+	if start >= len(runes) {
+		return "", start
+	}
+	switch runes[start] {
+	case '{':
+		depth := 0
+		for end := start; end < len(runes); end++ {
+			switch runes[end] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+				if depth == 0 {
+					return string(runes[start+1 : end]), end + 1
+				}
+			}
+		}
+		return string(runes[start+1:]), len(runes)
+	case '\\':
+		_, end := readLatexCommand(runes, start+1)
+		return string(runes[start:end]), end
+	default:
+		return string(runes[start]), start + 1
+	}
+}
+
+func latexCommandText(name string) string {
+	if symbol, ok := latexSymbols[name]; ok {
+		return symbol
+	}
+	switch name {
+	case ",", ";", ":", " ":
+		return " "
+	case "!":
+		return ""
+	case "\\":
+		return " "
+	}
+	return name
+}
+
+// latexScript raises (^) or lowers (_) text, falling back to the marker and parentheses when any
+// character has no unicode script form.
+func latexScript(marker rune, text string) string {
+	table := latexSuperscripts
+	if marker == '_' {
+		table = latexSubscripts
+	}
+	var out strings.Builder
+	for _, r := range text {
+		mapped, ok := table[r]
+		if !ok {
+			if utf8.RuneCountInString(text) == 1 {
+				return string(marker) + text
+			}
+			return string(marker) + "(" + text + ")"
+		}
+		out.WriteRune(mapped)
+	}
+	return out.String()
+}
+
+func isASCIILetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
 func renderInlineLinks(line string) string {
 	line = renderMarkdownLinks(line)
 	return renderPlainURLs(line)
@@ -642,7 +903,7 @@ func wrapWithPrefix(text, prefix string, width int) string {
 	if available < 10 {
 		available = 10
 	}
-	wrapped := wordwrap.String(rest, available)
+	wrapped := wrapProse(rest, available)
 	lines := splitLinesPreserve(wrapped)
 	indent := strings.Repeat(" ", prefixWidth)
 	for i, line := range lines {
@@ -655,6 +916,20 @@ func wrapWithPrefix(text, prefix string, width int) string {
 	return strings.Join(lines, "\n")
 }
 
+// wrapProse word-wraps text to width like wordwrap.String, except that text containing a URL only
+// breaks at spaces: wordwrap also breaks after hyphens, which would split the URL and the OSC 8
+// hyperlink renderInlineLinks later builds from it. A URL wider than width gets a line of its own.
+func wrapProse(text string, width int) string {
+	if !plainURLPattern.MatchString(text) {
+		return wordwrap.String(text, width)
+	}
+	w := wordwrap.NewWriter(width)
+	w.Breakpoints = nil
+	w.Write([]byte(text))
+	w.Close()
+	return w.String()
+}
+
 func previewText(value string, limit int) string {
 	value = strings.TrimSpace(value)
 	if limit <= 0 {
@@ -697,7 +972,19 @@ func transcriptLabel(kind string) string {
 		return "Scout (brief)"
 	case "brief":
 		return "Scout (brief)"
-	case "paper", "fetch", "save":
+	case "gaps":
+		return "Scout (gaps)"
+	case "guide":
+		return "Scout (guide)"
+	case "search":
+		return "Notes (search)"
+	case "related":
+		return "Notes (related)"
+	case "references":
+		return "Paper (references)"
+	case "abstract":
+		return "Paper (abstract)"
+	case "paper", "fetch", "save", "export", "help", "diagnostics":
 		return "System"
 	case "error":
 		return "Error"
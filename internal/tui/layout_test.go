@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -120,6 +121,30 @@ func TestBuildDisplayContentStripsMarkdown(t *testing.T) {
 	}
 }
 
+func TestBuildDisplayContentCollapsesOldTranscriptEntries(t *testing.T) {
+	m := &model{
+		viewport: viewport.New(80, 20),
+		composer: textarea.New(),
+		config:   Config{MaxTranscriptEntries: 3},
+	}
+	for i := 1; i <= 8; i++ {
+		m.transcriptEntries = append(m.transcriptEntries, transcriptEntry{Kind: "note", Content: fmt.Sprintf("message number %d", i)})
+	}
+	content := stripANSI(m.buildDisplayContent().body)
+	if !strings.Contains(content, "… 5 earlier messages …") {
+		t.Fatalf("expected the collapse placeholder:\n%s", content)
+	}
+	for i := 1; i <= 8; i++ {
+		shown := strings.Contains(content, fmt.Sprintf("message number %d\n", i))
+		if want := i > 5; shown != want {
+			t.Fatalf("message %d shown = %v, want %v:\n%s", i, shown, want, content)
+		}
+	}
+	if len(m.transcriptEntries) != 8 {
+		t.Fatalf("expected collapsed entries to stay in the transcript, got %d", len(m.transcriptEntries))
+	}
+}
+
 func TestFormatConversationEntryClickableURLs(t *testing.T) {
 	input := "See [Docs](https://example.com) and https://golang.org."
 	got := stripANSI(formatConversationEntry(input, 80))
@@ -129,6 +154,31 @@ func TestFormatConversationEntryClickableURLs(t *testing.T) {
 	}
 }
 
+func TestFormatConversationEntryKeepsLongURLsOnOneLine(t *testing.T) {
+	url := "https://example.com/papers/sparse-mixture-of-experts-routing-with-load-balancing-v2"
+	input := "- Code and checkpoints are released at " + url + " under an open license."
+	got := stripANSI(formatConversationEntry(input, 40))
+	lines := strings.Split(got, "\n")
+	found := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == url {
+			found = true
+		}
+		if strings.Contains(line, "https://") && strings.TrimSpace(line) != url {
+			t.Fatalf("expected the URL alone on its line, got %q", line)
+		}
+	}
+	if !found {
+		t.Fatalf("expected the URL intact on one line:\n%s", got)
+	}
+	if !strings.HasPrefix(lines[0], "• Code") || !strings.Contains(got, "under an open") {
+		t.Fatalf("expected the bullet text to wrap around the URL:\n%s", got)
+	}
+	if raw := formatConversationEntry(input, 40); !strings.Contains(raw, "\x1b]8;;"+url+"\x1b\\") {
+		t.Fatalf("expected an unbroken OSC 8 hyperlink for the URL:\n%q", raw)
+	}
+}
+
 func TestStripANSIHandlesHyperlinks(t *testing.T) {
 	url := "https://example.com"
 	raw := renderClickableURL(url)
@@ -136,3 +186,35 @@ func TestStripANSIHandlesHyperlinks(t *testing.T) {
 		t.Fatalf("stripANSI did not remove hyperlink sequences: %v", raw)
 	}
 }
+
+func TestFormatConversationEntryRendersLatexAsUnicode(t *testing.T) {
+	input := `Bound: $\alpha \leq \beta^2$ holds, and $$\sum_{i=1}^{n} x_i \in \mathbb{R}$$ too.`
+	got := stripANSI(formatConversationEntry(input, 120))
+	want := "Bound: α ≤ β² holds, and ∑ᵢ₌₁ⁿ xᵢ ∈ R too."
+	if got != want {
+		t.Fatalf("formatted output mismatch:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestLatexToUnicode(t *testing.T) {
+	cases := map[string]string{
+		`\frac{a}{b} \cdot \sqrt{x}`: "a/b · √x",
+		`x^{T} W_{q}`:                "xᵀ W_q",
+		`\foo + y^{zz}`:              "foo + y^(zz)",
+		`\{1, 2\}`:                   "{1, 2}",
+	}
+	for input, want := range cases {
+		if got := latexToUnicode(input); got != want {
+			t.Errorf("latexToUnicode(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestFormatConversationEntryLeavesProseOutsideMath(t *testing.T) {
+	input := `Escape \alpha in prose, but $\alpha$ in math.`
+	got := stripANSI(formatConversationEntry(input, 120))
+	want := `Escape \alpha in prose, but α in math.`
+	if got != want {
+		t.Fatalf("formatted output mismatch:\n got %q\nwant %q", got, want)
+	}
+}
@@ -4,15 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
-	"unicode"
 	"unicode/utf8"
 
-	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
@@ -25,19 +25,103 @@ import (
 	"github.com/csheth/browse/internal/guide"
 	"github.com/csheth/browse/internal/llm"
 	"github.com/csheth/browse/internal/notes"
+	"github.com/csheth/browse/internal/sentences"
 )
 
 // Config wires runtime options into the TUI program.
 type Config struct {
 	KnowledgeBasePath string
-	LLM               llm.Client
-}
+	// KnowledgeBaseFormat is the -kb-format layout of KnowledgeBasePath; empty means
+	// notes.FormatFile.
+	KnowledgeBaseFormat notes.Format
+	LLM                 llm.Client
+	ExportIncludeGuide  bool
+	// ExportFileTemplate names brief exports using {id}, {title}, and {date}; empty uses
+	// export.DefaultFileTemplate.
+	ExportFileTemplate string
+	// TranscriptFileTemplate names /export-transcript files with the same placeholders; empty uses
+	// export.DefaultTranscriptTemplate.
+	TranscriptFileTemplate string
+	CacheBriefs            bool
+	// AutoBrief launches the reading brief as soon as a paper loads; when false the brief waits for
+	// /brief so metered providers are only called on demand.
+	AutoBrief bool
+	// Preview opens papers from their metadata alone, skipping the PDF download so the abstract shows
+	// at once; /full then loads the text and the brief.
+	Preview bool
+	// ComposerCharLimit caps composer input; zero uses defaultComposerCharLimit.
+	ComposerCharLimit int
+	// InitialPaper is an arXiv URL or identifier fetched as soon as the program starts.
+	InitialPaper string
+	// Resume reopens the paper from the most recently active conversation snapshot at startup when
+	// InitialPaper is empty; without snapshots the URL prompt is shown as usual.
+	Resume bool
+	// FromClipboard fetches the arXiv identifier found on the clipboard at startup when InitialPaper
+	// is empty; without one the usual URL prompt is shown.
+	FromClipboard bool
+	// BriefStreamRate caps how many times per second a streaming brief section is redrawn; zero uses
+	// defaultBriefStreamRate and a negative value redraws on every delta.
+	BriefStreamRate int
+	// ClipboardUnavailable sends copies through the OSC 52 terminal escape instead of the system
+	// clipboard, which headless hosts and SSH sessions usually lack.
+	ClipboardUnavailable bool
+	// Announcements receives one plain-text line per state change ("Answer ready") for screen
+	// reader users; nil disables announcements.
+	Announcements io.Writer
+	// MinTechnicalBullets triggers one continuation prompt when the technical section returns fewer
+	// top-level bullets; zero disables the check.
+	MinTechnicalBullets int
+	// FetchTimeout bounds loading one paper, PDF download and parsing included; zero uses
+	// defaultFetchTimeout.
+	FetchTimeout time.Duration
+	// QuestionTimeout bounds one streamed answer; zero uses defaultQuestionTimeout.
+	QuestionTimeout time.Duration
+	// BriefSectionTimeout bounds the summary section's generation; larger sections get time in
+	// proportion to their context budget. Zero uses defaultBriefSectionTimeout.
+	BriefSectionTimeout time.Duration
+	// SnapshotFlushInterval batches conversation snapshot writes and flushes them this often (or
+	// sooner once enough messages pile up); zero writes every update as it happens.
+	SnapshotFlushInterval time.Duration
+	// KeyBindings remaps display keys by action name; nil keeps DefaultKeyBindings.
+	KeyBindings KeyBindings
+	// Offline turns every LLM feature off with a notice saying why; an LLM built by llm.NewFromEnv
+	// in offline mode sets it too. Papers then come only from the PDF cache (see arxiv.SetOffline).
+	Offline bool
+	// Theme colors the hero, composer, Markdown, and status line; the zero Theme is ThemeDark. A
+	// non-empty NO_COLOR environment variable overrides it with an uncolored theme.
+	Theme Theme
+	// TokenEstimator sizes the brief section contexts in tokens; nil uses llm.HeuristicEstimator.
+	TokenEstimator llm.TokenEstimator
+	// Autosave writes each manual note to the knowledge base as soon as it is captured instead of
+	// waiting for s, so quitting never drops a note.
+	Autosave bool
+	// MaxTranscriptEntries caps how many of the newest conversation entries are rendered; older ones
+	// collapse into a single placeholder line but stay in the conversation snapshot. Zero renders
+	// every entry.
+	MaxTranscriptEntries int
+	// BriefConcurrency caps how many brief sections generate at once; queued sections start in
+	// Summary → Technical → Deep Dive order. Zero uses llm.DefaultConcurrency for the LLM.
+	BriefConcurrency int
+}
+
+// NoticeMsg surfaces a transient status line from outside the program, such as LLM retry notices
+// delivered through tea.Program.Send.
+type NoticeMsg string
 
 // New returns a tea.Model ready to be mounted into a Program.
 func New(config Config) tea.Model {
+	config.KnowledgeBasePath = resolveKnowledgeBasePath(config.KnowledgeBasePath)
+	applyTheme(resolveTheme(config.Theme, os.Getenv("NO_COLOR") != ""))
+	if config.Offline || llm.IsOffline(config.LLM) {
+		config.Offline = true
+		config.LLM = nil
+	}
 	composer := textarea.New()
 	composer.Placeholder = composerNotePlaceholder
-	composer.CharLimit = 2000
+	composer.CharLimit = config.ComposerCharLimit
+	if composer.CharLimit <= 0 {
+		composer.CharLimit = defaultComposerCharLimit
+	}
 	composer.ShowLineNumbers = false
 	composer.Prompt = "> "
 	composer.SetPromptFunc(lipgloss.Width(composer.Prompt), func(line int) string {
@@ -89,10 +173,17 @@ func New(config Config) tea.Model {
 		sectionAnchors:          map[string]int{},
 		pendingFocusAnchor:      "",
 		jobBus:                  newJobBus(),
+		briefSlots:              newJobLimiter(briefConcurrency(config)),
 		layout:                  newPageLayout(),
 		transcriptViewportDirty: true,
+		keyActions:              config.KeyBindings.keyActions(),
+		sessions:                []paperSession{{}},
+		errorsSeenAt:            time.Now(),
 	}
 
+	if config.ClipboardUnavailable {
+		m.useOSC52Clipboard("no clipboard helper (xclip, xsel, wl-copy) was found")
+	}
 	m.setComposerMode(composerModeURL, composerURLPlaceholder, true)
 	m.resetBriefState()
 	return m
@@ -102,53 +193,111 @@ type model struct {
 	config Config
 	stage  stage
 
+	// fetchSeq numbers fetches so a result from a superseded fetch is ignored; fetchCtx and
+	// fetchCancel belong to the in-flight one.
 	fetchInProgress bool
+	fetchSeq        int
+	fetchCtx        context.Context
+	fetchCancel     context.CancelFunc
+	// resumeSnapshot is the conversation -resume is fetching; if the fetch fails the paper is
+	// rebuilt from it instead.
+	resumeSnapshot *notes.ConversationSnapshot
 
 	spinner            spinner.Model
 	viewport           viewport.Model
 	transcriptViewport viewport.Model
 	composer           textarea.Model
 
-	paper                   *arxiv.Paper
-	guide                   []guide.Step
-	suggestions             []notes.Candidate
-	selected                map[int]bool
-	persisted               map[int]bool
-	cursorLine              int
-	lineCount               int
-	manualNotes             []notes.Note
-	persistedNotes          []notes.Note
-	suggestionLines         map[int]int
-	viewportLines           []string
-	viewportContent         string
-	viewportDirty           bool
-	infoMessage             string
-	errorMessage            string
-	sectionAnchors          map[string]int
-	brief                   llm.ReadingBrief
-	briefSections           map[llm.BriefSectionKind]briefSectionState
-	briefFallbacks          map[llm.BriefSectionKind][]string
-	briefContexts           map[llm.BriefSectionKind]string
-	briefMessageIndex       map[llm.BriefSectionKind]int
-	briefChunks             []briefctx.Chunk
-	briefStreamCancels      map[llm.BriefSectionKind]context.CancelFunc
-	briefLoading            bool
-	suggestionLoading       bool
-	qaHistory               []qaExchange
-	queuedQuestions         []int
-	questionLoading         bool
+	paper      *arxiv.Paper
+	guide      []guide.Step
+	guideFocus string
+	scratchpad string
+	// paperWords and paperReadingTime size the paper's text for the hero; handlePaperResult
+	// measures them once, and zero words means the text is missing.
+	paperWords       int
+	paperReadingTime time.Duration
+	suggestions      []notes.Candidate
+	selected         map[int]bool
+	persisted        map[int]bool
+	cursorLine       int
+	lineCount        int
+	manualNotes      []notes.Note
+	draftCursor      int
+	persistedNotes   []notes.Note
+	// noteCursor highlights a persistedNotes entry in the note picker, which notePicker says is
+	// open to edit or delete one; editingNote is the stored note the composer is rewriting.
+	noteCursor       int
+	notePicker       notePickerPurpose
+	confirmingDelete bool
+	editingNote      *notes.Note
+	suggestionLines  map[int]int
+	viewportLines    []string
+	viewportContent  string
+	viewportDirty    bool
+	infoMessage      string
+	errorMessage     string
+	sectionAnchors   map[string]int
+	brief            llm.ReadingBrief
+	// briefLLM is the provider and model that wrote the brief, from this run or the saved snapshot.
+	briefLLM           *notes.LLMMetadata
+	briefSections      map[llm.BriefSectionKind]briefSectionState
+	briefFallbacks     map[llm.BriefSectionKind][]string
+	briefContexts      map[llm.BriefSectionKind]string
+	briefMessageIndex  map[llm.BriefSectionKind]int
+	briefChunks        []briefctx.Chunk
+	briefStreamCancels map[llm.BriefSectionKind]context.CancelFunc
+	briefStreamApplied map[llm.BriefSectionKind]time.Time
+	briefStreamPending map[llm.BriefSectionKind][]string
+	// briefStreamSeq numbers every section launch and briefStreamSeqs holds each section's current
+	// one, so deltas still buffered from a restarted stream are dropped.
+	briefStreamSeq    int
+	briefStreamSeqs   map[llm.BriefSectionKind]int
+	briefLoading      bool
+	suggestionLoading bool
+	// suggestionCursor is the suggested note n/p highlight and x toggles.
+	suggestionCursor int
+	// findQuery is the text / highlights in the viewport; findMatches lists the matching line
+	// indices and findCursor the one n/N last scrolled to.
+	findQuery       string
+	findMatches     []int
+	findCursor      int
+	qaHistory       []qaExchange
+	queuedQuestions []int
+	questionLoading bool
+	// keyActions maps display keys to KeyBindings action names.
+	keyActions map[string]string
+	// sessions holds one paperSession per tab; the entry at activeSession is stale while its state
+	// lives on the model.
+	sessions      []paperSession
+	activeSession int
+	// questionCancel stops the answer stream in flight; asking another question supersedes it.
+	questionCancel          context.CancelFunc
 	selectionAnchor         int
 	selectionActive         bool
 	mouseSelectionActive    bool
 	pendingFocusAnchor      string
 	jobBus                  *jobBus
+	briefSlots              *jobLimiter
 	layout                  pageLayout
 	transcriptEntries       []transcriptEntry
 	transcriptViewportDirty bool
 	composerMode            composerMode
+	composerLimitNotice     string
+	rawMarkdown             bool
+	osc52Clipboard          bool
+	pendingSnapshot         *pendingSnapshot
+	contextPreview          llm.BriefSectionKind
+	snapshotFlushScheduled  bool
+	// osc52Notice says why copies go through the terminal; it is shown once, after the first one.
+	osc52Notice string
+	// errorsView shows the error history in place of the conversation; errorsSeenAt is the newest
+	// error it has shown, so the status bar only badges errors posted after it.
+	errorsView   bool
+	errorsSeenAt time.Time
 }
 
 type paperResultMsg struct {
+	seq         int
 	paper       *arxiv.Paper
 	guide       []guide.Step
 	suggestions []notes.Candidate
@@ -163,18 +312,31 @@ type saveResultMsg struct {
 type briefSectionMsg struct {
 	paperID string
 	kind    llm.BriefSectionKind
+	// seq is the launch the result belongs to; see model.briefStreamSeqs.
+	seq     int
 	bullets []string
 	err     error
+	// continued marks the result of a follow-up prompt so it is never continued again.
+	continued bool
+	// partial marks bullets kept from a stream that hit the section timeout.
+	partial bool
 }
 
 type briefSectionStreamMsg struct {
 	paperID string
 	kind    llm.BriefSectionKind
+	seq     int
 	bullets []string
 	done    bool
 	updates <-chan llm.BriefSectionDelta
 }
 
+// briefStreamFlushMsg applies the latest debounced stream bullets for a section.
+type briefStreamFlushMsg struct {
+	paperID string
+	kind    llm.BriefSectionKind
+}
+
 type questionResultMsg struct {
 	paperID string
 	index   int
@@ -182,22 +344,42 @@ type questionResultMsg struct {
 	err     error
 }
 
+// answerStreamMsg carries the next chunk of the answer to qaHistory[index].
+type answerStreamMsg struct {
+	paperID string
+	index   int
+	text    string
+	updates <-chan llm.AnswerDelta
+}
+
 type suggestionResultMsg struct {
 	paperID     string
 	suggestions []notes.Candidate
 	err         error
 }
 
+// suggestionStreamMsg carries the next suggested note while the suggestion job is still running.
+type suggestionStreamMsg struct {
+	paperID   string
+	candidate notes.Candidate
+	updates   <-chan notes.Candidate
+}
+
 type transcriptEntry struct {
 	Kind      string
 	Content   string
 	Timestamp time.Time
 }
 
+// briefSectionState tracks one brief section. Regenerable marks a section a previous session
+// recorded as finished but saved without any content, so it can be rebuilt on its own.
 type briefSectionState struct {
-	Loading   bool
-	Completed bool
-	Error     string
+	Loading     bool
+	Completed   bool
+	Regenerable bool
+	// Partial marks a completed section whose stream timed out; its bullets are incomplete.
+	Partial bool
+	Error   string
 }
 
 const (
@@ -213,7 +395,30 @@ var briefSectionKinds = []llm.BriefSectionKind{
 }
 
 func (m *model) Init() tea.Cmd {
-	return textarea.Blink
+	return tea.Batch(textarea.Blink, m.initialFetchCmd())
+}
+
+// initialFetchCmd loads the paper named on the command line, or the arXiv identifier on the
+// clipboard when FromClipboard is set, so the session opens on it instead of the URL prompt.
+func (m *model) initialFetchCmd() tea.Cmd {
+	query := strings.TrimSpace(m.config.InitialPaper)
+	if query == "" && m.config.Resume {
+		return m.resumeLatestCmd()
+	}
+	if query == "" && m.config.FromClipboard {
+		text, err := clipboardRead()
+		if err == nil {
+			query = arxiv.IdentifierFromText(text)
+		}
+		if query == "" {
+			m.infoMessage = "No arXiv identifier on the clipboard. Paste an arXiv url or identifier to begin."
+			return nil
+		}
+	}
+	if query == "" {
+		return nil
+	}
+	return m.startFetchCmd(query)
 }
 
 func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -235,13 +440,13 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyCtrlC:
-			return m, tea.Quit
+			return m, m.quitCmd()
 		}
 		return m.handleKey(msg)
 	case tea.MouseMsg:
 		if m.stage == stageDisplay || m.stage == stageInput {
-			if m.handleMouseSelection(msg) {
-				return m, nil
+			if cmd, handled := m.handleMouseSelection(msg); handled {
+				return m, cmd
 			}
 			var cmd tea.Cmd
 			m.viewport, cmd = m.viewport.Update(msg)
@@ -252,14 +457,41 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.handlePaperResult(msg)
 	case saveResultMsg:
 		return m, m.handleSaveResult(msg)
+	case autosaveResultMsg:
+		return m, m.handleAutosaveResult(msg)
+	case noteChangeResultMsg:
+		return m, m.handleNoteChangeResult(msg)
 	case briefSectionMsg:
 		return m, m.handleBriefSectionResult(msg)
 	case briefSectionStreamMsg:
 		return m, m.handleBriefSectionStream(msg)
+	case snapshotFlushMsg:
+		return m, m.flushConversationSnapshotCmd()
+	case briefStreamFlushMsg:
+		m.handleBriefStreamFlush(msg)
+		return m, nil
 	case questionResultMsg:
 		return m, m.handleQuestionResult(msg)
+	case answerStreamMsg:
+		return m, m.handleAnswerStream(msg)
+	case suggestionStreamMsg:
+		return m, m.handleSuggestionStream(msg)
 	case suggestionResultMsg:
 		return m, m.handleSuggestionResult(msg)
+	case exportResultMsg:
+		return m, m.handleExportResult(msg)
+	case osc52ResultMsg:
+		return m, m.handleOSC52Result(msg)
+	case gapResultMsg:
+		return m, m.handleGapResult(msg)
+	case fullTextResultMsg:
+		return m, m.handleFullTextResult(msg)
+	case openPDFResultMsg:
+		return m, m.handleOpenPDFResult(msg)
+	case NoticeMsg:
+		m.infoMessage = string(msg)
+		m.markViewportDirty()
+		return m, nil
 	case tea.WindowSizeMsg:
 		m.layout.Update(msg.Width, msg.Height)
 		composerWidth := m.layout.viewportWidth
@@ -276,47 +508,50 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *model) handleMouseSelection(msg tea.MouseMsg) bool {
+func (m *model) handleMouseSelection(msg tea.MouseMsg) (tea.Cmd, bool) {
 	switch msg.Type {
 	case tea.MouseLeft, tea.MouseMotion, tea.MouseRelease:
 	default:
-		return false
+		return nil, false
 	}
 
 	line, ok := m.viewportLineForMouse(msg)
 	switch msg.Type {
 	case tea.MouseLeft:
 		if !ok {
-			return false
+			return nil, false
 		}
 		m.mouseSelectionActive = true
 		m.selectionActive = true
 		m.selectionAnchor = line
 		m.cursorLine = line
+		if index, ok := m.suggestionLines[line]; ok {
+			m.suggestionCursor = index
+		}
 		m.markViewportDirty()
-		return true
+		return nil, true
 	case tea.MouseMotion:
 		if !m.mouseSelectionActive || !ok {
-			return false
+			return nil, false
 		}
 		if line != m.cursorLine {
 			m.cursorLine = line
 			m.markViewportDirty()
 		}
-		return true
+		return nil, true
 	case tea.MouseRelease:
 		if !m.mouseSelectionActive {
-			return false
+			return nil, false
 		}
 		if ok {
 			m.cursorLine = line
 		}
-		m.copySelectionToClipboard()
+		cmd := m.copySelectionToClipboard()
 		m.clearSelection()
 		m.markViewportDirty()
-		return true
+		return cmd, true
 	default:
-		return false
+		return nil, false
 	}
 }
 
@@ -341,6 +576,16 @@ func (m *model) viewportStartRow() int {
 }
 
 func (m *model) handleKey(key tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.stage == stageInput || m.stage == stageDisplay {
+		switch m.keyActions[key.String()] {
+		case keyActionNextTab:
+			m.switchSession(1)
+			return m, nil
+		case keyActionPrevTab:
+			m.switchSession(-1)
+			return m, nil
+		}
+	}
 	switch m.stage {
 	case stageInput:
 		if cmd, handled := m.processComposerKey(key); handled {
@@ -348,9 +593,14 @@ func (m *model) handleKey(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case stageLoading:
-		var cmd tea.Cmd
-		m.spinner, cmd = m.spinner.Update(key)
-		return m, cmd
+		// The composer stays in URL mode while a paper loads so a new URL can replace the fetch;
+		// notes are held back because they would attach to the previous paper.
+		if m.composerMode == composerModeURL && !isCtrlEnter(key) {
+			if cmd, handled := m.processComposerKey(key); handled {
+				return m, cmd
+			}
+		}
+		return m, nil
 	case stageDisplay:
 		return m.handleDisplayKey(key)
 	case stageSaving:
@@ -364,22 +614,54 @@ func (m *model) handleDisplayKey(key tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if cmd, handled := m.processComposerKey(key); handled {
 		return m, cmd
 	}
+	if key.Type == tea.KeyEsc && m.findQuery != "" {
+		m.clearFind()
+		m.infoMessage = "Find cleared."
+		return m, nil
+	}
 	handled := true
-	switch key.String() {
-	case "m":
+	switch m.keyActions[key.String()] {
+	case keyActionManualNote:
 		return m, m.actionManualNoteCmd()
-	case "g":
+	case keyActionTop:
 		m.scrollToTop()
-	case "G":
+	case keyActionBottom:
 		m.scrollToBottom()
-	case "]":
+	case keyActionNextSection:
 		m.jumpToRelativeSection(1)
-	case "[":
+	case keyActionPrevSection:
 		m.jumpToRelativeSection(-1)
-	case "r":
+	case keyActionLoadNew:
 		return m, m.actionLoadNewCmd()
-	case "s":
+	case keyActionSave:
 		return m, m.actionSaveCmd()
+	case keyActionCopyBrief:
+		return m, m.actionCopyBriefCmd("")
+	case keyActionNextSuggestion:
+		// While a find is active, n steps through its matches instead.
+		if m.findQuery != "" {
+			m.moveFindMatch(1)
+		} else {
+			m.moveSuggestionCursor(1)
+		}
+	case keyActionPrevMatch:
+		m.moveFindMatch(-1)
+	case keyActionFind:
+		return m, m.actionFindCmd()
+	case keyActionPrevSuggestion:
+		m.moveSuggestionCursor(-1)
+	case keyActionToggleSuggestion:
+		m.toggleSuggestion()
+	case keyActionHalfPageDown:
+		m.scrollBy(max(m.viewport.Height/2, 1))
+	case keyActionHalfPageUp:
+		m.scrollBy(-max(m.viewport.Height/2, 1))
+	case keyActionPageDown:
+		m.scrollBy(max(m.viewport.Height, 1))
+	case keyActionPageUp:
+		m.scrollBy(-max(m.viewport.Height, 1))
+	case keyActionOpenPDF:
+		return m, m.actionOpenPDFCmd()
 	default:
 		handled = false
 	}
@@ -397,12 +679,37 @@ func (m *model) processComposerKey(key tea.KeyMsg) (tea.Cmd, bool) {
 	}
 	switch key.Type {
 	case tea.KeyCtrlC:
-		return tea.Quit, true
+		return m.quitCmd(), true
 	case tea.KeyEsc:
+		if m.errorsView {
+			m.closeErrorsView()
+			return nil, true
+		}
+		if m.contextPreview != "" {
+			m.closeContextPreview()
+			return nil, true
+		}
 		m.cancelComposerEntry()
 		return nil, true
+	case tea.KeyCtrlR:
+		m.toggleRawMarkdown()
+		return nil, true
+	}
+	if m.composerMode == composerModeScratchpad && (isCtrlEnter(key) || isAltEnter(key)) {
+		return m.saveScratchpadCmd(), true
+	}
+	if m.composerMode == composerModePickNote {
+		return m.handleNotePickerKey(key), true
+	}
+	if m.composerMode == composerModeEditNote && (isCtrlEnter(key) || isAltEnter(key)) {
+		return m.saveEditedNoteCmd(), true
+	}
+	if m.composerMode != composerModeScratchpad && m.handleDraftNoteKey(key) {
+		return nil, true
 	}
 	switch {
+	case m.composerMode == composerModeScratchpad || m.composerMode == composerModeEditNote:
+		// Plain Enter falls through to the textarea so the scratchpad and notes can span lines.
 	case isCtrlEnter(key):
 		m.composerMode = composerModeNote
 		return m.submitComposer(), true
@@ -410,7 +717,7 @@ func (m *model) processComposerKey(key tea.KeyMsg) (tea.Cmd, bool) {
 		m.composerMode = composerModeURL
 		return m.submitComposer(), true
 	case key.Type == tea.KeyEnter:
-		if m.composerMode == composerModeURL {
+		if m.composerMode == composerModeURL || m.composerMode == composerModeSearch || m.composerMode == composerModeFind {
 			return m.submitComposer(), true
 		}
 		m.composerMode = composerModeQuestion
@@ -419,10 +726,57 @@ func (m *model) processComposerKey(key tea.KeyMsg) (tea.Cmd, bool) {
 	var cmd tea.Cmd
 	m.composer, cmd = m.composer.Update(key)
 	m.updateComposerHeight()
+	m.updateComposerLimitNotice()
 	m.markViewportDirty()
 	return cmd, true
 }
 
+// toggleRawMarkdown switches the conversation between terminal-rendered markdown and the stored
+// entry text, which helps when debugging prompts or rendering bugs.
+func (m *model) toggleRawMarkdown() {
+	m.rawMarkdown = !m.rawMarkdown
+	if m.rawMarkdown {
+		m.infoMessage = "Showing raw markdown. Press Ctrl+R to render it again."
+	} else {
+		m.infoMessage = "Rendering markdown."
+	}
+	m.markTranscriptDirty()
+	m.markViewportDirty()
+}
+
+// composerCounterText returns the "N/limit" counter once input passes composerCounterRatio of the
+// limit, and "" before that so short questions keep a clean composer header.
+func (m *model) composerCounterText() string {
+	limit := m.composer.CharLimit
+	if limit <= 0 {
+		return ""
+	}
+	count := utf8.RuneCountInString(m.composer.Value())
+	if float64(count) < float64(limit)*composerCounterRatio {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", count, limit)
+}
+
+// updateComposerLimitNotice warns in infoMessage as the limit approaches and clears its own warning
+// (but not unrelated status) once the input shrinks again.
+func (m *model) updateComposerLimitNotice() {
+	limit := m.composer.CharLimit
+	if m.composerCounterText() == "" {
+		if m.composerLimitNotice != "" && m.infoMessage == m.composerLimitNotice {
+			m.infoMessage = ""
+		}
+		m.composerLimitNotice = ""
+		return
+	}
+	if utf8.RuneCountInString(m.composer.Value()) >= limit {
+		m.composerLimitNotice = fmt.Sprintf("Composer limit of %d characters reached; further input is dropped.", limit)
+	} else {
+		m.composerLimitNotice = fmt.Sprintf("Composer nearing its %d character limit.", limit)
+	}
+	m.infoMessage = m.composerLimitNotice
+}
+
 func (m *model) collectSelectedNotes() []notes.Note {
 	if m.paper == nil {
 		return nil
@@ -448,13 +802,13 @@ func (m *model) selectedCount() int {
 }
 
 func (m *model) refreshPersistedState() {
-	if m.paper == nil || m.config.KnowledgeBasePath == "" {
+	if m.paper == nil {
 		m.persistedNotes = nil
 		m.persisted = map[int]bool{}
 		m.markViewportDirty()
 		return
 	}
-	records, err := notes.Load(m.config.KnowledgeBasePath)
+	records, err := m.knowledgeBase().Load()
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			m.persistedNotes = nil
@@ -485,14 +839,12 @@ func (m *model) refreshPersistedState() {
 
 func (m *model) hydrateConversationHistory() {
 	m.transcriptEntries = nil
-	if m.paper == nil || m.config.KnowledgeBasePath == "" {
+	m.scratchpad = ""
+	if m.paper == nil {
 		return
 	}
-	snapshots, err := notes.LoadConversationSnapshots(m.config.KnowledgeBasePath)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return
-		}
+	snapshots, err := m.knowledgeBase().LoadConversationSnapshots()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
 		m.errorMessage = fmt.Sprintf("knowledge base error: %v", err)
 		return
 	}
@@ -503,9 +855,19 @@ func (m *model) hydrateConversationHistory() {
 			break
 		}
 	}
+	// A batch still waiting to be flushed belongs to the history too; it is shown from memory
+	// rather than written first, so loading a paper does not block on the knowledge base.
+	if pending := m.pendingSnapshot; pending != nil && pending.paper.ID == m.paper.ID {
+		if snapshot == nil {
+			snapshot = &notes.ConversationSnapshot{PaperID: m.paper.ID, PaperTitle: m.paper.Title}
+		}
+		snapshot.Apply(pending.update)
+	}
 	if snapshot == nil {
 		return
 	}
+	m.scratchpad = snapshot.Scratchpad
+	m.briefLLM = snapshot.LLM
 	if snapshot.Brief != nil {
 		m.brief = llm.ReadingBrief{
 			Summary:   append([]string(nil), snapshot.Brief.Summary...),
@@ -671,7 +1033,11 @@ func (m *model) refreshViewport() {
 		}
 	}
 
-	m.viewport.SetContent(view.body)
+	body := view.body
+	if m.findQuery != "" {
+		body = m.highlightFindMatches(m.viewportLines)
+	}
+	m.viewport.SetContent(body)
 	targetYOffset := prevYOffset
 	if forcedYOffset >= 0 {
 		targetYOffset = forcedYOffset
@@ -693,7 +1059,15 @@ func (m *model) refreshTranscript() {
 		wrap = 20
 	}
 	var b strings.Builder
-	for _, entry := range m.transcriptEntries {
+	cutoff, hidden := m.transcriptCutoff()
+	if hidden > 0 {
+		b.WriteString(helperStyle.Render(collapsedTranscriptPlaceholder(hidden)))
+		b.WriteString("\n\n")
+	}
+	for idx, entry := range m.transcriptEntries {
+		if idx < cutoff && !alwaysRenderedEntry(entry) {
+			continue
+		}
 		header := fmt.Sprintf("[%s] %s", entry.Timestamp.Format("15:04:05"), entry.Kind)
 		b.WriteString(helperStyle.Render(header))
 		b.WriteRune('\n')
@@ -745,32 +1119,101 @@ func (m *model) cancelComposerEntry() {
 		m.composer.SetValue("")
 		m.setComposerMode(composerModeNote, composerNotePlaceholder, false)
 		m.infoMessage = "Question canceled."
+	case composerModeScratchpad:
+		m.composer.SetValue("")
+		m.setComposerMode(composerModeNote, composerNotePlaceholder, false)
+		m.infoMessage = "Scratchpad edit canceled."
+	case composerModeSearch:
+		m.composer.SetValue("")
+		m.setComposerMode(composerModeNote, composerNotePlaceholder, false)
+		m.infoMessage = "Search canceled."
+	case composerModeFind:
+		m.composer.SetValue("")
+		m.setComposerMode(composerModeNote, composerNotePlaceholder, false)
+		m.clearFind()
+		m.infoMessage = "Find canceled."
+	case composerModePickNote, composerModeEditNote:
+		m.editingNote = nil
+		m.confirmingDelete = false
+		m.composer.SetValue("")
+		m.setComposerMode(composerModeNote, composerNotePlaceholder, false)
+		if m.notePicker == pickNoteToDelete {
+			m.infoMessage = "Note kept."
+		} else {
+			m.infoMessage = "Note edit canceled."
+		}
 	default:
 		m.composer.SetValue("")
 		m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
 	}
 }
 
+// startFetchCmd starts loading value, as an abstract-only preview when -preview is set.
+func (m *model) startFetchCmd(value string) tea.Cmd {
+	return m.startFetch(value, m.config.Preview)
+}
+
+// startFetch starts loading value, canceling a fetch that is still in flight so the newest URL
+// always wins. A preview fetches only the metadata.
+func (m *model) startFetch(value string, preview bool) tea.Cmd {
+	if m.fetchInProgress && m.fetchCancel != nil {
+		m.fetchCancel()
+		m.appendTranscript("fetch", "Canceled the previous fetch.")
+	}
+	// A new paper gets its own tab; the open one stays a tab switch away.
+	m.openSessionTab()
+	m.resumeSnapshot = nil
+	m.fetchSeq++
+	m.fetchCtx, m.fetchCancel = context.WithCancel(context.Background())
+	m.fetchInProgress = true
+	m.stage = stageLoading
+	m.errorMessage = ""
+	m.infoMessage = "Fetching metadata… Submit another URL to replace this fetch."
+	m.appendTranscript("fetch", fmt.Sprintf("Fetching %s", value))
+	m.composer.SetValue("")
+	m.setComposerMode(composerModeURL, composerURLPlaceholder, true)
+	return tea.Batch(m.spinner.Tick, m.jobBus.Start(jobKindFetch, fetchPaperJob(m.fetchCtx, m.fetchSeq, value, preview, fetchTimeout(m.config))))
+}
+
+// maxFetchInputLength is far longer than any arXiv URL; anything past it was pasted by mistake.
+const maxFetchInputLength = 200
+
+// looksLikeProse reports whether URL-mode input clearly cannot be an arXiv URL or identifier, so it
+// can be rejected before a fetch job round-trips to the same error.
+func looksLikeProse(value string) bool {
+	return len(value) > maxFetchInputLength || strings.ContainsAny(value, " \t\n") || arxiv.Identifier(value) == ""
+}
+
+// rejectProseFetch keeps the pasted text in the composer and, when a paper is open, switches to note
+// mode so the same keystrokes can send it as a question or note instead.
+func (m *model) rejectProseFetch() {
+	const notAnID = "That doesn't look like an arXiv URL or identifier"
+	if m.paper == nil {
+		m.infoMessage = notAnID + " (e.g. https://arxiv.org/abs/2101.00001 or 2101.00001)."
+		return
+	}
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.infoMessage = notAnID + ". Press Enter to ask it as a question or Ctrl+Enter to save it as a note."
+}
+
 func (m *model) submitComposer() tea.Cmd {
 	value := strings.TrimSpace(m.composer.Value())
 	if value == "" {
 		m.infoMessage = "Type something before submitting."
 		return nil
 	}
+	if m.composerMode != composerModeURL {
+		if cmd, ok := m.runSlashCommand(value); ok {
+			return cmd
+		}
+	}
 	switch m.composerMode {
 	case composerModeURL:
-		if m.fetchInProgress {
-			m.infoMessage = fetchInProgressMessage
+		if looksLikeProse(value) {
+			m.rejectProseFetch()
 			return nil
 		}
-		m.fetchInProgress = true
-		m.stage = stageLoading
-		m.errorMessage = ""
-		m.infoMessage = "Fetching metadata…"
-		m.appendTranscript("fetch", fmt.Sprintf("Fetching %s", value))
-		m.composer.SetValue("")
-		m.setComposerMode(composerModeURL, composerURLPlaceholder, false)
-		return tea.Batch(m.spinner.Tick, m.jobBus.Start(jobKindFetch, fetchPaperJob(value)))
+		return m.startFetchCmd(value)
 	case composerModeNote:
 		if m.paper == nil {
 			m.infoMessage = "Load a paper before drafting notes."
@@ -778,37 +1221,60 @@ func (m *model) submitComposer() tea.Cmd {
 		}
 		createdAt := time.Now()
 		title := trimmedTitle(value)
-		m.manualNotes = append(m.manualNotes, notes.Note{
+		tags := notes.ExtractTags(value)
+		note := notes.Note{
 			PaperID:    m.paper.ID,
 			PaperTitle: m.paper.Title,
 			Title:      title,
 			Body:       value,
 			Kind:       "manual",
 			CreatedAt:  createdAt,
-		})
-		m.infoMessage = fmt.Sprintf("Manual note added (%d total).", len(m.manualNotes))
+			Tags:       tags,
+		}
+		if !m.config.Autosave {
+			m.manualNotes = append(m.manualNotes, note)
+			m.draftCursor = len(m.manualNotes) - 1
+			m.infoMessage = fmt.Sprintf("Manual note added (%d total).", len(m.manualNotes))
+		}
 		m.markViewportDirty()
 		m.appendTranscript("note", value)
 		m.composer.SetValue("")
 		m.setComposerMode(composerModeNote, composerNotePlaceholder, false)
-		snapshotCmd := m.appendConversationSnapshotCmd(notes.SnapshotUpdate{
+		update := notes.SnapshotUpdate{
 			Notes: []notes.SnapshotNote{
 				{
 					Title:     title,
 					Body:      value,
 					Kind:      "manual",
 					CreatedAt: createdAt,
+					Tags:      tags,
 				},
 			},
-		})
-		return snapshotCmd
+		}
+		if !m.config.Autosave {
+			return m.appendConversationSnapshotCmd(update)
+		}
+		// Both writes rewrite the knowledge base, so an unbatched snapshot update rides in the
+		// autosave job and lands after the note instead of racing it.
+		if m.config.SnapshotFlushInterval > 0 {
+			return tea.Batch(m.appendConversationSnapshotCmd(update), m.autosaveNoteCmd(note, nil))
+		}
+		return m.autosaveNoteCmd(note, appendConversationSnapshotJob(m.knowledgeBase(), m.paper, update))
 	case composerModeQuestion:
 		if m.paper == nil {
 			m.infoMessage = "Load a paper before asking questions."
 			return nil
 		}
 		if m.config.LLM == nil {
-			m.infoMessage = "Configure Ollama to unlock questions."
+			m.infoMessage = m.llmUnavailableNotice("Configure Ollama to unlock questions.")
+			return nil
+		}
+		if m.paper.Restored {
+			m.infoMessage = arxiv.RestoredNotice
+			return nil
+		}
+		if m.paper.Preview {
+			m.infoMessage = arxiv.PreviewNotice + " " + previewFullTextHint
 			return nil
 		}
 		askedAt := time.Now()
@@ -845,6 +1311,14 @@ func (m *model) submitComposer() tea.Cmd {
 			return snapshotCmd
 		}
 		return questionCmd
+	case composerModeSearch:
+		m.composer.SetValue("")
+		m.setComposerMode(composerModeNote, composerNotePlaceholder, false)
+		m.searchNotes(value)
+		return nil
+	case composerModeFind:
+		m.runFind(value)
+		return nil
 	default:
 		m.infoMessage = "Composer inactive. Press m or q to begin."
 		return nil
@@ -858,6 +1332,9 @@ func (m *model) briefReadyForQuestions() bool {
 	if strings.TrimSpace(m.paper.FullText) == "" {
 		return true
 	}
+	if !m.config.AutoBrief && !m.briefLoading {
+		return true
+	}
 	if m.briefSections == nil {
 		return false
 	}
@@ -891,7 +1368,55 @@ func (m *model) launchQuestion(index int, infoMessage string) tea.Cmd {
 		m.infoMessage = "Answering question via LLM…"
 	}
 	m.questionLoading = true
-	return tea.Batch(m.spinner.Tick, m.jobBus.Start(jobKindQuestion, questionAnswerJob(index, m.config.LLM, m.paper, entry.Question)))
+	m.cancelQuestionStream()
+	streamCtx, cancel := context.WithCancel(context.Background())
+	m.questionCancel = cancel
+	runner, updates := questionAnswerJob(index, m.config.LLM, m.paper, entry.Question, streamCtx, m.questionTimeout())
+	return tea.Batch(m.spinner.Tick, m.jobBus.Start(jobKindQuestion, runner), waitAnswerStream(m.paper.ID, index, updates))
+}
+
+func (m *model) cancelQuestionStream() {
+	if m.questionCancel != nil {
+		m.questionCancel()
+		m.questionCancel = nil
+	}
+}
+
+func waitAnswerStream(paperID string, index int, updates <-chan llm.AnswerDelta) tea.Cmd {
+	return func() tea.Msg {
+		delta, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return answerStreamMsg{paperID: paperID, index: index, text: delta.Text, updates: updates}
+	}
+}
+
+// handleAnswerStream appends a chunk to the pending answer, creating its draft transcript entry on
+// the first visible text and updating it in place afterwards. The channel is drained even for stale
+// answers so the job never blocks on a reader that went away.
+func (m *model) handleAnswerStream(msg answerStreamMsg) tea.Cmd {
+	next := waitAnswerStream(msg.paperID, msg.index, msg.updates)
+	if m.paper == nil || m.paper.ID != msg.paperID || msg.index < 0 || msg.index >= len(m.qaHistory) {
+		return next
+	}
+	entry := &m.qaHistory[msg.index]
+	if !entry.Pending {
+		return next
+	}
+	entry.Answer += msg.text
+	draft := strings.TrimSpace(entry.Answer)
+	if draft == "" {
+		return next
+	}
+	if entry.TranscriptIndex >= 0 && entry.TranscriptIndex < len(m.transcriptEntries) {
+		m.transcriptEntries[entry.TranscriptIndex].Content = draft
+		m.markTranscriptDirty()
+		m.markViewportDirty()
+	} else {
+		entry.TranscriptIndex = m.appendTranscriptEntry("answer_draft", draft)
+	}
+	return next
 }
 
 func (m *model) maybeStartQueuedQuestion() tea.Cmd {
@@ -1003,18 +1528,20 @@ func (m *model) selectedText() string {
 	return strings.TrimSpace(stripANSI(strings.Join(lines, "\n")))
 }
 
-func (m *model) copySelectionToClipboard() {
+func (m *model) copySelectionToClipboard() tea.Cmd {
 	text := m.selectedText()
 	if text == "" {
 		m.infoMessage = "No text selected."
-		return
-	}
-	if err := clipboardWrite(text); err != nil {
-		m.errorMessage = fmt.Sprintf("Clipboard copy failed: %v", err)
-		return
+		return nil
 	}
+	osc52 := m.copyText(text)
 	m.errorMessage = ""
+	if osc52 != nil {
+		m.infoMessage = "Selection sent to the terminal clipboard (OSC 52)."
+		return osc52
+	}
 	m.infoMessage = "Selection copied to clipboard."
+	return nil
 }
 
 var ansiEscapeCodes = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]|\x1b\]8;;.*?\x1b\\`)
@@ -1023,8 +1550,6 @@ func stripANSI(text string) string {
 	return ansiEscapeCodes.ReplaceAllString(text, "")
 }
 
-var clipboardWrite = clipboard.WriteAll
-
 func (m *model) scrollToTop() {
 	m.viewport.SetYOffset(0)
 	if m.lineCount > 0 {
@@ -1052,6 +1577,30 @@ func (m *model) scrollToBottom() {
 	m.infoMessage = "Jumped to bottom."
 }
 
+// scrollBy moves the viewport delta lines (clamped to the content) and the cursor by the same
+// amount, keeping it on screen, so a selection started after scrolling begins where you look.
+func (m *model) scrollBy(delta int) {
+	offset := m.clampYOffset(m.viewport.YOffset + delta)
+	if offset == m.viewport.YOffset {
+		if delta > 0 {
+			m.infoMessage = "Already at the bottom."
+		} else {
+			m.infoMessage = "Already at the top."
+		}
+		return
+	}
+	moved := offset - m.viewport.YOffset
+	m.viewport.SetYOffset(offset)
+	cursor := m.cursorLine + moved
+	if last := offset + max(m.viewport.Height, 1) - 1; cursor > last {
+		cursor = last
+	}
+	m.cursorLine = max(min(cursor, m.lineCount-1), offset, 0)
+	m.clearSelection()
+	m.markViewportDirty()
+	m.refreshViewportIfDirty()
+}
+
 func (m *model) jumpToSection(anchor string) {
 	if m.sectionAnchors == nil {
 		m.infoMessage = "Load a paper to jump between sections."
@@ -1088,7 +1637,12 @@ func (m *model) resetBriefState() {
 			cancel()
 		}
 	}
+	// Continuations run without a stream cancel func, so the bus stops those.
+	for _, kind := range briefSectionKinds {
+		m.jobBus.Cancel(jobKindForSection(kind))
+	}
 	m.brief = llm.ReadingBrief{}
+	m.briefLLM = nil
 	m.briefSections = map[llm.BriefSectionKind]briefSectionState{}
 	for _, kind := range briefSectionKinds {
 		m.briefSections[kind] = briefSectionState{}
@@ -1097,6 +1651,9 @@ func (m *model) resetBriefState() {
 	m.briefContexts = nil
 	m.briefChunks = nil
 	m.briefStreamCancels = map[llm.BriefSectionKind]context.CancelFunc{}
+	m.briefStreamApplied = map[llm.BriefSectionKind]time.Time{}
+	m.briefStreamPending = map[llm.BriefSectionKind][]string{}
+	m.briefStreamSeqs = map[llm.BriefSectionKind]int{}
 	m.briefLoading = false
 	m.briefMessageIndex = nil
 }
@@ -1138,6 +1695,7 @@ func (m *model) markBriefSectionRunning(kind llm.BriefSectionKind) {
 	m.ensureBriefSections()
 	state := m.briefSections[kind]
 	state.Loading = true
+	state.Regenerable = false
 	state.Error = ""
 	m.briefSections[kind] = state
 	m.briefLoading = true
@@ -1147,6 +1705,7 @@ func (m *model) markBriefSectionResult(kind llm.BriefSectionKind, err error) bri
 	m.ensureBriefSections()
 	state := m.briefSections[kind]
 	state.Loading = false
+	state.Partial = false
 	if err != nil {
 		state.Error = err.Error()
 		state.Completed = false
@@ -1390,13 +1949,31 @@ func (m *model) briefBullets(kind llm.BriefSectionKind) []string {
 	}
 }
 
+// offlineNotice replaces the "configure a provider" hints when Config.Offline is set.
+const offlineNotice = "Offline mode: briefs, questions, and note suggestions need the LLM and are turned off."
+
+// llmUnavailableNotice returns hint, the way to enable an LLM feature, unless offline mode is why
+// it is off.
+func (m *model) llmUnavailableNotice(hint string) string {
+	if m.config.Offline {
+		return offlineNotice
+	}
+	return hint
+}
+
 func (m *model) pendingBriefNotice() string {
 	if m.config.LLM == nil {
-		return "Configure an LLM provider to generate this section."
+		return m.llmUnavailableNotice("Configure an LLM provider to generate this section.")
+	}
+	if m.paper != nil && m.paper.Notice() != "" {
+		return m.paper.Notice()
 	}
 	if m.paper != nil && strings.TrimSpace(m.paper.FullText) == "" {
 		return "PDF text missing; brief generation skipped."
 	}
+	if !m.config.AutoBrief && !m.briefLoading {
+		return "Run /brief to generate this section."
+	}
 	return "Awaiting LLM output."
 }
 
@@ -1420,6 +1997,41 @@ func (m *model) fallbackForSection(kind llm.BriefSectionKind) []string {
 	return m.briefFallbacks[kind]
 }
 
+// streamRestatesFallback reports whether a partial stream only repeats the fallback that is still on
+// screen, in which case rewriting the message would just flicker between equivalent bullets.
+func (m *model) streamRestatesFallback(kind llm.BriefSectionKind, bullets []string) bool {
+	fallback := m.fallbackForSection(kind)
+	if len(fallback) == 0 || m.briefMessageIndex == nil {
+		return false
+	}
+	idx, ok := m.briefMessageIndex[kind]
+	if !ok || idx < 0 || idx >= len(m.transcriptEntries) {
+		return false
+	}
+	if m.transcriptEntries[idx].Content != briefMessageContentWithNotice(kind, fallback, fallbackNotice(kind)) {
+		return false
+	}
+	incoming := normalizeBriefBullets(bullets)
+	return incoming != "" && strings.HasPrefix(normalizeBriefBullets(fallback), incoming)
+}
+
+// normalizeBriefBullets flattens bullets for comparison: headings, list markers, case, and
+// whitespace differences are ignored.
+func normalizeBriefBullets(bullets []string) string {
+	var lines []string
+	for _, bullet := range bullets {
+		for _, line := range strings.Split(bullet, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			line = strings.TrimLeft(line, "-*•· ")
+			lines = append(lines, strings.ToLower(strings.Join(strings.Fields(line), " ")))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m *model) mapBriefMessages() {
 	if len(m.transcriptEntries) == 0 {
 		m.briefMessageIndex = nil
@@ -1441,18 +2053,61 @@ func (m *model) markBriefSectionsFromSnapshot() {
 	}
 	m.ensureBriefSections()
 	for _, kind := range briefSectionKinds {
-		if _, ok := m.briefMessageIndex[kind]; !ok {
+		idx, ok := m.briefMessageIndex[kind]
+		if !ok {
 			continue
 		}
 		state := m.briefSections[kind]
 		state.Loading = false
 		state.Error = ""
 		state.Completed = true
+		state.Regenerable = false
+		if len(m.briefBullets(kind)) == 0 && briefEntryEmpty(kind, m.transcriptEntries[idx].Content) {
+			state.Completed = false
+			state.Regenerable = true
+			m.transcriptEntries[idx].Content = briefMessageContentWithNotice(kind, nil, regenerableNotice(kind))
+		}
 		m.briefSections[kind] = state
 	}
 	m.briefLoading = m.anyBriefSectionLoading()
 }
 
+// briefEntryEmpty reports whether a stored brief message has nothing beyond its heading, status
+// notices, or the "<Section> ready." placeholder written when a section finished without bullets.
+func briefEntryEmpty(kind llm.BriefSectionKind, content string) bool {
+	ready := strings.ToLower(briefSectionTitle(kind) + " ready.")
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "", strings.HasPrefix(trimmed, ">"), stripMarkdownHeading(trimmed) != "":
+			continue
+		case strings.ToLower(trimmed) == ready:
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func partialBriefNotice(kind llm.BriefSectionKind) string {
+	return fmt.Sprintf("Partial: generation timed out. Run /brief %s to regenerate it.", briefSectionCommandName(kind))
+}
+
+func regenerableNotice(kind llm.BriefSectionKind) string {
+	return fmt.Sprintf("Saved empty in an earlier session. Run /brief %s to regenerate it.", briefSectionCommandName(kind))
+}
+
+// regenerableBriefSections lists sections restored empty from the conversation snapshot.
+func (m *model) regenerableBriefSections() []llm.BriefSectionKind {
+	var kinds []llm.BriefSectionKind
+	for _, kind := range briefSectionKinds {
+		if m.briefSections[kind].Regenerable {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
 func (m *model) hasSnapshotBriefs() bool {
 	if m.briefMessageIndex == nil || len(m.transcriptEntries) == 0 {
 		return false
@@ -1566,9 +2221,15 @@ func fallbackDeepDiveBullets(paper *arxiv.Paper) []string {
 	if len(paper.Authors) > 0 {
 		bullets = append(bullets, fmt.Sprintf("Authors to explore: %s", shortenList(paper.Authors, 4)))
 	}
+	if paper.CitationCount > 0 {
+		bullets = append(bullets, fmt.Sprintf("Cited by %d papers on Semantic Scholar", paper.CitationCount))
+	}
+	if len(paper.RelatedPaperIDs) > 0 {
+		bullets = append(bullets, fmt.Sprintf("Related arXiv papers: %s", strings.Join(paper.RelatedPaperIDs, ", ")))
+	}
 	switch {
 	case paper.ID != "":
-		bullets = append(bullets, fmt.Sprintf("arXiv entry: https://arxiv.org/abs/%s", paper.ID))
+		bullets = append(bullets, fmt.Sprintf("%s entry: %s", paper.SourceName(), paper.AbsURL()))
 	case paper.PDFURL != "":
 		bullets = append(bullets, fmt.Sprintf("Source PDF: %s", paper.PDFURL))
 	}
@@ -1589,36 +2250,7 @@ func fallbackNotice(kind llm.BriefSectionKind) string {
 }
 
 func abstractSentences(text string) []string {
-	text = strings.TrimSpace(text)
-	if text == "" {
-		return nil
-	}
-	var sentences []string
-	start := 0
-	for idx, r := range text {
-		if r == '.' || r == '!' || r == '?' {
-			end := idx + utf8.RuneLen(r)
-			segment := strings.TrimSpace(text[start:end])
-			if segment != "" {
-				sentences = append(sentences, segment)
-			}
-			start = end
-			for start < len(text) {
-				nextRune, size := utf8.DecodeRuneInString(text[start:])
-				if unicode.IsSpace(nextRune) {
-					start += size
-					continue
-				}
-				break
-			}
-		}
-	}
-	if start < len(text) {
-		if segment := strings.TrimSpace(text[start:]); segment != "" {
-			sentences = append(sentences, segment)
-		}
-	}
-	return sentences
+	return sentences.Split(text)
 }
 
 func firstSentences(text string, limit int) string {
@@ -1632,7 +2264,7 @@ func firstSentences(text string, limit int) string {
 	return strings.Join(sentences, " ")
 }
 
-func waitBriefSectionStream(paperID string, kind llm.BriefSectionKind, updates <-chan llm.BriefSectionDelta) tea.Cmd {
+func waitBriefSectionStream(paperID string, kind llm.BriefSectionKind, seq int, updates <-chan llm.BriefSectionDelta) tea.Cmd {
 	if updates == nil {
 		return nil
 	}
@@ -1644,6 +2276,7 @@ func waitBriefSectionStream(paperID string, kind llm.BriefSectionKind, updates <
 		return briefSectionStreamMsg{
 			paperID: paperID,
 			kind:    kind,
+			seq:     seq,
 			bullets: append([]string(nil), delta.Bullets...),
 			done:    delta.Done,
 			updates: updates,
@@ -1656,8 +2289,8 @@ func (m *model) ensureBriefContexts() map[llm.BriefSectionKind]string {
 		return nil
 	}
 	if len(m.briefContexts) == 0 {
-		builder := briefctx.NewBuilder(nil)
-		pkg := builder.Build(m.paper.FullText)
+		builder := briefctx.NewBuilder(nil).WithTokenEstimator(m.config.TokenEstimator)
+		pkg := builder.BuildWithSections(m.paper.FullText, m.paper.Sections)
 		m.briefContexts = pkg.Sections
 		m.briefChunks = pkg.Chunks
 	}
@@ -1682,6 +2315,31 @@ func (m *model) contextForSection(kind llm.BriefSectionKind) string {
 	return context
 }
 
+// briefContextEstimate reports how much of a section's context budget its request will use, e.g.
+// "12.3k/27.5k tokens".
+func (m *model) briefContextEstimate(kind llm.BriefSectionKind) string {
+	used, budget := llm.EstimateBriefContext(kind, m.contextForSection(kind), m.config.TokenEstimator)
+	return fmt.Sprintf("%s/%s tokens", formatTokenCount(used), formatTokenCount(budget))
+}
+
+// briefContextUsage lists briefContextEstimate for every section, e.g. "Summary: sending
+// 4k/15k tokens; Technical: sending 12.3k/27.5k tokens; …".
+func (m *model) briefContextUsage() string {
+	parts := make([]string, 0, len(briefSectionKinds))
+	for _, kind := range briefSectionKinds {
+		parts = append(parts, fmt.Sprintf("%s: sending %s", briefSectionTitle(kind), m.briefContextEstimate(kind)))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// formatTokenCount shortens counts of a thousand or more to one decimal place: 27500 is "27.5k".
+func formatTokenCount(n int) string {
+	if n < 1000 {
+		return strconv.Itoa(n)
+	}
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/1000), ".0") + "k"
+}
+
 func (m *model) technicalMetadata() string {
 	if m.paper == nil {
 		return ""
@@ -1705,33 +2363,71 @@ func (m *model) launchBriefSections() tea.Cmd {
 	}
 	cmds := []tea.Cmd{m.spinner.Tick}
 	for _, kind := range briefSectionKinds {
-		if m.briefStreamCancels == nil {
-			m.briefStreamCancels = map[llm.BriefSectionKind]context.CancelFunc{}
-		}
-		if cancel, ok := m.briefStreamCancels[kind]; ok {
-			cancel()
-		}
-		streamCtx, cancel := context.WithCancel(context.Background())
-		m.briefStreamCancels[kind] = cancel
-		m.markBriefSectionRunning(kind)
-		ctx := m.contextForSection(kind)
-		runner, updates := briefSectionJob(kind, ctx, m.config.LLM, m.paper, streamCtx)
-		cmds = append(cmds, m.jobBus.Start(jobKindForSection(kind), runner))
-		if streamCmd := waitBriefSectionStream(m.paper.ID, kind, updates); streamCmd != nil {
-			cmds = append(cmds, streamCmd)
-		}
+		cmds = append(cmds, m.launchBriefSection(kind, llm.DetailStandard))
 	}
 	m.markViewportDirty()
 	return tea.Batch(cmds...)
 }
 
+// launchBriefSection (re)starts one section's stream, cancelling any run already in flight for it.
+// The section context comes from the cached chunks, so regenerating does not re-chunk the paper.
+func (m *model) launchBriefSection(kind llm.BriefSectionKind, detail llm.DetailLevel) tea.Cmd {
+	if m.briefStreamCancels == nil {
+		m.briefStreamCancels = map[llm.BriefSectionKind]context.CancelFunc{}
+	}
+	if cancel, ok := m.briefStreamCancels[kind]; ok {
+		cancel()
+	}
+	streamCtx, cancel := context.WithCancel(context.Background())
+	m.briefStreamCancels[kind] = cancel
+	if m.briefStreamSeqs == nil {
+		m.briefStreamSeqs = map[llm.BriefSectionKind]int{}
+	}
+	m.briefStreamSeq++
+	m.briefStreamSeqs[kind] = m.briefStreamSeq
+	delete(m.briefStreamPending, kind)
+	m.markBriefSectionRunning(kind)
+	ctx := m.contextForSection(kind)
+	runner, updates := briefSectionJob(kind, m.briefStreamSeq, detail, ctx, m.config.LLM, m.paper, streamCtx, m.briefSectionTimeout(kind), m.briefSlots.reserve())
+	return tea.Batch(m.jobBus.Start(jobKindForSection(kind), runner), waitBriefSectionStream(m.paper.ID, kind, m.briefStreamSeq, updates))
+}
+
+// offerBriefRegeneration handles sections restored empty from history: they are rebuilt right away
+// when the brief would have generated automatically, and otherwise the status line says how to
+// rebuild them.
+func (m *model) offerBriefRegeneration() tea.Cmd {
+	kinds := m.regenerableBriefSections()
+	if len(kinds) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		names = append(names, briefSectionTitle(kind))
+	}
+	canGenerate := m.config.LLM != nil && m.paper.Notice() == "" && strings.TrimSpace(m.paper.FullText) != ""
+	if !canGenerate || !m.config.AutoBrief {
+		m.infoMessage = fmt.Sprintf("Loaded %s. %s saved empty; run /brief %s to regenerate.", m.paper.Title, strings.Join(names, ", "), briefSectionCommandName(kinds[0]))
+		return nil
+	}
+	m.infoMessage = fmt.Sprintf("Loaded %s. Regenerating empty %s from the saved brief…", m.paper.Title, strings.Join(names, ", "))
+	cmds := []tea.Cmd{m.spinner.Tick}
+	for _, kind := range kinds {
+		cmds = append(cmds, m.launchBriefSection(kind, llm.DetailStandard))
+	}
+	return tea.Batch(cmds...)
+}
+
 func (m *model) actionSummarizeCmd() tea.Cmd {
 	if m.paper == nil {
 		m.infoMessage = "Load a paper before summarizing."
 		return nil
 	}
 	if m.config.LLM == nil {
-		m.infoMessage = "Configure Ollama via flags to enable summaries."
+		m.infoMessage = m.llmUnavailableNotice("Configure Ollama via flags to enable summaries.")
+		return nil
+	}
+	if notice := m.paper.Notice(); notice != "" {
+		m.infoMessage = notice
 		return nil
 	}
 	if strings.TrimSpace(m.paper.FullText) == "" {
@@ -1742,7 +2438,7 @@ func (m *model) actionSummarizeCmd() tea.Cmd {
 		m.infoMessage = "Reading brief already running."
 		return nil
 	}
-	m.infoMessage = "Generating LLM reading brief…"
+	m.infoMessage = fmt.Sprintf("Generating LLM reading brief… %s.", m.briefContextUsage())
 	return m.launchBriefSections()
 }
 
@@ -1752,7 +2448,7 @@ func (m *model) actionAskQuestionCmd() tea.Cmd {
 		return nil
 	}
 	if m.config.LLM == nil {
-		m.infoMessage = "Configure Ollama to unlock questions."
+		m.infoMessage = m.llmUnavailableNotice("Configure Ollama to unlock questions.")
 		return nil
 	}
 	m.composer.SetValue("")
@@ -1774,35 +2470,47 @@ func (m *model) actionManualNoteCmd() tea.Cmd {
 func (m *model) actionSaveCmd() tea.Cmd {
 	notesToSave := m.collectSelectedNotes()
 	if len(notesToSave) == 0 {
-		m.infoMessage = "No manual notes captured yet."
+		if m.config.Autosave {
+			m.infoMessage = "Nothing to save; notes are saved as you add them."
+		} else {
+			m.infoMessage = "No manual notes captured yet."
+		}
 		return nil
 	}
 	m.stage = stageSaving
-	target := m.config.KnowledgeBasePath
-	if strings.TrimSpace(target) == "" {
-		target = "zettelkasten.json"
-	}
-	m.infoMessage = fmt.Sprintf("Saving notes to %s…", target)
-	return tea.Batch(m.spinner.Tick, m.jobBus.Start(jobKindSave, saveNotesJob(m.config.KnowledgeBasePath, notesToSave)))
+	m.infoMessage = fmt.Sprintf("Saving notes to %s…", m.knowledgeBasePath())
+	return tea.Batch(m.spinner.Tick, m.jobBus.Start(jobKindSave, saveNotesJob(m.knowledgeBase(), notesToSave)))
 }
 
+// actionLoadNewCmd opens a new tab for the next paper, keeping the current one a tab switch away.
 func (m *model) actionLoadNewCmd() tea.Cmd {
+	m.openSessionTab()
 	m.stage = stageInput
 	m.paper = nil
 	m.resetBriefState()
+	m.jobBus.Cancel(jobKindSuggest)
+	m.jobBus.Cancel(jobKindGaps)
 	m.cursorLine = 0
 	m.guide = nil
+	m.guideFocus = ""
+	m.scratchpad = ""
 	m.suggestions = nil
+	m.suggestionCursor = 0
 	m.manualNotes = nil
+	m.draftCursor = 0
+	m.contextPreview = ""
+	m.errorsView = false
+	m.clearFind()
 	m.persistedNotes = nil
 	m.selected = map[int]bool{}
 	m.persisted = map[int]bool{}
 	m.viewport.SetContent("")
+	m.viewport.SetYOffset(0)
 	m.suggestionLines = map[int]int{}
 	m.sectionAnchors = map[string]int{}
 	m.suggestionLoading = false
 	m.pendingFocusAnchor = ""
-	m.infoMessage = "Ready for another paper."
+	m.infoMessage = fmt.Sprintf("%s ready for another paper.", m.tabLabel())
 	m.markViewportDirty()
 	m.composer.SetValue("")
 	m.setComposerMode(composerModeURL, composerURLPlaceholder, true)
@@ -1823,28 +2531,51 @@ func (m *model) appendTranscriptEntry(kind, content string) int {
 	m.transcriptEntries = append(m.transcriptEntries, entry)
 	m.markTranscriptDirty()
 	m.markViewportDirty()
+	if announceOnAppend(kind) {
+		m.announce(entry)
+	}
 	return len(m.transcriptEntries) - 1
 }
 
 func (m *model) ensureConversationSnapshotCmd() tea.Cmd {
-	if m.paper == nil || m.config.KnowledgeBasePath == "" {
+	if m.paper == nil {
 		return nil
 	}
-	return m.jobBus.Start(jobKindZettel, ensureConversationSnapshotJob(m.config.KnowledgeBasePath, m.paper))
+	return m.jobBus.Start(jobKindZettel, ensureConversationSnapshotJob(m.knowledgeBase(), m.paper))
 }
 
 func (m *model) appendConversationSnapshotCmd(update notes.SnapshotUpdate) tea.Cmd {
-	if m.paper == nil || m.config.KnowledgeBasePath == "" {
+	if m.paper == nil {
 		return nil
 	}
-	if len(update.Messages) == 0 && len(update.Notes) == 0 {
+	if len(update.Messages) == 0 && len(update.Notes) == 0 && update.Scratchpad == nil {
 		return nil
 	}
-	return m.jobBus.Start(jobKindZettel, appendConversationSnapshotJob(m.config.KnowledgeBasePath, m.paper, update))
+	if update.Brief != nil {
+		update.LLM = llmMetadata(m.config.LLM)
+		m.briefLLM = update.LLM
+	}
+	if m.config.SnapshotFlushInterval > 0 {
+		return m.queueConversationSnapshot(update)
+	}
+	return m.jobBus.Start(jobKindZettel, appendConversationSnapshotJob(m.knowledgeBase(), m.paper, update))
 }
 
 func (m *model) handlePaperResult(msg paperResultMsg) tea.Cmd {
+	if msg.seq != m.fetchSeq {
+		// A newer fetch replaced this one; its result (usually context.Canceled) is stale.
+		return nil
+	}
 	m.fetchInProgress = false
+	if m.fetchCancel != nil {
+		m.fetchCancel()
+		m.fetchCtx, m.fetchCancel = nil, nil
+	}
+	resume := m.resumeSnapshot
+	m.resumeSnapshot = nil
+	if msg.err != nil && resume != nil {
+		return m.restoreResumedPaper(msg, resume)
+	}
 	if msg.err != nil {
 		m.stage = stageInput
 		m.errorMessage = msg.err.Error()
@@ -1854,20 +2585,35 @@ func (m *model) handlePaperResult(msg paperResultMsg) tea.Cmd {
 		m.appendTranscript("error", fmt.Sprintf("Load failed: %v", msg.err))
 		return nil
 	}
+	if existing := m.sessionForPaper(msg.paper.ID); existing >= 0 && m.paper == nil {
+		m.stage = stageInput
+		m.switchSession(existing - m.activeSession)
+		m.infoMessage = fmt.Sprintf("%s is already open in %s.", m.paper.Title, m.tabLabel())
+		return nil
+	}
 	m.paper = msg.paper
+	m.paperWords = len(strings.Fields(m.paper.FullText))
+	m.paperReadingTime = estimateReadingTime(m.paper.FullText)
 	m.guide = msg.guide
+	m.guideFocus = ""
 	m.suggestions = nil
+	m.suggestionCursor = 0
 	m.stage = stageDisplay
 	m.cursorLine = 0
 	m.selected = map[int]bool{}
 	m.persisted = map[int]bool{}
 	m.manualNotes = []notes.Note{}
+	m.draftCursor = 0
+	m.contextPreview = ""
+	m.errorsView = false
+	m.clearFind()
 	m.persistedNotes = nil
 	m.suggestionLines = map[int]int{}
 	m.sectionAnchors = map[string]int{}
 	m.resetBriefState()
 	m.prepareBriefFallbacks()
 	m.suggestionLoading = false
+	m.cancelQuestionStream()
 	m.qaHistory = nil
 	m.queuedQuestions = nil
 	m.questionLoading = false
@@ -1878,35 +2624,68 @@ func (m *model) handlePaperResult(msg paperResultMsg) tea.Cmd {
 	m.infoMessage = fmt.Sprintf("Loaded %s.", m.paper.Title)
 	m.hydrateConversationHistory()
 	hasSnapshotBriefs := m.hasSnapshotBriefs()
+	cachedBrief := !hasSnapshotBriefs && m.seedBriefFromSidecar()
 	m.refreshPersistedState()
 	m.markViewportDirty()
 	m.composer.SetValue("")
 	m.setComposerMode(composerModeNote, composerNotePlaceholder, false)
 	m.appendTranscript("paper", fmt.Sprintf("Loaded %s", m.paper.Title))
+	if m.paper.Preview {
+		m.showAbstract()
+	}
+	m.showRelatedNotes()
 	m.seedBriefMessages()
 	snapshotCmd := m.ensureConversationSnapshotCmd()
 
 	if hasSnapshotBriefs {
 		m.infoMessage = fmt.Sprintf("Loaded %s. Reading brief restored from conversation history.", m.paper.Title)
+		if regenCmd := m.offerBriefRegeneration(); regenCmd != nil {
+			return tea.Batch(snapshotCmd, regenCmd)
+		}
 		return snapshotCmd
 	}
-
-	if m.config.LLM == nil {
-		m.infoMessage = fmt.Sprintf("Loaded %s. Configure an LLM provider to see the reading brief.", m.paper.Title)
+	if cachedBrief {
+		m.infoMessage = fmt.Sprintf("Loaded %s. Reading brief restored from the PDF cache.", m.paper.Title)
 		return snapshotCmd
 	}
-	if strings.TrimSpace(m.paper.FullText) == "" {
-		m.infoMessage = fmt.Sprintf("Loaded %s. PDF text missing; skipping reading brief.", m.paper.Title)
+
+	briefCmd := m.autoBriefCmd()
+	if briefCmd == nil {
 		return snapshotCmd
 	}
-	m.infoMessage = fmt.Sprintf("Loaded %s. Building reading brief…", m.paper.Title)
-	briefCmd := m.launchBriefSections()
 	if snapshotCmd != nil {
 		return tea.Batch(snapshotCmd, briefCmd)
 	}
 	return briefCmd
 }
 
+// autoBriefCmd starts the reading brief for a paper whose text just loaded, or says on the status
+// line why it did not start.
+func (m *model) autoBriefCmd() tea.Cmd {
+	if notice := m.paper.Notice(); notice != "" {
+		if m.paper.Preview {
+			notice += " " + previewFullTextHint
+		}
+		m.infoMessage = fmt.Sprintf("Loaded %s. %s", m.paper.Title, notice)
+		m.appendTranscript("paper", notice)
+		return nil
+	}
+	if m.config.LLM == nil {
+		m.infoMessage = fmt.Sprintf("Loaded %s. %s", m.paper.Title, m.llmUnavailableNotice("Configure an LLM provider to see the reading brief."))
+		return nil
+	}
+	if strings.TrimSpace(m.paper.FullText) == "" {
+		m.infoMessage = fmt.Sprintf("Loaded %s. PDF text missing; skipping reading brief.", m.paper.Title)
+		return nil
+	}
+	if !m.config.AutoBrief {
+		m.infoMessage = fmt.Sprintf("Loaded %s. Run /brief to generate the reading brief.", m.paper.Title)
+		return nil
+	}
+	m.infoMessage = fmt.Sprintf("Loaded %s. Building reading brief… %s.", m.paper.Title, m.briefContextUsage())
+	return m.launchBriefSections()
+}
+
 func (m *model) handleSaveResult(msg saveResultMsg) tea.Cmd {
 	m.stage = stageDisplay
 	if msg.err != nil {
@@ -1919,21 +2698,58 @@ func (m *model) handleSaveResult(msg saveResultMsg) tea.Cmd {
 		m.infoMessage = "No manual notes captured yet."
 		return nil
 	}
-	m.infoMessage = fmt.Sprintf("Saved %d note(s) to %s", msg.count, m.config.KnowledgeBasePath)
+	m.infoMessage = fmt.Sprintf("Saved %d note(s) to %s", msg.count, m.knowledgeBasePath())
 	m.errorMessage = ""
 	m.selected = map[int]bool{}
 	m.persisted = map[int]bool{}
 	m.manualNotes = []notes.Note{}
+	m.draftCursor = 0
+	m.contextPreview = ""
 	m.refreshPersistedState()
 	m.markViewportDirty()
 	m.appendTranscript("save", fmt.Sprintf("Saved %d note(s).", msg.count))
 	return nil
 }
 
+// briefSectionSnapshotUpdate records a completed section: its bullets, status, and the rendered
+// transcript message that hydrateConversationHistory maps back onto the brief.
+func briefSectionSnapshotUpdate(kind llm.BriefSectionKind, bullets []string, content string) notes.SnapshotUpdate {
+	update := notes.SnapshotUpdate{
+		SectionMetadata: []notes.BriefSectionMetadata{
+			{Kind: string(kind), Status: "completed"},
+		},
+	}
+	if len(bullets) > 0 {
+		copied := append([]string(nil), bullets...)
+		switch kind {
+		case llm.BriefSummary:
+			update.Brief = &notes.BriefSnapshot{Summary: copied}
+		case llm.BriefTechnical:
+			update.Brief = &notes.BriefSnapshot{Technical: copied}
+		case llm.BriefDeepDive:
+			update.Brief = &notes.BriefSnapshot{DeepDive: copied}
+		}
+	}
+	if trimmed := strings.TrimSpace(content); trimmed != "" {
+		update.Messages = []notes.ConversationMessage{
+			{
+				Kind:      transcriptKindForBriefSection(kind),
+				Content:   content,
+				Timestamp: time.Now(),
+			},
+		}
+	}
+	return update
+}
+
 func (m *model) handleBriefSectionResult(msg briefSectionMsg) tea.Cmd {
 	if m.paper == nil || m.paper.ID != msg.paperID {
 		return nil
 	}
+	if errors.Is(msg.err, context.Canceled) || msg.seq != m.briefStreamSeqs[msg.kind] {
+		// The run was superseded by a restart (or its tab was left); the newer state already stands.
+		return nil
+	}
 	state := m.markBriefSectionResult(msg.kind, msg.err)
 	title := briefSectionTitle(msg.kind)
 	var snapshotCmd tea.Cmd
@@ -1961,33 +2777,21 @@ func (m *model) handleBriefSectionResult(msg briefSectionMsg) tea.Cmd {
 			m.clearBriefInfoMessage()
 		}
 		content := briefMessageContent(msg.kind, msg.bullets)
-		m.setBriefMessage(msg.kind, content)
-		update := notes.SnapshotUpdate{
-			SectionMetadata: []notes.BriefSectionMetadata{
-				{Kind: string(msg.kind), Status: "completed"},
-			},
+		if msg.partial {
+			state.Partial = true
+			m.briefSections[msg.kind] = state
+			m.infoMessage = fmt.Sprintf("%s section timed out; kept the partial output. Run /brief %s to retry.", title, briefSectionCommandName(msg.kind))
+			content = briefMessageContentWithNotice(msg.kind, msg.bullets, partialBriefNotice(msg.kind))
 		}
-		if len(msg.bullets) > 0 {
-			bullets := append([]string(nil), msg.bullets...)
-			switch msg.kind {
-			case llm.BriefSummary:
-				update.Brief = &notes.BriefSnapshot{Summary: bullets}
-			case llm.BriefTechnical:
-				update.Brief = &notes.BriefSnapshot{Technical: bullets}
-			case llm.BriefDeepDive:
-				update.Brief = &notes.BriefSnapshot{DeepDive: bullets}
-			}
+		m.setBriefMessage(msg.kind, content)
+		if idx, ok := m.briefMessageIndex[msg.kind]; ok {
+			m.announceTranscriptIndex(idx)
 		}
-		if trimmed := strings.TrimSpace(content); trimmed != "" {
-			update.Messages = []notes.ConversationMessage{
-				{
-					Kind:      transcriptKindForBriefSection(msg.kind),
-					Content:   content,
-					Timestamp: time.Now(),
-				},
-			}
+		update := briefSectionSnapshotUpdate(msg.kind, msg.bullets, content)
+		if msg.partial {
+			update.SectionMetadata[0].Status = "partial"
 		}
-		snapshotCmd = m.appendConversationSnapshotCmd(update)
+		snapshotCmd = tea.Batch(m.appendConversationSnapshotCmd(update), m.cacheBriefSidecarCmd(), m.maybeContinueTechnicalCmd(msg), m.suggestNotesCmd())
 	}
 	m.markViewportDirty()
 	queuedCmd := m.maybeStartQueuedQuestion()
@@ -2010,22 +2814,135 @@ func (m *model) handleBriefSectionStream(msg briefSectionStreamMsg) tea.Cmd {
 	if m.paper == nil || m.paper.ID != msg.paperID {
 		return nil
 	}
+	if msg.seq != m.briefStreamSeqs[msg.kind] {
+		// A restart replaced this stream; what it still had buffered is stale.
+		return nil
+	}
+	if msg.done {
+		delete(m.briefStreamPending, msg.kind)
+	}
+	var flushCmd tea.Cmd
 	if len(msg.bullets) > 0 {
 		m.updateBriefContent(msg.kind, msg.bullets)
-		m.setBriefMessage(msg.kind, briefMessageContent(msg.kind, msg.bullets))
+		switch {
+		case msg.done:
+			m.setBriefMessage(msg.kind, briefMessageContent(msg.kind, msg.bullets))
+		case !m.streamRestatesFallback(msg.kind, msg.bullets):
+			flushCmd = m.debounceBriefStream(msg.paperID, msg.kind, msg.bullets)
+		}
 	} else if msg.done {
 		m.setBriefMessage(msg.kind, briefMessageContent(msg.kind, nil))
 	}
 	if msg.done {
 		return nil
 	}
-	return waitBriefSectionStream(msg.paperID, msg.kind, msg.updates)
+	return tea.Batch(flushCmd, waitBriefSectionStream(msg.paperID, msg.kind, msg.seq, msg.updates))
+}
+
+// debounceBriefStream redraws a streaming section at most once per briefStreamInterval. Deltas that
+// arrive sooner are parked and applied by a single timer, so token-level streams do not rebuild the
+// viewport on every token.
+func (m *model) debounceBriefStream(paperID string, kind llm.BriefSectionKind, bullets []string) tea.Cmd {
+	interval := m.briefStreamInterval()
+	now := time.Now()
+	if m.briefStreamApplied == nil {
+		m.briefStreamApplied = map[llm.BriefSectionKind]time.Time{}
+	}
+	if m.briefStreamPending == nil {
+		m.briefStreamPending = map[llm.BriefSectionKind][]string{}
+	}
+	elapsed := now.Sub(m.briefStreamApplied[kind])
+	_, scheduled := m.briefStreamPending[kind]
+	if !scheduled && (interval <= 0 || elapsed >= interval) {
+		m.briefStreamApplied[kind] = now
+		m.setBriefMessage(kind, briefMessageContent(kind, bullets))
+		return nil
+	}
+	m.briefStreamPending[kind] = bullets
+	if scheduled {
+		return nil
+	}
+	return tea.Tick(interval-elapsed, func(time.Time) tea.Msg {
+		return briefStreamFlushMsg{paperID: paperID, kind: kind}
+	})
+}
+
+func (m *model) handleBriefStreamFlush(msg briefStreamFlushMsg) {
+	if m.paper == nil || m.paper.ID != msg.paperID {
+		return
+	}
+	bullets, ok := m.briefStreamPending[msg.kind]
+	if !ok {
+		return
+	}
+	delete(m.briefStreamPending, msg.kind)
+	m.briefStreamApplied[msg.kind] = time.Now()
+	m.setBriefMessage(msg.kind, briefMessageContent(msg.kind, bullets))
+}
+
+func (m *model) briefStreamInterval() time.Duration {
+	rate := m.config.BriefStreamRate
+	if rate == 0 {
+		rate = defaultBriefStreamRate
+	}
+	if rate < 0 {
+		return 0
+	}
+	return time.Second / time.Duration(rate)
+}
+
+// briefConcurrency resolves Config.BriefConcurrency, defaulting by provider.
+func briefConcurrency(config Config) int {
+	if config.BriefConcurrency > 0 {
+		return config.BriefConcurrency
+	}
+	return llm.DefaultConcurrency(config.LLM)
+}
+
+// fetchTimeout resolves Config.FetchTimeout.
+func fetchTimeout(config Config) time.Duration {
+	if config.FetchTimeout > 0 {
+		return config.FetchTimeout
+	}
+	return defaultFetchTimeout
+}
+
+// questionTimeout resolves Config.QuestionTimeout.
+func (m *model) questionTimeout() time.Duration {
+	if m.config.QuestionTimeout > 0 {
+		return m.config.QuestionTimeout
+	}
+	return defaultQuestionTimeout
+}
+
+// briefSectionTimeout scales the configured timeout by the section's context budget relative to the
+// summary, so the technical section, which gets the most paper text, also gets the most time. No
+// section gets less than the base timeout.
+func (m *model) briefSectionTimeout(kind llm.BriefSectionKind) time.Duration {
+	base := m.config.BriefSectionTimeout
+	if base <= 0 {
+		base = defaultBriefSectionTimeout
+	}
+	scaled := base * time.Duration(llm.BriefSectionLimit(kind)) / time.Duration(llm.BriefSectionLimit(llm.BriefSummary))
+	if scaled < base {
+		return base
+	}
+	return scaled
 }
 
 func (m *model) handleQuestionResult(msg questionResultMsg) tea.Cmd {
 	if m.paper == nil || m.paper.ID != msg.paperID {
 		return nil
 	}
+	if errors.Is(msg.err, context.Canceled) {
+		// A newer question superseded this one; its streamed text stays in the transcript as a draft.
+		if msg.index >= 0 && msg.index < len(m.qaHistory) {
+			entry := &m.qaHistory[msg.index]
+			entry.Pending = false
+			entry.Error = "superseded by a newer question"
+		}
+		return nil
+	}
 	m.questionLoading = false
 	var snapshotCmd tea.Cmd
 	if msg.index >= 0 && msg.index < len(m.qaHistory) {
@@ -2041,6 +2958,7 @@ func (m *model) handleQuestionResult(msg questionResultMsg) tea.Cmd {
 			}
 			m.appendTranscript("error", fmt.Sprintf("Question failed: %v", msg.err))
 		} else {
+			msg.answer = appendCitationFootnotes(msg.answer, m.paper.References)
 			entry.Answer = msg.answer
 			entry.Error = ""
 			m.errorMessage = ""
@@ -2052,6 +2970,7 @@ func (m *model) handleQuestionResult(msg questionResultMsg) tea.Cmd {
 				transcript.Timestamp = time.Now()
 				m.markTranscriptDirty()
 				m.markViewportDirty()
+				m.announce(*transcript)
 			} else {
 				entry.TranscriptIndex = m.appendTranscriptEntry("answer", msg.answer)
 			}
@@ -2077,40 +2996,30 @@ func (m *model) handleQuestionResult(msg questionResultMsg) tea.Cmd {
 	return queuedCmd
 }
 
-func (m *model) handleSuggestionResult(msg suggestionResultMsg) tea.Cmd {
-	if m.paper == nil || m.paper.ID != msg.paperID {
-		return nil
-	}
-	m.suggestionLoading = false
-	if msg.err != nil {
-		m.errorMessage = fmt.Sprintf("suggestion error: %v", msg.err)
-		m.infoMessage = "Note suggestions are unavailable in this view."
-		m.markViewportDirty()
-		m.appendTranscript("error", fmt.Sprintf("Suggestion failed: %v", msg.err))
-		return nil
-	}
-	m.errorMessage = ""
-	m.infoMessage = "Note suggestions are unavailable in this view."
-	m.suggestions = nil
-	m.selected = map[int]bool{}
-	m.persisted = map[int]bool{}
-	m.refreshPersistedState()
-	m.markViewportDirty()
-	return nil
-}
-
 func (m *model) handleJobPayload(payload tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := payload.(type) {
 	case paperResultMsg:
 		return m, m.handlePaperResult(msg)
 	case saveResultMsg:
 		return m, m.handleSaveResult(msg)
+	case autosaveResultMsg:
+		return m, m.handleAutosaveResult(msg)
+	case noteChangeResultMsg:
+		return m, m.handleNoteChangeResult(msg)
 	case briefSectionMsg:
 		return m, m.handleBriefSectionResult(msg)
 	case questionResultMsg:
 		return m, m.handleQuestionResult(msg)
 	case suggestionResultMsg:
 		return m, m.handleSuggestionResult(msg)
+	case exportResultMsg:
+		return m, m.handleExportResult(msg)
+	case gapResultMsg:
+		return m, m.handleGapResult(msg)
+	case fullTextResultMsg:
+		return m, m.handleFullTextResult(msg)
+	case openPDFResultMsg:
+		return m, m.handleOpenPDFResult(msg)
 	default:
 		return m, nil
 	}
@@ -2134,58 +3043,6 @@ func (m *model) clampYOffset(offset int) int {
 }
 
 var (
-	titleStyle         = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).Underline(true)
-	subtitleStyle      = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("147"))
-	sectionHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("81"))
-	subjectStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("110"))
-	errorStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
-	helperStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
-
-	heroAccentColor        = lipgloss.Color("#ff8c00")
-	heroEmberColor         = lipgloss.Color("#2b1400")
-	heroTextColor          = lipgloss.Color("#fff4d0")
-	heroSecondaryTextColor = lipgloss.Color("#ffb347")
-
-	composerFocusedBackgroundColor = lipgloss.Color("#231507")
-	composerBlurredBackgroundColor = lipgloss.Color("#170c04")
-	composerCursorLineFocusedColor = lipgloss.Color("#3b200b")
-	composerCursorLineBlurredColor = lipgloss.Color("#251307")
-
-	heroTitleStyle                 = lipgloss.NewStyle().Bold(true).Foreground(heroAccentColor)
-	heroBoxStyle                   = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(heroAccentColor).Foreground(heroTextColor).Background(heroEmberColor).Padding(1, 2)
-	heroSummaryStyle               = lipgloss.NewStyle().PaddingLeft(2)
-	taglineStyle                   = lipgloss.NewStyle().Foreground(heroSecondaryTextColor).Italic(true)
-	statusBarStyle                 = lipgloss.NewStyle().Foreground(lipgloss.Color("#dcdcdc")).Padding(0, 1)
-	currentLineStyle               = lipgloss.NewStyle().Foreground(lipgloss.Color("#0f0f0f")).Background(lipgloss.Color("#8ecae6"))
-	persistedSuggestionStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("#a3be8c")).Italic(true)
-	logoFaceStyle                  = lipgloss.NewStyle().Bold(true).Foreground(heroTextColor).Background(heroEmberColor)
-	logoShadowStyle                = lipgloss.NewStyle().Foreground(lipgloss.Color("#110600"))
-	logoContainerStyle             = lipgloss.NewStyle().Padding(0, 1)
-	composerFocusedBaseStyle       = lipgloss.NewStyle().Background(composerFocusedBackgroundColor)
-	composerBlurredBaseStyle       = lipgloss.NewStyle().Background(composerBlurredBackgroundColor)
-	composerCursorLineFocusedStyle = lipgloss.NewStyle().
-					Background(composerCursorLineFocusedColor).
-					Foreground(heroTextColor)
-	composerCursorLineBlurredStyle = lipgloss.NewStyle().
-					Background(composerCursorLineBlurredColor).
-					Foreground(heroSecondaryTextColor)
-	composerFocusedTextStyle   = lipgloss.NewStyle().Foreground(heroTextColor)
-	composerBlurredTextStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#d3b38a"))
-	composerPlaceholderStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#f1c27a")).Italic(true)
-	composerPromptStyle        = lipgloss.NewStyle().Foreground(heroAccentColor).Bold(true)
-	markdownHeadingStyle       = lipgloss.NewStyle().Foreground(heroAccentColor).Bold(true)
-	markdownBulletStyle        = lipgloss.NewStyle().Foreground(heroSecondaryTextColor).Bold(true)
-	markdownTableStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("#d9b56c"))
-	markdownTableHeaderStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#f2c97d")).Bold(true)
-	markdownQuoteStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("#b0a08a")).Italic(true)
-	markdownCodeStyle          = lipgloss.NewStyle().Foreground(lipgloss.Color("#d3b38a"))
-	markdownBoldStyle          = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#ffd166"))
-	markdownItalicStyle        = lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("#f1c27a"))
-	markdownInlineCodeStyle    = lipgloss.NewStyle().Foreground(heroTextColor).Background(lipgloss.Color("#1c1c1c"))
-	latexStyle                 = lipgloss.NewStyle().Foreground(lipgloss.Color("#8ecae6")).Bold(true)
-	linkStyle                  = lipgloss.NewStyle().Foreground(lipgloss.Color("#93d7ff")).Underline(true)
-	markdownStrikethroughStyle = lipgloss.NewStyle().Strikethrough(true).Foreground(lipgloss.Color("#b0a08a"))
-
 	logoArtLines = []string{
 		"██████╗    █████╗   ██████╗   ███████╗  ██████╗   ███████╗   ██████╗   ██████╗   ██╗   ██╗  ████████╗  ",
 		"██╔══██╗  ██╔══██╗  ██╔══██╗  ██╔════╝  ██╔══██╗  ██╔════╝  ██╔════╝  ██╔═══██╗  ██║   ██║  ╚══██╔══╝  ",
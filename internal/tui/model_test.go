@@ -1,8 +1,11 @@
 package tui
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,6 +13,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/csheth/browse/internal/arxiv"
 	"github.com/csheth/browse/internal/llm"
@@ -86,37 +90,72 @@ func TestComposerEscCancelsQuestionMode(t *testing.T) {
 	}
 }
 
-func TestSubmitComposerSkipsDuplicateFetch(t *testing.T) {
+func TestSubmitComposerCancelsInFlightFetch(t *testing.T) {
 	m := newTestModel(t)
 	m.composer.SetValue("https://arxiv.org/abs/2504.12345")
 	m.setComposerMode(composerModeURL, composerURLPlaceholder, true)
 
-	first := m.submitComposer()
-	if first == nil {
+	if first := m.submitComposer(); first == nil {
 		t.Fatal("expected a command the first time the URL is submitted")
 	}
-	if !m.fetchInProgress {
-		t.Fatal("expected fetchInProgress to be true after starting a fetch")
+	if !m.fetchInProgress || m.stage != stageLoading {
+		t.Fatal("expected the first fetch to be loading")
 	}
+	firstCtx, firstSeq := m.fetchCtx, m.fetchSeq
 
-	m.infoMessage = ""
-	m.composer.SetValue("https://arxiv.org/abs/2504.12345")
-	if second := m.submitComposer(); second != nil {
-		t.Fatalf("expected nil when submitting a URL while a fetch is in flight, got %T", second)
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2504.54321")})
+	m = updated.(*model)
+	if got := m.composer.Value(); got != "2504.54321" {
+		t.Fatalf("expected the composer to accept input while loading, got %q", got)
+	}
+	updated, second := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(*model)
+	if second == nil {
+		t.Fatal("expected the second URL to start a fetch")
 	}
-	if got, want := m.infoMessage, fetchInProgressMessage; got != want {
-		t.Fatalf("expected info message %q, got %q", want, got)
+	if !errors.Is(firstCtx.Err(), context.Canceled) {
+		t.Fatalf("expected the first fetch to be canceled, got %v", firstCtx.Err())
+	}
+	if m.fetchSeq != firstSeq+1 || m.fetchCtx.Err() != nil {
+		t.Fatalf("expected a live second fetch, got seq %d (err %v)", m.fetchSeq, m.fetchCtx.Err())
 	}
 
-	m.handlePaperResult(paperResultMsg{paper: &arxiv.Paper{ID: "2504.12345", Title: "Fixture"}})
-	if m.fetchInProgress {
-		t.Fatal("expected fetchInProgress to clear after handling the result")
+	m.handlePaperResult(paperResultMsg{seq: firstSeq, err: context.Canceled})
+	if !m.fetchInProgress || m.errorMessage != "" {
+		t.Fatalf("expected the canceled result to be ignored, got inProgress=%v error=%q", m.fetchInProgress, m.errorMessage)
 	}
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: &arxiv.Paper{ID: "2504.54321", Title: "Second"}})
+	if m.fetchInProgress || m.paper == nil || m.paper.ID != "2504.54321" {
+		t.Fatalf("expected the second paper to load, got %+v", m.paper)
+	}
+}
 
-	m.composer.SetValue("https://arxiv.org/abs/2504.12345")
+func TestSubmitComposerRejectsPastedProseInURLMode(t *testing.T) {
+	m := newTestModel(t)
+	prose := "Transformers scale well, but attention is quadratic in sequence length, so we propose a sparse variant."
+	m.setComposerMode(composerModeURL, composerURLPlaceholder, true)
+	m.composer.SetValue(prose)
+
+	if cmd := m.submitComposer(); cmd != nil {
+		t.Fatalf("expected no fetch job for pasted prose, got %T", cmd)
+	}
+	if m.fetchInProgress || m.stage == stageLoading {
+		t.Fatal("expected pasted prose not to start a fetch")
+	}
+	if !strings.Contains(m.infoMessage, "doesn't look like an arXiv URL") {
+		t.Fatalf("expected guidance about arXiv URLs, got %q", m.infoMessage)
+	}
+	if m.composer.Value() != prose {
+		t.Fatalf("expected pasted text kept in the composer, got %q", m.composer.Value())
+	}
+
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Open Paper"}
 	m.setComposerMode(composerModeURL, composerURLPlaceholder, true)
-	if third := m.submitComposer(); third == nil {
-		t.Fatal("expected command after fetch cleared")
+	if cmd := m.submitComposer(); cmd != nil {
+		t.Fatalf("expected no fetch job for pasted prose, got %T", cmd)
+	}
+	if m.composerMode != composerModeNote || !strings.Contains(m.infoMessage, "ask it as a question") {
+		t.Fatalf("expected offer to use the text as a question or note, got mode %v and %q", m.composerMode, m.infoMessage)
 	}
 }
 
@@ -131,6 +170,66 @@ func TestHandlePaperResultClearsFetchInProgress(t *testing.T) {
 	}
 }
 
+func TestHandlePaperResultWithoutAutoBriefOnlySnapshots(t *testing.T) {
+	m := newTestModel(t)
+	m.config.AutoBrief = false
+	m.config.LLM = fakeLLM{}
+	m.config.KnowledgeBasePath = filepath.Join(t.TempDir(), "zettel.json")
+	paper := &arxiv.Paper{ID: "2104.00001", Title: "Metered", Abstract: "We study costs.", FullText: "Full text."}
+
+	cmd := m.handlePaperResult(paperResultMsg{paper: paper})
+	if cmd == nil {
+		t.Fatal("expected the conversation snapshot command")
+	}
+	if _, batched := cmd().(tea.BatchMsg); batched {
+		t.Fatal("expected only the snapshot command, got a batch including the brief")
+	}
+	if m.briefLoading {
+		t.Fatal("expected brief to stay idle without auto-brief")
+	}
+	if want := "Loaded Metered. Run /brief to generate the reading brief."; m.infoMessage != want {
+		t.Fatalf("expected %q, got %q", want, m.infoMessage)
+	}
+	if !m.briefReadyForQuestions() {
+		t.Fatal("questions should not wait on a brief that was never started")
+	}
+
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/brief")
+	if cmd, handled := m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter}); !handled || cmd == nil {
+		t.Fatal("expected /brief to launch the reading brief")
+	}
+	if !m.briefLoading {
+		t.Fatal("expected /brief to mark the brief as loading")
+	}
+}
+
+func TestHandlePaperResultScannedPDFSkipsBrief(t *testing.T) {
+	m := newTestModel(t)
+	m.config.LLM = fakeLLM{}
+	paper := &arxiv.Paper{ID: "0801.00001", Title: "Old Scan", Abstract: "We prove a lemma.", ScannedPDF: true}
+
+	m.handlePaperResult(paperResultMsg{paper: paper})
+	if m.briefLoading {
+		t.Fatal("expected no brief for a scanned PDF")
+	}
+	if !strings.Contains(m.infoMessage, "scanned PDF") {
+		t.Fatalf("expected scanned PDF notice, got %q", m.infoMessage)
+	}
+	if cmd := m.actionSummarizeCmd(); cmd != nil || m.infoMessage != arxiv.ScannedPDFNotice {
+		t.Fatalf("expected summarize to explain the scan, got %T / %q", cmd, m.infoMessage)
+	}
+	found := false
+	for _, entry := range m.transcriptEntries {
+		if entry.Kind == "paper" && entry.Content == arxiv.ScannedPDFNotice {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected the scanned PDF notice in the transcript")
+	}
+}
+
 func TestComposerAltEnterSubmitsURL(t *testing.T) {
 	m := newTestModel(t)
 	m.composer.SetValue("https://arxiv.org/abs/1234.5678")
@@ -293,8 +392,8 @@ func TestComposerCtrlEnterStoresManualNote(t *testing.T) {
 	if !handled {
 		t.Fatal("ctrl+enter should submit manual note entries")
 	}
-	if cmd != nil {
-		t.Fatalf("manual note submission should not trigger a command, got %v", cmd)
+	if cmd == nil {
+		t.Fatal("manual note submission should persist the note to the conversation snapshot")
 	}
 	if len(m.manualNotes) != 1 {
 		t.Fatalf("expected 1 manual note, got %d", len(m.manualNotes))
@@ -409,25 +508,114 @@ func TestMouseSelectionCopiesToClipboard(t *testing.T) {
 	}
 }
 
-func TestMouseSelectionClipboardFailureSetsError(t *testing.T) {
+func TestMouseSelectionClipboardFailureFallsBackToOSC52(t *testing.T) {
 	m := newTestModel(t)
 	m.stage = stageInput
 	m.viewport.SetYOffset(0)
 	m.refreshViewport()
 
+	attempts := 0
 	originalClipboard := clipboardWrite
 	clipboardWrite = func(text string) error {
+		attempts++
 		return errors.New("clipboard unavailable")
 	}
-	t.Cleanup(func() { clipboardWrite = originalClipboard })
+	var terminal bytes.Buffer
+	captureTerminalExec(t, &terminal)
+	t.Cleanup(func() {
+		clipboardWrite = originalClipboard
+	})
 
 	top := m.viewportStartRow()
-	m.Update(tea.MouseMsg{Type: tea.MouseLeft, Y: top})
-	m.Update(tea.MouseMsg{Type: tea.MouseRelease, Y: top})
+	var notices []string
+	for i := 0; i < 2; i++ {
+		m.Update(tea.MouseMsg{Type: tea.MouseLeft, Y: top})
+		_, cmd := m.Update(tea.MouseMsg{Type: tea.MouseRelease, Y: top})
+		if cmd == nil {
+			t.Fatalf("expected an OSC 52 command on release %d", i+1)
+		}
+		m.Update(cmd())
+		notices = append(notices, m.infoMessage)
+	}
+	if !strings.Contains(notices[0], "clipboard unavailable") || strings.Contains(notices[1], "clipboard unavailable") {
+		t.Fatalf("expected the fallback reason reported once, got %q", notices)
+	}
 
-	if m.errorMessage == "" {
-		t.Fatal("expected clipboard error message")
+	if m.errorMessage != "" {
+		t.Fatalf("expected no clipboard error, got %q", m.errorMessage)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected system clipboard to be tried once, got %d attempts", attempts)
+	}
+	if strings.Count(terminal.String(), "\x1b]52;c;") != 2 {
+		t.Fatalf("expected two OSC 52 sequences, got %q", terminal.String())
+	}
+	if !strings.Contains(m.infoMessage, "OSC 52") {
+		t.Fatalf("unexpected info message %q", m.infoMessage)
+	}
+}
+
+func TestClipboardUnavailableAtStartupSkipsSystemClipboard(t *testing.T) {
+	m := New(Config{ClipboardUnavailable: true}).(*model)
+	originalClipboard := clipboardWrite
+	clipboardWrite = func(text string) error {
+		t.Fatal("system clipboard should not be used when unavailable")
+		return nil
+	}
+	var terminal bytes.Buffer
+	captureTerminalExec(t, &terminal)
+	t.Cleanup(func() {
+		clipboardWrite = originalClipboard
+	})
+
+	cmd := m.copyText("hello")
+	if cmd == nil {
+		t.Fatal("expected an OSC 52 copy command")
+	}
+	if terminal.Len() != 0 {
+		t.Fatalf("expected nothing written before the command runs, got %q", terminal.String())
+	}
+	m.Update(cmd())
+	if terminal.String() != "\x1b]52;c;aGVsbG8=\a" {
+		t.Fatalf("unexpected OSC 52 output %q", terminal.String())
+	}
+	if !strings.Contains(m.infoMessage, "no clipboard helper") {
+		t.Fatalf("expected the startup reason reported, got %q", m.infoMessage)
+	}
+}
+
+func TestOSC52WriteFailureIsReported(t *testing.T) {
+	m := New(Config{ClipboardUnavailable: true}).(*model)
+	captureTerminalExec(t, failingWriter{})
+
+	cmd := m.copyText("hello")
+	if cmd == nil {
+		t.Fatal("expected an OSC 52 copy command")
+	}
+	m.Update(cmd())
+	if !strings.Contains(m.errorMessage, "Clipboard copy failed") {
+		t.Fatalf("expected clipboard error, got %q", m.errorMessage)
+	}
+}
+
+// captureTerminalExec runs tea.Exec commands immediately against w, standing in for the program's
+// terminal output.
+func captureTerminalExec(t *testing.T, w io.Writer) {
+	t.Helper()
+	original := terminalExec
+	terminalExec = func(c tea.ExecCommand, fn tea.ExecCallback) tea.Cmd {
+		return func() tea.Msg {
+			c.SetStdout(w)
+			return fn(c.Run())
+		}
 	}
+	t.Cleanup(func() { terminalExec = original })
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("terminal closed")
 }
 
 func TestHydrateConversationHistoryLoadsSnapshot(t *testing.T) {
@@ -553,6 +741,60 @@ func TestHydrateConversationHistoryMaintainsBriefAnchors(t *testing.T) {
 	}
 }
 
+func TestHydrateFlagsCompletedEmptyBriefSectionForRegeneration(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "zettel.json")
+	now := time.Now().UTC()
+	snapshot := notes.ConversationSnapshot{
+		PaperID:    "2468",
+		PaperTitle: "Empty Section Fixture",
+		CapturedAt: now,
+		Messages: []notes.ConversationMessage{
+			{Kind: briefTranscriptKindSummary, Content: "### Summary\n- Summary bullet", Timestamp: now.Add(10 * time.Second)},
+			{Kind: briefTranscriptKindTechnical, Content: "Technical ready.", Timestamp: now.Add(20 * time.Second)},
+			{Kind: briefTranscriptKindDeepDive, Content: "### Deep Dive\n- Deep bullet", Timestamp: now.Add(30 * time.Second)},
+		},
+		Brief: &notes.BriefSnapshot{
+			Summary:  []string{"Summary bullet"},
+			DeepDive: []string{"Deep bullet"},
+		},
+		SectionMetadata: []notes.BriefSectionMetadata{
+			{Kind: string(llm.BriefTechnical), Status: "completed"},
+		},
+	}
+	if err := notes.SaveConversationSnapshots(path, []notes.ConversationSnapshot{snapshot}); err != nil {
+		t.Fatalf("SaveConversationSnapshots() error = %v", err)
+	}
+
+	m := newTestModel(t)
+	m.config.KnowledgeBasePath = path
+	m.paper = &arxiv.Paper{ID: "2468", Title: "Empty Section Fixture"}
+	m.hydrateConversationHistory()
+
+	technical := m.briefSections[llm.BriefTechnical]
+	if !technical.Regenerable || technical.Completed {
+		t.Fatalf("expected technical section flagged for regeneration, got %#v", technical)
+	}
+	if state := m.briefSections[llm.BriefSummary]; state.Regenerable || !state.Completed {
+		t.Fatalf("expected summary restored as completed, got %#v", state)
+	}
+	if got := m.regenerableBriefSections(); len(got) != 1 || got[0] != llm.BriefTechnical {
+		t.Fatalf("expected only technical to be regenerable, got %v", got)
+	}
+	if content := m.transcriptEntries[m.briefMessageIndex[llm.BriefTechnical]].Content; !strings.Contains(content, "/brief technical") {
+		t.Fatalf("expected regeneration hint in technical message, got %q", content)
+	}
+
+	m.config.LLM = fakeLLM{}
+	m.paper.FullText = "content"
+	if cmd := m.offerBriefRegeneration(); cmd == nil {
+		t.Fatal("expected empty section to be regenerated automatically")
+	}
+	if !m.briefSections[llm.BriefTechnical].Loading || m.briefSections[llm.BriefSummary].Loading {
+		t.Fatalf("expected only technical to regenerate, got %#v", m.briefSections)
+	}
+}
+
 func TestHandlePaperResultResumesSnapshotInView(t *testing.T) {
 	t.Parallel()
 
@@ -654,8 +896,8 @@ func TestBriefSectionResultUpdatesState(t *testing.T) {
 		kind:    llm.BriefSummary,
 		bullets: []string{"Bullet"},
 	}
-	if cmd := m.handleBriefSectionResult(msg); cmd != nil {
-		t.Fatalf("brief handler should not return a command, got %v", cmd)
+	if cmd := m.handleBriefSectionResult(msg); cmd == nil {
+		t.Fatal("brief handler should persist the section to the conversation snapshot")
 	}
 	if len(m.brief.Summary) != 1 || m.brief.Summary[0] != "Bullet" {
 		t.Fatalf("summary not stored: %#v", m.brief.Summary)
@@ -746,6 +988,80 @@ func TestBriefSectionStreamRequestsNextDelta(t *testing.T) {
 	close(updates)
 }
 
+func TestBriefSectionStreamSkipsRewriteWhenMatchingFallback(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{
+		ID:       "1234.56789",
+		Title:    "Fixture",
+		Abstract: "Sentence one. Sentence two.",
+	}
+	m.prepareBriefFallbacks()
+	m.seedBriefMessages()
+	idx := m.briefMessageIndex[llm.BriefSummary]
+	before := m.transcriptEntries[idx]
+
+	fallback := m.fallbackForSection(llm.BriefSummary)
+	echoed := make([]string, len(fallback))
+	for i, bullet := range fallback {
+		echoed[i] = "  " + strings.ToUpper(bullet) + "  "
+	}
+	updates := make(chan llm.BriefSectionDelta)
+	defer close(updates)
+	m.handleBriefSectionStream(briefSectionStreamMsg{
+		paperID: m.paper.ID,
+		kind:    llm.BriefSummary,
+		bullets: echoed,
+		updates: updates,
+	})
+
+	after := m.transcriptEntries[idx]
+	if after.Content != before.Content || !after.Timestamp.Equal(before.Timestamp) {
+		t.Fatalf("expected fallback message to be left alone, got %q", after.Content)
+	}
+	if len(m.brief.Summary) != len(echoed) {
+		t.Fatalf("expected brief state to track the stream, got %#v", m.brief.Summary)
+	}
+}
+
+func TestBriefSectionStreamDebouncesRapidDeltas(t *testing.T) {
+	m := newTestModel(t)
+	m.config.BriefStreamRate = 2
+	m.paper = &arxiv.Paper{ID: "1234.56789", Title: "Fixture"}
+	m.refreshViewport()
+	updates := make(chan llm.BriefSectionDelta)
+	defer close(updates)
+
+	start := time.Now()
+	rebuilds := 0
+	var partial []string
+	for i := 0; i < 20; i++ {
+		partial = append(partial, fmt.Sprintf("- bullet %d", i))
+		m.handleBriefSectionStream(briefSectionStreamMsg{
+			paperID: m.paper.ID,
+			kind:    llm.BriefTechnical,
+			bullets: append([]string(nil), partial...),
+			updates: updates,
+		})
+		if m.viewportDirty {
+			rebuilds++
+			m.refreshViewport()
+		}
+	}
+	window := time.Since(start)
+	if allowed := 1 + int(window/m.briefStreamInterval()); rebuilds > allowed {
+		t.Fatalf("expected at most %d viewport rebuilds in %s, got %d", allowed, window, rebuilds)
+	}
+	if len(m.brief.Technical) != 20 {
+		t.Fatalf("expected brief state to keep every delta, got %d bullets", len(m.brief.Technical))
+	}
+
+	m.handleBriefStreamFlush(briefStreamFlushMsg{paperID: m.paper.ID, kind: llm.BriefTechnical})
+	idx := m.briefMessageIndex[llm.BriefTechnical]
+	if !strings.Contains(m.transcriptEntries[idx].Content, "bullet 19") {
+		t.Fatalf("expected flush to apply the latest delta, got %q", m.transcriptEntries[idx].Content)
+	}
+}
+
 func TestPrepareBriefFallbacks(t *testing.T) {
 	m := newTestModel(t)
 	m.paper = &arxiv.Paper{
@@ -951,3 +1267,228 @@ func TestDisplayContentOmitsStaticBriefSections(t *testing.T) {
 		t.Fatalf("static brief sections should be omitted in view:\n%s", view.body)
 	}
 }
+
+func TestNoticeMsgUpdatesInfoMessage(t *testing.T) {
+	m := newTestModel(t)
+	m.Update(NoticeMsg("LLM rate limited, retrying in 3s"))
+	if want, got := "LLM rate limited, retrying in 3s", m.infoMessage; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestComposerCounterAppearsPastThreshold(t *testing.T) {
+	m := New(Config{ComposerCharLimit: 100}).(*model)
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+
+	m.processComposerKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(strings.Repeat("a", 50))})
+	if got := m.buildDisplayContent().body; strings.Contains(got, "/100") {
+		t.Fatalf("expected no counter below threshold, got:\n%s", got)
+	}
+
+	m.processComposerKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(strings.Repeat("b", 35))})
+	if got := m.buildDisplayContent().body; !strings.Contains(got, "85/100") {
+		t.Fatalf("expected 85/100 counter, got:\n%s", got)
+	}
+	if want := "Composer nearing its 100 character limit."; m.infoMessage != want {
+		t.Fatalf("expected %q, got %q", want, m.infoMessage)
+	}
+
+	m.processComposerKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(strings.Repeat("c", 30))})
+	if got := m.buildDisplayContent().body; !strings.Contains(got, "100/100") {
+		t.Fatalf("expected input capped at the limit, got:\n%s", got)
+	}
+	if !strings.Contains(m.infoMessage, "limit of 100 characters reached") {
+		t.Fatalf("unexpected info message %q", m.infoMessage)
+	}
+}
+
+func TestRawMarkdownToggleShowsStoredContent(t *testing.T) {
+	m := newTestModel(t)
+	m.stage = stageDisplay
+	m.paper = &arxiv.Paper{ID: "1234", Title: "Fixture"}
+	m.transcriptEntries = []transcriptEntry{{Kind: "answer", Content: "### Findings\n- **Bold** claim"}}
+	m.refreshViewport()
+	if rendered := stripANSI(m.viewportContent); strings.Contains(rendered, "###") || strings.Contains(rendered, "**") {
+		t.Fatalf("expected rendered markdown by default, got:\n%s", rendered)
+	}
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m.refreshViewportIfDirty()
+	raw := stripANSI(m.viewportContent)
+	if !strings.Contains(raw, "### Findings") || !strings.Contains(raw, "- **Bold** claim") {
+		t.Fatalf("expected raw markdown markers, got:\n%s", raw)
+	}
+
+	m.handleKey(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m.refreshViewportIfDirty()
+	if strings.Contains(stripANSI(m.viewportContent), "**") {
+		t.Fatal("expected second toggle to restore rendering")
+	}
+}
+
+func TestFromClipboardSeedsInitialFetch(t *testing.T) {
+	originalRead := clipboardRead
+	clipboardRead = func() (string, error) {
+		return "https://arxiv.org/abs/2101.00001v2\n", nil
+	}
+	t.Cleanup(func() { clipboardRead = originalRead })
+
+	m := New(Config{FromClipboard: true}).(*model)
+	if cmd := m.Init(); cmd == nil {
+		t.Fatal("expected init command")
+	}
+	if !m.fetchInProgress || m.stage != stageLoading {
+		t.Fatalf("expected initial fetch to start, got fetchInProgress=%v stage=%v", m.fetchInProgress, m.stage)
+	}
+	if len(m.transcriptEntries) != 1 || m.transcriptEntries[0].Content != "Fetching 2101.00001v2" {
+		t.Fatalf("unexpected transcript %#v", m.transcriptEntries)
+	}
+}
+
+func TestFromClipboardWithoutIdentifierKeepsPrompt(t *testing.T) {
+	originalRead := clipboardRead
+	clipboardRead = func() (string, error) {
+		return "grocery list", nil
+	}
+	t.Cleanup(func() { clipboardRead = originalRead })
+
+	m := New(Config{FromClipboard: true}).(*model)
+	m.Init()
+	if m.fetchInProgress || m.stage != stageInput {
+		t.Fatalf("expected the URL prompt, got fetchInProgress=%v stage=%v", m.fetchInProgress, m.stage)
+	}
+	if !strings.Contains(m.infoMessage, "No arXiv identifier on the clipboard") {
+		t.Fatalf("unexpected info message %q", m.infoMessage)
+	}
+}
+
+func TestReadingPercent(t *testing.T) {
+	cases := []struct {
+		yOffset, height, lines int
+		want                   int
+	}{
+		{yOffset: 0, height: 20, lines: 120, want: 0},
+		{yOffset: 42, height: 20, lines: 120, want: 42},
+		{yOffset: 100, height: 20, lines: 120, want: 100},
+		{yOffset: 0, height: 40, lines: 25, want: 100},
+	}
+	for _, tc := range cases {
+		if got := readingPercent(tc.yOffset, tc.height, tc.lines); got != tc.want {
+			t.Errorf("readingPercent(%d, %d, %d) = %d, want %d", tc.yOffset, tc.height, tc.lines, got, tc.want)
+		}
+	}
+}
+
+func TestEstimateReadingTime(t *testing.T) {
+	cases := []struct {
+		words int
+		want  time.Duration
+	}{
+		{words: 0, want: 0},
+		{words: 220, want: time.Minute},
+		{words: 1100, want: 5 * time.Minute},
+		{words: 110, want: 30 * time.Second},
+	}
+	for _, tc := range cases {
+		text := strings.TrimSpace(strings.Repeat("word ", tc.words))
+		if got := estimateReadingTime(text); got != tc.want {
+			t.Errorf("estimateReadingTime(%d words) = %v, want %v", tc.words, got, tc.want)
+		}
+	}
+}
+
+func TestHeroShowsPaperLength(t *testing.T) {
+	m := newTestModel(t)
+	m.config.AutoBrief = false
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: &arxiv.Paper{ID: "2101.00001", Title: "Sized", FullText: strings.Repeat("word ", 4400)}})
+	if hero := stripANSI(m.heroView()); !strings.Contains(hero, "4400 words, ~20 min") {
+		t.Fatalf("expected word count and reading time in hero, got %q", hero)
+	}
+
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: &arxiv.Paper{ID: "2101.00002", Title: "No PDF"}})
+	if hero := stripANSI(m.heroView()); !strings.Contains(hero, "length unknown") {
+		t.Fatalf("expected unknown length without paper text, got %q", hero)
+	}
+}
+
+func TestHeroShowsPaperVersion(t *testing.T) {
+	m := newTestModel(t)
+	m.config.AutoBrief = false
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: &arxiv.Paper{ID: "2308.01234v2", Version: "v2", Title: "Pinned"}})
+	if hero := stripANSI(m.heroView()); !strings.Contains(hero, "arXiv: 2308.01234 v2 ·") {
+		t.Fatalf("expected the pinned version in the hero, got %q", hero)
+	}
+
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: &arxiv.Paper{ID: "2308.05678", Version: "v3", Title: "Latest"}})
+	if hero := stripANSI(m.heroView()); !strings.Contains(hero, "arXiv: 2308.05678 v3 ·") {
+		t.Fatalf("expected the latest version in the hero, got %q", hero)
+	}
+}
+
+func TestHeroShowsReadingProgressAndResetsOnLoadNew(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Long Paper"}
+	m.stage = stageDisplay
+	m.viewport.Height = 20
+	m.lineCount = 120
+	m.viewport.SetContent(strings.Repeat("line\n", 119) + "line")
+	m.viewport.SetYOffset(50)
+	if hero := stripANSI(m.heroView()); !strings.Contains(hero, "50% read") {
+		t.Fatalf("expected progress in hero, got %q", hero)
+	}
+
+	m.actionLoadNewCmd()
+	m.lineCount = 120
+	if m.viewport.YOffset != 0 || m.readingProgress() != 0 {
+		t.Fatalf("expected progress reset after loading a new paper, offset %d progress %d", m.viewport.YOffset, m.readingProgress())
+	}
+}
+
+func TestCustomThemeReusingABuiltInNameIsApplied(t *testing.T) {
+	t.Cleanup(func() { applyTheme(ThemeDark) })
+	applyTheme(ThemeDark)
+	custom := ThemeDark
+	custom.Title = lipgloss.Color("#123456")
+	applyTheme(custom)
+	if got := titleStyle.GetForeground(); got != custom.Title {
+		t.Fatalf("expected the custom title color, got %v", got)
+	}
+}
+
+func TestNewAppliesConfiguredTheme(t *testing.T) {
+	t.Cleanup(func() { applyTheme(ThemeDark) })
+	dark := New(Config{KnowledgeBasePath: filepath.Join(t.TempDir(), "notes.json")}).(*model)
+	darkTitle, darkComposer := titleStyle.GetForeground(), dark.composer.FocusedStyle.Text.GetForeground()
+
+	light := New(Config{KnowledgeBasePath: filepath.Join(t.TempDir(), "notes.json"), Theme: ThemeLight}).(*model)
+	if titleStyle.GetForeground() == darkTitle {
+		t.Fatalf("expected the light theme to change the title color, still %v", darkTitle)
+	}
+	if light.composer.FocusedStyle.Text.GetForeground() == darkComposer {
+		t.Fatalf("expected the light theme to change the composer text color, still %v", darkComposer)
+	}
+	if markdownHeadingStyle.GetForeground() != ThemeLight.Accent {
+		t.Fatalf("expected Markdown headings in the light accent, got %v", markdownHeadingStyle.GetForeground())
+	}
+
+	if got := resolveTheme(ThemeLight, true); got.Name != plainTheme.Name {
+		t.Fatalf("expected NO_COLOR to pick the plain theme, got %q", got.Name)
+	}
+	if got := resolveTheme(Theme{}, false); got.Name != ThemeDark.Name {
+		t.Fatalf("expected the zero theme to mean dark, got %q", got.Name)
+	}
+}
+
+func TestDeepDiveFallbackMentionsEnrichment(t *testing.T) {
+	paper := &arxiv.Paper{ID: "2101.00001", Title: "Cited", CitationCount: 42, RelatedPaperIDs: []string{"2202.00002", "2303.00003"}}
+	bullets := strings.Join(fallbackDeepDiveBullets(paper), "\n")
+	for _, want := range []string{"Cited by 42 papers on Semantic Scholar", "Related arXiv papers: 2202.00002, 2303.00003"} {
+		if !strings.Contains(bullets, want) {
+			t.Fatalf("expected %q in the deep-dive fallback, got %q", want, bullets)
+		}
+	}
+	if bullets := strings.Join(fallbackDeepDiveBullets(&arxiv.Paper{ID: "2101.00001"}), "\n"); strings.Contains(bullets, "Semantic Scholar") {
+		t.Fatalf("expected no citation bullet without enrichment, got %q", bullets)
+	}
+}
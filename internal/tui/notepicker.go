@@ -0,0 +1,204 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/notes"
+)
+
+// notePickerPurpose says what choosing a note in the picker does.
+type notePickerPurpose int
+
+const (
+	pickNoteToEdit notePickerPurpose = iota
+	pickNoteToDelete
+)
+
+// actionEditNoteCmd lists the paper's saved notes so one can be picked and edited in the composer.
+func (m *model) actionEditNoteCmd(string) tea.Cmd {
+	if m.openNotePicker(pickNoteToEdit) {
+		m.infoMessage = "Choose a saved note to edit. ↑/↓ select, Enter edits, Esc cancels."
+	}
+	return nil
+}
+
+// actionDeleteNoteCmd lists the paper's saved notes so one can be picked and, after a y/n
+// confirmation, removed from the knowledge base.
+func (m *model) actionDeleteNoteCmd(string) tea.Cmd {
+	if m.openNotePicker(pickNoteToDelete) {
+		m.infoMessage = "Choose a saved note to delete. ↑/↓ select, Enter picks, Esc cancels."
+	}
+	return nil
+}
+
+// openNotePicker shows the paper's saved notes, reporting false (with the reason in infoMessage)
+// when there is nothing to pick from.
+func (m *model) openNotePicker(purpose notePickerPurpose) bool {
+	if m.paper == nil {
+		m.infoMessage = "Load a paper before changing saved notes."
+		return false
+	}
+	m.refreshPersistedState()
+	if len(m.persistedNotes) == 0 {
+		m.infoMessage = "No saved notes for this paper yet."
+		return false
+	}
+	m.notePicker = purpose
+	m.noteCursor = 0
+	m.confirmingDelete = false
+	m.composer.SetValue("")
+	m.setComposerMode(composerModePickNote, composerPickNotePlaceholder, true)
+	m.markViewportDirty()
+	return true
+}
+
+// noteChangeResultMsg reports a note deleted by deleteNoteJob or rewritten by updateNoteJob.
+type noteChangeResultMsg struct {
+	title   string
+	deleted bool
+	missing bool
+	err     error
+}
+
+func deleteNoteJob(kb notes.Store, note notes.Note) jobRunner {
+	return func(parent context.Context) (tea.Msg, error) {
+		removed, err := kb.Delete(note)
+		return noteChangeResultMsg{title: note.Title, deleted: true, missing: err == nil && !removed, err: err}, err
+	}
+}
+
+func updateNoteJob(kb notes.Store, target notes.Note, body string) jobRunner {
+	return func(parent context.Context) (tea.Msg, error) {
+		err := kb.UpdateNote(target, body, "")
+		if errors.Is(err, notes.ErrNoteNotFound) {
+			return noteChangeResultMsg{title: target.Title, missing: true}, nil
+		}
+		return noteChangeResultMsg{title: target.Title, err: err}, err
+	}
+}
+
+// handleNotePickerKey moves through the saved-note list and acts on the highlighted note on Enter.
+// Other keys are swallowed so stray typing does not land in the hidden composer.
+func (m *model) handleNotePickerKey(key tea.KeyMsg) tea.Cmd {
+	defer m.markViewportDirty()
+	if m.confirmingDelete {
+		switch strings.ToLower(key.String()) {
+		case "y":
+			return m.deletePickedNoteCmd()
+		case "n":
+			m.cancelComposerEntry()
+		}
+		return nil
+	}
+	switch key.Type {
+	case tea.KeyUp:
+		m.noteCursor = clampIndex(m.noteCursor-1, len(m.persistedNotes))
+	case tea.KeyDown:
+		m.noteCursor = clampIndex(m.noteCursor+1, len(m.persistedNotes))
+	case tea.KeyEnter:
+		if len(m.persistedNotes) == 0 {
+			m.cancelComposerEntry()
+			return nil
+		}
+		if m.notePicker == pickNoteToDelete {
+			m.confirmingDelete = true
+			m.infoMessage = fmt.Sprintf("Delete %q? y/n", m.pickedNote().Title)
+			return nil
+		}
+		m.editPickedNote()
+	}
+	return nil
+}
+
+func (m *model) pickedNote() notes.Note {
+	return m.persistedNotes[clampIndex(m.noteCursor, len(m.persistedNotes))]
+}
+
+func (m *model) editPickedNote() {
+	note := m.pickedNote()
+	m.editingNote = &note
+	m.setComposerMode(composerModeEditNote, composerEditNotePlaceholder, true)
+	m.composer.SetValue(note.Body)
+	m.updateComposerHeight()
+	m.infoMessage = fmt.Sprintf("Editing %q. Ctrl+Enter saves, Esc cancels.", note.Title)
+}
+
+// deletePickedNoteCmd closes the picker and removes the confirmed note in a job.
+func (m *model) deletePickedNoteCmd() tea.Cmd {
+	note := m.pickedNote()
+	m.confirmingDelete = false
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, false)
+	m.infoMessage = fmt.Sprintf("Deleting %q…", note.Title)
+	return m.jobBus.Start(jobKindSave, deleteNoteJob(m.knowledgeBase(), note))
+}
+
+// saveEditedNoteCmd rewrites the note being edited in the knowledge base in a job. A note that was
+// deleted or changed elsewhere since the list was shown is reported rather than recreated.
+func (m *model) saveEditedNoteCmd() tea.Cmd {
+	target := m.editingNote
+	m.editingNote = nil
+	body := strings.TrimSpace(m.composer.Value())
+	m.composer.SetValue("")
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, false)
+	m.markViewportDirty()
+	if target == nil {
+		return nil
+	}
+	if body == "" {
+		m.infoMessage = "A note needs a body; edit canceled."
+		return nil
+	}
+	m.infoMessage = fmt.Sprintf("Saving %q…", target.Title)
+	return m.jobBus.Start(jobKindSave, updateNoteJob(m.knowledgeBase(), *target, body))
+}
+
+func (m *model) handleNoteChangeResult(msg noteChangeResultMsg) tea.Cmd {
+	failed, action, done := "Edit", "edit", "Updated"
+	if msg.deleted {
+		failed, action, done = "Delete", "delete", "Deleted"
+	}
+	switch {
+	case msg.err != nil:
+		m.errorMessage = fmt.Sprintf("%s failed: %v", failed, msg.err)
+	case msg.missing:
+		m.errorMessage = fmt.Sprintf("Could not %s %q: it is no longer in the knowledge base.", action, msg.title)
+	default:
+		m.errorMessage = ""
+		m.infoMessage = fmt.Sprintf("%s %q.", done, msg.title)
+	}
+	m.refreshPersistedState()
+	m.markViewportDirty()
+	return nil
+}
+
+// writeNotePicker replaces the conversation with the paper's saved notes while one is being chosen.
+func (m *model) writeNotePicker(cb *contentBuilder) {
+	cb.WriteString(sectionHeaderStyle.Render("Saved notes"))
+	cb.WriteRune('\n')
+	if m.paper != nil {
+		cb.WriteString(helperStyle.Render(m.paper.Title))
+		cb.WriteRune('\n')
+	}
+	cb.WriteRune('\n')
+	cursor := clampIndex(m.noteCursor, len(m.persistedNotes))
+	for idx, note := range m.persistedNotes {
+		line := fmt.Sprintf("%d. %s — %s", idx+1, note.Title, strings.Join(strings.Fields(note.Body), " "))
+		line = previewText(line, m.wrapWidth(8))
+		if idx == cursor {
+			cb.WriteString("  " + currentLineStyle.Render("› "+line))
+		} else {
+			cb.WriteString("    " + line)
+		}
+		cb.WriteRune('\n')
+	}
+	if m.confirmingDelete {
+		cb.WriteRune('\n')
+		cb.WriteString(helperStyle.Render(fmt.Sprintf("Delete %q? Press y to delete or n to keep it.", m.pickedNote().Title)))
+		cb.WriteRune('\n')
+	}
+}
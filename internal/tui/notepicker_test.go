@@ -0,0 +1,140 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/notes"
+)
+
+func newNotePickerModel(t *testing.T, saved ...notes.Note) *model {
+	t.Helper()
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Picker Paper"}
+	m.stage = stageDisplay
+	if err := notes.Save(m.knowledgeBasePath(), saved); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	return m
+}
+
+func processedCmd(m *model, key tea.KeyMsg) tea.Cmd {
+	cmd, _ := m.processComposerKey(key)
+	return cmd
+}
+
+func TestEditNoteRewritesChosenNote(t *testing.T) {
+	m := newNotePickerModel(t,
+		notes.Note{PaperID: "2101.00001", Title: "First", Body: "First body."},
+		notes.Note{PaperID: "2101.00001", Title: "Second", Body: "Secnod body."},
+		notes.Note{PaperID: "2101.99999", Title: "Elsewhere", Body: "Other paper."},
+	)
+	m.composer.SetValue("/edit-note")
+	m.submitComposer()
+	if m.composerMode != composerModePickNote || len(m.persistedNotes) != 2 {
+		t.Fatalf("expected the picker over this paper's two notes, got mode %v with %d notes", m.composerMode, len(m.persistedNotes))
+	}
+	if view := m.buildDisplayContent().body; !strings.Contains(view, "Secnod body.") || strings.Contains(view, "Other paper.") {
+		t.Fatalf("expected the picker to list only this paper's notes, got:\n%s", view)
+	}
+
+	m.processComposerKey(tea.KeyMsg{Type: tea.KeyDown})
+	m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.composerMode != composerModeEditNote || m.composer.Value() != "Secnod body." {
+		t.Fatalf("expected the second note loaded for editing, got mode %v value %q", m.composerMode, m.composer.Value())
+	}
+
+	m.composer.SetValue("Second body.")
+	cmd := processedCmd(m, tea.KeyMsg{Type: tea.KeyEnter, Alt: true})
+	if stored, _ := notes.Load(m.knowledgeBasePath()); stored[1].Body != "Secnod body." {
+		t.Fatalf("expected the edit to wait for its job, got %+v", stored)
+	}
+	runJobs(m, cmd)
+	if m.errorMessage != "" {
+		t.Fatalf("unexpected error %q", m.errorMessage)
+	}
+	stored, err := notes.Load(m.knowledgeBasePath())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(stored) != 3 || stored[1].Body != "Second body." || stored[1].Title != "Second" || stored[0].Body != "First body." {
+		t.Fatalf("expected only the second note rewritten, got %+v", stored)
+	}
+	if m.persistedNotes[1].Body != "Second body." {
+		t.Fatalf("expected persisted notes refreshed, got %+v", m.persistedNotes)
+	}
+}
+
+func TestEditNoteReportsNoteDeletedMeanwhile(t *testing.T) {
+	m := newNotePickerModel(t, notes.Note{PaperID: "2101.00001", Title: "Doomed", Body: "Soon gone."})
+	m.actionEditNoteCmd("")
+	m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if _, err := notes.Delete(m.knowledgeBasePath(), m.persistedNotes[0]); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	m.composer.SetValue("Edited too late.")
+	runJobs(m, processedCmd(m, tea.KeyMsg{Type: tea.KeyEnter, Alt: true}))
+	if !strings.Contains(m.errorMessage, "no longer in the knowledge base") {
+		t.Fatalf("expected a missing-note error, got %q", m.errorMessage)
+	}
+	if len(m.persistedNotes) != 0 {
+		t.Fatalf("expected persisted notes refreshed, got %+v", m.persistedNotes)
+	}
+}
+
+func TestEditNoteWithoutSavedNotes(t *testing.T) {
+	m := newNotePickerModel(t)
+	m.actionEditNoteCmd("")
+	if m.composerMode == composerModePickNote || !strings.Contains(m.infoMessage, "No saved notes") {
+		t.Fatalf("expected no picker without saved notes, got mode %v (%q)", m.composerMode, m.infoMessage)
+	}
+}
+
+func TestDeleteNoteAsksForConfirmation(t *testing.T) {
+	m := newNotePickerModel(t,
+		notes.Note{PaperID: "2101.00001", Title: "Keep", Body: "Still useful."},
+		notes.Note{PaperID: "2101.00001", Title: "Obsolete", Body: "Outdated idea."},
+	)
+	m.composer.SetValue("/delete-note")
+	m.submitComposer()
+	m.processComposerKey(tea.KeyMsg{Type: tea.KeyDown})
+	m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.confirmingDelete || !strings.Contains(m.infoMessage, `Delete "Obsolete"? y/n`) {
+		t.Fatalf("expected a y/n confirmation, got %q", m.infoMessage)
+	}
+
+	m.processComposerKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m.composerMode == composerModePickNote || len(m.persistedNotes) != 2 {
+		t.Fatalf("expected n to keep the note and close the picker, got mode %v with %d notes", m.composerMode, len(m.persistedNotes))
+	}
+
+	m.actionDeleteNoteCmd("")
+	m.processComposerKey(tea.KeyMsg{Type: tea.KeyDown})
+	m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter})
+	runJobs(m, processedCmd(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}))
+	if m.errorMessage != "" || !strings.Contains(m.infoMessage, `Deleted "Obsolete"`) {
+		t.Fatalf("expected the note deleted, got info %q error %q", m.infoMessage, m.errorMessage)
+	}
+	if len(m.persistedNotes) != 1 || m.persistedNotes[0].Title != "Keep" {
+		t.Fatalf("expected persisted notes refreshed, got %+v", m.persistedNotes)
+	}
+}
+
+func TestDeleteNoteReportsNoteAlreadyGone(t *testing.T) {
+	m := newNotePickerModel(t, notes.Note{PaperID: "2101.00001", Title: "Twice", Body: "Deleted elsewhere."})
+	m.actionDeleteNoteCmd("")
+	m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if _, err := notes.Delete(m.knowledgeBasePath(), m.persistedNotes[0]); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	runJobs(m, processedCmd(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")}))
+	if !strings.Contains(m.errorMessage, "no longer in the knowledge base") {
+		t.Fatalf("expected a missing-note error, got %q", m.errorMessage)
+	}
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+)
+
+// openFile hands path to the system viewer; tests swap it out like clipboardWrite.
+var openFile = openWithSystemViewer
+
+type openPDFResultMsg struct {
+	path string
+	err  error
+}
+
+// openerCommand is the command that opens path in the default application on goos.
+func openerCommand(goos, path string) *exec.Cmd {
+	switch goos {
+	case "darwin":
+		return exec.Command("open", path)
+	case "windows":
+		// start is a cmd built-in; the empty argument is the window title it would otherwise take
+		// from a quoted path.
+		return exec.Command("cmd", "/c", "start", "", path)
+	default:
+		return exec.Command("xdg-open", path)
+	}
+}
+
+// openWithSystemViewer starts the opener without waiting for the viewer to close.
+func openWithSystemViewer(path string) error {
+	cmd := openerCommand(runtime.GOOS, path)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%s: %w", cmd.Args[0], err)
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// actionOpenPDFCmd opens the paper's PDF in the system viewer, downloading it into the PDF cache
+// first when it is not there (for instance after the cache was cleared).
+func (m *model) actionOpenPDFCmd() tea.Cmd {
+	if m.paper == nil {
+		m.infoMessage = "Load a paper to open its PDF."
+		return nil
+	}
+	pdfURL := strings.TrimSpace(m.paper.PDFURL)
+	if pdfURL == "" {
+		m.infoMessage = "This paper has no PDF link."
+		return nil
+	}
+	if _, ok := arxiv.CachedPDFPath(pdfURL); ok {
+		m.infoMessage = "Opening the PDF…"
+	} else {
+		m.infoMessage = "Downloading the PDF…"
+	}
+	return m.jobBus.Start(jobKindOpenPDF, openPDFJob(pdfURL, fetchTimeout(m.config)))
+}
+
+func openPDFJob(pdfURL string, timeout time.Duration) jobRunner {
+	return func(parent context.Context) (tea.Msg, error) {
+		path, ok := arxiv.CachedPDFPath(pdfURL)
+		if !ok {
+			ctx, cancel := context.WithTimeout(parent, timeout)
+			defer cancel()
+			var err error
+			if path, err = arxiv.FetchPDF(ctx, pdfURL); err != nil {
+				return openPDFResultMsg{err: err}, err
+			}
+		}
+		if err := openFile(path); err != nil {
+			return openPDFResultMsg{path: path, err: err}, err
+		}
+		return openPDFResultMsg{path: path}, nil
+	}
+}
+
+func (m *model) handleOpenPDFResult(msg openPDFResultMsg) tea.Cmd {
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("Could not open the PDF: %v", msg.err)
+		m.appendTranscript("error", m.errorMessage)
+		return nil
+	}
+	m.errorMessage = ""
+	m.infoMessage = fmt.Sprintf("Opened %s in the system viewer.", msg.path)
+	return nil
+}
@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csheth/browse/internal/arxiv"
+)
+
+func stubOpenFile(t *testing.T, err error) *[]string {
+	t.Helper()
+	var opened []string
+	originalOpen := openFile
+	openFile = func(path string) error {
+		opened = append(opened, path)
+		return err
+	}
+	t.Cleanup(func() { openFile = originalOpen })
+	return &opened
+}
+
+func TestOpenPDFKeyOpensCachedPDF(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PAPERSCOUT_CACHE_DIR", dir)
+	cached := filepath.Join(dir, "2101.00001.pdf")
+	if err := os.WriteFile(cached, []byte("%PDF-1.4"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opened := stubOpenFile(t, nil)
+
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Cached Paper", PDFURL: "https://arxiv.org/pdf/2101.00001.pdf"}
+	m.stage = stageDisplay
+	m.blurComposer()
+
+	_, cmd := m.handleDisplayKey(runeKey('o'))
+	runJobs(m, cmd)
+
+	if len(*opened) != 1 || (*opened)[0] != cached {
+		t.Fatalf("expected the opener to get %q, got %v", cached, *opened)
+	}
+	if !strings.Contains(m.infoMessage, cached) {
+		t.Fatalf("expected the info message to name the PDF, got %q", m.infoMessage)
+	}
+}
+
+func TestOpenPDFSurfacesOpenerErrors(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PAPERSCOUT_CACHE_DIR", dir)
+	if err := os.WriteFile(filepath.Join(dir, "2101.00001.pdf"), []byte("%PDF-1.4"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stubOpenFile(t, errors.New("xdg-open: executable file not found"))
+
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", PDFURL: "https://arxiv.org/pdf/2101.00001.pdf"}
+	runJobs(m, m.actionOpenPDFCmd())
+
+	if !strings.Contains(m.errorMessage, "xdg-open") {
+		t.Fatalf("expected the opener error in errorMessage, got %q", m.errorMessage)
+	}
+	last := m.transcriptEntries[len(m.transcriptEntries)-1]
+	if last.Kind != "error" || !strings.Contains(last.Content, "xdg-open") {
+		t.Fatalf("expected the opener error in the transcript, got %+v", last)
+	}
+}
@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/csheth/browse/internal/arxiv"
+)
+
+const (
+	// defaultPrefetchConcurrency is how many papers are fetched and briefed at once when
+	// PrefetchConfig.Concurrency is unset.
+	defaultPrefetchConcurrency = 2
+	// prefetchJobCap bounds the concurrency so an overnight batch does not queue dozens of
+	// concurrent generations against a single Ollama instance.
+	prefetchJobCap = 8
+)
+
+// PrefetchConfig configures a headless batch run. Config supplies the knowledge base and LLM used by
+// the interactive UI so prefetched briefs restore exactly as if they were generated on screen.
+type PrefetchConfig struct {
+	Config
+	// Fetch loads a paper by identifier; nil uses arxiv.FetchPaper, which also fills the PDF cache.
+	Fetch func(ctx context.Context, id string) (*arxiv.Paper, error)
+	// Progress is called once per finished paper, never concurrently; nil discards results.
+	Progress func(result PrefetchResult)
+	// Concurrency is how many papers are processed at once; zero uses defaultPrefetchConcurrency
+	// and values above prefetchJobCap are clamped.
+	Concurrency int
+}
+
+// PrefetchResult reports the outcome for a single identifier.
+type PrefetchResult struct {
+	ID      string
+	Title   string
+	Skipped bool
+	Err     error
+}
+
+// LoadPrefetchIDs reads one arXiv identifier or URL per line, ignoring blank lines and # comments.
+// Lines naming the same paper (an identifier and its URL) are kept once so concurrent workers never
+// download the same PDF into the shared cache at the same time.
+func LoadPrefetchIDs(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var ids []string
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key := line
+		if id := arxiv.Identifier(line); id != "" {
+			key = id
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		ids = append(ids, line)
+	}
+	return ids, scanner.Err()
+}
+
+// Prefetch fetches each identifier, generates the three brief sections, and persists them into the
+// conversation snapshot store. Papers whose snapshot already holds a complete brief are skipped.
+func Prefetch(ctx context.Context, cfg PrefetchConfig, ids []string) []PrefetchResult {
+	fetch := cfg.Fetch
+	if fetch == nil {
+		fetch = arxiv.FetchPaper
+	}
+	results := make([]PrefetchResult, len(ids))
+	slots := make(chan struct{}, prefetchConcurrency(cfg.Concurrency))
+	// AppendConversationSnapshot rewrites the whole knowledge base, so writes are serialized.
+	var writeMu, progressMu sync.Mutex
+	var wg sync.WaitGroup
+	for idx, id := range ids {
+		wg.Add(1)
+		go func(idx int, id string) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				results[idx] = PrefetchResult{ID: id, Err: ctx.Err()}
+				return
+			}
+			defer func() { <-slots }()
+			results[idx] = prefetchPaper(ctx, cfg.Config, fetch, id, &writeMu)
+			if cfg.Progress != nil {
+				progressMu.Lock()
+				cfg.Progress(results[idx])
+				progressMu.Unlock()
+			}
+		}(idx, id)
+	}
+	wg.Wait()
+	return results
+}
+
+func prefetchConcurrency(n int) int {
+	switch {
+	case n <= 0:
+		return defaultPrefetchConcurrency
+	case n > prefetchJobCap:
+		return prefetchJobCap
+	default:
+		return n
+	}
+}
+
+func prefetchPaper(ctx context.Context, cfg Config, fetch func(context.Context, string) (*arxiv.Paper, error), id string, writeMu *sync.Mutex) PrefetchResult {
+	result := PrefetchResult{ID: id}
+	fetchCtx, cancel := context.WithTimeout(ctx, fetchTimeout(cfg))
+	paper, err := fetch(fetchCtx, id)
+	cancel()
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.ID = paper.ID
+	result.Title = paper.Title
+	if notice := paper.Notice(); notice != "" {
+		result.Err = errors.New(notice)
+		return result
+	}
+	if strings.TrimSpace(paper.FullText) == "" {
+		result.Err = fmt.Errorf("PDF text missing; cannot build brief")
+		return result
+	}
+
+	// A detached model reuses the interactive section contexts and snapshot hydration.
+	m := New(cfg).(*model)
+	m.paper = paper
+	writeMu.Lock()
+	m.hydrateConversationHistory()
+	writeMu.Unlock()
+	if m.hasSnapshotBriefs() {
+		result.Skipped = true
+		return result
+	}
+
+	for _, kind := range briefSectionKinds {
+		sectionCtx, cancel := context.WithTimeout(ctx, m.briefSectionTimeout(kind))
+		bullets, err := cfg.LLM.BriefSection(sectionCtx, kind, paper.Title, m.contextForSection(kind))
+		cancel()
+		if err != nil {
+			result.Err = fmt.Errorf("%s section: %w", briefSectionTitle(kind), err)
+			return result
+		}
+		update := briefSectionSnapshotUpdate(kind, bullets, briefMessageContent(kind, bullets))
+		update.LLM = llmMetadata(cfg.LLM)
+		writeMu.Lock()
+		err = m.knowledgeBase().AppendConversationSnapshot(paper.ID, paper.Title, update)
+		writeMu.Unlock()
+		if err != nil {
+			result.Err = err
+			return result
+		}
+	}
+	return result
+}
@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/llm"
+	"github.com/csheth/browse/internal/notes"
+)
+
+type prefetchLLM struct {
+	fakeLLM
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *prefetchLLM) BriefSection(ctx context.Context, kind llm.BriefSectionKind, title, content string) ([]string, error) {
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+	return []string{fmt.Sprintf("- %s bullet for %s", kind, title)}, nil
+}
+
+func TestPrefetchPersistsBriefsForEachID(t *testing.T) {
+	dir := t.TempDir()
+	idFile := filepath.Join(dir, "ids.txt")
+	if err := os.WriteFile(idFile, []byte("# overnight queue\n2101.00001\n\n2101.00002\n2101.00001\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	ids, err := LoadPrefetchIDs(idFile)
+	if err != nil {
+		t.Fatalf("LoadPrefetchIDs() error = %v", err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("expected comments, blanks, and duplicates skipped, got %v", ids)
+	}
+
+	client := &prefetchLLM{}
+	cfg := PrefetchConfig{
+		Config: Config{KnowledgeBasePath: filepath.Join(dir, "zettel.json"), LLM: client},
+		Fetch: func(ctx context.Context, id string) (*arxiv.Paper, error) {
+			return &arxiv.Paper{ID: id, Title: "Paper " + id, FullText: "Introduction. We propose a method.\n\nResults improve accuracy."}, nil
+		},
+	}
+	for _, result := range Prefetch(context.Background(), cfg, ids) {
+		if result.Err != nil || result.Skipped {
+			t.Fatalf("unexpected prefetch result %+v", result)
+		}
+	}
+
+	snapshots, err := notes.LoadConversationSnapshots(cfg.KnowledgeBasePath)
+	if err != nil {
+		t.Fatalf("LoadConversationSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("expected a snapshot per paper, got %d", len(snapshots))
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Brief == nil || len(snapshot.Brief.Summary) == 0 || len(snapshot.Brief.Technical) == 0 || len(snapshot.Brief.DeepDive) == 0 {
+			t.Fatalf("expected complete brief for %s, got %+v", snapshot.PaperID, snapshot.Brief)
+		}
+		if !strings.Contains(snapshot.Brief.Summary[0], snapshot.PaperID) {
+			t.Fatalf("brief persisted under the wrong paper: %+v", snapshot)
+		}
+	}
+
+	// Reloading in the UI restores the brief instead of regenerating it, and a rerun skips both papers.
+	m := newTestModel(t)
+	m.config = cfg.Config
+	m.handlePaperResult(paperResultMsg{paper: &arxiv.Paper{ID: ids[0], Title: "Paper " + ids[0], FullText: "text"}})
+	if !strings.Contains(m.infoMessage, "restored from conversation history") {
+		t.Fatalf("expected prefetched brief restored on load, got %q", m.infoMessage)
+	}
+	calls := client.calls
+	for _, result := range Prefetch(context.Background(), cfg, ids) {
+		if !result.Skipped {
+			t.Fatalf("expected rerun to skip %s, got %+v", result.ID, result)
+		}
+	}
+	if client.calls != calls {
+		t.Fatalf("expected no LLM calls on rerun, got %d more", client.calls-calls)
+	}
+}
+
+func TestPrefetchConcurrencyProcessesEveryPaper(t *testing.T) {
+	dir := t.TempDir()
+	ids := []string{"2101.00001", "2101.00002", "2101.00003", "2101.00004", "2101.00005"}
+	var mu sync.Mutex
+	active, peak := 0, 0
+	cfg := PrefetchConfig{
+		Config:      Config{KnowledgeBasePath: filepath.Join(dir, "zettel.json"), LLM: &prefetchLLM{}},
+		Concurrency: 2,
+		Fetch: func(ctx context.Context, id string) (*arxiv.Paper, error) {
+			mu.Lock()
+			active++
+			if active > peak {
+				peak = active
+			}
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			active--
+			mu.Unlock()
+			return &arxiv.Paper{ID: id, Title: "Paper " + id, FullText: "Introduction. We propose a method.\n\nResults improve accuracy."}, nil
+		},
+	}
+	reported := 0
+	cfg.Progress = func(PrefetchResult) { reported++ }
+
+	for _, result := range Prefetch(context.Background(), cfg, ids) {
+		if result.Err != nil || result.Skipped {
+			t.Fatalf("unexpected prefetch result %+v", result)
+		}
+	}
+	if reported != len(ids) {
+		t.Fatalf("expected progress for %d papers, got %d", len(ids), reported)
+	}
+	if peak > 2 {
+		t.Fatalf("expected at most 2 papers in flight, got %d", peak)
+	}
+	snapshots, err := notes.LoadConversationSnapshots(cfg.KnowledgeBasePath)
+	if err != nil {
+		t.Fatalf("LoadConversationSnapshots() error = %v", err)
+	}
+	if len(snapshots) != len(ids) {
+		t.Fatalf("expected %d snapshots after concurrent writes, got %d", len(ids), len(snapshots))
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Brief == nil || len(snapshot.Brief.Summary) == 0 || len(snapshot.Brief.Technical) == 0 || len(snapshot.Brief.DeepDive) == 0 {
+			t.Fatalf("expected complete brief for %s, got %+v", snapshot.PaperID, snapshot.Brief)
+		}
+	}
+}
+
+func TestLoadPrefetchIDsDedupesURLsAndIdentifiers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids.txt")
+	if err := os.WriteFile(path, []byte("2101.00001\nhttps://arxiv.org/abs/2101.00001\n2101.00002\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	ids, err := LoadPrefetchIDs(path)
+	if err != nil {
+		t.Fatalf("LoadPrefetchIDs() error = %v", err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("expected the URL to be treated as a duplicate, got %v", ids)
+	}
+}
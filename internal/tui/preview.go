@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+)
+
+// previewFullTextHint follows the preview notice so the status line says how to load the rest.
+const previewFullTextHint = "Run /full to load it."
+
+type fullTextResultMsg struct {
+	paperID string
+	paper   *arxiv.Paper
+	err     error
+}
+
+// actionPreviewCmd opens a paper from its metadata alone, whatever -preview says.
+func (m *model) actionPreviewCmd(args string) tea.Cmd {
+	value := strings.TrimSpace(args)
+	if value == "" {
+		m.infoMessage = "Usage: /preview <arXiv URL or identifier>"
+		return nil
+	}
+	return m.startFetch(value, true)
+}
+
+// showAbstract posts the preview's authors and abstract, which the view otherwise only samples.
+func (m *model) showAbstract() {
+	if m.paper == nil || strings.TrimSpace(m.paper.Abstract) == "" {
+		return
+	}
+	var b strings.Builder
+	if len(m.paper.Authors) > 0 {
+		fmt.Fprintf(&b, "Authors: %s\n\n", strings.Join(m.paper.Authors, ", "))
+	}
+	b.WriteString(m.paper.Abstract)
+	m.appendTranscript("abstract", b.String())
+}
+
+// actionLoadFullTextCmd downloads the text of a previewed paper; the brief starts once it arrives.
+func (m *model) actionLoadFullTextCmd() tea.Cmd {
+	if m.paper == nil {
+		m.infoMessage = "Load a paper first."
+		return nil
+	}
+	if !m.paper.Preview {
+		m.infoMessage = "The full text is already loaded."
+		return nil
+	}
+	m.jobBus.Cancel(jobKindFullText)
+	m.errorMessage = ""
+	m.infoMessage = fmt.Sprintf("Loading the full text of %s…", m.paper.Title)
+	return tea.Batch(m.spinner.Tick, m.jobBus.Start(jobKindFullText, fullTextJob(m.paper, fetchTimeout(m.config))))
+}
+
+// fullTextJob fetches into a copy of preview so the model's paper is only replaced on success.
+func fullTextJob(preview *arxiv.Paper, timeout time.Duration) jobRunner {
+	paper := *preview
+	return func(parent context.Context) (tea.Msg, error) {
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+		err := arxiv.FetchFullText(ctx, &paper)
+		// As with FetchPaper, a PDF that cannot be fetched still ends the preview with its notice.
+		if err != nil && !errors.Is(err, arxiv.ErrPDFUnavailable) {
+			return fullTextResultMsg{paperID: paper.ID, err: err}, err
+		}
+		return fullTextResultMsg{paperID: paper.ID, paper: &paper}, nil
+	}
+}
+
+func (m *model) handleFullTextResult(msg fullTextResultMsg) tea.Cmd {
+	if m.paper == nil || m.paper.ID != msg.paperID || !m.paper.Preview {
+		return nil
+	}
+	if msg.err != nil {
+		m.errorMessage = fmt.Sprintf("full text error: %v", msg.err)
+		m.infoMessage = "Still showing the preview. Retry with /full."
+		m.appendTranscript("error", fmt.Sprintf("Full text failed: %v", msg.err))
+		return nil
+	}
+	m.paper = msg.paper
+	m.paperWords = len(strings.Fields(m.paper.FullText))
+	m.paperReadingTime = estimateReadingTime(m.paper.FullText)
+	m.errorMessage = ""
+	m.appendTranscript("paper", fmt.Sprintf("Loaded the full text of %s", m.paper.Title))
+	m.markViewportDirty()
+	if m.briefComplete() {
+		m.infoMessage = fmt.Sprintf("Loaded the full text of %s.", m.paper.Title)
+		return nil
+	}
+	// The preview's brief contexts were built from no text at all.
+	m.briefContexts = nil
+	m.briefChunks = nil
+	m.prepareBriefFallbacks()
+	return m.autoBriefCmd()
+}
+
+// briefComplete reports whether every brief section already finished, as when a preview restored
+// its brief from conversation history or the brief cache.
+func (m *model) briefComplete() bool {
+	for _, kind := range briefSectionKinds {
+		if !m.briefSections[kind].Completed {
+			return false
+		}
+	}
+	return true
+}
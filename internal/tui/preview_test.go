@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+)
+
+func TestPreviewShowsAbstractAndWaitsForFullText(t *testing.T) {
+	m := newTestModel(t)
+	m.config.LLM = fakeLLM{}
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 16})
+	preview := &arxiv.Paper{
+		ID:       "2101.00001",
+		Title:    "Sparse Routing at Scale",
+		Authors:  []string{"Ada Lovelace", "Alan Turing"},
+		Abstract: "We route tokens to two experts. Experiments halve the compute.",
+		Preview:  true,
+	}
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: preview})
+
+	if m.briefLoading {
+		t.Fatal("expected the brief to wait for the full text")
+	}
+	if !strings.Contains(m.infoMessage, "/full") {
+		t.Fatalf("expected the status line to point at /full, got %q", m.infoMessage)
+	}
+	var abstract string
+	for _, entry := range m.transcriptEntries {
+		if entry.Kind == "abstract" {
+			abstract = entry.Content
+		}
+	}
+	if !strings.Contains(abstract, "Ada Lovelace, Alan Turing") || !strings.Contains(abstract, "halve the compute") {
+		t.Fatalf("expected the authors and abstract in the transcript, got %q", abstract)
+	}
+	m.composer.SetValue("/brief")
+	m.submitComposer()
+	if m.briefLoading {
+		t.Fatal("expected /brief to stay disabled during the preview")
+	}
+
+	full := *preview
+	full.Preview = false
+	full.FullText = strings.Repeat("Sparse routing sends each token to two experts and halves the compute per layer. ", 20)
+	if cmd := m.handleFullTextResult(fullTextResultMsg{paperID: full.ID, paper: &full}); cmd == nil {
+		t.Fatal("expected the brief to start once the full text loaded")
+	}
+	if m.paper.Preview || !m.briefLoading {
+		t.Fatalf("expected the full paper with its brief running, got preview=%v loading=%v", m.paper.Preview, m.briefLoading)
+	}
+	if m.paperWords == 0 {
+		t.Fatal("expected the word count to cover the full text")
+	}
+}
+
+func TestLoadFullTextRequiresPreview(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Loaded", FullText: "text"}
+	if cmd := m.actionLoadFullTextCmd(); cmd != nil {
+		t.Fatal("expected no fetch for a paper that is not a preview")
+	}
+	if m.infoMessage != "The full text is already loaded." {
+		t.Fatalf("unexpected info message %q", m.infoMessage)
+	}
+}
@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// actionShowReferencesCmd posts the paper's parsed bibliography to the transcript as a numbered
+// list, for literature reviews.
+func (m *model) actionShowReferencesCmd(string) tea.Cmd {
+	if m.paper == nil {
+		m.infoMessage = "Load a paper before listing references."
+		return nil
+	}
+	if len(m.paper.References) == 0 {
+		m.infoMessage = "No numbered references section was found in this paper's text."
+		return nil
+	}
+	m.appendTranscript("references", referencesMarkdown(m.paper.References))
+	m.infoMessage = fmt.Sprintf("Listed %d references.", len(m.paper.References))
+	m.markViewportDirty()
+	return nil
+}
+
+func referencesMarkdown(references []string) string {
+	var b strings.Builder
+	b.WriteString("**References**\n")
+	for i, ref := range references {
+		fmt.Fprintf(&b, "\n%d. %s", i+1, ref)
+	}
+	return b.String()
+}
@@ -0,0 +1,38 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+)
+
+func TestShowReferencesPostsNumberedList(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Cited Paper", References: []string{"A. Author. First.", "B. Author. Second."}}
+	m.stage = stageDisplay
+
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/references")
+	if _, handled := m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter}); !handled {
+		t.Fatal("expected /references to be handled")
+	}
+	if len(m.transcriptEntries) == 0 {
+		t.Fatal("expected a transcript entry")
+	}
+	last := m.transcriptEntries[len(m.transcriptEntries)-1]
+	if last.Kind != "references" || !strings.Contains(last.Content, "1. A. Author. First.") || !strings.Contains(last.Content, "2. B. Author. Second.") {
+		t.Fatalf("expected a numbered reference list, got %+v", last)
+	}
+}
+
+func TestShowReferencesWithoutSection(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Uncited Paper"}
+	m.actionShowReferencesCmd("")
+	if len(m.transcriptEntries) != 0 || !strings.Contains(m.infoMessage, "No numbered references") {
+		t.Fatalf("expected a friendly notice, got %q with %d entries", m.infoMessage, len(m.transcriptEntries))
+	}
+}
@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/notes"
+)
+
+// resumeLatestCmd refetches the paper from the most recently active conversation snapshot. The
+// snapshot is kept so a failed fetch can still reopen the saved brief and transcript.
+func (m *model) resumeLatestCmd() tea.Cmd {
+	snapshot, ok, err := m.knowledgeBase().LatestConversationSnapshot()
+	if err != nil {
+		m.errorMessage = fmt.Sprintf("knowledge base error: %v", err)
+		return nil
+	}
+	if !ok {
+		m.infoMessage = "No saved session to resume. Paste an arXiv url or identifier to begin."
+		return nil
+	}
+	cmd := m.startFetchCmd(snapshot.PaperID)
+	m.resumeSnapshot = &snapshot
+	title := snapshot.PaperTitle
+	if title == "" {
+		title = snapshot.PaperID
+	}
+	m.infoMessage = fmt.Sprintf("Resuming %s…", title)
+	return cmd
+}
+
+// restoreResumedPaper opens the resumed paper from its snapshot alone after the fetch failed, eg.
+// offline with the PDF gone from the cache. The saved brief and transcript are shown, but the
+// paper is marked Restored so questions and brief generation stay off.
+func (m *model) restoreResumedPaper(failed paperResultMsg, snapshot *notes.ConversationSnapshot) tea.Cmd {
+	title := snapshot.PaperTitle
+	if title == "" {
+		title = snapshot.PaperID
+	}
+	paper := &arxiv.Paper{ID: snapshot.PaperID, Title: title, Restored: true}
+	cmd := m.handlePaperResult(paperResultMsg{seq: failed.seq, paper: paper})
+	m.errorMessage = fmt.Sprintf("Could not fetch %s: %v", snapshot.PaperID, failed.err)
+	m.infoMessage = fmt.Sprintf("Resumed %s from saved history.", title)
+	if last := len(m.transcriptEntries) - 1; last < 0 || m.transcriptEntries[last].Content != arxiv.RestoredNotice {
+		m.appendTranscript("paper", arxiv.RestoredNotice)
+	}
+	return cmd
+}
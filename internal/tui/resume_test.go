@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/notes"
+)
+
+func newResumeModel(t *testing.T, snapshots ...notes.ConversationSnapshot) *model {
+	t.Helper()
+	m := newTestModel(t)
+	m.config.Resume = true
+	m.config.KnowledgeBasePath = filepath.Join(t.TempDir(), "zettel.json")
+	if len(snapshots) > 0 {
+		if err := notes.SaveConversationSnapshots(m.config.KnowledgeBasePath, snapshots); err != nil {
+			t.Fatalf("SaveConversationSnapshots() error = %v", err)
+		}
+	}
+	return m
+}
+
+func resumeFixtures() []notes.ConversationSnapshot {
+	older := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	return []notes.ConversationSnapshot{
+		{
+			PaperID:    "2101.00001",
+			PaperTitle: "Older Paper",
+			CapturedAt: older,
+			Messages:   []notes.ConversationMessage{{Kind: "question", Content: "Old question?", Timestamp: older}},
+		},
+		{
+			PaperID:    "2202.00002",
+			PaperTitle: "Newest Paper",
+			CapturedAt: older.Add(48 * time.Hour),
+			Messages:   []notes.ConversationMessage{{Kind: "question", Content: "What changed?", Timestamp: older.Add(48 * time.Hour)}},
+			Brief:      &notes.BriefSnapshot{Summary: []string{"Saved summary bullet"}},
+		},
+	}
+}
+
+func TestResumeLoadsNewestSnapshot(t *testing.T) {
+	m := newResumeModel(t, resumeFixtures()...)
+	if cmd := m.initialFetchCmd(); cmd == nil {
+		t.Fatal("expected a fetch for the resumed paper")
+	}
+	if m.resumeSnapshot == nil || m.resumeSnapshot.PaperID != "2202.00002" || !m.fetchInProgress {
+		t.Fatalf("expected the newest snapshot to be fetched, got %+v", m.resumeSnapshot)
+	}
+
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: &arxiv.Paper{ID: "2202.00002", Title: "Newest Paper", FullText: "Paper text."}})
+	if m.stage != stageDisplay || m.paper.Restored {
+		t.Fatalf("expected the fetched paper on display, got stage %v restored %v", m.stage, m.paper.Restored)
+	}
+	if !transcriptContains(m, "What changed?") || len(m.brief.Summary) != 1 {
+		t.Fatalf("expected the transcript and brief hydrated, got %+v", m.transcriptEntries)
+	}
+}
+
+func TestResumeRestoresFromSnapshotWhenFetchFails(t *testing.T) {
+	m := newResumeModel(t, resumeFixtures()...)
+	m.initialFetchCmd()
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, err: errors.New("network unreachable")})
+
+	if m.paper == nil || !m.paper.Restored || m.paper.ID != "2202.00002" || m.stage != stageDisplay {
+		t.Fatalf("expected the paper restored from its snapshot, got %+v stage %v", m.paper, m.stage)
+	}
+	if !transcriptContains(m, "What changed?") || len(m.brief.Summary) != 1 || m.brief.Summary[0] != "Saved summary bullet" {
+		t.Fatalf("expected the saved conversation shown, got %+v", m.brief)
+	}
+	if !strings.Contains(m.errorMessage, "network unreachable") {
+		t.Fatalf("expected the fetch error reported, got %q", m.errorMessage)
+	}
+
+	m.config.LLM = fakeLLM{}
+	m.setComposerMode(composerModeQuestion, composerQuestionPlaceholder, true)
+	m.composer.SetValue("Can I still ask?")
+	m.submitComposer()
+	if len(m.qaHistory) != 0 || m.infoMessage != arxiv.RestoredNotice {
+		t.Fatalf("expected questions disabled, got %d questions and %q", len(m.qaHistory), m.infoMessage)
+	}
+}
+
+func TestResumeWithoutSnapshotsShowsURLPrompt(t *testing.T) {
+	m := newResumeModel(t)
+	if cmd := m.initialFetchCmd(); cmd != nil {
+		t.Fatal("expected no fetch without saved sessions")
+	}
+	if m.stage != stageInput || m.composerMode != composerModeURL || !strings.Contains(m.infoMessage, "No saved session") {
+		t.Fatalf("expected the URL prompt, got stage %v mode %v info %q", m.stage, m.composerMode, m.infoMessage)
+	}
+}
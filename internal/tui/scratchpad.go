@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/reflow/wordwrap"
+
+	"github.com/csheth/browse/internal/notes"
+)
+
+// actionEditScratchpadCmd loads the paper's scratchpad into the composer. Unlike notes, the
+// scratchpad is one running document, so saving replaces it rather than appending.
+func (m *model) actionEditScratchpadCmd() tea.Cmd {
+	if m.paper == nil {
+		m.infoMessage = "Load a paper before opening the scratchpad."
+		return nil
+	}
+	m.setComposerMode(composerModeScratchpad, composerScratchPlaceholder, true)
+	m.composer.SetValue(m.scratchpad)
+	m.updateComposerHeight()
+	m.infoMessage = "Editing scratchpad. Ctrl+Enter saves, Esc cancels."
+	m.markViewportDirty()
+	return nil
+}
+
+func (m *model) saveScratchpadCmd() tea.Cmd {
+	value := strings.TrimSpace(m.composer.Value())
+	m.scratchpad = value
+	m.composer.SetValue("")
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, false)
+	if value == "" {
+		m.infoMessage = "Scratchpad cleared."
+	} else {
+		m.infoMessage = "Scratchpad saved."
+	}
+	m.markViewportDirty()
+	return m.appendConversationSnapshotCmd(notes.SnapshotUpdate{Scratchpad: &value})
+}
+
+// writeScratchpadOverlay replaces the conversation while the scratchpad is open so the saved text
+// stays visible above the draft being edited.
+func (m *model) writeScratchpadOverlay(cb *contentBuilder) {
+	cb.WriteString(sectionHeaderStyle.Render("Scratchpad"))
+	cb.WriteRune('\n')
+	if m.paper != nil {
+		cb.WriteString(helperStyle.Render(m.paper.Title))
+		cb.WriteRune('\n')
+	}
+	cb.WriteRune('\n')
+	if m.scratchpad == "" {
+		cb.WriteString(helperStyle.Render("Nothing saved yet."))
+		cb.WriteRune('\n')
+		return
+	}
+	cb.WriteString(indentMultiline(wordwrap.String(m.scratchpad, m.wrapWidth(4)), "  "))
+	cb.WriteRune('\n')
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/notes"
+)
+
+func TestScratchpadRoundTripsThroughSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zettel.json")
+	paper := &arxiv.Paper{ID: "2101.00001", Title: "Scratch Paper"}
+
+	m := newTestModel(t)
+	m.config.KnowledgeBasePath = path
+	m.paper = paper
+	m.stage = stageDisplay
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/scratch")
+	m.submitComposer()
+	if m.composerMode != composerModeScratchpad {
+		t.Fatalf("expected scratchpad mode, got %v", m.composerMode)
+	}
+
+	draft := "Compare with last week's baseline.\nCheck appendix B for ablations."
+	m.composer.SetValue(draft)
+	m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.composerMode != composerModeScratchpad || m.scratchpad != "" {
+		t.Fatalf("expected plain Enter to stay in the editor, got mode %v scratchpad %q", m.composerMode, m.scratchpad)
+	}
+	m.composer.SetValue(draft)
+	cmd, handled := m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter, Alt: true})
+	if !handled || cmd == nil {
+		t.Fatal("expected save to persist the scratchpad")
+	}
+	if m.scratchpad != draft || m.composerMode == composerModeScratchpad {
+		t.Fatalf("expected scratchpad saved and editor closed, got %q (mode %v)", m.scratchpad, m.composerMode)
+	}
+	saved := m.scratchpad
+	if _, err := appendConversationSnapshotJob(notes.Store{Path: path}, paper, notes.SnapshotUpdate{Scratchpad: &saved})(context.Background()); err != nil {
+		t.Fatalf("appendConversationSnapshotJob() error = %v", err)
+	}
+
+	reloaded := newTestModel(t)
+	reloaded.config.KnowledgeBasePath = path
+	reloaded.paper = paper
+	reloaded.hydrateConversationHistory()
+	if reloaded.scratchpad != draft {
+		t.Fatalf("expected scratchpad %q after reload, got %q", draft, reloaded.scratchpad)
+	}
+	reloaded.actionEditScratchpadCmd()
+	if reloaded.composer.Value() != draft {
+		t.Fatalf("expected editor to open with saved text, got %q", reloaded.composer.Value())
+	}
+	if body := reloaded.buildDisplayContent().body; !strings.Contains(body, "appendix B") {
+		t.Fatalf("expected overlay to show the scratchpad, got:\n%s", body)
+	}
+}
@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+)
+
+func TestHalfPageScrollMovesByHalfTheHeightAndClamps(t *testing.T) {
+	m := newTestModel(t)
+	m.config.AutoBrief = false
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 16})
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: &arxiv.Paper{ID: "2101.00001", Title: "Scrolly", Abstract: "We study scrolling.", FullText: "Body."}})
+	for _, kind := range briefSectionKinds {
+		var bullets []string
+		for i := 0; i < 12; i++ {
+			bullets = append(bullets, fmt.Sprintf("- %s point %d", briefSectionTitle(kind), i))
+		}
+		m.setBriefMessage(kind, briefMessageContent(kind, bullets))
+	}
+	m.markViewportDirty()
+	m.refreshViewportIfDirty()
+	m.blurComposer()
+	m.scrollToTop()
+	half := m.viewport.Height / 2
+	if half == 0 || m.lineCount < 3*m.viewport.Height {
+		t.Fatalf("expected a fixture several pages long, got %d lines at height %d", m.lineCount, m.viewport.Height)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	if m.viewport.YOffset != half || m.cursorLine != half {
+		t.Fatalf("expected Ctrl+D to move offset and cursor to %d, got %d and %d", half, m.viewport.YOffset, m.cursorLine)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	if want := half + m.viewport.Height; m.viewport.YOffset != want {
+		t.Fatalf("expected Ctrl+F to move a full page to %d, got %d", want, m.viewport.YOffset)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlU})
+	if want := m.viewport.Height; m.viewport.YOffset != want {
+		t.Fatalf("expected Ctrl+U to move back half a page to %d, got %d", want, m.viewport.YOffset)
+	}
+
+	bottom := m.clampYOffset(m.lineCount)
+	for i := 0; i < m.lineCount; i++ {
+		m.Update(tea.KeyMsg{Type: tea.KeyCtrlD})
+	}
+	if m.viewport.YOffset != bottom {
+		t.Fatalf("expected Ctrl+D to stop at the bottom offset %d, got %d", bottom, m.viewport.YOffset)
+	}
+	if m.cursorLine < bottom || m.cursorLine >= m.lineCount {
+		t.Fatalf("expected the cursor on the last screen, got %d", m.cursorLine)
+	}
+	if m.infoMessage != "Already at the bottom." {
+		t.Fatalf("unexpected info message %q", m.infoMessage)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	if want := bottom - m.viewport.Height; m.viewport.YOffset != want {
+		t.Fatalf("expected Ctrl+B to move up a page to %d, got %d", want, m.viewport.YOffset)
+	}
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/notes"
+)
+
+// maxSearchResults caps how many matching notes one search posts to the transcript.
+const maxSearchResults = 20
+
+// maxRelatedNotes caps how many notes from other papers are linked when a paper loads.
+const maxRelatedNotes = 3
+
+// actionSearchNotesCmd opens the composer for a knowledge base search. With args (from
+// "/search <query>") it searches right away instead.
+func (m *model) actionSearchNotesCmd(args string) tea.Cmd {
+	if query := strings.TrimSpace(args); query != "" {
+		m.searchNotes(query)
+		return nil
+	}
+	m.setComposerMode(composerModeSearch, composerSearchPlaceholder, true)
+	m.infoMessage = "Search saved notes. Enter searches, Esc cancels."
+	m.markViewportDirty()
+	return nil
+}
+
+// searchNotes posts each note matching query, best match first, as a transcript entry labelled
+// with its paper title.
+func (m *model) searchNotes(query string) {
+	results, err := m.knowledgeBase().Search(query)
+	if err != nil {
+		m.errorMessage = fmt.Sprintf("Search failed: %v", err)
+		m.appendTranscript("error", m.errorMessage)
+		return
+	}
+	m.errorMessage = ""
+	if len(results) == 0 {
+		m.infoMessage = fmt.Sprintf("No saved notes match %q.", query)
+		return
+	}
+	shown := results
+	if len(shown) > maxSearchResults {
+		shown = shown[:maxSearchResults]
+	}
+	for _, note := range shown {
+		m.appendTranscript("search", searchResultMarkdown(note))
+	}
+	if len(results) > len(shown) {
+		m.infoMessage = fmt.Sprintf("Showing the top %d of %d notes matching %q.", len(shown), len(results), query)
+	} else {
+		m.infoMessage = fmt.Sprintf("Found %d notes matching %q.", len(results), query)
+	}
+}
+
+// actionTagCmd posts every saved note tagged with args (#todo or todo). Without a tag it lists the
+// tags in use, most used first.
+func (m *model) actionTagCmd(args string) tea.Cmd {
+	tag := strings.TrimPrefix(strings.TrimSpace(args), "#")
+	if tag == "" {
+		m.listTags()
+		return nil
+	}
+	results, err := m.knowledgeBase().LoadByTag(tag)
+	if err != nil {
+		m.errorMessage = fmt.Sprintf("Tag lookup failed: %v", err)
+		m.appendTranscript("error", m.errorMessage)
+		return nil
+	}
+	m.errorMessage = ""
+	if len(results) == 0 {
+		m.infoMessage = fmt.Sprintf("No saved notes are tagged #%s.", tag)
+		return nil
+	}
+	shown := results
+	if len(shown) > maxSearchResults {
+		shown = shown[:maxSearchResults]
+	}
+	for _, note := range shown {
+		m.appendTranscript("search", searchResultMarkdown(note))
+	}
+	if len(results) > len(shown) {
+		m.infoMessage = fmt.Sprintf("Showing %d of %d notes tagged #%s.", len(shown), len(results), tag)
+	} else {
+		m.infoMessage = fmt.Sprintf("Found %d notes tagged #%s.", len(results), tag)
+	}
+	return nil
+}
+
+func (m *model) listTags() {
+	counts, err := m.knowledgeBase().TagCounts()
+	if err != nil {
+		m.errorMessage = fmt.Sprintf("Tag lookup failed: %v", err)
+		return
+	}
+	if len(counts) == 0 {
+		m.infoMessage = "No tagged notes yet; add #words to a note to tag it."
+		return
+	}
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] != counts[tags[j]] {
+			return counts[tags[i]] > counts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	for i, tag := range tags {
+		tags[i] = fmt.Sprintf("#%s (%d)", tag, counts[tag])
+	}
+	m.infoMessage = fmt.Sprintf("Tags: %s. Type /tag <tag> to list its notes.", strings.Join(tags, ", "))
+}
+
+// showRelatedNotes posts the saved notes from other papers that share the loaded paper's subjects
+// or title keywords as one transcript entry. It is a hint, so a knowledge base that cannot be read
+// is skipped quietly rather than reported.
+func (m *model) showRelatedNotes() {
+	related, err := m.knowledgeBase().FindRelated(m.paper, maxRelatedNotes)
+	if err != nil || len(related) == 0 {
+		return
+	}
+	var b strings.Builder
+	b.WriteString("Related notes from other papers:")
+	for _, note := range related {
+		paper := strings.TrimSpace(note.PaperTitle)
+		if paper == "" {
+			paper = note.PaperID
+		}
+		fmt.Fprintf(&b, "\n- **%s** — _%s_", strings.TrimSpace(note.Title), paper)
+	}
+	m.appendTranscript("related", b.String())
+}
+
+func searchResultMarkdown(note notes.Note) string {
+	paper := strings.TrimSpace(note.PaperTitle)
+	if paper == "" {
+		paper = note.PaperID
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s** — _%s_", strings.TrimSpace(note.Title), paper)
+	if body := strings.TrimSpace(note.Body); body != "" {
+		b.WriteString("\n\n")
+		b.WriteString(body)
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/notes"
+)
+
+func TestSearchModeRendersMatchingNotes(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Current Paper"}
+	saved := []notes.Note{
+		{PaperID: "1901.00001", PaperTitle: "Old Paper", Title: "Attention cost", Body: "Attention is quadratic."},
+		{PaperID: "1902.00002", PaperTitle: "Other Paper", Title: "Datasets", Body: "CIFAR only."},
+	}
+	if err := notes.Save(m.knowledgeBasePath(), saved); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/search")
+	if _, handled := m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter}); !handled {
+		t.Fatal("expected /search to be handled")
+	}
+	if m.composerMode != composerModeSearch {
+		t.Fatalf("expected search mode, got %v", m.composerMode)
+	}
+
+	m.composer.SetValue("ATTENTION")
+	if _, handled := m.processComposerKey(tea.KeyMsg{Type: tea.KeyEnter}); !handled {
+		t.Fatal("expected Enter to run the search")
+	}
+	if len(m.qaHistory) != 0 {
+		t.Fatalf("search should not be sent as a question, got %d entries", len(m.qaHistory))
+	}
+	if len(m.transcriptEntries) != 1 {
+		t.Fatalf("expected one search result entry, got %+v", m.transcriptEntries)
+	}
+	entry := m.transcriptEntries[0]
+	if entry.Kind != "search" || !strings.Contains(entry.Content, "Attention cost") || !strings.Contains(entry.Content, "Old Paper") {
+		t.Fatalf("unexpected search result entry: %+v", entry)
+	}
+	if m.composerMode != composerModeNote {
+		t.Fatalf("expected composer back in note mode, got %v", m.composerMode)
+	}
+}
+
+func TestTaggedNoteCanBeListedByTag(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Current Paper"}
+	m.stage = stageDisplay
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("Rerun the ablation #TODO #important")
+	m.submitComposer()
+	if len(m.manualNotes) != 1 || strings.Join(m.manualNotes[0].Tags, ",") != "todo,important" {
+		t.Fatalf("expected tags parsed from the note body, got %+v", m.manualNotes)
+	}
+	if err := notes.Save(m.knowledgeBasePath(), m.manualNotes); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	m.actionTagCmd("")
+	if !strings.Contains(m.infoMessage, "#important (1), #todo (1)") {
+		t.Fatalf("expected the tags listed, got %q", m.infoMessage)
+	}
+	before := len(m.transcriptEntries)
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, true)
+	m.composer.SetValue("/tag #todo")
+	m.submitComposer()
+	if len(m.transcriptEntries) != before+1 || !strings.Contains(m.transcriptEntries[before].Content, "Rerun the ablation") {
+		t.Fatalf("expected the tagged note posted, got %+v", m.transcriptEntries[before:])
+	}
+}
+
+func TestLoadingPaperLinksRelatedNotes(t *testing.T) {
+	m := newTestModel(t)
+	saved := []notes.Note{
+		{PaperID: "1901.00001", PaperTitle: "Balancing Experts at Scale", Title: "Balancing loss", Body: "Keeps every expert busy."},
+		{PaperID: "1902.00002", PaperTitle: "Protein Folding", Title: "Contact maps", Body: "Contacts constrain the fold."},
+	}
+	if err := notes.Save(m.knowledgeBasePath(), saved); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: &arxiv.Paper{ID: "2101.00001", Title: "Routing Experts Sparsely", FullText: "Body."}})
+
+	var related []transcriptEntry
+	for _, entry := range m.transcriptEntries {
+		if entry.Kind == "related" {
+			related = append(related, entry)
+		}
+	}
+	if len(related) != 1 || !strings.Contains(related[0].Content, "Balancing loss") || strings.Contains(related[0].Content, "Contact maps") {
+		t.Fatalf("expected one related-notes entry linking the balancing note, got %+v", related)
+	}
+}
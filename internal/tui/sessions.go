@@ -0,0 +1,235 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/guide"
+	"github.com/csheth/browse/internal/llm"
+	"github.com/csheth/browse/internal/notes"
+
+	briefctx "github.com/csheth/browse/internal/brief/context"
+)
+
+// paperSession is one tab's paper state. The active tab's state lives directly on the model so every
+// flow keeps reading m.paper, m.brief, and friends; switching tabs stores the active fields in its
+// session and restores the target's.
+type paperSession struct {
+	stage              stage
+	paper              *arxiv.Paper
+	guide              []guide.Step
+	guideFocus         string
+	scratchpad         string
+	paperWords         int
+	paperReadingTime   time.Duration
+	suggestions        []notes.Candidate
+	suggestionCursor   int
+	selected           map[int]bool
+	persisted          map[int]bool
+	cursorLine         int
+	manualNotes        []notes.Note
+	draftCursor        int
+	persistedNotes     []notes.Note
+	suggestionLines    map[int]int
+	sectionAnchors     map[string]int
+	brief              llm.ReadingBrief
+	briefLLM           *notes.LLMMetadata
+	briefSections      map[llm.BriefSectionKind]briefSectionState
+	briefFallbacks     map[llm.BriefSectionKind][]string
+	briefContexts      map[llm.BriefSectionKind]string
+	briefMessageIndex  map[llm.BriefSectionKind]int
+	briefChunks        []briefctx.Chunk
+	qaHistory          []qaExchange
+	transcriptEntries  []transcriptEntry
+	contextPreview     llm.BriefSectionKind
+	pendingFocusAnchor string
+	yOffset            int
+}
+
+// interruptedByTabSwitch explains brief sections and questions cut short by leaving their tab.
+const interruptedByTabSwitch = "interrupted by switching tabs"
+
+func (m *model) captureSession() paperSession {
+	return paperSession{
+		stage:              m.stage,
+		paper:              m.paper,
+		guide:              m.guide,
+		guideFocus:         m.guideFocus,
+		scratchpad:         m.scratchpad,
+		paperWords:         m.paperWords,
+		paperReadingTime:   m.paperReadingTime,
+		suggestions:        m.suggestions,
+		suggestionCursor:   m.suggestionCursor,
+		selected:           m.selected,
+		persisted:          m.persisted,
+		cursorLine:         m.cursorLine,
+		manualNotes:        m.manualNotes,
+		draftCursor:        m.draftCursor,
+		persistedNotes:     m.persistedNotes,
+		suggestionLines:    m.suggestionLines,
+		sectionAnchors:     m.sectionAnchors,
+		brief:              m.brief,
+		briefLLM:           m.briefLLM,
+		briefSections:      m.briefSections,
+		briefFallbacks:     m.briefFallbacks,
+		briefContexts:      m.briefContexts,
+		briefMessageIndex:  m.briefMessageIndex,
+		briefChunks:        m.briefChunks,
+		qaHistory:          m.qaHistory,
+		transcriptEntries:  m.transcriptEntries,
+		contextPreview:     m.contextPreview,
+		pendingFocusAnchor: m.pendingFocusAnchor,
+		yOffset:            m.viewport.YOffset,
+	}
+}
+
+func (m *model) restoreSession(s paperSession) {
+	m.resetBriefState()
+	m.clearFind()
+	m.stage = s.stage
+	m.paper = s.paper
+	m.guide = s.guide
+	m.guideFocus = s.guideFocus
+	m.scratchpad = s.scratchpad
+	m.paperWords = s.paperWords
+	m.paperReadingTime = s.paperReadingTime
+	m.suggestions = s.suggestions
+	m.suggestionCursor = s.suggestionCursor
+	m.selected = s.selected
+	m.persisted = s.persisted
+	m.cursorLine = s.cursorLine
+	m.manualNotes = s.manualNotes
+	m.draftCursor = s.draftCursor
+	m.persistedNotes = s.persistedNotes
+	m.suggestionLines = s.suggestionLines
+	m.sectionAnchors = s.sectionAnchors
+	m.brief = s.brief
+	m.briefLLM = s.briefLLM
+	if s.briefSections != nil {
+		m.briefSections = s.briefSections
+	}
+	m.briefFallbacks = s.briefFallbacks
+	m.briefContexts = s.briefContexts
+	m.briefMessageIndex = s.briefMessageIndex
+	m.briefChunks = s.briefChunks
+	m.qaHistory = s.qaHistory
+	m.transcriptEntries = s.transcriptEntries
+	m.contextPreview = s.contextPreview
+	m.pendingFocusAnchor = s.pendingFocusAnchor
+	if m.selected == nil {
+		m.selected = map[int]bool{}
+	}
+	if m.persisted == nil {
+		m.persisted = map[int]bool{}
+	}
+	if m.suggestionLines == nil {
+		m.suggestionLines = map[int]int{}
+	}
+	if m.sectionAnchors == nil {
+		m.sectionAnchors = map[string]int{}
+	}
+	m.clearSelection()
+	m.markTranscriptDirty()
+	m.markViewportDirty()
+	m.refreshViewportIfDirty()
+	m.viewport.SetYOffset(s.yOffset)
+}
+
+// suspendPaperWork stops the active tab's brief streams and answers before it goes to the
+// background, since their results are only applied to the paper on screen. Interrupted sections and
+// questions keep what they produced so far and say why they stopped.
+func (m *model) suspendPaperWork() {
+	for _, cancel := range m.briefStreamCancels {
+		cancel()
+	}
+	m.briefStreamCancels = map[llm.BriefSectionKind]context.CancelFunc{}
+	m.briefStreamPending = map[llm.BriefSectionKind][]string{}
+	m.briefStreamSeqs = map[llm.BriefSectionKind]int{}
+	for kind, state := range m.briefSections {
+		if state.Loading {
+			state.Loading = false
+			state.Regenerable = true
+			state.Error = interruptedByTabSwitch
+			m.briefSections[kind] = state
+		}
+	}
+	m.briefLoading = false
+	m.cancelQuestionStream()
+	for i := range m.qaHistory {
+		if m.qaHistory[i].Pending {
+			m.qaHistory[i].Pending = false
+			m.qaHistory[i].Error = interruptedByTabSwitch
+		}
+	}
+	m.queuedQuestions = nil
+	m.questionLoading = false
+	m.suggestionLoading = false
+}
+
+// switchSession moves delta tabs forward (or back), wrapping around. A tab that never loaded a paper
+// is closed on the way out.
+func (m *model) switchSession(delta int) {
+	if len(m.sessions) < 2 {
+		m.infoMessage = "Only one tab is open; load another paper to open a second."
+		return
+	}
+	if m.fetchInProgress {
+		m.infoMessage = "Wait for the paper to load before switching tabs."
+		return
+	}
+	m.suspendPaperWork()
+	m.editingNote = nil
+	target := (m.activeSession + delta + len(m.sessions)) % len(m.sessions)
+	if m.paper == nil {
+		m.sessions = append(m.sessions[:m.activeSession], m.sessions[m.activeSession+1:]...)
+		if target > m.activeSession {
+			target--
+		}
+	} else {
+		m.sessions[m.activeSession] = m.captureSession()
+	}
+	m.activeSession = target
+	m.restoreSession(m.sessions[target])
+	m.errorMessage = ""
+	if m.paper == nil {
+		m.composer.SetValue("")
+		m.setComposerMode(composerModeURL, composerURLPlaceholder, true)
+		m.infoMessage = fmt.Sprintf("%s: paste an arXiv url or identifier.", m.tabLabel())
+		return
+	}
+	m.composer.SetValue("")
+	m.setComposerMode(composerModeNote, composerNotePlaceholder, false)
+	m.infoMessage = fmt.Sprintf("%s: %s", m.tabLabel(), m.paper.Title)
+}
+
+// openSessionTab stores the active paper in its tab and starts an empty one for the next paper. An
+// active tab without a paper is reused.
+func (m *model) openSessionTab() {
+	if m.paper == nil {
+		return
+	}
+	m.suspendPaperWork()
+	m.sessions[m.activeSession] = m.captureSession()
+	m.sessions = append(m.sessions, paperSession{stage: stageInput})
+	m.activeSession = len(m.sessions) - 1
+	m.restoreSession(m.sessions[m.activeSession])
+}
+
+// sessionForPaper returns the tab other than the active one already showing paperID, or -1. Tabs
+// restored from history without the paper text do not count, so reloading such a paper keeps the
+// freshly fetched copy.
+func (m *model) sessionForPaper(paperID string) int {
+	for i, session := range m.sessions {
+		if i != m.activeSession && session.paper != nil && session.paper.ID == paperID && !session.paper.Restored {
+			return i
+		}
+	}
+	return -1
+}
+
+// tabLabel names the active tab, eg. "Tab 2/3".
+func (m *model) tabLabel() string {
+	return fmt.Sprintf("Tab %d/%d", m.activeSession+1, len(m.sessions))
+}
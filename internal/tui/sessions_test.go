@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+)
+
+func loadTestPaper(t *testing.T, m *model, paper *arxiv.Paper) {
+	t.Helper()
+	m.startFetchCmd(paper.ID)
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: paper})
+	if m.paper == nil || m.paper.ID != paper.ID {
+		t.Fatalf("expected %s loaded, got %+v", paper.ID, m.paper)
+	}
+}
+
+func transcriptContains(m *model, text string) bool {
+	for _, entry := range m.transcriptEntries {
+		if strings.Contains(entry.Content, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLoadingSecondPaperOpensTabWithIndependentTranscript(t *testing.T) {
+	m := newTestModel(t)
+	loadTestPaper(t, m, &arxiv.Paper{ID: "2101.00001", Title: "First Paper"})
+	m.appendTranscript("note", "first paper remark")
+	m.qaHistory = append(m.qaHistory, qaExchange{Question: "first question?", Answer: "first answer"})
+
+	loadTestPaper(t, m, &arxiv.Paper{ID: "2101.00002", Title: "Second Paper"})
+	if len(m.sessions) != 2 || m.activeSession != 1 {
+		t.Fatalf("expected the second paper in a new tab, got %d tabs (active %d)", len(m.sessions), m.activeSession)
+	}
+	if transcriptContains(m, "first paper remark") || len(m.qaHistory) != 0 {
+		t.Fatal("expected the new tab to start with an empty transcript")
+	}
+	m.appendTranscript("note", "second paper remark")
+	if !strings.Contains(m.footerTickerView(), "Tab 2/2") {
+		t.Fatalf("expected the footer to show the active tab, got %q", m.footerTickerView())
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if m.paper.ID != "2101.00001" || m.activeSession != 0 {
+		t.Fatalf("expected Tab to switch to the first paper, got %s", m.paper.ID)
+	}
+	if !transcriptContains(m, "first paper remark") || transcriptContains(m, "second paper remark") {
+		t.Fatal("expected the first tab's transcript restored")
+	}
+	if len(m.qaHistory) != 1 || m.qaHistory[0].Answer != "first answer" {
+		t.Fatalf("expected the first tab's questions restored, got %+v", m.qaHistory)
+	}
+	if !strings.Contains(m.heroView(), "Tab 1/2") {
+		t.Fatal("expected the hero to show the active tab")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if m.paper.ID != "2101.00002" || !transcriptContains(m, "second paper remark") || transcriptContains(m, "first paper remark") {
+		t.Fatal("expected Shift+Tab to return to the second tab's transcript")
+	}
+}
+
+func TestSwitchingAwayInterruptsPendingQuestion(t *testing.T) {
+	m := newTestModel(t)
+	loadTestPaper(t, m, &arxiv.Paper{ID: "2101.00001", Title: "First Paper"})
+	loadTestPaper(t, m, &arxiv.Paper{ID: "2101.00002", Title: "Second Paper"})
+	m.qaHistory = []qaExchange{{Question: "still thinking?", Pending: true}}
+	m.questionLoading = true
+
+	m.switchSession(1)
+	m.switchSession(1)
+	if m.questionLoading || m.qaHistory[0].Pending || m.qaHistory[0].Error != interruptedByTabSwitch {
+		t.Fatalf("expected the pending question marked interrupted, got %+v", m.qaHistory[0])
+	}
+}
+
+func TestReloadingOpenPaperSwitchesToItsTab(t *testing.T) {
+	m := newTestModel(t)
+	loadTestPaper(t, m, &arxiv.Paper{ID: "2101.00001", Title: "First Paper"})
+	loadTestPaper(t, m, &arxiv.Paper{ID: "2101.00002", Title: "Second Paper"})
+	loadTestPaper(t, m, &arxiv.Paper{ID: "2101.00001", Title: "First Paper"})
+
+	if len(m.sessions) != 2 || m.activeSession != 0 {
+		t.Fatalf("expected the existing tab reused, got %d tabs (active %d)", len(m.sessions), m.activeSession)
+	}
+	if !strings.Contains(m.infoMessage, "already open in Tab 1/2") {
+		t.Fatalf("unexpected info message %q", m.infoMessage)
+	}
+}
+
+func TestSwitchingWithOneTabExplains(t *testing.T) {
+	m := newTestModel(t)
+	loadTestPaper(t, m, &arxiv.Paper{ID: "2101.00001", Title: "Only Paper"})
+
+	m.switchSession(1)
+	if m.paper.ID != "2101.00001" || !strings.Contains(m.infoMessage, "Only one tab") {
+		t.Fatalf("expected to stay on the only tab, got %q", m.infoMessage)
+	}
+}
@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/version"
+)
+
+// slashCommand is a composer-driven action triggered by typing "/name [args]".
+type slashCommand struct {
+	Name        string
+	Description string
+	Run         func(m *model, args string) tea.Cmd
+}
+
+var slashCommands = map[string]slashCommand{}
+
+func registerSlashCommand(cmd slashCommand) {
+	slashCommands[cmd.Name] = cmd
+}
+
+func init() {
+	registerSlashCommand(slashCommand{
+		Name:        "help",
+		Description: "list composer commands",
+		Run:         (*model).slashHelpCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "brief",
+		Description: "generate (or regenerate) the three-pass reading brief, or one section (/brief technical)",
+		Run:         (*model).actionBriefCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "more",
+		Description: "regenerate one brief section with more detail (/more technical)",
+		Run:         (*model).actionExpandBriefSectionCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "raw",
+		Description: "toggle raw markdown for the conversation (also Ctrl+R)",
+		Run: func(m *model, args string) tea.Cmd {
+			m.toggleRawMarkdown()
+			return nil
+		},
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "export",
+		Description: "write the reading brief to a Markdown file",
+		Run: func(m *model, args string) tea.Cmd {
+			return m.actionExportBriefCmd()
+		},
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "export-transcript",
+		Description: "write the whole conversation, with speakers and times, to a Markdown file",
+		Run:         (*model).actionExportTranscriptCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "copy-brief",
+		Description: "copy the reading brief to the clipboard as Markdown",
+		Run:         (*model).actionCopyBriefCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "guide",
+		Description: "rebuild the reading guide, optionally for a new focus (/guide cs.LG)",
+		Run:         (*model).actionRebuildGuideCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "gaps",
+		Description: "ask what the paper covers that your saved notes miss",
+		Run: func(m *model, args string) tea.Cmd {
+			return m.actionNoteGapsCmd()
+		},
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "context",
+		Description: "preview the paper text sent for a brief section (/context technical)",
+		Run:         (*model).actionContextPreviewCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "copy-paper",
+		Description: "copy the cleaned, deduplicated paper text to the clipboard for other tools",
+		Run:         (*model).actionCopyPaperContextCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "preview",
+		Description: "open a paper's title, authors, and abstract without downloading its PDF (/preview 2101.00001)",
+		Run:         (*model).actionPreviewCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "full",
+		Description: "load the full text of a previewed paper and build its reading brief",
+		Run: func(m *model, args string) tea.Cmd {
+			return m.actionLoadFullTextCmd()
+		},
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "open-pdf",
+		Description: "open the paper's PDF in the system viewer, downloading it first if needed",
+		Run: func(m *model, args string) tea.Cmd {
+			return m.actionOpenPDFCmd()
+		},
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "jump",
+		Description: "jump to a section by name; partial names work (/jump tech, /jump dd)",
+		Run:         (*model).actionJumpSectionCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "references",
+		Description: "list the paper's numbered bibliography in the transcript",
+		Run:         (*model).actionShowReferencesCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "search",
+		Description: "search every saved note by title and body (/search attention)",
+		Run:         (*model).actionSearchNotesCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "tag",
+		Description: "list saved notes tagged #tag (/tag todo); alone, list the tags in use",
+		Run:         (*model).actionTagCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "edit-note",
+		Description: "pick a saved note for this paper and edit it in the composer",
+		Run:         (*model).actionEditNoteCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "delete-note",
+		Description: "pick a saved note for this paper and delete it after a y/n confirmation",
+		Run:         (*model).actionDeleteNoteCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "errors",
+		Description: "list the errors posted to this conversation with their times",
+		Run:         (*model).actionShowErrorsCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "diag",
+		Description: "show cache and knowledge base paths, LLM, and version",
+		Run:         (*model).slashDiagnosticsCmd,
+	})
+	registerSlashCommand(slashCommand{
+		Name:        "scratch",
+		Description: "edit the free-form scratchpad for this paper",
+		Run: func(m *model, args string) tea.Cmd {
+			return m.actionEditScratchpadCmd()
+		},
+	})
+}
+
+// parseSlashCommand splits "/name args" into its parts. The name ends at the first space or line
+// break and may only hold letters, digits, and dashes, so prose that happens to start with a slash
+// (such as "/usr/lib is missing") is not misread as a command.
+func parseSlashCommand(value string) (string, string, bool) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "/") || strings.HasPrefix(value, "//") {
+		return "", "", false
+	}
+	rest := strings.TrimPrefix(value, "/")
+	name, args := rest, ""
+	if end := strings.IndexFunc(rest, unicode.IsSpace); end >= 0 {
+		name, args = rest[:end], strings.TrimSpace(rest[end:])
+	}
+	name = strings.ToLower(name)
+	if !slashCommandNamePattern.MatchString(name) {
+		return "", "", false
+	}
+	return name, args, true
+}
+
+var slashCommandNamePattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// runSlashCommand runs a registered command and clears the composer. An unknown name leaves the
+// composer untouched so the text can be fixed rather than retyped.
+func (m *model) runSlashCommand(value string) (tea.Cmd, bool) {
+	name, args, ok := parseSlashCommand(value)
+	if !ok {
+		return nil, false
+	}
+	command, found := slashCommands[name]
+	if !found {
+		m.infoMessage = fmt.Sprintf("Unknown command /%s. Type /help for the list.", name)
+		return nil, true
+	}
+	m.composer.SetValue("")
+	m.updateComposerHeight()
+	m.markViewportDirty()
+	return command.Run(m, args), true
+}
+
+func (m *model) slashHelpCmd(string) tea.Cmd {
+	names := make([]string, 0, len(slashCommands))
+	for name := range slashCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("- `/%s` – %s", name, slashCommands[name].Description))
+	}
+	lines = append(lines, "", fmt.Sprintf("PaperScout %s", version.String()))
+	m.appendTranscript("help", strings.Join(lines, "\n"))
+	m.infoMessage = "Composer commands listed in the transcript."
+	return nil
+}
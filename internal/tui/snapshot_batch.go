@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/notes"
+)
+
+// snapshotFlushThreshold flushes a batch early once this many messages and notes are waiting, so a
+// long Q&A burst never holds more than a handful of entries in memory.
+const snapshotFlushThreshold = 20
+
+// snapshotFlushMsg fires Config.SnapshotFlushInterval after the first update of a batch.
+type snapshotFlushMsg struct{}
+
+// pendingSnapshot is a batch of conversation snapshot updates for one paper.
+type pendingSnapshot struct {
+	kb     notes.Store
+	paper  *arxiv.Paper
+	update notes.SnapshotUpdate
+}
+
+func (p *pendingSnapshot) size() int {
+	return len(p.update.Messages) + len(p.update.Notes)
+}
+
+// queueConversationSnapshot adds update to the current batch. The first update of a batch schedules
+// the periodic flush; reaching snapshotFlushThreshold, or an update for a different paper, writes
+// the batch immediately.
+func (m *model) queueConversationSnapshot(update notes.SnapshotUpdate) tea.Cmd {
+	var cmds []tea.Cmd
+	if m.pendingSnapshot != nil && m.pendingSnapshot.paper.ID != m.paper.ID {
+		cmds = append(cmds, m.flushConversationSnapshotCmd())
+	}
+	if m.pendingSnapshot == nil {
+		m.pendingSnapshot = &pendingSnapshot{kb: m.knowledgeBase(), paper: m.paper}
+	}
+	m.pendingSnapshot.update.Merge(update)
+	if m.pendingSnapshot.size() >= snapshotFlushThreshold {
+		cmds = append(cmds, m.flushConversationSnapshotCmd())
+	} else if !m.snapshotFlushScheduled {
+		m.snapshotFlushScheduled = true
+		cmds = append(cmds, tea.Tick(m.config.SnapshotFlushInterval, func(time.Time) tea.Msg {
+			return snapshotFlushMsg{}
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
+// takePendingSnapshot clears the pending batch and returns the job that writes it, or nil when
+// nothing is waiting.
+func (m *model) takePendingSnapshot() jobRunner {
+	m.snapshotFlushScheduled = false
+	pending := m.pendingSnapshot
+	if pending == nil {
+		return nil
+	}
+	m.pendingSnapshot = nil
+	return appendConversationSnapshotJob(pending.kb, pending.paper, pending.update)
+}
+
+// flushConversationSnapshotCmd writes the pending batch as one snapshot job.
+func (m *model) flushConversationSnapshotCmd() tea.Cmd {
+	job := m.takePendingSnapshot()
+	if job == nil {
+		return nil
+	}
+	return m.jobBus.Start(jobKindZettel, job)
+}
+
+// quitCmd writes any pending batch before quitting. The write runs inside the returned command
+// rather than as a job so the program cannot exit before it finishes.
+func (m *model) quitCmd() tea.Cmd {
+	job := m.takePendingSnapshot()
+	if job == nil {
+		return tea.Quit
+	}
+	return func() tea.Msg {
+		_, _ = job(context.Background())
+		return tea.Quit()
+	}
+}
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/notes"
+)
+
+func TestSnapshotBatchingWritesRapidMessagesOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zettel.json")
+	m := newTestModel(t)
+	m.config.KnowledgeBasePath = path
+	m.config.SnapshotFlushInterval = time.Hour
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Batch Paper"}
+
+	for idx, content := range []string{"What is new?", "It batches writes.", "Thanks."} {
+		cmd := m.appendConversationSnapshotCmd(notes.SnapshotUpdate{
+			Messages: []notes.ConversationMessage{{Kind: "question", Content: content, Timestamp: time.Now()}},
+		})
+		if idx == 0 && cmd == nil {
+			t.Fatal("expected the first message to schedule a flush")
+		}
+		if idx > 0 && cmd != nil {
+			t.Fatalf("expected message %d to join the pending batch, got a command", idx)
+		}
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected no write before the flush, stat err = %v", err)
+	}
+
+	msg := m.quitCmd()()
+	if _, ok := msg.(tea.QuitMsg); !ok {
+		t.Fatalf("expected quit after the final flush, got %T", msg)
+	}
+	snapshots, err := notes.LoadConversationSnapshots(path)
+	if err != nil {
+		t.Fatalf("LoadConversationSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 1 || len(snapshots[0].Messages) != 3 {
+		t.Fatalf("expected one snapshot holding 3 messages, got %#v", snapshots)
+	}
+	if m.pendingSnapshot != nil {
+		t.Fatal("expected the batch to be cleared after flushing")
+	}
+}
+
+func TestSnapshotBatchingFlushesAtThreshold(t *testing.T) {
+	m := newTestModel(t)
+	m.config.KnowledgeBasePath = filepath.Join(t.TempDir(), "zettel.json")
+	m.config.SnapshotFlushInterval = time.Hour
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Batch Paper"}
+
+	for i := 0; i < snapshotFlushThreshold; i++ {
+		m.appendConversationSnapshotCmd(notes.SnapshotUpdate{
+			Messages: []notes.ConversationMessage{{Kind: "answer", Content: "ok", Timestamp: time.Now()}},
+		})
+	}
+	if m.pendingSnapshot != nil {
+		t.Fatalf("expected the batch to flush at %d messages", snapshotFlushThreshold)
+	}
+}
+
+func TestPaperLoadShowsPendingBatchWithoutWriting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zettel.json")
+	m := newTestModel(t)
+	m.config.KnowledgeBasePath = path
+	m.config.SnapshotFlushInterval = time.Hour
+	paper := &arxiv.Paper{ID: "2101.00001", Title: "Batch Paper", FullText: "Body."}
+	m.paper = paper
+	m.appendConversationSnapshotCmd(notes.SnapshotUpdate{
+		Messages: []notes.ConversationMessage{{Kind: "question", Content: "Still batched?", Timestamp: time.Now()}},
+	})
+
+	m.handlePaperResult(paperResultMsg{seq: m.fetchSeq, paper: paper})
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected loading the paper not to write the knowledge base, stat err = %v", err)
+	}
+	found := false
+	for _, entry := range m.transcriptEntries {
+		found = found || entry.Content == "Still batched?"
+	}
+	if !found {
+		t.Fatalf("expected the pending message in the hydrated history, got %+v", m.transcriptEntries)
+	}
+	if m.pendingSnapshot == nil {
+		t.Fatal("expected the batch kept for its scheduled flush")
+	}
+}
@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/notes"
+)
+
+// suggestNotesCmd asks the LLM for note suggestions once the brief has finished, so the request
+// does not compete with the brief sections for the model. It runs at most once per paper unless
+// the previous attempt came back empty.
+func (m *model) suggestNotesCmd() tea.Cmd {
+	if m.paper == nil || m.config.LLM == nil || m.briefLoading || m.suggestionLoading || len(m.suggestions) > 0 {
+		return nil
+	}
+	if m.paper.Notice() != "" || strings.TrimSpace(m.paper.FullText) == "" {
+		return nil
+	}
+	m.suggestionLoading = true
+	m.suggestionCursor = 0
+	m.selected = map[int]bool{}
+	m.persisted = map[int]bool{}
+	runner, updates := suggestNotesJob(m.config.LLM, m.paper)
+	return tea.Batch(m.jobBus.Start(jobKindSuggest, runner), waitSuggestionStream(m.paper.ID, updates))
+}
+
+func waitSuggestionStream(paperID string, updates <-chan notes.Candidate) tea.Cmd {
+	return func() tea.Msg {
+		candidate, ok := <-updates
+		if !ok {
+			return nil
+		}
+		return suggestionStreamMsg{paperID: paperID, candidate: candidate, updates: updates}
+	}
+}
+
+// handleSuggestionStream appends a suggestion as it arrives. Once the result has landed the list
+// is final, so late stream messages are only drained.
+func (m *model) handleSuggestionStream(msg suggestionStreamMsg) tea.Cmd {
+	next := waitSuggestionStream(msg.paperID, msg.updates)
+	if m.paper == nil || m.paper.ID != msg.paperID || !m.suggestionLoading {
+		return next
+	}
+	index := len(m.suggestions)
+	m.suggestions = append(m.suggestions, msg.candidate)
+	if candidateMatchesNotes(msg.candidate, m.persistedNotes) {
+		m.persisted[index] = true
+		m.selected[index] = true
+	}
+	m.markViewportDirty()
+	return next
+}
+
+func (m *model) handleSuggestionResult(msg suggestionResultMsg) tea.Cmd {
+	if m.paper == nil || m.paper.ID != msg.paperID {
+		return nil
+	}
+	m.suggestionLoading = false
+	// The streamed suggestions are a prefix of the result, so selections made while they arrived
+	// still point at the same notes.
+	m.suggestions = msg.suggestions
+	if msg.err != nil {
+		m.refreshPersistedState()
+		m.errorMessage = fmt.Sprintf("suggestion error: %v", msg.err)
+		m.appendTranscript("error", fmt.Sprintf("Suggestion failed: %v", msg.err))
+		return nil
+	}
+	m.errorMessage = ""
+	m.suggestionCursor = clampIndex(m.suggestionCursor, len(m.suggestions))
+	m.refreshPersistedState()
+	if len(m.suggestions) > 0 {
+		m.infoMessage = fmt.Sprintf("%d suggested notes. n/p move, x selects, s saves.", len(m.suggestions))
+	}
+	m.markViewportDirty()
+	return nil
+}
+
+// moveSuggestionCursor highlights the next or previous suggested note.
+func (m *model) moveSuggestionCursor(delta int) {
+	if len(m.suggestions) == 0 {
+		m.infoMessage = "No suggested notes yet."
+		return
+	}
+	m.suggestionCursor = clampIndex(m.suggestionCursor+delta, len(m.suggestions))
+	m.infoMessage = fmt.Sprintf("Suggested note %d of %d.", m.suggestionCursor+1, len(m.suggestions))
+	m.markViewportDirty()
+}
+
+// toggleSuggestion selects or deselects the highlighted suggestion for the next save. Suggestions
+// already in the knowledge base stay selected.
+func (m *model) toggleSuggestion() {
+	if len(m.suggestions) == 0 {
+		m.infoMessage = "No suggested notes yet."
+		return
+	}
+	index := clampIndex(m.suggestionCursor, len(m.suggestions))
+	if m.persisted[index] {
+		m.infoMessage = "That suggestion is already saved."
+		return
+	}
+	m.selected[index] = !m.selected[index]
+	if m.selected[index] {
+		m.infoMessage = fmt.Sprintf("Selected %q. Press s to save %d note(s).", m.suggestions[index].Title, m.selectedCount())
+	} else {
+		m.infoMessage = fmt.Sprintf("Deselected %q.", m.suggestions[index].Title)
+	}
+	m.markViewportDirty()
+}
+
+// writeSuggestedNotes lists the suggestions as checkboxes and records which viewport line shows
+// which suggestion, so clicking a line highlights it.
+func (m *model) writeSuggestedNotes(cb *contentBuilder) {
+	if len(m.suggestions) == 0 {
+		return
+	}
+	cursor := clampIndex(m.suggestionCursor, len(m.suggestions))
+	cb.WriteRune('\n')
+	cb.WriteString(helperStyle.Render("Suggested notes · n/p move · x select · s save"))
+	cb.WriteRune('\n')
+	for idx, candidate := range m.suggestions {
+		box := "[ ]"
+		switch {
+		case m.persisted[idx]:
+			box = "[saved]"
+		case m.selected[idx]:
+			box = "[x]"
+		}
+		line := fmt.Sprintf("%s %s (%s)", box, previewText(candidate.Title, m.wrapWidth(16)), candidate.Kind)
+		if cb.suggestionLines != nil {
+			cb.suggestionLines[cb.Line()] = idx
+		}
+		switch {
+		case idx == cursor:
+			cb.WriteString("  " + currentLineStyle.Render("› "+line))
+		case m.persisted[idx]:
+			cb.WriteString("    " + persistedSuggestionStyle.Render(line))
+		default:
+			cb.WriteString("    " + line)
+		}
+		cb.WriteRune('\n')
+	}
+}
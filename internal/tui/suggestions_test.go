@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/llm"
+	"github.com/csheth/browse/internal/notes"
+)
+
+func TestSuggestionResultIsSelectableAndSaveable(t *testing.T) {
+	m := newTestModel(t)
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Sparse Routing", FullText: "Body."}
+	m.stage = stageDisplay
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m.handleSuggestionResult(suggestionResultMsg{paperID: m.paper.ID, suggestions: []notes.Candidate{
+		{Title: "Sparse routing halves compute", Body: "Two experts per token.", Kind: "method"},
+		{Title: "Load balancing loss", Body: "Keeps experts busy.", Kind: "concept"},
+	}})
+	m.refreshViewportIfDirty()
+	if view := stripANSI(m.viewportContent); !strings.Contains(view, "[ ] Sparse routing halves compute (method)") || !strings.Contains(view, "[ ] Load balancing loss (concept)") {
+		t.Fatalf("expected both suggestions as checkboxes, got %q", view)
+	}
+	if len(m.suggestionLines) != 2 {
+		t.Fatalf("expected both suggestions mapped to viewport lines, got %v", m.suggestionLines)
+	}
+
+	m.composer.Blur()
+	for _, key := range []string{"n", "x"} {
+		m.handleDisplayKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+	if !m.selected[1] || m.selected[0] {
+		t.Fatalf("expected only the second suggestion selected, got %v", m.selected)
+	}
+
+	_, cmd := m.handleDisplayKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if cmd == nil || m.stage != stageSaving {
+		t.Fatalf("expected s to start saving, stage %v", m.stage)
+	}
+	msg, err := saveNotesJob(m.knowledgeBase(), m.collectSelectedNotes())(context.Background())
+	if err != nil {
+		t.Fatalf("saveNotesJob() error = %v", err)
+	}
+	m.handleSaveResult(msg.(saveResultMsg))
+	saved, err := notes.Load(m.config.KnowledgeBasePath)
+	if err != nil || len(saved) != 1 || saved[0].Title != "Load balancing loss" {
+		t.Fatalf("expected the selected suggestion saved, got %+v, %v", saved, err)
+	}
+	if !m.persisted[1] || m.persisted[0] {
+		t.Fatalf("expected the saved suggestion marked persisted, got %v", m.persisted)
+	}
+}
+
+func TestSuggestionsStartOnceTheBriefFinishes(t *testing.T) {
+	m := newTestModel(t)
+	m.config.LLM = fakeLLM{}
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Sparse Routing", FullText: "Body."}
+	m.briefLoading = true
+	if cmd := m.suggestNotesCmd(); cmd != nil || m.suggestionLoading {
+		t.Fatal("expected no suggestions while the brief is still loading")
+	}
+	m.briefLoading = false
+	if cmd := m.suggestNotesCmd(); cmd == nil || !m.suggestionLoading {
+		t.Fatal("expected suggestions to start after the brief")
+	}
+	if cmd := m.suggestNotesCmd(); cmd != nil {
+		t.Fatal("expected a running suggestion job not to start another")
+	}
+}
+
+// gatedSuggestLLM streams its first note, then waits for release before the second.
+type gatedSuggestLLM struct {
+	fakeLLM
+	release chan struct{}
+}
+
+func (c gatedSuggestLLM) StreamSuggestNotes(ctx context.Context, title, abstract string, contributions []string, content string, handler llm.SuggestedNoteHandler) ([]llm.SuggestedNote, error) {
+	suggested := []llm.SuggestedNote{
+		{Title: "Sparse routing halves compute", Body: "Two experts per token.", Kind: "method"},
+		{Title: "Load balancing loss", Body: "Keeps experts busy."},
+	}
+	if err := handler(suggested[0]); err != nil {
+		return nil, err
+	}
+	<-c.release
+	if err := handler(suggested[1]); err != nil {
+		return suggested[:1], err
+	}
+	return suggested, nil
+}
+
+func TestSuggestionsAppearAsTheyStream(t *testing.T) {
+	client := gatedSuggestLLM{release: make(chan struct{})}
+	m := newTestModel(t)
+	m.config.LLM = client
+	m.paper = &arxiv.Paper{ID: "2101.00001", Title: "Sparse Routing", FullText: "Body."}
+	m.stage = stageDisplay
+	m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m.suggestionLoading = true
+
+	runner, updates := suggestNotesJob(client, m.paper)
+	results := make(chan tea.Msg, 1)
+	go func() {
+		msg, _ := runner(context.Background())
+		results <- msg
+	}()
+
+	m.Update(waitSuggestionStream(m.paper.ID, updates)())
+	m.refreshViewportIfDirty()
+	if len(m.suggestions) != 1 || !strings.Contains(stripANSI(m.viewportContent), "[ ] Sparse routing halves compute (method)") {
+		t.Fatalf("expected the first suggestion shown before the second streamed, got %+v", m.suggestions)
+	}
+	m.composer.Blur()
+	m.handleDisplayKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+
+	close(client.release)
+	m.Update(waitSuggestionStream(m.paper.ID, updates)())
+	if len(m.suggestions) != 2 || m.suggestions[1].Kind != "llm" {
+		t.Fatalf("expected the second suggestion appended with the default kind, got %+v", m.suggestions)
+	}
+	m.Update(<-results)
+	if m.suggestionLoading || len(m.suggestions) != 2 {
+		t.Fatalf("expected the result to settle on both suggestions, got %+v", m.suggestions)
+	}
+	if !m.selected[0] || m.selected[1] {
+		t.Fatalf("expected the selection made while streaming to survive the result, got %v", m.selected)
+	}
+	if msg := waitSuggestionStream(m.paper.ID, updates)(); msg != nil {
+		t.Fatalf("expected the stream closed after the result, got %#v", msg)
+	}
+}
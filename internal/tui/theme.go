@@ -0,0 +1,309 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is the palette behind every style the TUI draws with. Each field names a role rather than
+// a widget, so one color can serve the hero, the composer, and the rendered Markdown alike.
+type Theme struct {
+	// Name is what -theme selects; a zero Theme (empty Name) means ThemeDark.
+	Name string
+
+	Title         lipgloss.TerminalColor
+	Subtitle      lipgloss.TerminalColor
+	SectionHeader lipgloss.TerminalColor
+	Subject       lipgloss.TerminalColor
+	Error         lipgloss.TerminalColor
+	Helper        lipgloss.TerminalColor
+
+	// Accent outlines the hero and colors prompts and headings; Text and SecondaryText sit on
+	// HeroBackground.
+	Accent         lipgloss.TerminalColor
+	HeroBackground lipgloss.TerminalColor
+	Text           lipgloss.TerminalColor
+	SecondaryText  lipgloss.TerminalColor
+	LogoShadow     lipgloss.TerminalColor
+
+	ComposerFocusedBackground lipgloss.TerminalColor
+	ComposerBlurredBackground lipgloss.TerminalColor
+	ComposerCursorLineFocused lipgloss.TerminalColor
+	ComposerCursorLineBlurred lipgloss.TerminalColor
+	// DimText is blurred composer text and code blocks; SoftText is placeholders and italics.
+	DimText  lipgloss.TerminalColor
+	SoftText lipgloss.TerminalColor
+
+	Status        lipgloss.TerminalColor
+	Highlight     lipgloss.TerminalColor
+	HighlightText lipgloss.TerminalColor
+	Saved         lipgloss.TerminalColor
+
+	Table                lipgloss.TerminalColor
+	TableHeader          lipgloss.TerminalColor
+	Muted                lipgloss.TerminalColor
+	Strong               lipgloss.TerminalColor
+	InlineCodeBackground lipgloss.TerminalColor
+	Latex                lipgloss.TerminalColor
+	Link                 lipgloss.TerminalColor
+}
+
+var (
+	// ThemeDark is the original ember palette, meant for dark terminals.
+	ThemeDark = Theme{
+		Name:                      "dark",
+		Title:                     lipgloss.Color("205"),
+		Subtitle:                  lipgloss.Color("147"),
+		SectionHeader:             lipgloss.Color("81"),
+		Subject:                   lipgloss.Color("110"),
+		Error:                     lipgloss.Color("9"),
+		Helper:                    lipgloss.Color("244"),
+		Accent:                    lipgloss.Color("#ff8c00"),
+		HeroBackground:            lipgloss.Color("#2b1400"),
+		Text:                      lipgloss.Color("#fff4d0"),
+		SecondaryText:             lipgloss.Color("#ffb347"),
+		LogoShadow:                lipgloss.Color("#110600"),
+		ComposerFocusedBackground: lipgloss.Color("#231507"),
+		ComposerBlurredBackground: lipgloss.Color("#170c04"),
+		ComposerCursorLineFocused: lipgloss.Color("#3b200b"),
+		ComposerCursorLineBlurred: lipgloss.Color("#251307"),
+		DimText:                   lipgloss.Color("#d3b38a"),
+		SoftText:                  lipgloss.Color("#f1c27a"),
+		Status:                    lipgloss.Color("#dcdcdc"),
+		Highlight:                 lipgloss.Color("#8ecae6"),
+		HighlightText:             lipgloss.Color("#0f0f0f"),
+		Saved:                     lipgloss.Color("#a3be8c"),
+		Table:                     lipgloss.Color("#d9b56c"),
+		TableHeader:               lipgloss.Color("#f2c97d"),
+		Muted:                     lipgloss.Color("#b0a08a"),
+		Strong:                    lipgloss.Color("#ffd166"),
+		InlineCodeBackground:      lipgloss.Color("#1c1c1c"),
+		Latex:                     lipgloss.Color("#8ecae6"),
+		Link:                      lipgloss.Color("#93d7ff"),
+	}
+
+	// ThemeLight keeps the ember accents but uses dark text on pale backgrounds for light terminals.
+	ThemeLight = Theme{
+		Name:                      "light",
+		Title:                     lipgloss.Color("#af005f"),
+		Subtitle:                  lipgloss.Color("#5f00af"),
+		SectionHeader:             lipgloss.Color("#005f87"),
+		Subject:                   lipgloss.Color("#005f5f"),
+		Error:                     lipgloss.Color("#c00000"),
+		Helper:                    lipgloss.Color("#6c6c6c"),
+		Accent:                    lipgloss.Color("#c05600"),
+		HeroBackground:            lipgloss.Color("#fff1dc"),
+		Text:                      lipgloss.Color("#2b1400"),
+		SecondaryText:             lipgloss.Color("#9a4a00"),
+		LogoShadow:                lipgloss.Color("#e0c8a8"),
+		ComposerFocusedBackground: lipgloss.Color("#fbe9d0"),
+		ComposerBlurredBackground: lipgloss.Color("#f6f0e6"),
+		ComposerCursorLineFocused: lipgloss.Color("#f5d9b0"),
+		ComposerCursorLineBlurred: lipgloss.Color("#efe4d3"),
+		DimText:                   lipgloss.Color("#6b5436"),
+		SoftText:                  lipgloss.Color("#8a5a1c"),
+		Status:                    lipgloss.Color("#3a3a3a"),
+		Highlight:                 lipgloss.Color("#bde0f0"),
+		HighlightText:             lipgloss.Color("#0f0f0f"),
+		Saved:                     lipgloss.Color("#3f6e2a"),
+		Table:                     lipgloss.Color("#7a5a14"),
+		TableHeader:               lipgloss.Color("#5c3d00"),
+		Muted:                     lipgloss.Color("#7d6f5c"),
+		Strong:                    lipgloss.Color("#8a3b00"),
+		InlineCodeBackground:      lipgloss.Color("#ece6dc"),
+		Latex:                     lipgloss.Color("#00708f"),
+		Link:                      lipgloss.Color("#005fa3"),
+	}
+
+	// ThemeHighContrast uses white and bright primaries on black for low-vision readers.
+	ThemeHighContrast = Theme{
+		Name:                      "high-contrast",
+		Title:                     lipgloss.Color("#ffff00"),
+		Subtitle:                  lipgloss.Color("#ffffff"),
+		SectionHeader:             lipgloss.Color("#00ffff"),
+		Subject:                   lipgloss.Color("#ffffff"),
+		Error:                     lipgloss.Color("#ff5555"),
+		Helper:                    lipgloss.Color("#d0d0d0"),
+		Accent:                    lipgloss.Color("#ffff00"),
+		HeroBackground:            lipgloss.Color("#000000"),
+		Text:                      lipgloss.Color("#ffffff"),
+		SecondaryText:             lipgloss.Color("#ffff00"),
+		LogoShadow:                lipgloss.Color("#5f5f5f"),
+		ComposerFocusedBackground: lipgloss.Color("#000000"),
+		ComposerBlurredBackground: lipgloss.Color("#000000"),
+		ComposerCursorLineFocused: lipgloss.Color("#303030"),
+		ComposerCursorLineBlurred: lipgloss.Color("#1a1a1a"),
+		DimText:                   lipgloss.Color("#e0e0e0"),
+		SoftText:                  lipgloss.Color("#ffffaf"),
+		Status:                    lipgloss.Color("#ffffff"),
+		Highlight:                 lipgloss.Color("#ffff00"),
+		HighlightText:             lipgloss.Color("#000000"),
+		Saved:                     lipgloss.Color("#00ff00"),
+		Table:                     lipgloss.Color("#ffffff"),
+		TableHeader:               lipgloss.Color("#ffff00"),
+		Muted:                     lipgloss.Color("#d0d0d0"),
+		Strong:                    lipgloss.Color("#ffffff"),
+		InlineCodeBackground:      lipgloss.Color("#303030"),
+		Latex:                     lipgloss.Color("#00ffff"),
+		Link:                      lipgloss.Color("#00ffff"),
+	}
+)
+
+// plainTheme leaves every color to the terminal; New uses it when NO_COLOR is set. Bold, italics,
+// borders, and padding still apply, so the layout is unchanged.
+var plainTheme = Theme{
+	Name:                      "none",
+	Title:                     lipgloss.NoColor{},
+	Subtitle:                  lipgloss.NoColor{},
+	SectionHeader:             lipgloss.NoColor{},
+	Subject:                   lipgloss.NoColor{},
+	Error:                     lipgloss.NoColor{},
+	Helper:                    lipgloss.NoColor{},
+	Accent:                    lipgloss.NoColor{},
+	HeroBackground:            lipgloss.NoColor{},
+	Text:                      lipgloss.NoColor{},
+	SecondaryText:             lipgloss.NoColor{},
+	LogoShadow:                lipgloss.NoColor{},
+	ComposerFocusedBackground: lipgloss.NoColor{},
+	ComposerBlurredBackground: lipgloss.NoColor{},
+	ComposerCursorLineFocused: lipgloss.NoColor{},
+	ComposerCursorLineBlurred: lipgloss.NoColor{},
+	DimText:                   lipgloss.NoColor{},
+	SoftText:                  lipgloss.NoColor{},
+	Status:                    lipgloss.NoColor{},
+	Highlight:                 lipgloss.NoColor{},
+	HighlightText:             lipgloss.NoColor{},
+	Saved:                     lipgloss.NoColor{},
+	Table:                     lipgloss.NoColor{},
+	TableHeader:               lipgloss.NoColor{},
+	Muted:                     lipgloss.NoColor{},
+	Strong:                    lipgloss.NoColor{},
+	InlineCodeBackground:      lipgloss.NoColor{},
+	Latex:                     lipgloss.NoColor{},
+	Link:                      lipgloss.NoColor{},
+}
+
+// ParseTheme validates a -theme value; empty means ThemeDark.
+func ParseTheme(value string) (Theme, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", ThemeDark.Name:
+		return ThemeDark, nil
+	case ThemeLight.Name:
+		return ThemeLight, nil
+	case ThemeHighContrast.Name:
+		return ThemeHighContrast, nil
+	default:
+		return Theme{}, fmt.Errorf("unknown theme %q (want dark, light, or high-contrast)", value)
+	}
+}
+
+// resolveTheme picks the theme New applies: NO_COLOR wins over any configured theme.
+func resolveTheme(theme Theme, noColor bool) Theme {
+	if noColor {
+		return plainTheme
+	}
+	if theme.Name == "" {
+		return ThemeDark
+	}
+	return theme
+}
+
+var (
+	titleStyle         lipgloss.Style
+	subtitleStyle      lipgloss.Style
+	sectionHeaderStyle lipgloss.Style
+	subjectStyle       lipgloss.Style
+	errorStyle         lipgloss.Style
+	helperStyle        lipgloss.Style
+
+	heroTitleStyle                 lipgloss.Style
+	heroBoxStyle                   lipgloss.Style
+	heroSummaryStyle               lipgloss.Style
+	taglineStyle                   lipgloss.Style
+	statusBarStyle                 lipgloss.Style
+	currentLineStyle               lipgloss.Style
+	persistedSuggestionStyle       lipgloss.Style
+	findMatchStyle                 lipgloss.Style
+	logoFaceStyle                  lipgloss.Style
+	logoShadowStyle                lipgloss.Style
+	logoContainerStyle             lipgloss.Style
+	composerFocusedBaseStyle       lipgloss.Style
+	composerBlurredBaseStyle       lipgloss.Style
+	composerCursorLineFocusedStyle lipgloss.Style
+	composerCursorLineBlurredStyle lipgloss.Style
+	composerFocusedTextStyle       lipgloss.Style
+	composerBlurredTextStyle       lipgloss.Style
+	composerPlaceholderStyle       lipgloss.Style
+	composerPromptStyle            lipgloss.Style
+	markdownHeadingStyle           lipgloss.Style
+	markdownBulletStyle            lipgloss.Style
+	markdownTableStyle             lipgloss.Style
+	markdownTableHeaderStyle       lipgloss.Style
+	markdownQuoteStyle             lipgloss.Style
+	markdownCodeStyle              lipgloss.Style
+	markdownBoldStyle              lipgloss.Style
+	markdownItalicStyle            lipgloss.Style
+	markdownInlineCodeStyle        lipgloss.Style
+	latexStyle                     lipgloss.Style
+	linkStyle                      lipgloss.Style
+	markdownStrikethroughStyle     lipgloss.Style
+
+	// activeTheme is the theme the styles above were built from.
+	activeTheme Theme
+)
+
+func init() {
+	applyTheme(ThemeDark)
+}
+
+// applyTheme rebuilds the package styles from t. The styles are shared by every model, so the
+// rebuild is skipped when t is already active; models built with the same theme never write them.
+func applyTheme(t Theme) {
+	if t == activeTheme {
+		return
+	}
+	activeTheme = t
+
+	titleStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Title).Underline(true)
+	subtitleStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Subtitle)
+	sectionHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(t.SectionHeader)
+	subjectStyle = lipgloss.NewStyle().Foreground(t.Subject)
+	errorStyle = lipgloss.NewStyle().Foreground(t.Error)
+	helperStyle = lipgloss.NewStyle().Foreground(t.Helper)
+
+	heroTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Accent)
+	heroBoxStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(t.Accent).Foreground(t.Text).Background(t.HeroBackground).Padding(1, 2)
+	heroSummaryStyle = lipgloss.NewStyle().PaddingLeft(2)
+	taglineStyle = lipgloss.NewStyle().Foreground(t.SecondaryText).Italic(true)
+	statusBarStyle = lipgloss.NewStyle().Foreground(t.Status).Padding(0, 1)
+	currentLineStyle = lipgloss.NewStyle().Foreground(t.HighlightText).Background(t.Highlight)
+	persistedSuggestionStyle = lipgloss.NewStyle().Foreground(t.Saved).Italic(true)
+	findMatchStyle = lipgloss.NewStyle().Foreground(t.HighlightText).Background(t.Accent).Bold(true)
+	logoFaceStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Text).Background(t.HeroBackground)
+	logoShadowStyle = lipgloss.NewStyle().Foreground(t.LogoShadow)
+	logoContainerStyle = lipgloss.NewStyle().Padding(0, 1)
+
+	composerFocusedBaseStyle = lipgloss.NewStyle().Background(t.ComposerFocusedBackground)
+	composerBlurredBaseStyle = lipgloss.NewStyle().Background(t.ComposerBlurredBackground)
+	composerCursorLineFocusedStyle = lipgloss.NewStyle().Background(t.ComposerCursorLineFocused).Foreground(t.Text)
+	composerCursorLineBlurredStyle = lipgloss.NewStyle().Background(t.ComposerCursorLineBlurred).Foreground(t.SecondaryText)
+	composerFocusedTextStyle = lipgloss.NewStyle().Foreground(t.Text)
+	composerBlurredTextStyle = lipgloss.NewStyle().Foreground(t.DimText)
+	composerPlaceholderStyle = lipgloss.NewStyle().Foreground(t.SoftText).Italic(true)
+	composerPromptStyle = lipgloss.NewStyle().Foreground(t.Accent).Bold(true)
+
+	markdownHeadingStyle = lipgloss.NewStyle().Foreground(t.Accent).Bold(true)
+	markdownBulletStyle = lipgloss.NewStyle().Foreground(t.SecondaryText).Bold(true)
+	markdownTableStyle = lipgloss.NewStyle().Foreground(t.Table)
+	markdownTableHeaderStyle = lipgloss.NewStyle().Foreground(t.TableHeader).Bold(true)
+	markdownQuoteStyle = lipgloss.NewStyle().Foreground(t.Muted).Italic(true)
+	markdownCodeStyle = lipgloss.NewStyle().Foreground(t.DimText)
+	markdownBoldStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Strong)
+	markdownItalicStyle = lipgloss.NewStyle().Italic(true).Foreground(t.SoftText)
+	markdownInlineCodeStyle = lipgloss.NewStyle().Foreground(t.Text).Background(t.InlineCodeBackground)
+	latexStyle = lipgloss.NewStyle().Foreground(t.Latex).Bold(true)
+	linkStyle = lipgloss.NewStyle().Foreground(t.Link).Underline(true)
+	markdownStrikethroughStyle = lipgloss.NewStyle().Strikethrough(true).Foreground(t.Muted)
+}
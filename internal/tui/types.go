@@ -48,12 +48,39 @@ const (
 	composerModeURL
 	composerModeNote
 	composerModeQuestion
+	composerModeScratchpad
+	composerModeSearch
+	composerModePickNote
+	composerModeEditNote
+	composerModeFind
 )
 
 const (
 	composerURLPlaceholder      = "Paste an arXiv URL or identifier (Alt+Enter to load)…"
 	composerNotePlaceholder     = "Enter: ask • Ctrl+Enter: note • Alt+Enter: URL"
 	composerQuestionPlaceholder = "Ask about the loaded PDF (Enter to send)…"
+	composerScratchPlaceholder  = "Jot anything about this paper (Ctrl+Enter to save, Esc to cancel)…"
+	composerSearchPlaceholder   = "Search saved notes (Enter to search, Esc to cancel)…"
+	composerPickNotePlaceholder = "↑/↓ to choose a saved note, Enter to pick it, Esc to cancel…"
+	composerEditNotePlaceholder = "Edit the note (Ctrl+Enter to save, Esc to cancel)…"
+	composerFindPlaceholder     = "Find in the conversation (Enter to highlight, Esc to cancel)…"
 )
 
-const fetchInProgressMessage = "Fetch already in progress; wait for it to finish."
+const (
+	defaultComposerCharLimit = 2000
+	// composerCounterRatio is the fraction of the limit after which the N/limit counter appears.
+	composerCounterRatio = 0.8
+)
+
+// defaultBriefStreamRate is how many redraws per second a streaming brief section gets by default.
+const defaultBriefStreamRate = 8
+
+// defaultBriefSectionTimeout bounds the summary section's generation when Config.BriefSectionTimeout
+// is unset.
+const defaultBriefSectionTimeout = 2 * time.Minute
+
+// defaultFetchTimeout bounds loading a paper when Config.FetchTimeout is unset.
+const defaultFetchTimeout = 3 * time.Minute
+
+// defaultQuestionTimeout bounds a streamed answer when Config.QuestionTimeout is unset.
+const defaultQuestionTimeout = 2 * time.Minute
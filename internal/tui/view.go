@@ -3,9 +3,13 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/reflow/wordwrap"
+
+	"github.com/csheth/browse/internal/arxiv"
+	"github.com/csheth/browse/internal/notes"
 )
 
 func (m *model) View() string {
@@ -58,11 +62,35 @@ func (m *model) renderStackedDisplay() string {
 }
 
 func (m *model) composerHelpText() string {
+	if m.composerMode == composerModeScratchpad {
+		return "Enter: new line • Ctrl+Enter: save scratchpad • Esc: cancel"
+	}
+	if m.composerMode == composerModeSearch {
+		return "Enter: search saved notes • Esc: cancel"
+	}
+	if m.composerMode == composerModeFind {
+		return "Enter: highlight matches • Esc: cancel"
+	}
+	if m.composerMode == composerModePickNote {
+		if m.confirmingDelete {
+			return "y: delete note • n: keep it"
+		}
+		if m.notePicker == pickNoteToDelete {
+			return "↑/↓: choose note • Enter: delete • Esc: cancel"
+		}
+		return "↑/↓: choose note • Enter: edit • Esc: cancel"
+	}
+	if m.composerMode == composerModeEditNote {
+		return "Enter: new line • Ctrl+Enter: save note • Esc: cancel"
+	}
 	return "Enter: load/ask • Ctrl+Enter: note • Alt+Enter: URL • Esc: clear"
 }
 
 func (m *model) footerTickerView() string {
 	hints := m.composerHelpText()
+	if len(m.sessions) > 1 {
+		hints = m.tabLabel() + "  •  " + hints
+	}
 	width := m.viewport.Width
 	if width <= 0 {
 		width = m.layout.viewportWidth
@@ -74,6 +102,9 @@ func (m *model) footerTickerView() string {
 	if available <= 0 {
 		available = width
 	}
+	if badge := m.errorBadge(); badge != "" {
+		hints = badge + "  •  " + hints
+	}
 	line := previewText(hints, available)
 	if event := m.lastTranscriptEvent(); event != "" {
 		separator := "  •  "
@@ -109,6 +140,20 @@ func describeTranscriptEntry(entry transcriptEntry) string {
 		return briefEventLabel(entry)
 	case "save":
 		return "Notes saved"
+	case "export":
+		return "Brief exported"
+	case "gaps":
+		return "Note gaps suggested"
+	case "guide":
+		return "Reading guide rebuilt"
+	case "search":
+		return "Saved note found"
+	case "related":
+		return "Related notes found"
+	case "abstract":
+		return "Abstract shown"
+	case "diagnostics":
+		return "Diagnostics shown"
 	case "error":
 		return errorEventLabel(entry.Content)
 	default:
@@ -159,26 +204,97 @@ func errorEventLabel(content string) string {
 
 func (m *model) heroView() string {
 	logo := renderLogo()
+	tagline := heroTagline
+	if len(m.sessions) > 1 {
+		tagline = m.tabLabel() + " · " + tagline
+	}
 	if m.paper == nil {
 		return lipgloss.JoinVertical(
 			lipgloss.Left,
 			logo,
-			taglineStyle.Render(heroTagline),
+			taglineStyle.Render(tagline),
 		)
 	}
 
 	title := heroTitleStyle.Render(wordwrap.String(m.paper.Title, 48))
-	meta := []string{helperStyle.Render(fmt.Sprintf("arXiv: %s", m.paper.ID))}
+	meta := []string{helperStyle.Render(fmt.Sprintf("%s: %s · %s · %d%% read", m.paper.SourceName(), paperIDLabel(m.paper), m.paperLength(), m.readingProgress()))}
 	if len(m.paper.Authors) > 0 {
 		meta = append(meta, helperStyle.Render("Authors: "+shortenList(m.paper.Authors, 3)))
 	}
 	if len(m.paper.Subjects) > 0 {
 		meta = append(meta, helperStyle.Render("Subjects: "+shortenList(m.paper.Subjects, 3)))
 	}
+	if model := briefModelLabel(m.briefLLM); model != "" {
+		meta = append(meta, helperStyle.Render("Brief by: "+model))
+	}
 	content := strings.Join(append([]string{title}, meta...), "\n")
 	summary := heroBoxStyle.Render(content)
 	panel := lipgloss.JoinHorizontal(lipgloss.Top, logo, heroSummaryStyle.Render(summary))
-	return lipgloss.JoinVertical(lipgloss.Left, panel, taglineStyle.Render(heroTagline))
+	return lipgloss.JoinVertical(lipgloss.Left, panel, taglineStyle.Render(tagline))
+}
+
+// paperIDLabel sets the version apart from the identifier ("2308.01234 v2"), whether the URL pinned
+// it or the API reported it as the latest.
+func paperIDLabel(paper *arxiv.Paper) string {
+	if paper.Version == "" {
+		return paper.ID
+	}
+	return strings.TrimSuffix(paper.ID, paper.Version) + " " + paper.Version
+}
+
+// briefModelLabel renders stored LLM metadata as "provider · model", or "" when nothing was recorded.
+func briefModelLabel(meta *notes.LLMMetadata) string {
+	if meta == nil {
+		return ""
+	}
+	var parts []string
+	for _, part := range []string{meta.Provider, meta.Model} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// readingWordsPerMinute is the pace estimateReadingTime assumes.
+const readingWordsPerMinute = 220
+
+// estimateReadingTime is how long text takes to read at readingWordsPerMinute.
+func estimateReadingTime(text string) time.Duration {
+	return time.Duration(len(strings.Fields(text))) * time.Minute / readingWordsPerMinute
+}
+
+// paperLength describes the paper's size for the hero, or "length unknown" without its text.
+func (m *model) paperLength() string {
+	if m.paperWords == 0 {
+		return "length unknown"
+	}
+	minutes := int(m.paperReadingTime.Round(time.Minute) / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	return fmt.Sprintf("%d words, ~%d min", m.paperWords, minutes)
+}
+
+// readingProgress is how far the viewport has scrolled through the paper's content.
+func (m *model) readingProgress() int {
+	return readingPercent(m.viewport.YOffset, m.viewport.Height, m.lineCount)
+}
+
+// readingPercent maps a scroll offset to 0–100%, where 100% means the last line is visible.
+// Content that fits in the viewport counts as fully read.
+func readingPercent(yOffset, height, lines int) int {
+	scrollable := lines - height
+	if height <= 0 || scrollable <= 0 {
+		return 100
+	}
+	if yOffset <= 0 {
+		return 0
+	}
+	if yOffset >= scrollable {
+		return 100
+	}
+	return yOffset * 100 / scrollable
 }
 
 func (m *model) frameWithHero(body string) string {
@@ -0,0 +1,129 @@
+package tuitest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// UpdateGoldenEnv mirrors the integration snapshot switch: when set, CompareGolden rewrites the
+// golden file instead of diffing against it.
+const UpdateGoldenEnv = "PAPERSCOUT_UPDATE_SNAPSHOTS"
+
+const goldenFrameHeader = "--- frame %d ---"
+
+// GoldenFrames returns the plain frames that make up a golden session. Consecutive identical
+// renders are collapsed because Bubble Tea repaints the same view several times per keystroke and
+// the exact repaint count depends on timing.
+func (r *Recording) GoldenFrames() []string {
+	if r == nil {
+		return nil
+	}
+	var frames []string
+	for _, frame := range r.Frames {
+		plain := normalizeLines(frame.Plain)
+		if strings.TrimSpace(plain) == "" {
+			continue
+		}
+		if len(frames) > 0 && frames[len(frames)-1] == plain {
+			continue
+		}
+		frames = append(frames, plain)
+	}
+	return frames
+}
+
+// SaveGolden serializes the normalized frames to path, creating parent directories as needed.
+func (r *Recording) SaveGolden(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(encodeGolden(r.GoldenFrames())), 0o644)
+}
+
+// CompareGolden diffs the normalized frames against the golden file at path and reports the first
+// mismatching frame. When UpdateGoldenEnv is set the golden file is rewritten instead.
+func (r *Recording) CompareGolden(path string) error {
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		return r.SaveGolden(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("tuitest: golden %s missing; rerun with %s=1 to record it", path, UpdateGoldenEnv)
+		}
+		return err
+	}
+	want := decodeGolden(string(data))
+	got := r.GoldenFrames()
+	for i := 0; i < len(want) || i < len(got); i++ {
+		switch {
+		case i >= len(got):
+			return fmt.Errorf("tuitest: golden %s expects %d frames, recording has %d", path, len(want), len(got))
+		case i >= len(want):
+			return fmt.Errorf("tuitest: recording has extra frame %d not in golden %s:\n%s", i, path, got[i])
+		case want[i] != got[i]:
+			return fmt.Errorf("tuitest: frame %d differs from golden %s\n%s", i, path, diffLines(want[i], got[i]))
+		}
+	}
+	return nil
+}
+
+func encodeGolden(frames []string) string {
+	var b strings.Builder
+	for i, frame := range frames {
+		b.WriteString(fmt.Sprintf(goldenFrameHeader, i))
+		b.WriteRune('\n')
+		b.WriteString(frame)
+		b.WriteRune('\n')
+	}
+	return b.String()
+}
+
+func decodeGolden(data string) []string {
+	var frames []string
+	var current []string
+	inFrame := false
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		var idx int
+		if _, err := fmt.Sscanf(line, goldenFrameHeader, &idx); err == nil && strings.HasPrefix(line, "--- frame ") {
+			if inFrame {
+				frames = append(frames, normalizeLines(strings.Join(current, "\n")))
+			}
+			current = nil
+			inFrame = true
+			continue
+		}
+		if inFrame {
+			current = append(current, line)
+		}
+	}
+	if inFrame {
+		frames = append(frames, normalizeLines(strings.Join(current, "\n")))
+	}
+	return frames
+}
+
+// diffLines renders a minimal line-by-line diff: unchanged lines are omitted, and each mismatch
+// shows the golden (-) and recorded (+) line.
+func diffLines(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	var b strings.Builder
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w == g {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("line %d:\n- %s\n+ %s\n", i+1, w, g))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
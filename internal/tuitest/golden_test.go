@@ -0,0 +1,58 @@
+package tuitest
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func syntheticRecording() *Recording {
+	raw := "\x1b[2J\x1b[HPaperScout\n> \x1b[2J\x1b[HPaperScout\n> \x1b[2J\x1b[HPaperScout\n> 2101.00001   \n\n"
+	return &Recording{Raw: []byte(raw), Frames: parseFrames([]byte(raw))}
+}
+
+func TestGoldenRoundTrip(t *testing.T) {
+	t.Setenv(UpdateGoldenEnv, "")
+	path := filepath.Join(t.TempDir(), "session.golden")
+	rec := syntheticRecording()
+
+	if got := len(rec.GoldenFrames()); got != 2 {
+		t.Fatalf("expected duplicate repaint collapsed into 2 frames, got %d", got)
+	}
+	if err := rec.SaveGolden(path); err != nil {
+		t.Fatalf("SaveGolden() error = %v", err)
+	}
+	if err := rec.CompareGolden(path); err != nil {
+		t.Fatalf("CompareGolden() error = %v", err)
+	}
+}
+
+func TestCompareGoldenReportsMismatch(t *testing.T) {
+	t.Setenv(UpdateGoldenEnv, "")
+	path := filepath.Join(t.TempDir(), "session.golden")
+	if err := syntheticRecording().SaveGolden(path); err != nil {
+		t.Fatalf("SaveGolden() error = %v", err)
+	}
+
+	raw := "\x1b[2J\x1b[HPaperScout\n> \x1b[2J\x1b[HPaperScout\n> 2202.00002"
+	changed := &Recording{Frames: parseFrames([]byte(raw))}
+	err := changed.CompareGolden(path)
+	if err == nil {
+		t.Fatal("expected mismatch error")
+	}
+	if !strings.Contains(err.Error(), "frame 1") || !strings.Contains(err.Error(), "+ > 2202.00002") {
+		t.Fatalf("expected frame diff in error, got %v", err)
+	}
+}
+
+func TestCompareGoldenUpdatesWhenRequested(t *testing.T) {
+	t.Setenv(UpdateGoldenEnv, "1")
+	path := filepath.Join(t.TempDir(), "session.golden")
+	if err := syntheticRecording().CompareGolden(path); err != nil {
+		t.Fatalf("CompareGolden() with update env error = %v", err)
+	}
+	t.Setenv(UpdateGoldenEnv, "")
+	if err := syntheticRecording().CompareGolden(path); err != nil {
+		t.Fatalf("CompareGolden() after update error = %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+// Package version exposes the PaperScout build version.
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Version, Commit, and Date are overridden at build time, eg.
+// -ldflags "-X github.com/csheth/browse/internal/version.Version=v1.2.3
+// -X github.com/csheth/browse/internal/version.Commit=$(git rev-parse --short HEAD)
+// -X github.com/csheth/browse/internal/version.Date=$(date -u +%Y-%m-%d)".
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+)
+
+// String describes the build as "v1.2.3 (commit abc1234, built 2024-05-01)", leaving out whatever
+// was not injected.
+func String() string {
+	var details []string
+	if commit := strings.TrimSpace(Commit); commit != "" {
+		details = append(details, "commit "+commit)
+	}
+	if date := strings.TrimSpace(Date); date != "" {
+		details = append(details, "built "+date)
+	}
+	if len(details) == 0 {
+		return Version
+	}
+	return fmt.Sprintf("%s (%s)", Version, strings.Join(details, ", "))
+}
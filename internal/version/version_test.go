@@ -0,0 +1,20 @@
+package version
+
+import "testing"
+
+func TestVersionDefaultsToDev(t *testing.T) {
+	if Version != "dev" {
+		t.Fatalf("Version = %q, want dev", Version)
+	}
+	if got := String(); got != "dev" {
+		t.Fatalf("String() = %q, want dev", got)
+	}
+}
+
+func TestStringIncludesInjectedCommitAndDate(t *testing.T) {
+	defer func(commit, date string) { Commit, Date = commit, date }(Commit, Date)
+	Commit, Date = "abc1234", "2024-05-01"
+	if got, want := String(), "dev (commit abc1234, built 2024-05-01)"; got != want {
+		t.Fatalf("String() = %q, want %q", got, want)
+	}
+}